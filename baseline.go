@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// defaultBaselinePath is where baseline create and update write when
+// --baseline is not given
+const defaultBaselinePath = "reputation-baseline.json"
+
+// runBaseline creates or updates the baseline at path from fresh scans.
+// The targets are addresses and an optional network, as for scan, or a
+// batch file of addresses; update without targets rescans the addresses
+// the baseline already holds findings of.
+func runBaseline(ctx context.Context, s *scanner.Scanner, args []string, path string, chainID int64, local bool) {
+	if len(args) < 1 || args[0] != "create" && args[0] != "update" {
+		fatalf("Usage: scanner baseline create|update [0x... | addresses.txt] [network] [--baseline file]")
+	}
+	action, args := args[0], args[1:]
+	if path == "" {
+		path = defaultBaselinePath
+	}
+
+	baseline := scanner.NewBaseline()
+	if action == "update" {
+		loaded, err := scanner.LoadBaseline(path)
+		switch {
+		case err == nil:
+			baseline = loaded
+		case errors.Is(err, os.ErrNotExist):
+			infof("No baseline at %s yet; creating it", path)
+		default:
+			fatalf("Cannot read baseline: %v", err)
+		}
+	}
+
+	targets := map[string][]string{} // network -> addresses
+	switch {
+	case len(args) == 0 && action == "update":
+		for _, network := range s.NetworkNames() {
+			if addresses := baseline.Addresses(network); len(addresses) > 0 {
+				targets[network] = addresses
+			}
+		}
+		if len(targets) == 0 {
+			fatalf("The baseline at %s holds no findings; name the addresses to scan", path)
+		}
+	case len(args) == 0:
+		fatalf("Addresses required: scanner baseline create 0x... (or a file of addresses)")
+	default:
+		addresses, network := splitScanArgs(args)
+		if len(addresses) == 1 && !strings.HasPrefix(strings.ToLower(addresses[0]), "0x") && !scanner.IsENSName(addresses[0]) {
+			data, err := readBatchInput(addresses[0])
+			if err != nil {
+				fatalf("Cannot read file: %v", err)
+			}
+			input := parseBatchInput(string(data), inputText)
+			input.logSkipped()
+			addresses = input.addresses
+		}
+		network = selectNetwork(s, network, chainID)
+		targets[network] = uniqueAddresses(addresses)
+	}
+
+	for network, addresses := range targets {
+		verifyChainID(s, network, local)
+		infof("🔍 Scanning %d addresses on %s for the baseline...", len(addresses), network)
+		reports, errs := s.ScanBatchContext(ctx, addresses, network, nil)
+		for _, err := range errs {
+			warnf("%v", err)
+		}
+		if err := firstAuthError(errs); err != nil {
+			fatalf("%v", err)
+		}
+		reportSetupErrors(reports...)
+		for _, report := range reports {
+			if report.Incomplete {
+				warnf("%s timed out; its baseline findings are left as they were", report.Address)
+				continue
+			}
+			baseline.Record(report)
+		}
+	}
+
+	if err := baseline.Save(path); err != nil {
+		fatalf("Cannot write baseline: %v", err)
+	}
+	infof("✅ Baseline of %d accepted findings saved to %s", len(baseline.Findings), path)
+}
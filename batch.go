@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// batchOptions carries the batch-specific CLI flags
+type batchOptions struct {
+	outputOptions
+	network string
+	resume  bool
+	restart bool
+	timeout time.Duration // deadline for the whole batch; 0 means none
+	ordered bool          // stream ndjson results in input order
+	since   time.Duration // reuse history reports newer than this; 0 disables the history
+	input   string        // inputText or inputCSV
+}
+
+// interruptGrace is how long in-flight scans may finish after Ctrl-C
+// before they are cut short
+const interruptGrace = 5 * time.Second
+
+// stdinInput is the batch file name that reads addresses from stdin
+const stdinInput = "-"
+
+// Checkpoints for stdin batches are named after this file
+const stdinCheckpointBase = "reputation-stdin"
+
+func batchScan(s *scanner.Scanner, filename string, opts batchOptions) []scanner.ReputationReport {
+	runAt := s.Now()
+	data, err := readBatchInput(filename)
+	if err != nil {
+		fatalf("Cannot read file: %v", err)
+	}
+
+	input := parseBatchInput(string(data), opts.input)
+	input.logSkipped()
+	addresses := input.addresses
+
+	checkpointBase := filename
+	if filename == stdinInput {
+		checkpointBase = stdinCheckpointBase
+	}
+	cp, err := openCheckpoint(checkpointBase, addresses, opts.resume, opts.restart)
+	if err != nil {
+		fatalf("%v", err)
+	}
+
+	// Skip addresses completed by a previous run
+	results := make([]scanner.ReputationReport, len(addresses))
+	pending := []string{}
+	pendingIndex := []int{}
+	for i, addr := range addresses {
+		if report, ok := cp.done(addr); ok {
+			results[i] = report
+			continue
+		}
+		pending = append(pending, addr)
+		pendingIndex = append(pendingIndex, i)
+	}
+
+	if skipped := len(addresses) - len(pending); skipped > 0 {
+		infof("⏩ Resuming: %d of %d addresses already scanned", skipped, len(addresses))
+	}
+	if opts.since > 0 {
+		pending, pendingIndex = reuseHistory(s, pending, pendingIndex, results, opts)
+	}
+	infof("🔍 Batch scanning %d addresses on %s...", len(pending), opts.network)
+
+	ctx, cancelScans := context.WithCancel(context.Background())
+	defer cancelScans()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	// The first interrupt stops dispatching and lets in-flight scans finish
+	// so the partial results can be written; a second one quits at once,
+	// keeping only the checkpoint
+	stop := make(chan struct{})
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		close(stop)
+		stderr.setStatus("")
+		warnf("Interrupted; finishing in-flight scans (interrupt again to quit now)")
+		time.AfterFunc(interruptGrace, cancelScans)
+		<-interrupt
+		stderr.setStatus("")
+		if err := cp.flush(); err != nil {
+			errorf("Cannot write checkpoint: %v", err)
+		} else {
+			infof("⏸  Progress saved to %s (rerun with --resume)", cp.path)
+		}
+		os.Exit(exitInterrupted)
+	}()
+	defer signal.Stop(interrupt)
+
+	jobs := make(chan string)
+	go func() {
+		defer close(jobs)
+		for _, address := range pending {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			select {
+			case jobs <- address:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	progress := startProgress(len(pending), progressEnabled(opts))
+	var fresh []scanner.ReputationReport
+	var errs []error
+	s.ScanStreamFunc(ctx, jobs, opts.network, func(i int, report scanner.ReputationReport, err error) {
+		results[pendingIndex[i]] = report
+		fresh = append(fresh, report)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", pending[i], err))
+		}
+		cp.record(report)
+		input.annotate(&report)
+		printBatchLine(report)
+		progress.advance()
+		// Record the report so the next --since run can reuse it
+		if opts.since > 0 && report.Error == "" && !report.Incomplete {
+			if err := scanner.AppendHistory(scanner.DefaultHistoryDir(), report); err != nil {
+				warnf("Cannot write history: %v", err)
+			}
+		}
+	})
+	progress.finish()
+	interrupted := false
+	select {
+	case <-stop:
+		interrupted = true
+	default:
+	}
+
+	// An interrupted batch keeps only the addresses it got to; the others
+	// have no report yet
+	if interrupted {
+		var partial []scanner.ReputationReport
+		for _, report := range results {
+			if report.Address != "" {
+				partial = append(partial, report)
+			}
+		}
+		results = partial
+	}
+	for i := range results {
+		input.annotate(&results[i])
+	}
+	if err := firstAuthError(errs); err != nil {
+		cp.flush()
+		fatalf("%v", err)
+	}
+	reportSetupErrors(fresh...)
+
+	// Save results
+	outputFile := opts.output
+	if outputFile == "" && opts.format != formatVerdict {
+		outputFile = "reputation-results." + formatExtension(opts.format)
+	}
+	var buf bytes.Buffer
+	summary := summarize(results, input)
+	out := batchOutput{Summary: summary, Results: results, InvalidLines: input.invalid}
+	if opts.format != formatSQLite {
+		if err := renderBatch(&buf, opts.redactBatch(out), opts.format); err != nil {
+			fatalf("Cannot render results: %v", err)
+		}
+	}
+	if opts.validate {
+		if err := scanner.ValidateBatchJSON(buf.Bytes()); err != nil {
+			cp.flush()
+			fatalf("Results do not match schema: %v", err)
+		}
+	}
+	switch {
+	case opts.format == formatSQLite:
+		// Rejected input lines have no report to store
+		err = writeSQLite(outputFile, opts.redactBatch(out).Results, runAt)
+	case outputFile == "":
+		_, err = os.Stdout.Write(buf.Bytes())
+	default:
+		err = writeOutput(outputFile, buf.Bytes())
+	}
+	if err != nil {
+		cp.flush()
+		fatalf("Cannot write results: %v", err)
+	}
+	if interrupted {
+		if err := cp.flush(); err != nil {
+			errorf("Cannot write checkpoint: %v", err)
+		}
+	} else {
+		cp.remove()
+	}
+
+	if len(errs) > 0 {
+		warnf("%d addresses could not be scanned:", len(errs))
+		for _, err := range errs {
+			warnf("  %v", err)
+		}
+	}
+	infof("📊 %s", summary)
+	if interrupted {
+		if outputFile != "" {
+			infof("⏸  Partial results for %d of %d addresses saved to %s", len(results), len(addresses), outputFile)
+		}
+		infof("⏸  Progress saved to %s (rerun with --resume)", cp.path)
+		os.Exit(exitInterrupted)
+	}
+	if outputFile != "" {
+		infof("✅ Results saved to %s", outputFile)
+	}
+	return results
+}
+
+// reuseHistory fills results with the latest history report of each
+// pending address scanned within opts.since, and returns the addresses
+// (and their positions) that still need a scan. Failed and incomplete
+// reports are never stored, so they are always rescanned.
+func reuseHistory(s *scanner.Scanner, pending []string, pendingIndex []int, results []scanner.ReputationReport, opts batchOptions) ([]string, []int) {
+	cutoff := s.Now().Add(-opts.since)
+	dir := scanner.DefaultHistoryDir()
+	var rescan []string
+	var rescanIndex []int
+	for i, addr := range pending {
+		history, err := scanner.LoadHistory(dir, addr, opts.network)
+		if err != nil {
+			warnf("Cannot read history: %v", err)
+		}
+		if n := len(history); n > 0 && history[n-1].Timestamp.After(cutoff) {
+			results[pendingIndex[i]] = history[n-1]
+			continue
+		}
+		rescan = append(rescan, addr)
+		rescanIndex = append(rescanIndex, pendingIndex[i])
+	}
+	if reused := len(pending) - len(rescan); reused > 0 {
+		infof("⏩ Reusing %d reports scanned since %s; rescanning %d addresses", reused, cutoff.Format("2006-01-02 15:04"), len(rescan))
+	}
+	return rescan, rescanIndex
+}
+
+// readBatchInput reads the batch file, or stdin for "-"
+func readBatchInput(filename string) ([]byte, error) {
+	if filename == stdinInput {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(filename)
+}
+
+// stdinIsTerminal reports whether stdin is interactive rather than a pipe
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func printBatchLine(report scanner.ReputationReport) {
+	if report.Error != "" {
+		errorf("%s %s", shortAddress(report.Address), report.Error)
+		return
+	}
+	infof("%s [%s] Score: %d/100 %s",
+		shortAddress(report.Address),
+		report.RiskLevel,
+		report.OverallScore,
+		getRiskEmoji(report.RiskLevel))
+	if report.Incomplete {
+		warnf("%s timed out; some checks have no result (raise --scan-timeout)", shortAddress(report.Address))
+	}
+	if report.RiskMismatch {
+		name := shortAddress(report.Address)
+		if report.Label != "" {
+			name += " (" + report.Label + ")"
+		}
+		warnf("%s expected %s risk, scanned %s", name, report.ExpectedRisk, report.RiskLevel)
+	}
+}
+
+func shortAddress(address string) string {
+	if len(address) <= 20 {
+		return address
+	}
+	return address[:20] + "..."
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// Batch input formats
+const (
+	inputText = "text" // one address per line
+	inputCSV  = "csv"  // address,label,expected_risk rows
+)
+
+// inputFormat returns the --input-format, else csv for .csv files and
+// text otherwise
+func inputFormat(filename, flag string) (string, error) {
+	switch flag {
+	case inputText, inputCSV:
+		return flag, nil
+	case "":
+		if strings.EqualFold(filepath.Ext(filename), ".csv") {
+			return inputCSV, nil
+		}
+		return inputText, nil
+	}
+	return "", fmt.Errorf("unsupported input format %q (supported: %s, %s)", flag, inputText, inputCSV)
+}
+
+// invalidLine is an input line that could not be scanned
+type invalidLine struct {
+	Line   int    `json:"line"`
+	Input  string `json:"input"`
+	Reason string `json:"reason"`
+}
+
+// annotation is the label and expected risk level given for an address
+// in a CSV batch file
+type annotation struct {
+	label        string
+	expectedRisk string
+}
+
+// batchInput is the parsed contents of a batch file
+type batchInput struct {
+	addresses   []string
+	annotations map[string]annotation // lowercase address -> annotation
+	invalid     []invalidLine
+	duplicates  int
+}
+
+// parseBatchInput validates each line of a batch file. Blank lines and
+// # comments are ignored. Addresses are deduplicated case-insensitively;
+// the first spelling is kept so the Address Format check still sees it.
+func parseBatchInput(data, format string) batchInput {
+	var in batchInput
+	p := newBatchParser()
+	err := p.read(strings.NewReader(data), format, func(address string) {
+		in.addresses = append(in.addresses, address)
+	})
+	if err != nil {
+		warnf("Cannot read input: %v", err)
+	}
+	in.annotations, in.invalid, in.duplicates = p.annotations, p.invalid, p.duplicates
+	return in
+}
+
+// annotate copies the label and expected risk level of the address into
+// report and flags a scanned risk level that differs from the expected one
+func (in batchInput) annotate(report *scanner.ReputationReport) {
+	if a, ok := in.annotations[strings.ToLower(report.Address)]; ok {
+		a.apply(report)
+	}
+}
+
+func (a annotation) apply(report *scanner.ReputationReport) {
+	report.Label = a.label
+	report.ExpectedRisk = a.expectedRisk
+	report.RiskMismatch = a.expectedRisk != "" && report.Error == "" && report.RiskLevel != a.expectedRisk
+	if report.Error == "" {
+		report.Seal()
+	}
+}
+
+// batchParser validates batch input one line at a time, for inputs that
+// are streamed rather than read whole
+type batchParser struct {
+	line       int
+	seen       map[string]bool
+	invalid    []invalidLine
+	duplicates int
+
+	mu          sync.Mutex // annotations are read while the input streams in
+	annotations map[string]annotation
+}
+
+func newBatchParser() *batchParser {
+	return &batchParser{seen: map[string]bool{}, annotations: map[string]annotation{}}
+}
+
+// read passes each new valid address in r to fn as it is read
+func (p *batchParser) read(r io.Reader, format string, fn func(address string)) error {
+	if format == inputCSV {
+		return p.readCSV(r, fn)
+	}
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		if address, ok := p.parse(lines.Text()); ok {
+			fn(address)
+		}
+	}
+	return lines.Err()
+}
+
+// readCSV reads address,label,expected_risk rows. A first row naming an
+// "address" column is a header, so columns may come in any order and
+// other columns are ignored.
+func (p *batchParser) readCSV(r io.Reader, fn func(address string)) error {
+	rows := csv.NewReader(r)
+	rows.Comment = '#'
+	rows.FieldsPerRecord = -1
+	rows.TrimLeadingSpace = true
+	columns := map[string]int{"address": 0, "label": 1, "expected_risk": 2}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	for first := true; ; first = false {
+		row, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := rows.FieldPos(0)
+		if first && isCSVHeader(row) {
+			columns = map[string]int{}
+			for i, name := range row {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			continue
+		}
+
+		address := field(row, "address")
+		if !p.check(line, address) {
+			continue
+		}
+		a := annotation{label: field(row, "label"), expectedRisk: strings.ToLower(field(row, "expected_risk"))}
+		if a.expectedRisk != "" && scanner.RiskRank(a.expectedRisk) < 0 {
+			p.invalid = append(p.invalid, invalidLine{Line: line, Input: strings.Join(row, ","), Reason: fmt.Sprintf("unknown expected_risk %q (use %s)", a.expectedRisk, strings.Join(scanner.RiskLevels, ", "))})
+			continue
+		}
+		p.accept(address)
+		if a != (annotation{}) {
+			p.mu.Lock()
+			p.annotations[strings.ToLower(address)] = a
+			p.mu.Unlock()
+		}
+		fn(address)
+	}
+}
+
+// annotate is batchInput.annotate for streamed input
+func (p *batchParser) annotate(report *scanner.ReputationReport) {
+	p.mu.Lock()
+	a, ok := p.annotations[strings.ToLower(report.Address)]
+	p.mu.Unlock()
+	if ok {
+		a.apply(report)
+	}
+}
+
+func isCSVHeader(row []string) bool {
+	for _, name := range row {
+		if strings.EqualFold(strings.TrimSpace(name), "address") {
+			return true
+		}
+	}
+	return false
+}
+
+// parse returns the address on the next line, or false for blank,
+// comment, invalid and duplicate lines
+func (p *batchParser) parse(line string) (string, bool) {
+	p.line++
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false
+	}
+	if !p.check(p.line, line) {
+		return "", false
+	}
+	p.accept(line)
+	return line, true
+}
+
+// check records invalid and duplicate addresses and reports whether
+// address is new and valid
+func (p *batchParser) check(line int, address string) bool {
+	if reason := addressProblem(address); reason != "" {
+		p.invalid = append(p.invalid, invalidLine{Line: line, Input: address, Reason: reason})
+		return false
+	}
+	if p.seen[strings.ToLower(address)] {
+		p.duplicates++
+		return false
+	}
+	return true
+}
+
+// accept marks address as seen, so later copies count as duplicates
+func (p *batchParser) accept(address string) {
+	p.seen[strings.ToLower(address)] = true
+}
+
+// addressProblem explains why s is not a hex address, or returns ""
+func addressProblem(s string) string {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return "missing 0x prefix"
+	}
+	digits := s[2:]
+	for _, c := range digits {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return fmt.Sprintf("non-hex character %q", c)
+		}
+	}
+	if len(digits) != 40 {
+		return fmt.Sprintf("wrong length: %d hex characters, want 40", len(digits))
+	}
+	if s[1] == 'X' {
+		return "prefix must be lowercase 0x"
+	}
+	return ""
+}
+
+// logSkipped prints a summary of lines that will not be scanned
+func (in batchInput) logSkipped() {
+	if len(in.invalid) > 0 {
+		warnf("Skipping %d invalid lines:", len(in.invalid))
+		for _, l := range in.invalid {
+			warnf("  line %d: %s (%s)", l.Line, l.Input, l.Reason)
+		}
+	}
+	if in.duplicates > 0 {
+		infof("⏭  Skipping %d duplicate addresses", in.duplicates)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"agent-reputation-scanner/scanner"
+)
+
+func TestAddressProblem(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ""},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", ""},
+		{"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "missing 0x prefix"},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea", "wrong length: 38 hex characters, want 40"},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00", "wrong length: 42 hex characters, want 40"},
+		{"0xgaaeb6053f3e94c9b9a09f33669435e7ef1beaed", `non-hex character 'g'`},
+		{"0X5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "prefix must be lowercase 0x"},
+	}
+	for _, tt := range tests {
+		if got := addressProblem(tt.in); got != tt.want {
+			t.Errorf("addressProblem(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseBatchInput(t *testing.T) {
+	in := parseBatchInput(`# watchlist
+0x1111111111111111111111111111111111111111
+
+  0x2222222222222222222222222222222222222222
+0x12345
+0X1111111111111111111111111111111111111111
+0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA
+0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+0x2222222222222222222222222222222222222222
+`, inputText)
+
+	// The first spelling of an address is the one scanned
+	want := []string{
+		"0x1111111111111111111111111111111111111111",
+		"0x2222222222222222222222222222222222222222",
+		"0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+	}
+	if !reflect.DeepEqual(in.addresses, want) {
+		t.Errorf("addresses = %q, want %q", in.addresses, want)
+	}
+	wantInvalid := []invalidLine{
+		{Line: 5, Input: "0x12345", Reason: "wrong length: 5 hex characters, want 40"},
+		{Line: 6, Input: "0X1111111111111111111111111111111111111111", Reason: "prefix must be lowercase 0x"},
+	}
+	if !reflect.DeepEqual(in.invalid, wantInvalid) {
+		t.Errorf("invalid = %+v, want %+v", in.invalid, wantInvalid)
+	}
+	if in.duplicates != 2 {
+		t.Errorf("duplicates = %d, want 2", in.duplicates)
+	}
+}
+
+func TestInputFormat(t *testing.T) {
+	tests := []struct {
+		filename, flag string
+		want           string
+	}{
+		{"watchlist.txt", "", inputText},
+		{"watchlist.CSV", "", inputCSV},
+		{"-", "", inputText},
+		{"watchlist.txt", inputCSV, inputCSV},
+		{"watchlist.csv", inputText, inputText},
+	}
+	for _, tt := range tests {
+		if got, err := inputFormat(tt.filename, tt.flag); err != nil || got != tt.want {
+			t.Errorf("inputFormat(%q, %q) = %q, %v, want %q", tt.filename, tt.flag, got, err, tt.want)
+		}
+	}
+	if _, err := inputFormat("watchlist.csv", "tsv"); err == nil {
+		t.Error("inputFormat accepted tsv")
+	}
+}
+
+func TestParseBatchInputCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          string
+		addresses   []string
+		annotations map[string]annotation
+		invalid     []invalidLine
+	}{
+		{"no header", `0x1111111111111111111111111111111111111111,Treasury,low
+0x2222222222222222222222222222222222222222
+# comment
+0x3333333333333333333333333333333333333333,"Hot wallet, EU"
+`, []string{
+			"0x1111111111111111111111111111111111111111",
+			"0x2222222222222222222222222222222222222222",
+			"0x3333333333333333333333333333333333333333",
+		}, map[string]annotation{
+			"0x1111111111111111111111111111111111111111": {label: "Treasury", expectedRisk: "low"},
+			"0x3333333333333333333333333333333333333333": {label: "Hot wallet, EU"},
+		}, nil},
+		// A header lets columns come in any order, with extra ones ignored
+		{"header", `owner,Expected_Risk,Address,label
+ops,HIGH,0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA,Bridge
+ops,,0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa,Copy
+ops,severe,0x4444444444444444444444444444444444444444,Typo
+ops,low,0x12345,Short
+`, []string{"0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}, map[string]annotation{
+			"0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": {label: "Bridge", expectedRisk: "high"},
+		}, []invalidLine{
+			{Line: 4, Input: "ops,severe,0x4444444444444444444444444444444444444444,Typo", Reason: `unknown expected_risk "severe" (use low, medium, high, critical)`},
+			{Line: 5, Input: "0x12345", Reason: "wrong length: 5 hex characters, want 40"},
+		}},
+	}
+	for _, tt := range tests {
+		in := parseBatchInput(tt.in, inputCSV)
+		if !reflect.DeepEqual(in.addresses, tt.addresses) {
+			t.Errorf("%s: addresses = %q, want %q", tt.name, in.addresses, tt.addresses)
+		}
+		if !reflect.DeepEqual(in.annotations, tt.annotations) {
+			t.Errorf("%s: annotations = %+v, want %+v", tt.name, in.annotations, tt.annotations)
+		}
+		if !reflect.DeepEqual(in.invalid, tt.invalid) {
+			t.Errorf("%s: invalid = %+v, want %+v", tt.name, in.invalid, tt.invalid)
+		}
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	in := parseBatchInput("address,label,expected_risk\n0x1111111111111111111111111111111111111111,Trusted,low\n", inputCSV)
+	tests := []struct {
+		report   scanner.ReputationReport
+		mismatch bool
+	}{
+		{scanner.ReputationReport{Address: "0x1111111111111111111111111111111111111111", RiskLevel: "low"}, false},
+		{scanner.ReputationReport{Address: "0x1111111111111111111111111111111111111111", RiskLevel: "critical"}, true},
+		// Failed scans have no risk level to compare
+		{scanner.ReputationReport{Address: "0x1111111111111111111111111111111111111111", Error: "timed out"}, false},
+	}
+	for _, tt := range tests {
+		report := tt.report
+		in.annotate(&report)
+		if report.Label != "Trusted" || report.ExpectedRisk != "low" || report.RiskMismatch != tt.mismatch {
+			t.Errorf("annotate(%s %s) = label %q, expected %q, mismatch %v, want Trusted, low, %v",
+				report.RiskLevel, report.Error, report.Label, report.ExpectedRisk, report.RiskMismatch, tt.mismatch)
+		}
+	}
+
+	report := scanner.ReputationReport{Address: "0x2222222222222222222222222222222222222222", RiskLevel: "critical"}
+	in.annotate(&report)
+	if report.Label != "" || report.RiskMismatch {
+		t.Errorf("annotate() labelled an address missing from the input: %q, mismatch %v", report.Label, report.RiskMismatch)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// calibrationClasses maps the label column of a calibrate file to whether
+// the address is a known scam
+var calibrationClasses = map[string]bool{
+	"scam":       true,
+	"malicious":  true,
+	"legit":      false,
+	"legitimate": false,
+	"benign":     false,
+}
+
+// readCalibrationLabels reads the address,label rows of a calibrate file
+// and returns the addresses in order with whether each is a known scam
+func readCalibrationLabels(filename string) ([]string, map[string]bool) {
+	data, err := readBatchInput(filename)
+	if err != nil {
+		fatalf("Cannot read file: %v", err)
+	}
+	in := parseBatchInput(string(data), inputCSV)
+	in.logSkipped()
+
+	var addresses []string
+	scam := map[string]bool{}
+	var unlabeled []string
+	for _, address := range in.addresses {
+		label := strings.ToLower(in.annotations[strings.ToLower(address)].label)
+		isScam, ok := calibrationClasses[label]
+		if !ok {
+			unlabeled = append(unlabeled, fmt.Sprintf("%s (%q)", address, label))
+			continue
+		}
+		addresses = append(addresses, address)
+		scam[strings.ToLower(address)] = isScam
+	}
+	if len(unlabeled) > 0 {
+		warnf("Skipping %d addresses not labeled scam or legit: %s", len(unlabeled), strings.Join(unlabeled, ", "))
+	}
+
+	scams := 0
+	for _, isScam := range scam {
+		if isScam {
+			scams++
+		}
+	}
+	if scams == 0 || scams == len(scam) {
+		fatalf("calibrate needs both scam and legit addresses (got %d scam, %d legit)", scams, len(scam)-scams)
+	}
+	return addresses, scam
+}
+
+// calibrate scans the labeled addresses on network and writes
+// how well their scores match the labels: a scanner.Calibration in JSON,
+// or tables of precision and recall by risk level and score threshold and
+// of each check's discriminative power in text
+func calibrate(ctx context.Context, s *scanner.Scanner, addresses []string, scam map[string]bool, network string, out outputOptions) scanner.Calibration {
+	if out.format != formatText && out.format != formatJSON {
+		fatalf("calibrate supports --format text or json")
+	}
+	infof("🔍 Calibrating on %d labeled addresses on %s...", len(addresses), network)
+
+	progress := startProgress(len(addresses), progressEnabled(batchOptions{outputOptions: out}))
+	reports, errs := s.ScanBatchContext(ctx, addresses, network, func(scanner.ReputationReport) {
+		progress.advance()
+	})
+	progress.finish()
+	for _, err := range errs {
+		warnf("%v", err)
+	}
+	if err := firstAuthError(errs); err != nil {
+		fatalf("%v", err)
+	}
+	reportSetupErrors(reports...)
+
+	calibration := scanner.Calibrate(reports, scam)
+	var buf bytes.Buffer
+	if out.format == formatJSON {
+		if err := writeJSON(&buf, calibration); err != nil {
+			fatalf("Cannot render calibration: %v", err)
+		}
+	} else {
+		writeCalibration(&buf, calibration)
+	}
+
+	if out.output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return calibration
+	}
+	if err := writeOutput(out.output, buf.Bytes()); err != nil {
+		fatalf("Cannot write calibration: %v", err)
+	}
+	infof("✅ Calibration saved to %s", out.output)
+	return calibration
+}
+
+func writeCalibration(w io.Writer, c scanner.Calibration) {
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "  SCORE CALIBRATION\n")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "Network: %s\n", c.Network)
+	labeled := fmt.Sprintf("%d scam, %d legit", c.Scams, c.Legit)
+	if c.Failed > 0 {
+		labeled += fmt.Sprintf(" (%d failed scans left out)", c.Failed)
+	}
+	fmt.Fprintf(w, "Labeled: %s\n", labeled)
+	fmt.Fprintf(w, "AUC:     %.3f (0.5 is chance, 1 separates scams from legit perfectly)\n", c.AUC)
+
+	writeMetricsTable(w, "RISK LEVELS (flagged at or above):", c.RiskLevels, "")
+	writeMetricsTable(w, "SCORE THRESHOLDS (flagged below):", c.Thresholds, fmt.Sprintf("score < %d", c.BestThreshold))
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "CHECKS (most discriminating first):")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	fmt.Fprintf(w, "  %-25s %6s %6s %6s\n", "Check", "AUC", "Scam%", "Legit%")
+	for _, check := range c.Checks {
+		fmt.Fprintf(w, "  %-25s %6.3f %5.0f%% %5.0f%%\n", check.Name, check.AUC, check.ScamFlagged*100, check.LegitFlagged*100)
+	}
+	fmt.Fprintf(w, "  Scam%%/Legit%%: share of each that the check warned or failed on\n")
+
+	if len(c.Missed) > 0 || len(c.FalseAlarms) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "MISCLASSIFIED:")
+		fmt.Fprintln(w, strings.Repeat("─", 60))
+		for _, address := range c.Missed {
+			fmt.Fprintf(w, "  scam rated low risk:          %s\n", address)
+		}
+		for _, address := range c.FalseAlarms {
+			fmt.Fprintf(w, "  legit rated high or critical: %s\n", address)
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+}
+
+// writeMetricsTable writes one row per rule, marking the rule best
+func writeMetricsTable(w io.Writer, title string, metrics []scanner.ClassifierMetrics, best string) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	fmt.Fprintf(w, "  %-16s %9s %7s %6s %6s\n", "Rule", "Precision", "Recall", "F1", "FPR")
+	for _, m := range metrics {
+		mark := ""
+		if m.Rule == best {
+			mark = "  ← best F1"
+		}
+		fmt.Fprintf(w, "  %-16s %9.3f %7.3f %6.3f %6.3f%s\n", m.Rule, m.Precision, m.Recall, m.F1, m.FalsePositiveRate, mark)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// parseNetworks splits a --networks list such as "ethereum,base"
+func parseNetworks(s *scanner.Scanner, list string) []string {
+	var networks []string
+	seen := map[string]bool{}
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, err := s.Network(name); err != nil {
+			fatalf("%v", err)
+		}
+		seen[name] = true
+		networks = append(networks, name)
+	}
+	if len(networks) == 0 {
+		fatalf("--networks needs at least one network")
+	}
+	return networks
+}
+
+// scanChains scans address on every network and writes the combined
+// report: a MultiChainReport in JSON, the per-check rows of every chain in
+// CSV, or a per-chain breakdown with the findings in text
+func scanChains(ctx context.Context, s *scanner.Scanner, address string, networks []string, out outputOptions) scanner.MultiChainReport {
+	if out.format != formatText && out.format != formatJSON && out.format != formatCSV && out.format != formatVerdict {
+		fatalf("--all-chains and --networks support --format text, json or csv")
+	}
+	infof("🔍 Scanning %s on %d networks (%s)...", address, len(networks), strings.Join(networks, ", "))
+
+	report, err := s.ScanChainsContext(ctx, address, networks)
+	for _, chain := range report.Chains {
+		if chain.Error != "" {
+			warnf("%s: %s", chain.Network, chain.Error)
+		}
+	}
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if report.Incomplete {
+		warnf("Scan deadline exceeded; some checks timed out (raise --timeout)")
+	}
+	reportSetupErrors(report.Chains...)
+
+	rendered := out.redactChains(report)
+	var buf bytes.Buffer
+	switch out.format {
+	case formatJSON:
+		err = writeJSON(&buf, rendered)
+	case formatCSV:
+		cw := csv.NewWriter(&buf)
+		cw.Write(csvHeader)
+		for _, chain := range rendered.Chains {
+			writeCSVRows(cw, chain)
+		}
+		cw.Flush()
+		err = cw.Error()
+	case formatVerdict:
+		fmt.Fprintf(&buf, "%s %d %s\n", rendered.RiskLevel, rendered.OverallScore, rendered.Address)
+	default:
+		writeMultiChainReport(&buf, rendered)
+	}
+	if err != nil {
+		fatalf("Cannot render report: %v", err)
+	}
+	if out.validate {
+		if err := scanner.ValidateMultiChainJSON(buf.Bytes()); err != nil {
+			fatalf("Report does not match schema: %v", err)
+		}
+	}
+
+	if out.output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return report
+	}
+	if err := writeOutput(out.output, buf.Bytes()); err != nil {
+		fatalf("Cannot write report: %v", err)
+	}
+	infof("✅ Report saved to %s", out.output)
+	return report
+}
+
+func writeMultiChainReport(w io.Writer, report scanner.MultiChainReport) {
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "  MULTI-CHAIN REPUTATION REPORT\n")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "Address:  %s\n", report.Address)
+	if report.ENSName != "" {
+		fmt.Fprintf(w, "ENS:      %s\n", report.ENSName)
+	}
+	fmt.Fprintf(w, "Networks: %s\n", strings.Join(report.Networks, ", "))
+	fmt.Fprintf(w, "Time:     %s\n", report.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "Overall Score: %d/100 (worst chain)\n", report.OverallScore)
+	fmt.Fprintf(w, "Risk Level:    %s %s (on %s)\n", getRiskEmoji(report.RiskLevel), strings.ToUpper(report.RiskLevel), report.RiskiestNetwork)
+	fmt.Fprintf(w, "Confidence:    %d%%\n", report.Confidence)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "CHAINS:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	for _, chain := range report.Chains {
+		if chain.Error != "" {
+			fmt.Fprintf(w, "  %s %-12s error: %s\n", getRiskEmoji(""), chain.Network, chain.Error)
+			continue
+		}
+		fmt.Fprintf(w, "  %s %-12s %3d/100 %s\n", getRiskEmoji(chain.RiskLevel), chain.Network, chain.OverallScore, chain.RiskLevel)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "FINDINGS:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(w, "  ✓ No failed or warning checks on any chain")
+	}
+	for _, finding := range report.Findings {
+		statusIcon := "⚠️"
+		if finding.Status == "fail" {
+			statusIcon = "✗"
+		}
+		fmt.Fprintf(w, "  %s [%s] %-25s [%d%%] %s\n", statusIcon, finding.Network, finding.Check, finding.Score, finding.Status)
+		fmt.Fprintf(w, "     └─ %s\n", finding.Details)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "RECOMMENDATIONS:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	for _, rec := range report.Recommendations {
+		fmt.Fprintf(w, "  %s\n", rec)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintln(w, "⚠️  This is an automated assessment. Always conduct")
+	fmt.Fprintln(w, "   additional due diligence for high-value transactions.")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"agent-reputation-scanner/internal/atomicfile"
+	"agent-reputation-scanner/scanner"
+)
+
+// Completed reports are flushed to disk every checkpointInterval scans
+const checkpointInterval = 10
+
+var errCheckpointExists = errors.New("checkpoint exists")
+
+// checkpoint tracks completed batch scans so an interrupted batch can resume
+type checkpoint struct {
+	InputHash string                              `json:"input_hash"`
+	UpdatedAt time.Time                           `json:"updated_at"`
+	Completed map[string]scanner.ReputationReport `json:"completed"`
+
+	path    string
+	mu      sync.Mutex
+	pending int
+}
+
+func checkpointPath(input string) string {
+	return input + ".checkpoint.json"
+}
+
+// hashInput identifies a batch by its address list
+func hashInput(addresses []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(addresses, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// openCheckpoint loads the checkpoint for input. An existing checkpoint for
+// the same addresses is reused with resume, discarded with restart and
+// otherwise reported as errCheckpointExists.
+func openCheckpoint(input string, addresses []string, resume, restart bool) (*checkpoint, error) {
+	cp := &checkpoint{
+		InputHash: hashInput(addresses),
+		Completed: map[string]scanner.ReputationReport{},
+		path:      checkpointPath(input),
+	}
+	if restart {
+		return cp, nil
+	}
+
+	data, err := os.ReadFile(cp.path)
+	if os.IsNotExist(err) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var saved checkpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("corrupt checkpoint %s: %w (use --restart)", cp.path, err)
+	}
+	if saved.InputHash != cp.InputHash {
+		// Different input under the same name; start over
+		return cp, nil
+	}
+	if !resume {
+		return nil, fmt.Errorf("%w: %s has %d completed scans (use --resume to continue or --restart to start over)",
+			errCheckpointExists, cp.path, len(saved.Completed))
+	}
+	if saved.Completed != nil {
+		cp.Completed = saved.Completed
+	}
+	return cp, nil
+}
+
+func checkpointKey(address string) string {
+	return strings.ToLower(address)
+}
+
+// done reports whether address was completed in a previous run
+func (cp *checkpoint) done(address string) (scanner.ReputationReport, bool) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	report, ok := cp.Completed[checkpointKey(address)]
+	return report, ok
+}
+
+// record adds a completed report and periodically flushes to disk
+func (cp *checkpoint) record(report scanner.ReputationReport) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if report.Error != "" || report.Incomplete {
+		return // retry failed and timed out addresses on resume
+	}
+	cp.Completed[checkpointKey(report.Address)] = report
+	cp.pending++
+	if cp.pending >= checkpointInterval {
+		cp.saveLocked()
+	}
+}
+
+// flush writes the checkpoint to disk
+func (cp *checkpoint) flush() error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.saveLocked()
+}
+
+func (cp *checkpoint) saveLocked() error {
+	cp.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(cp.path, data); err != nil {
+		return err
+	}
+	cp.pending = 0
+	return nil
+}
+
+// remove deletes the checkpoint once the batch has completed
+func (cp *checkpoint) remove() {
+	os.Remove(cp.path)
+}
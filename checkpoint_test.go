@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"agent-reputation-scanner/scanner"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "watchlist.txt")
+	addresses := []string{
+		"0x1111111111111111111111111111111111111111",
+		"0x2222222222222222222222222222222222222222",
+		"0x3333333333333333333333333333333333333333",
+	}
+	cp, err := openCheckpoint(input, addresses, false, false)
+	if err != nil {
+		t.Fatalf("openCheckpoint() error = %v", err)
+	}
+	cp.record(scanner.ReputationReport{Address: addresses[0], RiskLevel: "low"})
+	// Failed and timed out scans are retried on resume
+	cp.record(scanner.ReputationReport{Address: addresses[1], Error: "rate limited"})
+	cp.record(scanner.ReputationReport{Address: addresses[2], RiskLevel: "medium", Incomplete: true})
+	if err := cp.flush(); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	_, err = openCheckpoint(input, addresses, false, false)
+	if !errors.Is(err, errCheckpointExists) || !strings.Contains(err.Error(), "has 1 completed scans") {
+		t.Errorf("reopening without --resume: error = %v, want errCheckpointExists", err)
+	}
+
+	resumed, err := openCheckpoint(input, addresses, true, false)
+	if err != nil {
+		t.Fatalf("openCheckpoint(resume) error = %v", err)
+	}
+	// Addresses match case-insensitively
+	if report, ok := resumed.done("0x" + strings.ToUpper(addresses[0][2:])); !ok || report.RiskLevel != "low" {
+		t.Errorf("done(%s) = %+v, %v, want the low risk report", addresses[0], report, ok)
+	}
+	for _, address := range addresses[1:] {
+		if _, ok := resumed.done(address); ok {
+			t.Errorf("done(%s) = true for a scan that did not complete", address)
+		}
+	}
+
+	restarted, err := openCheckpoint(input, addresses, true, true)
+	if err != nil {
+		t.Fatalf("openCheckpoint(restart) error = %v", err)
+	}
+	if _, ok := restarted.done(addresses[0]); ok {
+		t.Errorf("--restart kept completed scans")
+	}
+
+	// A checkpoint for another address list is not resumed
+	other, err := openCheckpoint(input, addresses[:2], false, false)
+	if err != nil {
+		t.Fatalf("openCheckpoint(other input) error = %v", err)
+	}
+	if _, ok := other.done(addresses[0]); ok {
+		t.Errorf("checkpoint of another input was resumed")
+	}
+
+	resumed.remove()
+	if _, err := os.Stat(checkpointPath(input)); !os.IsNotExist(err) {
+		t.Errorf("remove() left %s: %v", checkpointPath(input), err)
+	}
+}
+
+func TestCheckpointFlushesPeriodically(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "watchlist.txt")
+	var addresses []string
+	for i := 0; i < checkpointInterval; i++ {
+		addresses = append(addresses, fmt.Sprintf("0x%040x", i+1))
+	}
+	cp, err := openCheckpoint(input, addresses, false, false)
+	if err != nil {
+		t.Fatalf("openCheckpoint() error = %v", err)
+	}
+	for i, address := range addresses {
+		if _, err := os.Stat(checkpointPath(input)); !os.IsNotExist(err) {
+			t.Fatalf("checkpoint written after %d of %d scans", i, checkpointInterval)
+		}
+		cp.record(scanner.ReputationReport{Address: address, RiskLevel: "low"})
+	}
+	resumed, err := openCheckpoint(input, addresses, true, false)
+	if err != nil {
+		t.Fatalf("openCheckpoint(resume) error = %v", err)
+	}
+	if len(resumed.Completed) != checkpointInterval {
+		t.Errorf("checkpoint has %d completed scans, want %d", len(resumed.Completed), checkpointInterval)
+	}
+}
+
+func TestCheckpointCorrupt(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "watchlist.txt")
+	if err := os.WriteFile(checkpointPath(input), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openCheckpoint(input, nil, true, false); err == nil || !strings.Contains(err.Error(), "(use --restart)") {
+		t.Errorf("openCheckpoint(corrupt) error = %v, want a hint to use --restart", err)
+	}
+	if _, err := openCheckpoint(input, nil, true, true); err != nil {
+		t.Errorf("openCheckpoint(corrupt, restart) error = %v", err)
+	}
+}
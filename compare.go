@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// compareAddresses scans addresses on network and writes them ranked by
+// score: a scanner.Comparison in JSON, one verdict line per address in rank
+// order, or a ranking and a check-by-check table of the differences in text
+func compareAddresses(ctx context.Context, s *scanner.Scanner, addresses []string, network string, out outputOptions) scanner.Comparison {
+	if out.format != formatText && out.format != formatJSON && out.format != formatVerdict {
+		fatalf("compare supports --format text or json")
+	}
+	infof("🔍 Comparing %d addresses on %s...", len(addresses), network)
+
+	reports, errs := s.ScanBatchContext(ctx, addresses, network, nil)
+	for _, err := range errs {
+		warnf("%v", err)
+	}
+	if err := firstAuthError(errs); err != nil {
+		fatalf("%v", err)
+	}
+	reportSetupErrors(reports...)
+	for _, report := range reports {
+		if report.Incomplete {
+			warnf("Scan deadline exceeded; some checks timed out (raise --timeout)")
+			break
+		}
+	}
+
+	comparison := scanner.CompareReports(out.redactReports(reports))
+	var buf bytes.Buffer
+	var err error
+	switch out.format {
+	case formatJSON:
+		err = writeJSON(&buf, comparison)
+	case formatVerdict:
+		for _, report := range comparison.Reports {
+			writeVerdict(&buf, report)
+		}
+	default:
+		writeComparison(&buf, comparison)
+	}
+	if err != nil {
+		fatalf("Cannot render comparison: %v", err)
+	}
+	if out.validate {
+		if err := scanner.ValidateComparisonJSON(buf.Bytes()); err != nil {
+			fatalf("Comparison does not match schema: %v", err)
+		}
+	}
+
+	if out.output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return comparison
+	}
+	if err := writeOutput(out.output, buf.Bytes()); err != nil {
+		fatalf("Cannot write comparison: %v", err)
+	}
+	infof("✅ Comparison saved to %s", out.output)
+	return comparison
+}
+
+func writeComparison(w io.Writer, c scanner.Comparison) {
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "  REPUTATION COMPARISON\n")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "Network: %s\n", c.Network)
+	fmt.Fprintf(w, "Time:    %s\n", c.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "RANKING:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	for _, r := range c.Ranking {
+		name := r.Address
+		if r.ENSName != "" {
+			name += " (" + r.ENSName + ")"
+		}
+		if r.Error != "" {
+			fmt.Fprintf(w, "  #%d %s %s error: %s\n", r.Rank, getRiskEmoji(""), name, r.Error)
+			continue
+		}
+		delta := ""
+		if r.Rank > 1 {
+			delta = fmt.Sprintf(" (%+d)", r.ScoreDelta)
+		}
+		fmt.Fprintf(w, "  #%d %s %3d/100 %-8s %s%s\n", r.Rank, getRiskEmoji(r.RiskLevel), r.OverallScore, r.RiskLevel, name, delta)
+	}
+
+	differing := c.Differing()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "DIFFERENCES:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	if len(differing) == 0 {
+		fmt.Fprintf(w, "  ✓ Every check has the same result for all addresses\n")
+		fmt.Fprintln(w, strings.Repeat("═", 60))
+		return
+	}
+
+	header := fmt.Sprintf("  %-25s", "Check")
+	for _, r := range c.Ranking {
+		if r.Error == "" {
+			header += fmt.Sprintf(" %-13s", fmt.Sprintf("#%d", r.Rank))
+		}
+	}
+	fmt.Fprintln(w, strings.TrimRight(header, " "))
+	for _, check := range differing {
+		results := map[string]scanner.CheckResultDelta{}
+		for _, result := range check.Results {
+			results[result.Address] = result
+		}
+		row := fmt.Sprintf("  %-25s", check.Name)
+		for i, r := range c.Ranking {
+			if r.Error != "" {
+				continue
+			}
+			result, ok := results[r.Address]
+			if !ok {
+				row += fmt.Sprintf(" %-13s", "-")
+				continue
+			}
+			cell := fmt.Sprintf("%s %3d", statusWord(result.Status), result.Score)
+			if i > 0 && result.ScoreDelta != 0 {
+				cell += fmt.Sprintf(" %+d", result.ScoreDelta)
+			}
+			row += fmt.Sprintf(" %-13s", cell)
+		}
+		fmt.Fprintln(w, strings.TrimRight(row, " "))
+	}
+	if same := len(c.Checks) - len(differing); same > 0 {
+		fmt.Fprintf(w, "  (%d other checks have the same result for all addresses)\n", same)
+	}
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+}
+
+// uniqueAddresses drops repeated addresses, which differ only in case at
+// most, keeping the first of each
+func uniqueAddresses(addresses []string) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, address := range addresses {
+		if key := strings.ToLower(address); !seen[key] {
+			seen[key] = true
+			unique = append(unique, address)
+		}
+	}
+	return unique
+}
+
+// statusWord shortens a check status to four letters for table cells
+func statusWord(status string) string {
+	if status == "warning" {
+		return "warn"
+	}
+	return status
+}
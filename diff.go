@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// diffOutput is the JSON document written by `scanner diff`; Diff is nil
+// on the first scan of an address
+type diffOutput struct {
+	Report scanner.ReputationReport `json:"report"`
+	Diff   *scanner.ReportDiff      `json:"diff"`
+}
+
+// diffAddress scans address, compares the report with the last one in the
+// history and appends the new report to the history
+func diffAddress(ctx context.Context, s *scanner.Scanner, address, network string, out outputOptions) scanner.ReputationReport {
+	if out.format != formatText && out.format != formatJSON && out.format != formatVerdict {
+		fatalf("diff supports --format text or json")
+	}
+	infof("🔍 Scanning %s on %s...", address, network)
+
+	report, err := s.ScanContext(ctx, address, network)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if report.Incomplete {
+		warnf("Scan deadline exceeded; some checks timed out (raise --timeout)")
+	}
+
+	dir := scanner.DefaultHistoryDir()
+	history, err := scanner.LoadHistory(dir, report.Address, network)
+	if err != nil {
+		fatalf("Cannot read history: %v", err)
+	}
+	// Incomplete reports would show timed out checks as changes
+	if report.Incomplete {
+		warnf("Report not added to history since it is incomplete")
+	} else if err := scanner.AppendHistory(dir, report); err != nil {
+		fatalf("Cannot write history: %v", err)
+	}
+
+	result := diffOutput{Report: report}
+	if len(history) > 0 {
+		diff := scanner.DiffReports(history[len(history)-1], report)
+		result.Diff = &diff
+	}
+
+	result = out.redactDiff(result)
+	var buf bytes.Buffer
+	switch out.format {
+	case formatJSON:
+		err = writeJSON(&buf, result)
+	case formatVerdict:
+		writeVerdict(&buf, result.Report)
+	default:
+		writeChangelog(&buf, result, len(history))
+	}
+	if err != nil {
+		fatalf("Cannot render diff: %v", err)
+	}
+
+	if out.output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return report
+	}
+	if err := writeOutput(out.output, buf.Bytes()); err != nil {
+		fatalf("Cannot write diff: %v", err)
+	}
+	infof("✅ Diff saved to %s", out.output)
+	return report
+}
+
+// writeChangelog prints what changed since the previous scan; scans is the
+// number of earlier reports in the history
+func writeChangelog(w io.Writer, result diffOutput, scans int) {
+	report, diff := result.Report, result.Diff
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "  REPUTATION CHANGES\n")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "Address: %s\n", report.Address)
+	if report.ENSName != "" {
+		fmt.Fprintf(w, "ENS:     %s\n", report.ENSName)
+	}
+	fmt.Fprintf(w, "Network: %s\n", report.Network)
+	fmt.Fprintf(w, "Time:    %s\n", report.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(w)
+
+	if diff == nil {
+		fmt.Fprintf(w, "First scan: %d/100, %s %s\n", report.OverallScore, getRiskEmoji(report.RiskLevel), strings.ToUpper(report.RiskLevel))
+		fmt.Fprintln(w, "Run diff again later to see what changed.")
+		return
+	}
+
+	fmt.Fprintf(w, "Since:         %s (%s ago, scan #%d)\n",
+		diff.Previous.Format("2006-01-02 15:04:05"), formatAge(diff.Current.Sub(diff.Previous)), scans+1)
+	fmt.Fprintf(w, "Overall Score: %d → %d (%+d)\n", diff.ScoreBefore, diff.ScoreAfter, diff.ScoreDelta)
+	fmt.Fprintf(w, "Risk Level:    %s %s → %s %s\n", getRiskEmoji(diff.RiskBefore), strings.ToUpper(diff.RiskBefore),
+		getRiskEmoji(diff.RiskAfter), strings.ToUpper(diff.RiskAfter))
+
+	if diff.Unchanged() {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "No changes since the last scan")
+		return
+	}
+	writeChanges(w, "🚨 NEW LIST HITS:", diff.ListHits)
+	writeChanges(w, "✗ NEWLY FAILING:", diff.NewlyFailing)
+	writeChanges(w, "⚠️  CHANGED:", diff.Changed)
+	writeChanges(w, "✓ RECOVERED:", diff.Recovered)
+}
+
+func writeChanges(w io.Writer, title string, changes []scanner.CheckChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	for _, c := range changes {
+		before := c.Before
+		if before == "" {
+			before = "new"
+		}
+		fmt.Fprintf(w, "  %-25s %s → %s [%d%% → %d%%]\n", c.Name, before, c.After, c.ScoreBefore, c.ScoreAfter)
+		fmt.Fprintf(w, "     └─ %s\n", c.Details)
+	}
+}
+
+// formatAge renders a duration as whole days, hours or minutes
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	case d >= 2*time.Hour:
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	case d >= 2*time.Minute:
+		return fmt.Sprintf("%d minutes", int(d.Minutes()))
+	}
+	return "moments"
+}
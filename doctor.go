@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// runDoctor probes the RPC endpoint and explorer of each network (all of
+// them when none are named) and prints what works. It returns exitError if
+// any endpoint is unreachable or rejects its credentials.
+func runDoctor(s *scanner.Scanner, networks []string, format string) int {
+	if len(networks) == 0 {
+		networks = s.NetworkNames()
+	}
+	for i, network := range networks {
+		networks[i] = strings.ToLower(network)
+		if _, err := s.Network(networks[i]); err != nil {
+			fatalf("%v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultScanTimeout)
+	defer cancel()
+	results := make([]scanner.NetworkDiagnosis, len(networks))
+	var wg sync.WaitGroup
+	for i, network := range networks {
+		wg.Add(1)
+		go func(i int, network string) {
+			defer wg.Done()
+			results[i], _ = s.Diagnose(ctx, network)
+		}(i, network)
+	}
+	wg.Wait()
+
+	var err error
+	switch format {
+	case formatText:
+		writeDiagnoses(os.Stdout, results)
+	case formatJSON:
+		err = writeJSON(os.Stdout, results)
+	default:
+		fatalf("doctor supports --format text or json")
+	}
+	if err != nil {
+		fatalf("Cannot render results: %v", err)
+	}
+
+	for _, d := range results {
+		if !d.OK() {
+			return exitError
+		}
+	}
+	return exitOK
+}
+
+func writeDiagnoses(w io.Writer, results []scanner.NetworkDiagnosis) {
+	broken := 0
+	for _, d := range results {
+		icon := "✅"
+		if !d.OK() {
+			icon = "❌"
+			broken++
+		}
+		fmt.Fprintf(w, "%s %s\n", icon, d.Network)
+		fmt.Fprintf(w, "   RPC:      %s (%s)\n", formatService(d.RPC), d.RPCURL)
+		fmt.Fprintf(w, "   Explorer: %s\n", formatService(d.Explorer))
+	}
+	fmt.Fprintln(w)
+	if broken == 0 {
+		fmt.Fprintf(w, "All %d networks reachable\n", len(results))
+		return
+	}
+	fmt.Fprintf(w, "%d of %d networks have problems\n", broken, len(results))
+}
+
+func formatService(status scanner.ServiceStatus) string {
+	icon := map[string]string{
+		scanner.ServiceOK:            "✓",
+		scanner.ServiceNotConfigured: "–",
+		scanner.ServiceAuthFailed:    "✗",
+		scanner.ServiceError:         "✗",
+	}[status.Status]
+	if status.LatencyMS > 0 {
+		return fmt.Sprintf("%s %s, %dms", icon, status.Details, status.LatencyMS)
+	}
+	return fmt.Sprintf("%s %s", icon, status.Details)
+}
+
+// reportSetupErrors prints each distinct setup error of the reports once,
+// so a rejected key is not mistaken for a merely degraded scan
+func reportSetupErrors(reports ...scanner.ReputationReport) {
+	seen := map[string]bool{}
+	for _, report := range reports {
+		for _, setupErr := range report.SetupErrors {
+			if !seen[setupErr] {
+				seen[setupErr] = true
+				errorf("%s", setupErr)
+			}
+		}
+	}
+	if len(seen) > 0 {
+		errorf("Checks that needed these credentials are incomplete; run `scanner doctor` to test the setup")
+	}
+}
+
+// firstAuthError returns the first scan error caused by rejected
+// credentials, which --strict-auth turns into an abort
+func firstAuthError(errs []error) error {
+	for _, err := range errs {
+		if errors.Is(err, scanner.ErrAuth) {
+			return err
+		}
+	}
+	return nil
+}
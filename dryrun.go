@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// runDryRun prints what scanning addresses addresses on each network would
+// run and cost, without making any requests
+func runDryRun(s *scanner.Scanner, addresses int, networks []string, format string) {
+	plans := make([]scanner.ScanPlan, len(networks))
+	for i, network := range networks {
+		plans[i] = s.Plan(addresses, network)
+	}
+
+	var err error
+	switch format {
+	case formatText:
+		writePlans(os.Stdout, plans)
+	case formatJSON:
+		if len(plans) == 1 {
+			err = writeJSON(os.Stdout, plans[0])
+		} else {
+			err = writeJSON(os.Stdout, plans)
+		}
+	default:
+		fatalf("--dry-run supports --format text or json")
+	}
+	if err != nil {
+		fatalf("Cannot render plan: %v", err)
+	}
+}
+
+func writePlans(w io.Writer, plans []scanner.ScanPlan) {
+	for _, plan := range plans {
+		addresses := fmt.Sprintf("%d addresses", plan.Addresses)
+		if plan.Addresses == 1 {
+			addresses = "1 address"
+		}
+		fmt.Fprintf(w, "Dry run: %d checks on %s on %s\n\n", len(plan.Checks), addresses, plan.Network)
+		fmt.Fprintf(w, "  %-24s %5s %9s %6s\n", "CHECK", "RPC", "EXPLORER", "OTHER")
+		unknown := 0
+		for _, check := range plan.Checks {
+			if check.Unknown {
+				fmt.Fprintf(w, "  %-24s %5s %9s %6s\n", check.Name, "?", "?", "?")
+				unknown++
+				continue
+			}
+			fmt.Fprintf(w, "  %-24s %5d %9d %6d\n", check.Name, check.RPC, check.Explorer, check.Other)
+		}
+		fmt.Fprintf(w, "  %-24s %5d %9d %6d\n", "Per address", plan.PerAddress.RPC, plan.PerAddress.Explorer, plan.PerAddress.Other)
+		fmt.Fprintf(w, "  %-24s %5d %9d %6d\n\n", "Total", plan.Total.RPC, plan.Total.Explorer, plan.Total.Other)
+		fmt.Fprintf(w, "Estimated time: %s (%d workers, explorer limit %g req/s)\n", formatEstimate(plan.EstimatedTime), plan.Concurrency, plan.RequestsPerSecond)
+		var notes []string
+		if plan.NoExplorer {
+			notes = append(notes, "no explorer API key: explorer-backed checks will fall back without requests")
+		}
+		if unknown > 0 {
+			notes = append(notes, fmt.Sprintf("%d registered checks of unknown cost not counted", unknown))
+		}
+		notes = append(notes, "counts are typical per-check estimates; cached results need fewer requests")
+		for _, note := range notes {
+			fmt.Fprintf(w, "  • %s\n", note)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "No requests were made.")
+}
+
+// formatEstimate rounds an estimate to a readable precision
+func formatEstimate(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
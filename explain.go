@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// explainReports writes the --explain breakdown of reports: after the
+// report in text output, and to stderr for other formats so that
+// structured output stays parsable
+func (o outputOptions) explainReports(buf *bytes.Buffer, s *scanner.Scanner, reports ...scanner.ReputationReport) {
+	if !o.explain {
+		return
+	}
+	var w io.Writer = os.Stderr
+	if o.format == formatText {
+		w = buf
+	}
+	for _, report := range reports {
+		e := s.Explain(report)
+		if o.redact != nil {
+			e.Notes = o.redactTexts(e.Notes)
+			report = o.redactReport(report)
+		}
+		writeExplanation(w, report.Address, e)
+	}
+}
+
+func writeExplanation(w io.Writer, address string, e scanner.ScoreExplanation) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "SCORE EXPLANATION: %s\n", address)
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	if len(e.Contributions) > 0 {
+		fmt.Fprintf(w, "  %-25s %5s %7s %13s\n", "CHECK", "SCORE", "WEIGHT", "CONTRIBUTION")
+		for _, c := range e.Contributions {
+			fmt.Fprintf(w, "  %-25s %5d %7.2f %13.1f\n", c.Name, c.Score, c.Weight, c.Contribution)
+		}
+		if e.EntityBonus > 0 {
+			fmt.Fprintf(w, "  %-25s %5s %7s %13s\n", "Known entity", "", "", fmt.Sprintf("+%d", e.EntityBonus))
+		}
+		fmt.Fprintf(w, "  %-25s %5s %7.2f %13d\n", "Overall", "", e.TotalWeight, e.Score)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  Each contribution is score × weight ÷ total weight; they add up to the")
+		fmt.Fprintln(w, "  overall score before rounding.")
+	}
+	if len(e.Drags) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "  Points lost, most first:")
+		for _, c := range e.Drags {
+			fmt.Fprintf(w, "    -%.1f  %s (scored %d)\n", c.Lost, c.Name, c.Score)
+		}
+	}
+	if len(e.Notes) > 0 {
+		fmt.Fprintln(w)
+	}
+	for _, note := range e.Notes {
+		fmt.Fprintf(w, "  • %s\n", note)
+	}
+}
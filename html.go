@@ -0,0 +1,55 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"time"
+
+	"agent-reputation-scanner/scanner"
+)
+
+//go:embed report.html
+var htmlTemplateSource string
+
+// Length of the gauge's semicircular arc (radius 80)
+var gaugeLength = math.Pi * 80
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	// gaugeDash fills the share of the arc matching score
+	"gaugeDash": func(score int) string {
+		return fmt.Sprintf("%.1f %.1f", gaugeLength*float64(score)/100, gaugeLength)
+	},
+}).Parse(htmlTemplateSource))
+
+// htmlPage is the data of the HTML template; Summary is nil for a single
+// report
+type htmlPage struct {
+	Title      string
+	Generated  string
+	Version    string
+	RiskLevels []string // most severe first
+	Summary    *batchSummary
+	Reports    []scanner.ReputationReport
+	Invalid    []invalidLine
+}
+
+// writeHTML renders a self-contained HTML page. html/template escapes every
+// value, so attacker-controlled details cannot inject markup.
+func writeHTML(w io.Writer, title string, summary *batchSummary, reports []scanner.ReputationReport, invalid []invalidLine) error {
+	levels := make([]string, len(scanner.RiskLevels))
+	for i, level := range scanner.RiskLevels {
+		levels[len(levels)-1-i] = level
+	}
+	return htmlTemplate.Execute(w, htmlPage{
+		Title:      title,
+		Generated:  time.Now().Format("2006-01-02 15:04:05 MST"),
+		Version:    version,
+		RiskLevels: levels,
+		Summary:    summary,
+		Reports:    reports,
+		Invalid:    invalid,
+	})
+}
@@ -0,0 +1,184 @@
+// Package jsonschema validates JSON documents against the subset of JSON
+// Schema used by the scanner's report schema: type, enum, required,
+// properties, additionalProperties, items, minimum, maximum and local
+// $ref pointers into $defs.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Schema is a parsed JSON Schema document
+type Schema struct {
+	root map[string]interface{}
+}
+
+// Parse decodes a JSON Schema document
+func Parse(data []byte) (*Schema, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return &Schema{root: root}, nil
+}
+
+// Validate checks data against the schema root
+func (s *Schema) Validate(data []byte) error {
+	return s.ValidateRef("#", data)
+}
+
+// ValidateRef checks data against the subschema at ref, e.g. "#/$defs/Item"
+func (s *Schema) ValidateRef(ref string, data []byte) error {
+	var doc interface{}
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	sub, err := s.resolve(ref)
+	if err != nil {
+		return err
+	}
+	return s.validate(sub, doc, "$")
+}
+
+func (s *Schema) resolve(ref string) (map[string]interface{}, error) {
+	if ref == "#" {
+		return s.root, nil
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	var node interface{} = s.root
+	for _, part := range strings.Split(ref[2:], "/") {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		node = obj[part]
+	}
+	sub, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %q", ref)
+	}
+	return sub, nil
+}
+
+func (s *Schema) validate(schema map[string]interface{}, doc interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		sub, err := s.resolve(ref)
+		if err != nil {
+			return err
+		}
+		return s.validate(sub, doc, path)
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, doc) {
+		return fmt.Errorf("%s: expected %v, got %s", path, t, typeOf(doc))
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(enum, doc) {
+		return fmt.Errorf("%s: value %v not in enum %v", path, doc, enum)
+	}
+
+	switch v := doc.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		if min, ok := schema["minimum"].(float64); ok && f < min {
+			return fmt.Errorf("%s: %v is below minimum %v", path, v, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && f > max {
+			return fmt.Errorf("%s: %v is above maximum %v", path, v, max)
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				if err := s.validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]interface{}:
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[name.(string)]; !ok {
+					return fmt.Errorf("%s: missing required property %q", path, name)
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := props[name].(map[string]interface{})
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Errorf("%s: unexpected property %q", path, name)
+				}
+				continue
+			}
+			if err := s.validate(prop, v[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func matchesType(t interface{}, doc interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		return typeMatches(t, doc)
+	case []interface{}:
+		for _, alt := range t {
+			if name, ok := alt.(string); ok && typeMatches(name, doc) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeMatches(name string, doc interface{}) bool {
+	actual := typeOf(doc)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+	return name == actual
+}
+
+func typeOf(doc interface{}) string {
+	switch v := doc.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) && !strings.ContainsAny(v.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", doc)
+}
+
+func inEnum(enum []interface{}, doc interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(doc) && typeOf(doc) != "object" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+// Package term puts a terminal into raw mode and reports its size, which is
+// all the interactive TUI needs from the platform.
+package term
+
+import "errors"
+
+// ErrUnsupported is returned on platforms without terminal support
+var ErrUnsupported = errors.New("interactive terminal not supported on this platform")
+
+// IsTerminal reports whether fd refers to a terminal
+func IsTerminal(fd int) bool {
+	_, err := getState(fd)
+	return err == nil
+}
+
+// MakeRaw switches fd to raw mode: no echo, no line buffering and no signal
+// keys. The returned function restores the previous mode.
+func MakeRaw(fd int) (restore func() error, err error) {
+	old, err := getState(fd)
+	if err != nil {
+		return nil, err
+	}
+	if err := setState(fd, makeRaw(*old)); err != nil {
+		return nil, err
+	}
+	return func() error { return setState(fd, old) }, nil
+}
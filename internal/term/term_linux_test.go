@@ -0,0 +1,101 @@
+package term
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY returns the controller and the terminal end of a new
+// pseudo-terminal
+func openPTY(t *testing.T) (controller, tty *os.File) {
+	t.Helper()
+	controller, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminals: %v", err)
+	}
+	t.Cleanup(func() { controller.Close() })
+	fd := int(controller.Fd())
+	if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatalf("unlock pty: %v", err)
+	}
+	n, err := unix.IoctlGetInt(fd, unix.TIOCGPTN)
+	if err != nil {
+		t.Fatalf("pty number: %v", err)
+	}
+	tty, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatalf("open pty: %v", err)
+	}
+	t.Cleanup(func() { tty.Close() })
+	return controller, tty
+}
+
+func TestIsTerminal(t *testing.T) {
+	_, tty := openPTY(t)
+	if !IsTerminal(int(tty.Fd())) {
+		t.Error("IsTerminal(pty) = false")
+	}
+	file, err := os.CreateTemp(t.TempDir(), "file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if IsTerminal(int(file.Fd())) {
+		t.Error("IsTerminal(regular file) = true")
+	}
+	if _, err := MakeRaw(int(file.Fd())); err == nil {
+		t.Error("MakeRaw(regular file) succeeded")
+	}
+}
+
+func TestMakeRawRestores(t *testing.T) {
+	_, tty := openPTY(t)
+	fd := int(tty.Fd())
+	before, err := getState(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Lflag&(unix.ECHO|unix.ICANON) == 0 {
+		t.Fatal("new pty is not in cooked mode")
+	}
+
+	restore, err := MakeRaw(fd)
+	if err != nil {
+		t.Fatalf("MakeRaw() error = %v", err)
+	}
+	raw, err := getState(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw.Lflag&(unix.ECHO|unix.ICANON|unix.ISIG|unix.IEXTEN) != 0 || raw.Iflag&(unix.ICRNL|unix.IXON) != 0 || raw.Oflag&unix.OPOST != 0 {
+		t.Errorf("raw mode kept echo, line, signal or output processing: %+v", raw)
+	}
+	if raw.Cflag&unix.CSIZE != unix.CS8 || raw.Cc[unix.VMIN] != 1 || raw.Cc[unix.VTIME] != 0 {
+		t.Errorf("raw mode does not read 8-bit bytes one at a time: %+v", raw)
+	}
+
+	if err := restore(); err != nil {
+		t.Fatalf("restore() error = %v", err)
+	}
+	after, err := getState(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *after != *before {
+		t.Errorf("restore() left %+v, want %+v", after, before)
+	}
+}
+
+func TestSize(t *testing.T) {
+	controller, tty := openPTY(t)
+	if err := unix.IoctlSetWinsize(int(controller.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Col: 132, Row: 43}); err != nil {
+		t.Fatal(err)
+	}
+	width, height, err := Size(int(tty.Fd()))
+	if err != nil || width != 132 || height != 43 {
+		t.Errorf("Size() = %d, %d, %v, want 132, 43", width, height, err)
+	}
+}
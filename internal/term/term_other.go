@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package term
+
+type state struct{}
+
+func getState(int) (*state, error) { return nil, ErrUnsupported }
+
+func setState(int, *state) error { return ErrUnsupported }
+
+func makeRaw(s state) *state { return &s }
+
+// Size returns the width and height of the terminal
+func Size(int) (width, height int, err error) { return 0, 0, ErrUnsupported }
@@ -0,0 +1,436 @@
+// Package websocket is a minimal RFC 6455 client: enough to hold a JSON-RPC
+// subscription open, with text messages, ping/pong and close.
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxMessageSize bounds the messages ReadMessage accepts
+const MaxMessageSize = 16 << 20
+
+// acceptGUID is appended to the handshake key to form the accept header
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Frame opcodes
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// ErrClosed is returned by ReadMessage once the server closed the
+// connection with a close frame
+var ErrClosed = errors.New("websocket closed by server")
+
+// Conn is a client connection. ReadMessage must not be called
+// concurrently; writes may come from any goroutine.
+type Conn struct {
+	conn    net.Conn
+	br      *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// Options configure how Dial reaches the server
+type Options struct {
+	// Proxy returns the proxy for a request, as http.Transport.Proxy does;
+	// it sees the http or https URL matching the ws or wss one. nil, or a
+	// nil proxy URL, connects directly. http and https proxies are
+	// tunnelled through with CONNECT, socks5 proxies with SOCKS5 CONNECT.
+	Proxy func(*http.Request) (*url.URL, error)
+	// TLSConfig configures wss connections and https proxies, e.g. with
+	// extra RootCAs; ServerName is set for each host
+	TLSConfig *tls.Config
+}
+
+// Dial opens a ws:// or wss:// connection, sending header with the
+// handshake request. opts may be nil.
+func Dial(ctx context.Context, rawURL string, header http.Header, opts *Options) (*Conn, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	port, httpScheme := "", ""
+	switch u.Scheme {
+	case "ws":
+		port, httpScheme = "80", "http"
+	case "wss":
+		port, httpScheme = "443", "https"
+	default:
+		return nil, fmt.Errorf("unsupported websocket scheme %q (use ws or wss)", u.Scheme)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	var proxy *url.URL
+	if opts.Proxy != nil {
+		target := *u
+		target.Scheme = httpScheme
+		if proxy, err = opts.Proxy(&http.Request{Method: http.MethodGet, URL: &target, Host: u.Host}); err != nil {
+			return nil, err
+		}
+	}
+	conn, err := dialVia(ctx, proxy, addr, opts.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "wss" {
+		tlsConn := tls.Client(conn, tlsConfig(opts.TLSConfig, u.Hostname()))
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+	req := &http.Request{Method: http.MethodGet, URL: u, Host: u.Host, Header: http.Header{}}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake failed: HTTP %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, errors.New("websocket handshake failed: bad Sec-WebSocket-Accept")
+	}
+	conn.SetDeadline(time.Time{})
+	return &Conn{conn: conn, br: br}, nil
+}
+
+// tlsConfig is base, or the defaults, for a connection to host
+func tlsConfig(base *tls.Config, host string) *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if base != nil {
+		config = base.Clone()
+	}
+	config.ServerName = host
+	return config
+}
+
+// dialVia opens a TCP connection to addr, through proxy when it is set.
+// The connection keeps the deadline of ctx until the handshake is done.
+func dialVia(ctx context.Context, proxy *url.URL, addr string, tlsBase *tls.Config) (net.Conn, error) {
+	target := addr
+	if proxy != nil {
+		target = proxy.Host
+		if proxy.Port() == "" {
+			port := map[string]string{"http": "80", "https": "443", "socks5": "1080"}[proxy.Scheme]
+			target = net.JoinHostPort(proxy.Hostname(), port)
+		}
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", target)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if proxy == nil {
+		return conn, nil
+	}
+
+	switch proxy.Scheme {
+	case "https":
+		tlsConn := tls.Client(conn, tlsConfig(tlsBase, proxy.Hostname()))
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy %s: %w", proxy.Redacted(), err)
+		}
+		conn = tlsConn
+		fallthrough
+	case "http":
+		err = connectTunnel(conn, proxy, addr)
+	case "socks5":
+		err = socks5Connect(conn, proxy, addr)
+	default:
+		err = fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", proxy.Scheme)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// connectTunnel asks an HTTP proxy on conn to open a tunnel to addr
+func connectTunnel(conn net.Conn, proxy *url.URL, addr string) error {
+	req := &http.Request{Method: http.MethodConnect, URL: &url.URL{Opaque: addr}, Host: addr, Header: http.Header{}}
+	if proxy.User != nil {
+		password, _ := proxy.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return err
+	}
+	// The server says nothing before the handshake, so nothing is left
+	// buffered past the proxy's response
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("proxy %s: %w", proxy.Redacted(), err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy %s refused the tunnel to %s: HTTP %d", proxy.Redacted(), addr, resp.StatusCode)
+	}
+	return nil
+}
+
+// socks5Connect asks a SOCKS5 proxy on conn (RFC 1928) to connect to addr,
+// authenticating with the proxy URL's user and password (RFC 1929) if any
+func socks5Connect(conn net.Conn, proxy *url.URL, addr string) error {
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("proxy %s: "+format, append([]interface{}{proxy.Redacted()}, args...)...)
+	}
+	host, portText, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	port, err := strconv.ParseUint(portText, 10, 16)
+	if err != nil || len(host) > 255 {
+		return fail("cannot connect to %s", addr)
+	}
+
+	method := byte(0x00) // no authentication
+	if proxy.User != nil {
+		method = 0x02 // username and password
+	}
+	if _, err := conn.Write([]byte{0x05, 1, method}); err != nil {
+		return err
+	}
+	var reply [2]byte
+	if _, err := io.ReadFull(conn, reply[:]); err != nil {
+		return fail("%v", err)
+	}
+	if reply[0] != 0x05 || reply[1] != method {
+		return fail("no acceptable SOCKS5 authentication method")
+	}
+	if method == 0x02 {
+		user := proxy.User.Username()
+		password, _ := proxy.User.Password()
+		if len(user) > 255 || len(password) > 255 {
+			return fail("SOCKS5 credentials longer than 255 bytes")
+		}
+		auth := append([]byte{0x01, byte(len(user))}, user...)
+		auth = append(append(auth, byte(len(password))), password...)
+		if _, err := conn.Write(auth); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(conn, reply[:]); err != nil {
+			return fail("%v", err)
+		}
+		if reply[1] != 0x00 {
+			return fail("SOCKS5 authentication failed")
+		}
+	}
+
+	request := append([]byte{0x05, 0x01, 0x00, 0x03, byte(len(host))}, host...)
+	request = binary.BigEndian.AppendUint16(request, uint16(port))
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+	var head [4]byte
+	if _, err := io.ReadFull(conn, head[:]); err != nil {
+		return fail("%v", err)
+	}
+	if head[1] != 0x00 {
+		return fail("SOCKS5 connect to %s failed with code %d", addr, head[1])
+	}
+	// Skip the bound address and port
+	var skip int
+	switch head[3] {
+	case 0x01:
+		skip = net.IPv4len
+	case 0x04:
+		skip = net.IPv6len
+	case 0x03:
+		var n [1]byte
+		if _, err := io.ReadFull(conn, n[:]); err != nil {
+			return fail("%v", err)
+		}
+		skip = int(n[0])
+	default:
+		return fail("unknown SOCKS5 address type %d", head[3])
+	}
+	if _, err := io.ReadFull(conn, make([]byte, skip+2)); err != nil {
+		return fail("%v", err)
+	}
+	return nil
+}
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// SetReadDeadline bounds the wait of ReadMessage
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// ReadMessage returns the next text or binary message, answering pings on
+// the way
+func (c *Conn) ReadMessage() ([]byte, error) {
+	var message []byte
+	started := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.writeFrame(opClose, payload)
+			c.conn.Close()
+			return nil, ErrClosed
+		case opText, opBinary:
+			if started {
+				return nil, errors.New("websocket: new message inside a fragmented one")
+			}
+			started = true
+		case opContinuation:
+			if !started {
+				return nil, errors.New("websocket: continuation without a message")
+			}
+		default:
+			return nil, fmt.Errorf("websocket: unknown opcode %d", opcode)
+		}
+		if len(message)+len(payload) > MaxMessageSize {
+			return nil, fmt.Errorf("websocket: message larger than %d bytes", MaxMessageSize)
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (c *Conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.br, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0f
+	if head[1]&0x80 != 0 {
+		return false, 0, nil, errors.New("websocket: server sent a masked frame")
+	}
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > MaxMessageSize {
+		return false, 0, nil, fmt.Errorf("websocket: frame larger than %d bytes", MaxMessageSize)
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends data as one text message
+func (c *Conn) WriteMessage(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// writeFrame sends a single masked frame, as clients must
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	frame := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// Close sends a normal closure frame and closes the connection
+func (c *Conn) Close() error {
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.writeFrame(opClose, []byte{0x03, 0xe8}) // 1000: normal closure
+	return c.conn.Close()
+}
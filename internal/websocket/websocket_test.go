@@ -0,0 +1,531 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// serve answers the websocket handshake and hands the raw connection to
+// handle
+func serve(t *testing.T, handle func(conn net.Conn, rw *bufio.ReadWriter)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+			http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		if err := rw.Flush(); err != nil {
+			t.Error(err)
+			return
+		}
+		handle(conn, rw)
+	})
+}
+
+// echo sends every text frame it reads back to the client
+func echo(t *testing.T) http.Handler {
+	return serve(t, func(conn net.Conn, rw *bufio.ReadWriter) {
+		for {
+			opcode, payload, masked, err := readClientFrame(rw.Reader)
+			if err != nil || opcode == opClose {
+				return
+			}
+			if !masked {
+				t.Error("client sent an unmasked frame")
+			}
+			writeServerFrame(conn, true, opcode, payload)
+		}
+	})
+}
+
+// readClientFrame reads one frame and unmasks its payload
+func readClientFrame(r io.Reader) (opcode byte, payload []byte, masked bool, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, false, err
+	}
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, false, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return 0, nil, false, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	masked = head[1]&0x80 != 0
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return 0, nil, false, err
+		}
+	}
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, false, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return head[0] & 0x0f, payload, masked, nil
+}
+
+// writeServerFrame writes an unmasked frame, as servers must
+func writeServerFrame(w io.Writer, fin bool, opcode byte, payload []byte) error {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, byte(n))
+	case n <= 0xffff:
+		frame = binary.BigEndian.AppendUint16(append(frame, 126), uint16(n))
+	default:
+		frame = binary.BigEndian.AppendUint64(append(frame, 127), uint64(n))
+	}
+	_, err := w.Write(append(frame, payload...))
+	return err
+}
+
+// connectProxy tunnels CONNECT requests, recording the target and the
+// Proxy-Authorization header of each
+type connectProxy struct {
+	mu      sync.Mutex
+	targets []string
+	auth    []string
+}
+
+func (p *connectProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "CONNECT only", http.StatusMethodNotAllowed)
+		return
+	}
+	p.mu.Lock()
+	p.targets = append(p.targets, r.Host)
+	p.auth = append(p.auth, r.Header.Get("Proxy-Authorization"))
+	p.mu.Unlock()
+	upstream, err := net.Dial("tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer upstream.Close()
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	rw.WriteString("HTTP/1.1 200 Connection established\r\n\r\n")
+	rw.Flush()
+	go io.Copy(upstream, rw)
+	io.Copy(conn, upstream)
+}
+
+func dialContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// roundTrip sends a message and expects it echoed back
+func roundTrip(t *testing.T, conn *Conn, message string) {
+	t.Helper()
+	if err := conn.WriteMessage([]byte(message)); err != nil {
+		t.Fatal(err)
+	}
+	got, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != message {
+		t.Errorf("echo = %q, want %q", got, message)
+	}
+}
+
+func wsURL(server *httptest.Server) string {
+	return strings.Replace(server.URL, "http", "ws", 1)
+}
+
+func TestDialRootCAs(t *testing.T) {
+	server := httptest.NewUnstartedServer(echo(t))
+	// The first dial is meant to fail the TLS handshake
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	if _, err := Dial(dialContext(t), wsURL(server), nil, nil); err == nil {
+		t.Fatal("dial trusted the test certificate without RootCAs")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	transport := server.Client().Transport.(*http.Transport)
+	transport.TLSClientConfig.RootCAs = pool
+	conn, err := Dial(dialContext(t), wsURL(server), nil, &Options{TLSConfig: transport.TLSClientConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	roundTrip(t, conn, "hello")
+}
+
+func TestDialConnectProxy(t *testing.T) {
+	server := httptest.NewTLSServer(echo(t))
+	defer server.Close()
+	tunnel := &connectProxy{}
+	proxy := httptest.NewServer(tunnel)
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	proxyURL.User = url.UserPassword("scanner", "s3cret")
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	var asked *url.URL
+	opts := &Options{
+		Proxy: func(r *http.Request) (*url.URL, error) {
+			asked = r.URL
+			return proxyURL, nil
+		},
+		TLSConfig: server.Client().Transport.(*http.Transport).TLSClientConfig.Clone(),
+	}
+	opts.TLSConfig.RootCAs = pool
+
+	conn, err := Dial(dialContext(t), wsURL(server)+"/ws", nil, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	roundTrip(t, conn, "through the tunnel")
+
+	if asked == nil || asked.Scheme != "https" || asked.Path != "/ws" {
+		t.Errorf("Proxy asked about %v, want the https URL", asked)
+	}
+	host := strings.TrimPrefix(server.URL, "https://")
+	wantAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("scanner:s3cret"))
+	tunnel.mu.Lock()
+	defer tunnel.mu.Unlock()
+	if len(tunnel.targets) != 1 || tunnel.targets[0] != host || tunnel.auth[0] != wantAuth {
+		t.Errorf("proxy saw CONNECT %v with auth %v, want %s with %s", tunnel.targets, tunnel.auth, host, wantAuth)
+	}
+}
+
+func TestDialProxyRefused(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusProxyAuthRequired)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	_, err := Dial(dialContext(t), "ws://node.invalid/ws", nil, &Options{Proxy: http.ProxyURL(proxyURL)})
+	if err == nil || !strings.Contains(err.Error(), "HTTP 407") {
+		t.Errorf("err = %v, want the proxy's 407", err)
+	}
+}
+
+func TestDialSOCKS5Proxy(t *testing.T) {
+	server := httptest.NewServer(echo(t))
+	defer server.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	seen := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		br := bufio.NewReader(conn)
+		read := func(n int) []byte {
+			buf := make([]byte, n)
+			io.ReadFull(br, buf)
+			return buf
+		}
+		greeting := read(2)
+		methods := read(int(greeting[1]))
+		if greeting[0] != 5 || methods[0] != 2 {
+			conn.Write([]byte{5, 0xff})
+			return
+		}
+		conn.Write([]byte{5, 2})
+		user := string(read(int(read(2)[1])))
+		password := string(read(int(read(1)[0])))
+		credentials := user + ":" + password
+		conn.Write([]byte{1, 0})
+		request := read(5)
+		host := string(read(int(request[4])))
+		port := binary.BigEndian.Uint16(read(2))
+		target := net.JoinHostPort(host, strconv.Itoa(int(port)))
+		seen <- target + " as " + credentials
+		upstream, err := net.Dial("tcp", target)
+		if err != nil {
+			conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		defer upstream.Close()
+		conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+		go io.Copy(upstream, br)
+		io.Copy(conn, upstream)
+	}()
+
+	proxyURL := &url.URL{Scheme: "socks5", Host: listener.Addr().String(), User: url.UserPassword("scanner", "s3cret")}
+	conn, err := Dial(dialContext(t), wsURL(server), nil, &Options{Proxy: http.ProxyURL(proxyURL)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	roundTrip(t, conn, "through socks")
+	if got, want := <-seen, strings.TrimPrefix(server.URL, "http://")+" as scanner:s3cret"; got != want {
+		t.Errorf("SOCKS5 proxy saw %s, want %s", got, want)
+	}
+}
+
+func TestDialUnsupportedProxy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	proxyURL := &url.URL{Scheme: "ftp", Host: listener.Addr().String()}
+	_, err = Dial(dialContext(t), "ws://node.invalid", nil, &Options{Proxy: http.ProxyURL(proxyURL)})
+	if err == nil || !strings.Contains(err.Error(), "unsupported proxy scheme") {
+		t.Errorf("err = %v, want unsupported proxy scheme", err)
+	}
+}
+
+// dialScript dials a server that runs script after the handshake
+func dialScript(t *testing.T, script func(conn net.Conn, r *bufio.Reader)) *Conn {
+	t.Helper()
+	server := httptest.NewServer(serve(t, func(conn net.Conn, rw *bufio.ReadWriter) {
+		script(conn, rw.Reader)
+	}))
+	t.Cleanup(server.Close)
+	conn, err := Dial(dialContext(t), wsURL(server), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestDialHandshake(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(serve(t, func(conn net.Conn, rw *bufio.ReadWriter) {}))
+	defer server.Close()
+	badAccept := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Header().Set("Upgrade", "websocket")
+		w.Header().Set("Connection", "Upgrade")
+		w.Header().Set("Sec-WebSocket-Accept", "wrong")
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	}))
+	defer badAccept.Close()
+	refused := httptest.NewServer(http.NotFoundHandler())
+	defer refused.Close()
+
+	conn, err := Dial(dialContext(t), wsURL(server), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	_, err = Dial(dialContext(t), wsURL(badAccept), http.Header{"Authorization": {"Bearer key"}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "bad Sec-WebSocket-Accept") {
+		t.Errorf("wrong accept key: err = %v", err)
+	}
+	if header.Get("Authorization") != "Bearer key" || header.Get("Sec-WebSocket-Key") == "" {
+		t.Errorf("handshake headers = %v, want Authorization and Sec-WebSocket-Key", header)
+	}
+	if _, err := Dial(dialContext(t), wsURL(refused), nil, nil); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("HTTP 404: err = %v", err)
+	}
+	if _, err := Dial(dialContext(t), strings.Replace(wsURL(server), "ws", "ftp", 1), nil, nil); err == nil {
+		t.Error("ftp URL dialed")
+	}
+}
+
+func TestWriteMessageMasksFrames(t *testing.T) {
+	type frame struct {
+		payload []byte
+		masked  bool
+		raw     []byte
+	}
+	frames := make(chan frame, 3)
+	conn := dialScript(t, func(conn net.Conn, r *bufio.Reader) {
+		for i := 0; i < 3; i++ {
+			// Keep the raw bytes to check the payload is not sent in clear
+			var raw bytes.Buffer
+			_, payload, masked, err := readClientFrame(io.TeeReader(r, &raw))
+			if err != nil {
+				return
+			}
+			frames <- frame{payload, masked, raw.Bytes()}
+		}
+	})
+
+	// One message per length encoding: 7 bit, 16 bit and 64 bit
+	for _, size := range []int{5, 300, 70000} {
+		message := []byte(strings.Repeat("a", size))
+		if err := conn.WriteMessage(message); err != nil {
+			t.Fatal(err)
+		}
+		got := <-frames
+		if !got.masked {
+			t.Errorf("%d byte message sent unmasked", size)
+		}
+		if string(got.payload) != string(message) {
+			t.Errorf("%d byte message arrived as %d bytes", size, len(got.payload))
+		}
+		if strings.Contains(string(got.raw), "aaaa") {
+			t.Errorf("%d byte message sent in clear", size)
+		}
+	}
+}
+
+func TestReadMessageFragmented(t *testing.T) {
+	pongs := make(chan string, 1)
+	conn := dialScript(t, func(conn net.Conn, r *bufio.Reader) {
+		writeServerFrame(conn, false, opText, []byte("hel"))
+		writeServerFrame(conn, true, opPing, []byte("still there?"))
+		writeServerFrame(conn, false, opContinuation, []byte("lo "))
+		writeServerFrame(conn, true, opContinuation, []byte("world"))
+		opcode, payload, _, err := readClientFrame(r)
+		if err == nil && opcode == opPong {
+			pongs <- string(payload)
+		}
+		close(pongs)
+	})
+
+	got, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello world" {
+		t.Errorf("message = %q, want the fragments joined", got)
+	}
+	if pong := <-pongs; pong != "still there?" {
+		t.Errorf("pong = %q, want the ping payload", pong)
+	}
+}
+
+func TestReadMessageRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		script func(conn net.Conn)
+		want   string
+	}{
+		{"oversized frame", func(conn net.Conn) {
+			head := binary.BigEndian.AppendUint64([]byte{0x80 | opBinary, 127}, MaxMessageSize+1)
+			conn.Write(head)
+		}, "frame larger than"},
+		{"oversized message", func(conn net.Conn) {
+			chunk := make([]byte, MaxMessageSize/2+1)
+			writeServerFrame(conn, false, opBinary, chunk)
+			writeServerFrame(conn, true, opContinuation, chunk)
+		}, "message larger than"},
+		{"masked frame", func(conn net.Conn) {
+			conn.Write([]byte{0x80 | opText, 0x80 | 1, 0, 0, 0, 0, 'x'})
+		}, "masked frame"},
+		{"continuation first", func(conn net.Conn) {
+			writeServerFrame(conn, true, opContinuation, []byte("x"))
+		}, "continuation without a message"},
+		{"interleaved message", func(conn net.Conn) {
+			writeServerFrame(conn, false, opText, []byte("x"))
+			writeServerFrame(conn, true, opText, []byte("y"))
+		}, "new message inside a fragmented one"},
+		{"unknown opcode", func(conn net.Conn) {
+			writeServerFrame(conn, true, 0x3, nil)
+		}, "unknown opcode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan struct{})
+			conn := dialScript(t, func(conn net.Conn, r *bufio.Reader) {
+				tt.script(conn)
+				<-done
+			})
+			defer close(done)
+			_, err := conn.ReadMessage()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerClose(t *testing.T) {
+	echoed := make(chan []byte, 1)
+	conn := dialScript(t, func(conn net.Conn, r *bufio.Reader) {
+		writeServerFrame(conn, true, opClose, []byte{0x03, 0xe9}) // 1001: going away
+		opcode, payload, _, err := readClientFrame(r)
+		if err == nil && opcode == opClose {
+			echoed <- payload
+		}
+		close(echoed)
+	})
+
+	if _, err := conn.ReadMessage(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("err = %v, want ErrClosed", err)
+	}
+	if got := <-echoed; string(got) != "\x03\xe9" {
+		t.Errorf("close reply = %x, want the server's status echoed", got)
+	}
+	if err := conn.WriteMessage([]byte("late")); err == nil {
+		t.Error("write after close succeeded")
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	closed := make(chan []byte, 1)
+	conn := dialScript(t, func(conn net.Conn, r *bufio.Reader) {
+		opcode, payload, masked, err := readClientFrame(r)
+		if err == nil && opcode == opClose && masked {
+			closed <- payload
+		}
+		close(closed)
+	})
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-closed; string(got) != "\x03\xe8" {
+		t.Errorf("close frame payload = %x, want a masked 1000 normal closure", got)
+	}
+	if _, err := conn.ReadMessage(); err == nil {
+		t.Error("read after close succeeded")
+	}
+}
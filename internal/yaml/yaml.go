@@ -0,0 +1,461 @@
+// Package yaml parses the subset of YAML used by config files: block
+// mappings and sequences, single-line flow collections, plain and quoted
+// scalars, and comments. Anchors, aliases, tags, block scalars and
+// multi-line flow collections are rejected. Nodes keep their line
+// numbers, so callers can point at the offending line.
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind is the type of a Node
+type Kind int
+
+const (
+	Scalar Kind = iota
+	Mapping
+	Sequence
+)
+
+// Node is a parsed YAML value
+type Node struct {
+	Kind Kind
+	Line int // 1-based line the value starts on
+	// Value is the text of a scalar, unescaped if Quoted
+	Value  string
+	Quoted bool
+	Pairs  []Pair  // of a Mapping, in file order
+	Items  []*Node // of a Sequence
+}
+
+// Pair is one key of a mapping with its value
+type Pair struct {
+	Key   string
+	Line  int // of the key
+	Value *Node
+}
+
+// IsNull reports whether n is a null scalar: empty, ~ or null
+func (n *Node) IsNull() bool {
+	if n.Kind != Scalar || n.Quoted {
+		return false
+	}
+	switch n.Value {
+	case "", "~", "null", "Null", "NULL":
+		return true
+	}
+	return false
+}
+
+// Error is a syntax error on a line
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// line is a non-blank source line without its comment
+type line struct {
+	num    int
+	indent int
+	text   string
+}
+
+// Parse parses a YAML document. An empty document is a null scalar.
+func Parse(data []byte) (*Node, error) {
+	if !utf8.Valid(data) {
+		return nil, &Error{Line: 1, Msg: "invalid UTF-8"}
+	}
+	var lines []line
+	for i, raw := range strings.Split(strings.TrimPrefix(string(data), "\ufeff"), "\n") {
+		num := i + 1
+		raw = strings.TrimRight(raw, "\r")
+		text := strings.TrimRight(stripComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, &Error{Line: num, Msg: "tabs are not allowed in indentation"}
+		}
+		if text == "---" || text == "..." {
+			if len(lines) > 0 && text == "---" {
+				return nil, &Error{Line: num, Msg: "multiple documents are not supported"}
+			}
+			continue
+		}
+		if strings.HasPrefix(text, "%") {
+			return nil, &Error{Line: num, Msg: "directives are not supported"}
+		}
+		lines = append(lines, line{num: num, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if len(lines) == 0 {
+		return &Node{Kind: Scalar, Line: 1}, nil
+	}
+
+	p := &parser{lines: lines}
+	node, err := p.block(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, &Error{Line: p.lines[p.pos].num, Msg: "unexpected indentation"}
+	}
+	return node, nil
+}
+
+type parser struct {
+	lines []line
+	pos   int
+}
+
+// block parses the mapping, sequence or scalar starting at the current
+// line, which is indented by indent
+func (p *parser) block(indent int) (*Node, error) {
+	l := p.lines[p.pos]
+	if isSequenceItem(l.text) {
+		return p.sequence(indent)
+	}
+	if _, _, ok := splitKey(l.text); ok {
+		return p.mapping(indent)
+	}
+	p.pos++
+	if p.pos < len(p.lines) && p.lines[p.pos].indent >= indent {
+		return nil, &Error{Line: p.lines[p.pos].num, Msg: "multi-line scalars are not supported"}
+	}
+	return inline(l.text, l.num)
+}
+
+func (p *parser) mapping(indent int) (*Node, error) {
+	node := &Node{Kind: Mapping, Line: p.lines[p.pos].num}
+	seen := map[string]bool{}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, &Error{Line: l.num, Msg: "unexpected indentation"}
+		}
+		if isSequenceItem(l.text) {
+			return nil, &Error{Line: l.num, Msg: "sequence item where a mapping key was expected"}
+		}
+		rawKey, rest, ok := splitKey(l.text)
+		if !ok {
+			return nil, &Error{Line: l.num, Msg: fmt.Sprintf("expected \"key: value\", got %q", l.text)}
+		}
+		key, err := inline(rawKey, l.num)
+		if err != nil {
+			return nil, err
+		}
+		if key.Kind != Scalar {
+			return nil, &Error{Line: l.num, Msg: "mapping keys must be scalars"}
+		}
+		if seen[key.Value] {
+			return nil, &Error{Line: l.num, Msg: fmt.Sprintf("duplicate key %q", key.Value)}
+		}
+		seen[key.Value] = true
+		p.pos++
+
+		value, err := p.value(indent, rest, l.num, true)
+		if err != nil {
+			return nil, err
+		}
+		node.Pairs = append(node.Pairs, Pair{Key: key.Value, Line: l.num, Value: value})
+	}
+	return node, nil
+}
+
+func (p *parser) sequence(indent int) (*Node, error) {
+	node := &Node{Kind: Sequence, Line: p.lines[p.pos].num}
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, &Error{Line: l.num, Msg: "unexpected indentation"}
+		}
+		if !isSequenceItem(l.text) {
+			break
+		}
+		rest := strings.TrimLeft(l.text[1:], " ")
+		if rest == "" {
+			p.pos++
+			item, err := p.value(indent, "", l.num, false)
+			if err != nil {
+				return nil, err
+			}
+			node.Items = append(node.Items, item)
+			continue
+		}
+		// The item's content starts a nested block at its own column, so
+		// "- key: value" continues with keys aligned under "key"
+		p.lines[p.pos] = line{num: l.num, indent: indent + len(l.text) - len(rest), text: rest}
+		item, err := p.block(p.lines[p.pos].indent)
+		if err != nil {
+			return nil, err
+		}
+		node.Items = append(node.Items, item)
+	}
+	return node, nil
+}
+
+// value parses the value after a key or "-" on line num: rest when given,
+// or the block nested under it. Mapping values may be sequences at the
+// same indentation as their key.
+func (p *parser) value(indent int, rest string, num int, inMapping bool) (*Node, error) {
+	if rest != "" {
+		// Parsed first, so block scalars and multi-line flow collections
+		// are named rather than their continuation lines
+		node, err := inline(rest, num)
+		if err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+			return nil, &Error{Line: p.lines[p.pos].num, Msg: "unexpected indentation"}
+		}
+		return node, nil
+	}
+	if p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		if next.indent > indent || inMapping && next.indent == indent && isSequenceItem(next.text) {
+			return p.block(next.indent)
+		}
+	}
+	return &Node{Kind: Scalar, Line: num}, nil
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" at the first colon outside quotes that is
+// followed by a space or ends the line
+func splitKey(text string) (key, rest string, ok bool) {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return "", "", false
+	}
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\', quote == '\'' && c == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && i == 0:
+			quote = c
+		case c == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// stripComment cuts a # comment, which starts a line or follows a space,
+// outside quotes; a doubled quote within single quotes stays quoted
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\', quote == '\'' && c == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" \t[{,", rune(text[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// inline parses a value on one line: a flow collection, a quoted scalar
+// or a plain scalar
+func inline(text string, num int) (*Node, error) {
+	switch text[0] {
+	case '[', '{':
+		f := &flow{text: text, num: num}
+		node, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		f.space()
+		if f.pos < len(f.text) {
+			return nil, f.errorf("unexpected %q after flow collection", f.text[f.pos:])
+		}
+		return node, nil
+	case '"', '\'':
+		value, n, err := quoted(text, num)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(text[n:]) != "" {
+			return nil, &Error{Line: num, Msg: fmt.Sprintf("unexpected %q after quoted string", strings.TrimSpace(text[n:]))}
+		}
+		return &Node{Kind: Scalar, Line: num, Value: value, Quoted: true}, nil
+	case '&', '*':
+		return nil, &Error{Line: num, Msg: "anchors and aliases are not supported"}
+	case '!':
+		return nil, &Error{Line: num, Msg: "tags are not supported"}
+	case '|', '>':
+		return nil, &Error{Line: num, Msg: "block scalars are not supported; use a quoted string"}
+	case '@', '`':
+		return nil, &Error{Line: num, Msg: fmt.Sprintf("plain scalars cannot start with %q; quote the value", text[0])}
+	}
+	return &Node{Kind: Scalar, Line: num, Value: text}, nil
+}
+
+// quoted unescapes the quoted string at the start of text and returns its
+// length in text
+func quoted(text string, num int) (string, int, error) {
+	if text[0] == '\'' {
+		var b strings.Builder
+		for i := 1; i < len(text); i++ {
+			if text[i] != '\'' {
+				b.WriteByte(text[i])
+				continue
+			}
+			if i+1 < len(text) && text[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+			return b.String(), i + 1, nil
+		}
+		return "", 0, &Error{Line: num, Msg: "unterminated single-quoted string"}
+	}
+
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(text[:i+1])
+			if err != nil {
+				return "", 0, &Error{Line: num, Msg: fmt.Sprintf("invalid escape in %s", text[:i+1])}
+			}
+			return value, i + 1, nil
+		}
+	}
+	return "", 0, &Error{Line: num, Msg: "unterminated double-quoted string"}
+}
+
+// flow parses a single-line flow collection such as [a, b] or {a: 1}
+type flow struct {
+	text string
+	pos  int
+	num  int
+}
+
+func (f *flow) errorf(format string, args ...interface{}) error {
+	return &Error{Line: f.num, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (f *flow) space() {
+	for f.pos < len(f.text) && f.text[f.pos] == ' ' {
+		f.pos++
+	}
+}
+
+func (f *flow) value() (*Node, error) {
+	f.space()
+	if f.pos == len(f.text) {
+		return nil, f.errorf("multi-line flow collections are not supported")
+	}
+	switch f.text[f.pos] {
+	case '[':
+		return f.collection(']')
+	case '{':
+		return f.collection('}')
+	case '"', '\'':
+		value, n, err := quoted(f.text[f.pos:], f.num)
+		if err != nil {
+			return nil, err
+		}
+		f.pos += n
+		return &Node{Kind: Scalar, Line: f.num, Value: value, Quoted: true}, nil
+	}
+	start := f.pos
+	for f.pos < len(f.text) && !strings.ContainsRune(",]}", rune(f.text[f.pos])) &&
+		!(f.text[f.pos] == ':' && (f.pos+1 == len(f.text) || f.text[f.pos+1] == ' ')) {
+		f.pos++
+	}
+	text := strings.TrimSpace(f.text[start:f.pos])
+	if text == "" {
+		return &Node{Kind: Scalar, Line: f.num}, nil
+	}
+	return inline(text, f.num)
+}
+
+func (f *flow) collection(end byte) (*Node, error) {
+	node := &Node{Kind: Sequence, Line: f.num}
+	if end == '}' {
+		node.Kind = Mapping
+	}
+	f.pos++
+	seen := map[string]bool{}
+	for {
+		f.space()
+		if f.pos == len(f.text) {
+			return nil, f.errorf("multi-line flow collections are not supported")
+		}
+		if f.text[f.pos] == end {
+			f.pos++
+			return node, nil
+		}
+		item, err := f.value()
+		if err != nil {
+			return nil, err
+		}
+		if node.Kind == Mapping {
+			if item.Kind != Scalar {
+				return nil, f.errorf("mapping keys must be scalars")
+			}
+			if seen[item.Value] {
+				return nil, f.errorf("duplicate key %q", item.Value)
+			}
+			seen[item.Value] = true
+			f.space()
+			if f.pos == len(f.text) || f.text[f.pos] != ':' {
+				return nil, f.errorf("expected \":\" after key %q", item.Value)
+			}
+			f.pos++
+			value, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			node.Pairs = append(node.Pairs, Pair{Key: item.Value, Line: f.num, Value: value})
+		} else {
+			node.Items = append(node.Items, item)
+		}
+		f.space()
+		if f.pos == len(f.text) {
+			return nil, f.errorf("multi-line flow collections are not supported")
+		}
+		if f.text[f.pos] == ',' {
+			f.pos++
+			continue
+		}
+		if f.text[f.pos] == end {
+			continue
+		}
+		return nil, f.errorf("expected \",\" or %q in flow collection", end)
+	}
+}
@@ -0,0 +1,113 @@
+package yaml
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dump renders a node compactly with its line numbers: scalars as
+// value@line, quoted ones in quotes, mappings as {key@line: value} and
+// sequences as [item, ...]@line
+func dump(n *Node) string {
+	switch n.Kind {
+	case Mapping:
+		pairs := make([]string, len(n.Pairs))
+		for i, p := range n.Pairs {
+			pairs[i] = fmt.Sprintf("%s@%d: %s", p.Key, p.Line, dump(p.Value))
+		}
+		return fmt.Sprintf("{%s}@%d", strings.Join(pairs, ", "), n.Line)
+	case Sequence:
+		items := make([]string, len(n.Items))
+		for i, item := range n.Items {
+			items[i] = dump(item)
+		}
+		return fmt.Sprintf("[%s]@%d", strings.Join(items, ", "), n.Line)
+	}
+	if n.Quoted {
+		return fmt.Sprintf("%q@%d", n.Value, n.Line)
+	}
+	return fmt.Sprintf("%s@%d", n.Value, n.Line)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", "@1"},
+		{"comment only", "# nothing\n", "@1"},
+		{"scalar", "42", "42@1"},
+		{"mapping", "a: 1\nb: two\n", "{a@1: 1@1, b@2: two@2}@1"},
+		{"nested mapping", "networks:\n  ethereum:\n    api_key: k\n",
+			"{networks@1: {ethereum@2: {api_key@3: k@3}@3}@2}@1"},
+		{"null value", "a:\nb: ~\n", "{a@1: @1, b@2: ~@2}@1"},
+		{"sequence", "- a\n- b\n", "[a@1, b@2]@1"},
+		{"sequence in mapping", "urls:\n  - x\n  - y\n", "{urls@1: [x@2, y@3]@2}@1"},
+		{"unindented sequence in mapping", "urls:\n- x\n- y\nn: 1\n", "{urls@1: [x@2, y@3]@2, n@4: 1@4}@1"},
+		{"mappings in sequence", "- a: 1\n  b: 2\n- a: 3\n", "[{a@1: 1@1, b@2: 2@2}@1, {a@3: 3@3}@3]@1"},
+		{"flow sequence", "checks: [age, 'nonce', \"code\"]\n", `{checks@1: [age@1, "nonce"@1, "code"@1]@1}@1`},
+		{"flow mapping", "thresholds: {low: 85, medium: 60}\n", "{thresholds@1: {low@1: 85@1, medium@1: 60@1}@1}@1"},
+		{"empty flow", "a: []\nb: {}\n", "{a@1: []@1, b@2: {}@2}@1"},
+		{"single quoted", "a: 'it''s # not a comment'\n", `{a@1: "it's # not a comment"@1}@1`},
+		{"double quoted escapes", `a: "tab\there \u00e9 \"q\""`, "{a@1: \"tab\\there é \\\"q\\\"\"@1}@1"},
+		{"quoted key", "\"0xab\": label\n", "{0xab@1: label@1}@1"},
+		{"comments", "# head\na: 1 # trailing\n\n# between\nb: x#y\n", "{a@2: 1@2, b@5: x#y@5}@2"},
+		{"url value", "rpc_url: https://host:8545/path\n", "{rpc_url@1: https://host:8545/path@1}@1"},
+		{"crlf and bom", "\ufeffa: 1\r\nb: 2\r\n", "{a@1: 1@1, b@2: 2@2}@1"},
+		{"document start", "---\na: 1\n", "{a@2: 1@2}@2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Parse([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := dump(n); got != tt.want {
+				t.Errorf("Parse() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		line int
+		msg  string
+	}{
+		{"duplicate key", "a: 1\nb: 2\na: 3\n", 3, `duplicate key "a"`},
+		{"duplicate nested key", "n:\n  k: 1\n  k: 2\n", 3, `duplicate key "k"`},
+		{"over indented key", "a: 1\n   b: 2\n", 2, "unexpected indentation"},
+		{"under indented key", "n:\n    a: 1\n  b: 2\n", 3, "unexpected indentation"},
+		{"tab indentation", "n:\n\ta: 1\n", 2, "tabs are not allowed in indentation"},
+		{"item among keys", "a: 1\n- b\n", 2, "sequence item where a mapping key was expected"},
+		{"missing colon", "a: 1\njust text\n", 2, `expected "key: value", got "just text"`},
+		{"multi-line scalar", "a\nb\n", 2, "multi-line scalars are not supported"},
+		{"unterminated single quote", "a: 'x\n", 1, "unterminated single-quoted string"},
+		{"unterminated double quote", "a: 1\nb: \"x\n", 2, "unterminated double-quoted string"},
+		{"bad escape", `a: "\q"`, 1, `invalid escape in "\q"`},
+		{"text after quote", "a: 'x' y\n", 1, `unexpected "y" after quoted string`},
+		{"anchor", "a: &x 1\n", 1, "anchors and aliases are not supported"},
+		{"tag", "a: !!str 1\n", 1, "tags are not supported"},
+		{"block scalar", "a: |\n  text\n", 1, "block scalars are not supported; use a quoted string"},
+		{"multi-line flow", "a: [1,\n  2]\n", 1, "multi-line flow collections are not supported"},
+		{"second document", "a: 1\n---\nb: 2\n", 2, "multiple documents are not supported"},
+		{"directive", "%YAML 1.2\na: 1\n", 1, "directives are not supported"},
+		{"invalid utf-8", "a: \xff\n", 1, "invalid UTF-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.in))
+			e, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("Parse() error = %v, want a *Error", err)
+			}
+			if e.Line != tt.line || e.Msg != tt.msg {
+				t.Errorf("Parse() error = line %d: %s, want line %d: %s", e.Line, e.Msg, tt.line, tt.msg)
+			}
+		})
+	}
+}
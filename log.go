@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// logger carries all status output; it writes to stderr so stdout only
+// holds the rendered report
+var logger = newLogger(stderr, 0)
+
+// newLogger returns a CLI logger for a -v count: 0 logs info and above,
+// 1 adds debug (HTTP requests), 2 or more adds trace (payloads, cache).
+// A negative count (--quiet) logs only warnings and errors.
+func newLogger(w io.Writer, verbosity int) *slog.Logger {
+	level := slog.LevelInfo
+	switch {
+	case verbosity < 0:
+		level = slog.LevelWarn
+	case verbosity >= 2:
+		level = scanner.LevelTrace
+	case verbosity == 1:
+		level = slog.LevelDebug
+	}
+	return slog.New(&cliHandler{w: w, level: level, mu: &sync.Mutex{}})
+}
+
+func infof(format string, args ...interface{}) { logger.Info(fmt.Sprintf(format, args...)) }
+func warnf(format string, args ...interface{}) { logger.Warn(fmt.Sprintf(format, args...)) }
+func errorf(format string, args ...interface{}) {
+	logger.Error(fmt.Sprintf(format, args...))
+}
+
+// fatalf logs an error and exits with exitError
+func fatalf(format string, args ...interface{}) {
+	errorf(format, args...)
+	os.Exit(exitError)
+}
+
+// cliHandler prints human readable log lines: errors and warnings get an
+// emoji prefix, info messages are printed as is and debug lines are tagged
+type cliHandler struct {
+	w     io.Writer
+	level slog.Level
+	mu    *sync.Mutex
+	attrs []slog.Attr
+}
+
+func (h *cliHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *cliHandler) Handle(_ context.Context, r slog.Record) error {
+	var b strings.Builder
+	switch {
+	case r.Level >= slog.LevelError:
+		b.WriteString("❌ ")
+	case r.Level >= slog.LevelWarn:
+		b.WriteString("⚠️  ")
+	case r.Level >= slog.LevelInfo:
+	case r.Level >= slog.LevelDebug:
+		b.WriteString("[debug] ")
+	default:
+		b.WriteString("[trace] ")
+	}
+	b.WriteString(r.Message)
+
+	writeAttr := func(a slog.Attr) bool {
+		value := a.Value.String()
+		if strings.ContainsAny(value, " \t\n\"") {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&b, " %s=%s", a.Key, value)
+		return true
+	}
+	for _, a := range h.attrs {
+		writeAttr(a)
+	}
+	r.Attrs(writeAttr)
+	b.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
+
+func (h *cliHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+// Groups are not used by the scanner; attributes are printed flat
+func (h *cliHandler) WithGroup(string) slog.Handler { return h }
+
+// countFlag is a boolean flag that counts how often it is given, e.g. -v -v
+type countFlag int
+
+func (c *countFlag) String() string   { return strconv.Itoa(int(*c)) }
+func (c *countFlag) IsBoolFlag() bool { return true }
+
+func (c *countFlag) Set(value string) error {
+	if value == "true" {
+		*c++
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid count %q", value)
+	}
+	*c = countFlag(n)
+	return nil
+}
+
+// expandShortFlags rewrites -vv style arguments as repeated -v flags
+func expandShortFlags(args []string) []string {
+	expanded := make([]string, 0, len(args))
+	for _, arg := range args {
+		if len(arg) > 2 && strings.Trim(arg, "v") == "-" {
+			for i := 1; i < len(arg); i++ {
+				expanded = append(expanded, "-v")
+			}
+			continue
+		}
+		expanded = append(expanded, arg)
+	}
+	return expanded
+}
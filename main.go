@@ -1,76 +1,620 @@
 package main
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"flag"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
+
+	"agent-reputation-scanner/scanner"
 )
 
-const version = "0.1.0"
+const version = scanner.Version
 
-// Known risk indicators
-var (
-	// Known phishing/scam contract patterns
-	maliciousPatterns = []string{
-		"0x0000000000000000000000000000000000000000", // Burn address (context dependent)
-	}
-	
-	// Known high-risk contract types (simplified)
-	highRiskFunctions = []string{
-		"approve",
-		"setApprovalForAll", 
-		"transferOwnership",
-		"selfdestruct",
-	}
+// Process exit codes
+const (
+	exitOK       = 0
+	exitError    = 1 // usage or runtime error
+	exitRisk     = 2 // risk level at or above --fail-on (below critical)
+	exitCritical = 3 // critical risk at or above --fail-on
+
+	exitInterrupted = 130 // interrupted by SIGINT/SIGTERM
 )
 
-type ReputationReport struct {
-	Address        string          `json:"address"`
-	Network        string          `json:"network"`
-	Timestamp      time.Time       `json:"timestamp"`
-	OverallScore   int             `json:"overall_score"` // 0-100, higher = more trustworthy
-	RiskLevel      string          `json:"risk_level"`    // low, medium, high, critical
-	Checks         []CheckResult   `json:"checks"`
-	Recommendations []string       `json:"recommendations"`
-}
+// Deadline for a single scan when --timeout is not given
+const defaultScanTimeout = 30 * time.Second
 
-type CheckResult struct {
-	Name        string `json:"name"`
-	Status      string `json:"status"` // pass, warning, fail
-	Score       int    `json:"score"`  // 0-100
-	Details     string `json:"details"`
-}
+// Deadline for each address of a batch when --scan-timeout is not given;
+// generous, as a slow explorer with retries can take a while
+const defaultBatchScanTimeout = 2 * time.Minute
 
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 
 	cmd := os.Args[1]
 
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	format := fs.String("format", "", "output format: text, json, ndjson, csv, sarif, html, sqlite (default: text for scan, json for batch, sqlite for batch --output *.db)")
+	concurrency := fs.Int("concurrency", scanner.DefaultConcurrency, "number of parallel workers for batch scans")
+	maxRetries := fs.Int("max-retries", scanner.DefaultMaxRetries, "retries for transient explorer API failures")
+	retryDelay := fs.Duration("retry-delay", scanner.DefaultRetryDelay, "base delay for exponential retry backoff")
+	rateLimit := fs.Float64("rate-limit", 0, "explorer requests per second (default: config requests_per_second, else 5)")
+	timeout := fs.Duration("timeout", 0, "overall deadline (default: 30s for scan, none for batch)")
+	requestTimeout := fs.Duration("request-timeout", scanner.DefaultRequestTimeout, "timeout for each RPC/explorer request")
+	scanTimeout := fs.Duration("scan-timeout", defaultBatchScanTimeout, "deadline for each address of a batch (0 for none)")
+	output := fs.String("output", "", "write the rendered report to this file instead of stdout")
+	resume := fs.Bool("resume", false, "resume an interrupted batch from its checkpoint")
+	restart := fs.Bool("restart", false, "ignore an existing batch checkpoint and start over")
+	ordered := fs.Bool("ordered", false, "with --format ndjson, write batch results in input order instead of as they finish")
+	inputFlag := fs.String("input-format", "", "batch input format: text (one address per line) or csv (address,label,expected_risk); default: csv for .csv files")
+	since := fs.Duration("since", 0, "reuse batch results from the history that are newer than this, e.g. 24h; rescan the rest")
+	configPath := fs.String("config", "", "config file, JSON or YAML (default: config.json, config.yaml or config.yml in ~/.config/agent-reputation-scanner)")
+	validate := fs.Bool("validate", false, "check JSON output against the report schema before writing it")
+	metricsAddr := fs.String("metrics-addr", "", "serve Prometheus metrics on this address, e.g. :9090")
+	chainID := fs.Int64("chain-id", 0, "select the network by chain ID, e.g. 8453 for base")
+	allChains := fs.Bool("all-chains", false, "scan the address on every supported network and combine the reports")
+	networksList := fs.String("networks", "", "scan the address on these comma-separated networks and combine the reports")
+	source := fs.String("source", scanner.SourceExplorer, "account history data source: explorer or graph")
+	deep := fs.Bool("deep", false, "run expensive checks: honeypot swap simulation, NFT metadata, active approvals, counterparty reputation and MEV activity")
+	revocations := fs.Bool("revocations", false, "read the token approval events of accounts to recommend revoking unlimited approvals to risky spenders (one extra explorer query per scan)")
+	traceDepth := fs.Int("trace-depth", 0, "trace incoming funds this many hops back for denylisted or sanctioned sources (expensive; 0 disables)")
+	traceNodes := fs.Int("trace-nodes", scanner.DefaultTraceMaxNodes, "addresses a --trace-depth trace may visit")
+	traceTimeout := fs.Duration("trace-timeout", scanner.DefaultTraceTimeout, "time a --trace-depth trace may take")
+	upgradeWindow := fs.String("upgrade-window", "", "flag proxy implementation changes more recent than this, e.g. 7d or 48h (default: upgrade_window from the config, else 7d)")
+	debounce := fs.Duration("debounce", scanner.DefaultWatchDebounce, "with watch, quiet period after activity before rescanning")
+	alertOn := fs.String("alert-on", "", "with watch, lowest risk level that alerts (default: --webhook-threshold)")
+	wsURL := fs.String("ws-url", "", "with watch, WebSocket RPC endpoint (default: <NETWORK>_WS_URL, else the RPC endpoint)")
+	redact := fs.Bool("redact", false, "mask addresses and strip URLs and keys from output for sharing, emptying the report fields listed under redaction in the config")
+	explain := fs.Bool("explain", false, "after the report, show how each check's weighted score produced the overall score")
+	dryRun := fs.Bool("dry-run", false, "list the checks a scan or batch would run and estimate its requests and time, without making any")
+	abuseReports := fs.String("abuse-reports", "", "community abuse report API to check addresses against, {address} is filled in (default: abuse_reports_url from the config)")
+	ipfsGateway := fs.String("ipfs-gateway", "", "gateway for ipfs:// NFT metadata (default: ipfs_gateway from the config, else "+scanner.DefaultIPFSGateway+")")
+	profile := fs.String("profile", "", "preset checks and weights: compliance, dev, trading, full or a profile from the config")
+	checksList := fs.String("checks", "", "run only these checks, e.g. address,verification,patterns")
+	skipList := fs.String("skip", "", "leave out these checks, e.g. age,volume")
+	strictAuth := fs.Bool("strict-auth", false, "abort when the RPC endpoint or explorer rejects the configured credentials")
+	strict := fs.Bool("strict", false, "score warnings as failures, raising the risk level to at least high")
+	baselinePath := fs.String("baseline", "", "baseline of accepted findings: scans mark them and report only new ones as issues")
+	retryLowConfidence := fs.Int("retry-low-confidence", 0, "rescan checks that failed on rate limits, outages or timeouts up to N times when confidence is low (0 for never)")
+	rescanDelay := fs.Duration("rescan-delay", scanner.DefaultRescanDelay, "wait before each --retry-low-confidence rescan")
+	lookalikeChars := fs.Int("lookalike-chars", scanner.DefaultLookalikeChars, "leading/trailing hex characters compared to detect lookalike addresses")
+	noCache := fs.Bool("no-cache", false, "bypass the on-disk result cache")
+	maxAge := fs.String("max-age", "", "cache TTL per check, e.g. verification=7d,volume=1h,contract-age=infinite (default: cache_ttls from the config, else built-in per-check TTLs)")
+	fixtures := fs.String("fixtures", "", "answer all RPC and explorer requests from this fixtures JSON file (offline, reproducible)")
+	local := fs.Bool("local", false, "scan against a local anvil/hardhat node (LOCAL_RPC_URL, default "+scanner.DefaultLocalRPC+") without explorer checks or caching")
+	var denylistFiles stringList
+	fs.Var(&denylistFiles, "denylist", "denylist file with one address per line (repeatable)")
+	var sanctionsFiles stringList
+	fs.Var(&sanctionsFiles, "sanctions", "extra sanctions list file, one address per line (repeatable)")
+	var bytecodeFiles stringList
+	fs.Var(&bytecodeFiles, "bytecode-hashes", "known scam bytecode hash list, one hash per line (repeatable)")
+	var signatureFiles stringList
+	fs.Var(&signatureFiles, "signatures", "function signature list for the Method Profile check, one per line (repeatable)")
+	var labelFiles stringList
+	fs.Var(&labelFiles, "labels", "address label CSV of well-known entities, address,name,category[,networks] (repeatable)")
+	var allowlistFiles stringList
+	fs.Var(&allowlistFiles, "allowlist", "allowlist file of trusted addresses with optional labels (repeatable)")
+	webhook := fs.String("webhook", "", "POST reports at or above --webhook-threshold to this URL")
+	webhookThreshold := fs.String("webhook-threshold", scanner.DefaultWebhookThreshold, "lowest risk level sent to --webhook (low, medium, high, critical)")
+	thresholds := fs.String("thresholds", "", "lowest score per risk level, e.g. low=85,medium=60,high=35 (default: config, else "+scanner.DefaultRiskThresholds.String()+")")
+	minCoverage := fs.Float64("min-coverage", 0, "share of checks that must have live or cached data for a low risk level, negative to disable (default: min_data_coverage from the config, else 0.6)")
+	failOn := fs.String("fail-on", "high", "exit non-zero when risk is at or above this level (low, medium, high, critical, none)")
+	quiet := fs.Bool("quiet", false, "print only \"RISK_LEVEL SCORE ADDRESS\" lines (ignored with structured formats)")
+	fs.BoolVar(quiet, "q", false, "same as --quiet")
+	var verbosity countFlag
+	fs.Var(&verbosity, "v", "increase log verbosity (repeatable: -v debug, -vv trace)")
+	fs.Var(&verbosity, "verbose", "same as -v")
+	args := parseFlags(fs, expandShortFlags(os.Args[2:]))
+	// A database file name selects the database writer
+	if *format == "" && cmd == "batch" && isSQLitePath(*output) {
+		*format = formatSQLite
+	}
+	// --quiet only applies to text output; structured formats win
+	if *quiet && *format != "" && *format != formatText {
+		*quiet = false
+	}
+	if *quiet && verbosity == 0 {
+		verbosity = -1
+	}
+	logger = newLogger(stderr, int(verbosity))
+
+	if *format == "" {
+		*format = formatText
+		if cmd == "batch" && !*quiet {
+			*format = formatJSON
+		}
+	}
+	if err := validateFormat(*format); err != nil {
+		fatalf("%v", err)
+	}
+	if *format == formatSQLite && cmd != "batch" {
+		fatalf("--format sqlite only applies to batch")
+	}
+	if *validate && *format != formatJSON && *format != formatNDJSON {
+		fatalf("--validate requires --format json or ndjson")
+	}
+	out := outputOptions{format: *format, output: *output, validate: *validate, explain: *explain}
+	if *quiet {
+		out.format = formatVerdict
+	}
+	if *failOn != "none" && scanner.RiskRank(*failOn) < 0 {
+		fatalf("Invalid --fail-on level %q (use %s or none)", *failOn, strings.Join(scanner.RiskLevels, ", "))
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if *redact {
+		out.redact = &cfg.Redaction
+	}
+	if *maxRetries < 0 || *retryDelay < 0 {
+		fatalf("--max-retries and --retry-delay must not be negative")
+	}
+	// 0 disables retries or backoff; the scanner takes a zero Config
+	// field as unset
+	cfg.MaxRetries, cfg.RetryDelay = *maxRetries, *retryDelay
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = -1
+	}
+	if cfg.RetryDelay == 0 {
+		cfg.RetryDelay = -1
+	}
+	if cfg.Thresholds, err = scanner.ParseRiskThresholds(*thresholds, cfg.Thresholds); err != nil {
+		fatalf("Invalid --thresholds: %v", err)
+	}
+	if cfg.CacheTTLs, err = scanner.ParseCacheTTLs(*maxAge, cfg.CacheTTLs); err != nil {
+		fatalf("Invalid --max-age: %v", err)
+	}
+	if *minCoverage > 1 {
+		fatalf("Invalid --min-coverage %v (must be at most 1)", *minCoverage)
+	}
+	if *minCoverage != 0 {
+		cfg.MinDataCoverage = *minCoverage
+	}
+	if *rateLimit < 0 {
+		fatalf("Invalid --rate-limit %v (must be positive)", *rateLimit)
+	}
+	if *rateLimit > 0 {
+		cfg.RequestsPerSecond = *rateLimit
+	}
+	if *concurrency < 1 {
+		fatalf("Invalid --concurrency %d (must be at least 1)", *concurrency)
+	}
+	cfg.Concurrency = *concurrency
+	cfg.RequestTimeout = *requestTimeout
+	switch cmd {
+	case "watch":
+		// The watch runs until interrupted; --timeout bounds each rescan
+		cfg.Timeout = *timeout
+		if cfg.Timeout == 0 {
+			cfg.Timeout = defaultScanTimeout
+		}
+	case "batch", "tui", "calibrate", "baseline":
+		// --timeout bounds the whole batch, --scan-timeout each address
+		if *scanTimeout < 0 {
+			fatalf("--scan-timeout must not be negative")
+		}
+		cfg.Timeout = *scanTimeout
+	}
+	cfg.Deep = *deep
+	cfg.Revocations = *revocations
+	cfg.StrictAuth = *strictAuth
+	cfg.Strict = *strict
+	if *retryLowConfidence < 0 || *rescanDelay < 0 {
+		fatalf("--retry-low-confidence and --rescan-delay must not be negative")
+	}
+	cfg.RescanLowConfidence = *retryLowConfidence
+	cfg.RescanDelay = *rescanDelay
+	if *baselinePath != "" && cmd != "baseline" {
+		if cfg.Baseline, err = scanner.LoadBaseline(*baselinePath); err != nil {
+			fatalf("Cannot load --baseline: %v", err)
+		}
+	}
+	if *ipfsGateway != "" {
+		cfg.IPFSGateway = *ipfsGateway
+	}
+	if *traceDepth < 0 {
+		fatalf("Invalid --trace-depth %d (must not be negative)", *traceDepth)
+	}
+	cfg.TraceDepth = *traceDepth
+	cfg.TraceMaxNodes = *traceNodes
+	cfg.TraceTimeout = *traceTimeout
+	if *upgradeWindow != "" {
+		window, err := scanner.ParseCacheTTL(*upgradeWindow)
+		if err != nil || window <= 0 || window == scanner.CacheForever {
+			fatalf("Invalid --upgrade-window %q (use e.g. 7d or 48h)", *upgradeWindow)
+		}
+		cfg.UpgradeWindow = window
+	}
+	if *abuseReports != "" {
+		cfg.AbuseReportsURL = *abuseReports
+	}
+	cfg.Checks = splitList(*checksList)
+	cfg.SkipChecks = splitList(*skipList)
+	for _, names := range [][]string{cfg.Checks, cfg.SkipChecks} {
+		if err := scanner.ValidateCheckNames(names); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	if *profile != "" {
+		if err := cfg.ApplyProfile(*profile); err != nil {
+			fatalf("Invalid --profile: %v", err)
+		}
+	}
+	if *fixtures != "" {
+		if *local {
+			fatalf("--fixtures and --local cannot be combined")
+		}
+		if cfg.Fixtures, err = scanner.LoadFixtures(*fixtures); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	if *local {
+		// The fork replaces the configured endpoints
+		cfg.Local = true
+		cfg.RPCURLs = nil
+		cfg.FallbackRPCURLs = nil
+	}
+	if *lookalikeChars < 1 || *lookalikeChars > scanner.MaxLookalikeChars {
+		fatalf("Invalid --lookalike-chars %d (use 1 to %d)", *lookalikeChars, scanner.MaxLookalikeChars)
+	}
+	cfg.LookalikeChars = *lookalikeChars
+	if !contains(scanner.Sources, *source) {
+		fatalf("Invalid --source %q (use %s)", *source, strings.Join(scanner.Sources, " or "))
+	}
+	cfg.Source = *source
+	if scanner.RiskRank(*webhookThreshold) < 0 {
+		fatalf("Invalid --webhook-threshold %q (use %s)", *webhookThreshold, strings.Join(scanner.RiskLevels, ", "))
+	}
+	cfg.Webhook.URL = *webhook
+	cfg.Webhook.Threshold = *webhookThreshold
+	cfg.CacheDir = scanner.DefaultCacheDir()
+	cfg.Logger = logger
+	if cmd == "tui" {
+		// Log lines would corrupt the screen
+		cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	// A local fork's state changes between runs, so its results are not cached
+	if *noCache || *local {
+		cfg.CacheDir = ""
+	}
+	s := scanner.NewScanner(cfg)
+	if len(s.CheckNames()) == 0 {
+		fatalf("--checks and --skip leave no checks to run")
+	}
+	if *metricsAddr != "" {
+		serveMetrics(*metricsAddr, s)
+	}
+
+	// update-lists must work even when the current lists do not load
+	if cmd != "update-lists" {
+		if err := loadLists(s, allowlistFiles, denylistFiles, sanctionsFiles, bytecodeFiles, signatureFiles, labelFiles); err != nil {
+			fatalf("%v", err)
+		}
+	}
+
+	// A 66-character hash names a transaction rather than an address
+	if cmd == "scan" && len(args) > 0 && scanner.IsTxHash(args[0]) {
+		cmd = "scan-tx"
+	}
+
 	switch cmd {
 	case "scan":
-		if len(os.Args) < 3 {
-			fmt.Println("❌ Address required: scanner scan 0x...")
-			os.Exit(1)
+		if len(args) < 1 {
+			fatalf("Address required: scanner scan 0x...")
+		}
+		addresses, network := splitScanArgs(args)
+		if *allChains || *networksList != "" {
+			switch {
+			case len(addresses) != 1:
+				fatalf("--all-chains and --networks scan a single address")
+			case network != "" || *chainID != 0:
+				fatalf("--all-chains and --networks select the networks; do not name one as well")
+			case *local:
+				fatalf("--all-chains and --networks do not apply to --local")
+			}
+			networks := s.NetworkNames()
+			if *networksList != "" {
+				networks = parseNetworks(s, *networksList)
+			}
+			if *dryRun {
+				runDryRun(s, 1, networks, *format)
+				os.Exit(exitOK)
+			}
+			for _, network := range networks {
+				verifyChainID(s, network, false)
+			}
+			if *timeout == 0 {
+				*timeout = defaultScanTimeout
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+			defer cancel()
+			report := scanChains(ctx, s, addresses[0], networks, out)
+			os.Exit(failedExitCode(riskExitCode(report.RiskLevel, *failOn), report.Chains...))
+		}
+		network = selectNetwork(s, network, *chainID)
+		if *dryRun {
+			runDryRun(s, len(addresses), []string{network}, *format)
+			os.Exit(exitOK)
 		}
-		address := os.Args[2]
-		network := "ethereum"
-		if len(os.Args) > 3 {
-			network = os.Args[3]
+		verifyChainID(s, network, *local)
+		if *timeout == 0 {
+			*timeout = defaultScanTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		if len(addresses) == 1 {
+			report := scanAddress(ctx, s, addresses[0], network, out)
+			os.Exit(reportExitCode(report, *failOn))
+		}
+		code := exitOK
+		for _, report := range scanAddresses(ctx, s, addresses, network, out) {
+			if c := reportExitCode(report, *failOn); c > code {
+				code = c
+			}
 		}
-		scanAddress(address, network)
+		os.Exit(code)
+	case "scan-tx":
+		if len(args) < 1 || len(args) > 2 {
+			fatalf("Usage: scanner scan-tx 0x<txhash> [network]")
+		}
+		if scanner.IsHexAddress(args[0]) {
+			fatalf("%s is an address, not a transaction hash: use scanner scan %s", args[0], args[0])
+		}
+		if !scanner.IsTxHash(args[0]) {
+			fatalf("Invalid transaction hash %q (want 0x and 64 hex characters)", args[0])
+		}
+		if *allChains || *networksList != "" {
+			fatalf("--all-chains and --networks do not apply to transactions, which exist on one network")
+		}
+		if *dryRun {
+			fatalf("--dry-run does not apply to scan-tx")
+		}
+		network := ""
+		if len(args) == 2 {
+			network = strings.ToLower(args[1])
+		}
+		network = selectNetwork(s, network, *chainID)
+		verifyChainID(s, network, *local)
+		if *timeout == 0 {
+			*timeout = defaultScanTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		report := scanTransaction(ctx, s, args[0], network, out)
+		code := riskExitCode(report.RiskLevel, *failOn)
+		for _, p := range report.Participants {
+			code = failedExitCode(code, p.Report)
+		}
+		os.Exit(code)
+	case "diff":
+		if len(args) < 1 || len(args) > 2 {
+			fatalf("Usage: scanner diff 0x... [network]")
+		}
+		network := ""
+		if len(args) == 2 {
+			network = strings.ToLower(args[1])
+		}
+		network = selectNetwork(s, network, *chainID)
+		verifyChainID(s, network, *local)
+		if *timeout == 0 {
+			*timeout = defaultScanTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		report := diffAddress(ctx, s, args[0], network, out)
+		os.Exit(riskExitCode(report.RiskLevel, *failOn))
+	case "compare":
+		if len(args) < 1 {
+			fatalf("Usage: scanner compare 0xaaa 0xbbb ... [network]")
+		}
+		if *allChains || *networksList != "" {
+			fatalf("--all-chains and --networks do not apply to compare, which ranks addresses on one network")
+		}
+		addresses, network := splitScanArgs(args)
+		addresses = uniqueAddresses(addresses)
+		if len(addresses) < 2 {
+			fatalf("compare needs at least two different addresses")
+		}
+		network = selectNetwork(s, network, *chainID)
+		if *dryRun {
+			runDryRun(s, len(addresses), []string{network}, *format)
+			os.Exit(exitOK)
+		}
+		verifyChainID(s, network, *local)
+		if *timeout == 0 {
+			*timeout = defaultScanTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		code := exitOK
+		for _, report := range compareAddresses(ctx, s, addresses, network, out).Reports {
+			if c := reportExitCode(report, *failOn); c > code {
+				code = c
+			}
+		}
+		os.Exit(code)
 	case "batch":
-		if len(os.Args) < 3 {
-			fmt.Println("❌ File required: scanner batch addresses.txt")
-			os.Exit(1)
+		filename := stdinInput
+		if len(args) > 0 {
+			filename = args[0]
+		} else if stdinIsTerminal() {
+			fatalf("File required: scanner batch addresses.txt (or - to read stdin)")
+		}
+		network := selectNetwork(s, "", *chainID)
+		input, err := inputFormat(filename, *inputFlag)
+		if err != nil {
+			fatalf("Invalid --input-format: %v", err)
+		}
+		if *dryRun {
+			data, err := readBatchInput(filename)
+			if err != nil {
+				fatalf("Cannot read file: %v", err)
+			}
+			parsed := parseBatchInput(string(data), input)
+			parsed.logSkipped()
+			runDryRun(s, len(parsed.addresses), []string{network}, *format)
+			os.Exit(exitOK)
+		}
+		verifyChainID(s, network, *local)
+		opts := batchOptions{
+			outputOptions: out,
+			network:       network,
+			resume:        *resume,
+			restart:       *restart,
+			timeout:       *timeout,
+			ordered:       *ordered,
+			since:         *since,
+			input:         input,
+		}
+		if *since < 0 {
+			fatalf("--since must be positive")
+		}
+		if out.format == formatNDJSON {
+			if *resume || *restart {
+				fatalf("--resume and --restart do not apply to --format ndjson, which keeps no checkpoint")
+			}
+			if *since > 0 {
+				fatalf("--since does not apply to --format ndjson")
+			}
+			worst, failed := streamBatch(s, filename, opts)
+			code := riskExitCode(worst, *failOn)
+			if failed > 0 && code < exitError {
+				code = exitError
+			}
+			os.Exit(code)
+		}
+		results := batchScan(s, filename, opts)
+		code := exitOK
+		for _, report := range results {
+			if c := reportExitCode(report, *failOn); c > code {
+				code = c
+			}
+		}
+		os.Exit(code)
+	case "calibrate":
+		if len(args) < 1 || len(args) > 2 {
+			fatalf("Usage: scanner calibrate labeled.csv [network]")
+		}
+		if *allChains || *networksList != "" {
+			fatalf("--all-chains and --networks do not apply to calibrate, which scores addresses on one network")
+		}
+		if *validate {
+			fatalf("--validate does not apply to calibrate")
+		}
+		network := ""
+		if len(args) == 2 {
+			network = strings.ToLower(args[1])
+		}
+		network = selectNetwork(s, network, *chainID)
+		addresses, scam := readCalibrationLabels(args[0])
+		if *dryRun {
+			runDryRun(s, len(addresses), []string{network}, *format)
+			os.Exit(exitOK)
+		}
+		verifyChainID(s, network, *local)
+		ctx := context.Background()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		calibrate(ctx, s, addresses, scam, network, out)
+		os.Exit(exitOK)
+	case "watch":
+		if len(args) < 1 || len(args) > 2 {
+			fatalf("Usage: scanner watch 0x... [network]")
+		}
+		network := ""
+		if len(args) == 2 {
+			network = strings.ToLower(args[1])
+		}
+		network = selectNetwork(s, network, *chainID)
+		verifyChainID(s, network, *local)
+		threshold := *alertOn
+		if threshold == "" {
+			threshold = *webhookThreshold
+		}
+		if scanner.RiskRank(threshold) < 0 {
+			fatalf("Invalid --alert-on %q (use %s)", threshold, strings.Join(scanner.RiskLevels, ", "))
+		}
+		if *debounce <= 0 {
+			fatalf("--debounce must be positive")
+		}
+		opts := scanner.WatchOptions{Threshold: threshold, Debounce: *debounce, URL: *wsURL}
+		os.Exit(runWatch(s, args[0], network, opts, out))
+	case "baseline":
+		ctx := context.Background()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+		runBaseline(ctx, s, args, *baselinePath, *chainID, *local)
+	case "tui":
+		if len(args) < 1 {
+			fatalf("File required: scanner tui addresses.txt")
+		}
+		network := selectNetwork(s, "", *chainID)
+		verifyChainID(s, network, *local)
+		input, err := inputFormat(args[0], *inputFlag)
+		if err != nil {
+			fatalf("Invalid --input-format: %v", err)
+		}
+		runTUI(s, args[0], network, input)
+	case "update-lists":
+		source := ""
+		if len(args) > 0 {
+			source = args[0]
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), defaultScanTimeout)
+		n, err := s.UpdateSanctions(ctx, source, scanner.DefaultSanctionsPath())
+		cancel()
+		if err != nil {
+			fatalf("Cannot update sanctions list: %v", err)
+		}
+		infof("✅ Saved %d sanctioned addresses to %s", n, scanner.DefaultSanctionsPath())
+		if cfg.LabelsURL == "" {
+			infof("ℹ️  Set labels_url in the config to also update the address labels")
+			break
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), defaultScanTimeout)
+		n, err = s.UpdateLabels(ctx, "", scanner.DefaultLabelsPath())
+		cancel()
+		if err != nil {
+			fatalf("Cannot update address labels: %v", err)
+		}
+		infof("✅ Saved %d address labels to %s", n, scanner.DefaultLabelsPath())
+	case "cache":
+		if len(args) < 1 || args[0] != "clear" {
+			fatalf("Usage: scanner cache clear")
+		}
+		if err := scanner.NewScanner(scanner.Config{CacheDir: scanner.DefaultCacheDir()}).ClearCache(); err != nil {
+			fatalf("Cannot clear cache: %v", err)
 		}
-		batchScan(os.Args[2])
+		infof("✅ Cache cleared")
+	case "doctor":
+		os.Exit(runDoctor(s, args, *format))
+	case "networks":
+		if err := writeNetworks(os.Stdout, s, *format); err != nil {
+			fatalf("Cannot list networks: %v", err)
+		}
+	case "schema":
+		os.Stdout.Write(scanner.ReportSchema())
+	case "verify":
+		os.Exit(runVerify(args))
 	case "version":
 		fmt.Printf("agent-reputation-scanner v%s\n", version)
 	default:
@@ -78,15 +622,111 @@ func main() {
 	}
 }
 
+// parseFlags parses flags that may appear before, after or between
+// positional arguments and returns the positional arguments.
+func parseFlags(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...)
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
 func printUsage() {
 	fmt.Println("🔍 Agent Reputation Scanner")
 	fmt.Println("============================")
 	fmt.Println("")
 	fmt.Println("Usage:")
 	fmt.Println("  scanner scan 0x... [network]  - Scan single address")
+	fmt.Println("  scanner scan 0xaaa 0xbbb ...  - Scan several addresses into one report")
+	fmt.Println("  scanner scan name.eth         - Resolve an ENS name and scan it")
+	fmt.Println("  scanner scan-tx 0x<txhash>    - Scan the sender, recipient and created contract of a transaction")
+	fmt.Println("  scanner diff 0x... [network]  - Scan and show changes since the last diff")
+	fmt.Println("  scanner compare 0xaaa 0xbbb ... - Rank addresses by score and show where their checks differ")
+	fmt.Println("  scanner watch 0x... [network] - Rescan on new activity and alert when risk rises")
 	fmt.Println("  scanner batch addresses.txt   - Batch scan from file")
+	fmt.Println("  ... | scanner batch -         - Batch scan addresses from stdin")
+	fmt.Println("  scanner tui addresses.txt     - Browse batch results interactively")
+	fmt.Println("  scanner calibrate labeled.csv - Measure how well scores match known scam/legit labels")
+	fmt.Println("  scanner baseline create 0x... - Record the current findings as accepted (update to refresh)")
+	fmt.Println("  scanner update-lists [url]    - Download the OFAC sanctions list and address labels")
+	fmt.Println("  scanner networks              - List supported networks and chain IDs")
+	fmt.Println("  scanner doctor [network ...]  - Test RPC and explorer connectivity and credentials")
+	fmt.Println("  scanner cache clear           - Remove cached check results")
+	fmt.Println("  scanner schema                - Print the JSON Schema of the report output")
+	fmt.Println("  scanner verify report.json    - Check that saved reports match their content hash")
 	fmt.Println("")
-	fmt.Println("Networks: ethereum, base")
+	fmt.Println("Flags:")
+	fmt.Println("  --format text|json|ndjson|csv|sarif|html|sqlite - Output format (scan: text, batch: json)")
+	fmt.Println("  -q, --quiet                   - Print only \"RISK_LEVEL SCORE ADDRESS\" per address")
+	fmt.Println("  --output path                 - Write the report/results to a file")
+	fmt.Println("  --validate                    - Check JSON output against the report schema")
+	fmt.Println("  --resume / --restart          - Continue or discard an interrupted batch")
+	fmt.Println("  --ordered                     - Stream ndjson batch results in input order")
+	fmt.Println("  --input-format text|csv       - Batch input: addresses, or address,label,expected_risk rows")
+	fmt.Println("  --since 24h                   - Only rescan batch addresses not scanned within this time")
+	fmt.Println("  --concurrency N               - Parallel workers for batch scans (default: 4)")
+	fmt.Println("  --denylist file.txt           - Extra denylist file (repeatable)")
+	fmt.Println("  --sanctions file.txt          - Extra sanctions list (repeatable)")
+	fmt.Println("  --bytecode-hashes file.txt    - Known scam bytecode hashes (repeatable)")
+	fmt.Println("  --signatures file.txt         - Extra function signatures (repeatable)")
+	fmt.Println("  --allowlist file.txt          - Trusted addresses that skip checks (repeatable)")
+	fmt.Println("  --labels file.csv             - Extra labels of well-known entities (repeatable)")
+	fmt.Println("  --max-retries N               - Retries for transient API errors (default: 3)")
+	fmt.Println("  --rate-limit N                - Explorer requests per second (default: 5)")
+	fmt.Println("  --retry-delay 500ms           - Base retry backoff delay")
+	fmt.Println("  --timeout 30s                 - Overall deadline (scan: 30s, batch: none)")
+	fmt.Println("  --request-timeout 15s         - Timeout for each RPC/explorer request")
+	fmt.Println("  --scan-timeout 2m             - Deadline for each address of a batch (0 for none)")
+	fmt.Println("  --config file.json            - Config file to use, JSON or YAML (.yaml, .yml)")
+	fmt.Println("  --no-cache                    - Bypass the on-disk result cache")
+	fmt.Println("  --max-age volume=1h,...       - Cache TTL per check (d suffix for days, infinite, 0 disables)")
+	fmt.Println("  --local                       - Scan a local anvil/hardhat node at 127.0.0.1:8545")
+	fmt.Println("  --fixtures file.json          - Scan offline against canned chain and explorer data")
+	fmt.Println("  --metrics-addr :9090          - Serve Prometheus metrics at /metrics")
+	fmt.Println("  --chain-id N                  - Select the network by chain ID (e.g. 8453)")
+	fmt.Println("  --all-chains                  - Scan one address on every network, with an aggregate risk")
+	fmt.Println("  --networks ethereum,base      - Scan one address on the listed networks, with an aggregate risk")
+	fmt.Println("  --source explorer|graph       - Account history from the explorer or a subgraph")
+	fmt.Println("  --lookalike-chars N           - Prefix/suffix length for address poisoning (default: 4)")
+	fmt.Println("  --profile compliance          - Preset checks and weights (compliance, dev, trading, full)")
+	fmt.Println("  --checks address,patterns,... - Run only the named checks")
+	fmt.Println("  --skip age,volume             - Leave out the named checks")
+	fmt.Println("  --redact                      - Mask addresses and strip URLs and keys from output for sharing")
+	fmt.Println("  --explain                     - Show how each check's weighted score produced the overall score")
+	fmt.Println("  --dry-run                     - List the checks that would run and estimate requests and time")
+	fmt.Println("  --deep                        - Also simulate honeypot swaps, fetch NFT metadata and approvals, vet counterparties, detect MEV bots")
+	fmt.Println("  --revocations                 - Recommend revoking unlimited approvals to risky spenders")
+	fmt.Println("  --trace-depth N               - Trace incoming funds N hops back to denylisted sources (default: 0, off)")
+	fmt.Println("  --trace-nodes N               - Addresses a trace may visit (default: 100)")
+	fmt.Println("  --trace-timeout 20s           - Time a trace may take (default: 20s)")
+	fmt.Println("  --upgrade-window 7d           - Flag proxy upgrades more recent than this (default: 7d)")
+	fmt.Println("  --abuse-reports URL           - Check addresses against a community abuse report API")
+	fmt.Println("  --ipfs-gateway URL            - Gateway for ipfs:// NFT metadata (default: https://ipfs.io)")
+	fmt.Println("  --strict-auth                 - Abort instead of degrading when credentials are rejected")
+	fmt.Println("  --strict                      - Treat warnings as failures, so uncertain scans fail")
+	fmt.Println("  --baseline file.json          - Mark accepted findings; exit 0 when there are no new ones")
+	fmt.Println("  --retry-low-confidence N      - Rescan checks hit by outages up to N times when confidence is low")
+	fmt.Println("  --rescan-delay 5s             - Wait before each of those rescans (default: 5s)")
+	fmt.Println("  --webhook URL                 - POST high/critical reports as signed JSON")
+	fmt.Println("  --alert-on <level>            - Lowest risk level watch alerts on (default: --webhook-threshold)")
+	fmt.Println("  --debounce 30s                - Quiet period after activity before watch rescans")
+	fmt.Println("  --ws-url URL                  - WebSocket endpoint for watch (default: <NETWORK>_WS_URL)")
+	fmt.Println("  --webhook-threshold <level>   - Lowest risk level sent to --webhook (default: high)")
+	fmt.Println("  --thresholds low=90,...       - Lowest score per risk level (default: low=90,medium=70,high=40)")
+	fmt.Println("  --min-coverage 0.6            - Share of checks needing data for a low risk level (-1 disables)")
+	fmt.Println("  --fail-on <level>             - Exit non-zero at or above risk level (default: high)")
+	fmt.Println("  -v, -vv                       - Log HTTP requests (debug) and payloads (trace) to stderr")
+	fmt.Println("")
+	fmt.Println("Networks: " + strings.Join(scanner.NewScanner(scanner.Config{}).NetworkNames(), ", "))
 	fmt.Println("")
 	fmt.Println("Checks performed:")
 	fmt.Println("  • Address format validation")
@@ -96,296 +736,312 @@ func printUsage() {
 	fmt.Println("  • Known malicious associations")
 }
 
-func scanAddress(address, network string) {
-	fmt.Printf("🔍 Scanning %s on %s...\n\n", address, network)
-
-	report := ReputationReport{
-		Address:   address,
-		Network:   network,
-		Timestamp: time.Now(),
-		Checks:    []CheckResult{},
-	}
-
-	// Check 1: Address format
-	report.Checks = append(report.Checks, checkAddressFormat(address))
-
-	// Check 2: Is contract
-	report.Checks = append(report.Checks, checkIsContract(address, network))
-
-	// Check 3: Contract verification
-	report.Checks = append(report.Checks, checkVerification(address, network))
+// outputOptions controls how reports are rendered and where they go
+type outputOptions struct {
+	format   string
+	output   string // file path; empty for stdout
+	validate bool   // check JSON output against the report schema
+	// redact is the --redact policy, nil without --redact
+	redact *scanner.RedactionPolicy
+	// explain adds the --explain score breakdown
+	explain bool
+}
 
-	// Check 4: Account age
-	report.Checks = append(report.Checks, checkAccountAge(address, network))
+func scanAddress(ctx context.Context, s *scanner.Scanner, address, network string, out outputOptions) scanner.ReputationReport {
+	infof("🔍 Scanning %s on %s...", address, network)
 
-	// Check 5: Transaction volume
-	report.Checks = append(report.Checks, checkTransactionVolume(address, network))
+	report, err := s.ScanContext(ctx, address, network)
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if report.Incomplete {
+		warnf("Scan deadline exceeded; some checks timed out (raise --timeout)")
+	}
+	reportSetupErrors(report)
 
-	// Check 6: Known patterns
-	report.Checks = append(report.Checks, checkKnownPatterns(address))
+	var buf bytes.Buffer
+	if err := renderReport(&buf, out.redactReport(report), out.format); err != nil {
+		fatalf("Cannot render report: %v", err)
+	}
+	if out.validate {
+		if err := scanner.ValidateReportJSON(buf.Bytes()); err != nil {
+			fatalf("Report does not match schema: %v", err)
+		}
+	}
+	out.explainReports(&buf, s, report)
 
-	// Calculate overall score
-	report.OverallScore = calculateOverallScore(report.Checks)
-	report.RiskLevel = determineRiskLevel(report.OverallScore)
-	report.Recommendations = generateRecommendations(report.Checks)
+	if out.output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return report
+	}
+	if err := writeOutput(out.output, buf.Bytes()); err != nil {
+		fatalf("Cannot write report: %v", err)
+	}
+	infof("✅ Report saved to %s", out.output)
+	return report
+}
 
-	// Print report
-	printReport(report)
+// splitScanArgs separates the addresses from the optional trailing network:
+// the last argument is the network unless it looks like an address or ENS
+// name. The network is empty when not given.
+func splitScanArgs(args []string) ([]string, string) {
+	last := args[len(args)-1]
+	if len(args) > 1 && !strings.HasPrefix(strings.ToLower(last), "0x") && !scanner.IsENSName(last) {
+		return args[:len(args)-1], strings.ToLower(last)
+	}
+	return args, ""
 }
 
-func checkAddressFormat(address string) CheckResult {
-	if !strings.HasPrefix(address, "0x") || len(address) != 42 {
-		return CheckResult{
-			Name:    "Address Format",
-			Status:  "fail",
-			Score:   0,
-			Details: "Invalid Ethereum address format",
+// selectNetwork returns the network named on the command line or selected
+// with --chain-id, defaulting to ethereum
+func selectNetwork(s *scanner.Scanner, network string, chainID int64) string {
+	if chainID != 0 {
+		byID, err := s.NetworkByChainID(chainID)
+		if err != nil {
+			fatalf("%v", err)
 		}
+		if network != "" && network != byID {
+			fatalf("--chain-id %d is %s, not %s", chainID, byID, network)
+		}
+		network = byID
+	}
+	if network == "" {
+		network = "ethereum"
 	}
-	return CheckResult{
-		Name:    "Address Format",
-		Status:  "pass",
-		Score:   100,
-		Details: "Valid checksummed address",
+	if _, err := s.Network(network); err != nil {
+		fatalf("%v", err)
 	}
+	return network
 }
 
-func checkIsContract(address, network string) CheckResult {
-	// This would normally query the blockchain
-	// For now, we'll return a placeholder that can be implemented
-	return CheckResult{
-		Name:    "Contract Check",
-		Status:  "warning",
-		Score:   50,
-		Details: "Requires RPC connection (see: cast code " + address + ")",
+// verifyChainID warns when the network's RPC endpoint serves another chain.
+// An unreachable endpoint is left for the checks to report, except for a
+// local node, which is most likely not started. Local forks often report
+// their own chain ID (hardhat uses 31337), so it is only shown.
+func verifyChainID(s *scanner.Scanner, network string, local bool) {
+	netCfg, _ := s.Network(network)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultScanTimeout)
+	defer cancel()
+	id, err := s.RPCChainID(ctx, network)
+	if local {
+		if err != nil {
+			fatalf("Cannot reach local node at %s: %v (start anvil or hardhat node, or set LOCAL_RPC_URL)", s.RPCURL(network), err)
+		}
+		infof("🧪 Local node at %s (chain ID %d); explorer-backed checks are skipped", s.RPCURL(network), id)
+		return
+	}
+	if err != nil {
+		logger.Debug("cannot verify chain ID", "network", network, "err", err)
+		return
+	}
+	if id != netCfg.ChainID {
+		warnf("RPC endpoint for %s reports chain ID %d, expected %d (check %s_RPC_URL or rpc_url)", network, id, netCfg.ChainID, strings.ToUpper(network))
 	}
 }
 
-func checkVerification(address, network string) CheckResult {
-	// Check Etherscan/BaseScan for verification status
-	apiKey := getAPIKey(network)
-	if apiKey == "" {
-		return CheckResult{
-			Name:    "Contract Verification",
-			Status:  "warning",
-			Score:   50,
-			Details: "No API key configured",
+// scanAddresses scans several addresses in parallel and writes a combined
+// report: a JSON array, or the batch summary followed by every report
+func scanAddresses(ctx context.Context, s *scanner.Scanner, addresses []string, network string, out outputOptions) []scanner.ReputationReport {
+	infof("🔍 Scanning %d addresses on %s...", len(addresses), network)
+
+	reports, errs := s.ScanBatchContext(ctx, addresses, network, nil)
+	for _, err := range errs {
+		warnf("%v", err)
+	}
+	if err := firstAuthError(errs); err != nil {
+		fatalf("%v", err)
+	}
+	reportSetupErrors(reports...)
+
+	var buf bytes.Buffer
+	var err error
+	if out.format == formatJSON {
+		err = writeJSON(&buf, out.redactReports(reports))
+	} else {
+		summary := summarize(reports, batchInput{})
+		err = renderBatch(&buf, out.redactBatch(batchOutput{Summary: summary, Results: reports}), out.format)
+	}
+	if err != nil {
+		fatalf("Cannot render report: %v", err)
+	}
+	if out.validate {
+		validate := scanner.ValidateReportsJSON
+		if out.format == formatNDJSON {
+			validate = validateNDJSON
+		}
+		if err := validate(buf.Bytes()); err != nil {
+			fatalf("Report does not match schema: %v", err)
 		}
 	}
+	out.explainReports(&buf, s, reports...)
 
-	// This would call Etherscan API
-	// For demo, return neutral
-	return CheckResult{
-		Name:    "Contract Verification",
-		Status:  "warning",
-		Score:   50,
-		Details: "API integration required (config in ~/.config/agent-reputation-scanner/)",
+	if out.output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return reports
+	}
+	if err := writeOutput(out.output, buf.Bytes()); err != nil {
+		fatalf("Cannot write report: %v", err)
 	}
+	infof("✅ Report saved to %s", out.output)
+	return reports
 }
 
-func checkAccountAge(address, network string) CheckResult {
-	// Check first transaction date
-	return CheckResult{
-		Name:    "Account Age",
-		Status:  "warning",
-		Score:   50,
-		Details: "Requires blockchain query (see: cast tx-count " + address + ")",
+// validateNDJSON checks each line of ndjson output against the report schema
+func validateNDJSON(data []byte) error {
+	for i, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		if err := scanner.ValidateReportJSON(line); err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
 	}
+	return nil
 }
 
-func checkTransactionVolume(address, network string) CheckResult {
-	return CheckResult{
-		Name:    "Transaction Volume",
-		Status:  "warning",
-		Score:   50,
-		Details: "Requires blockchain query",
+// serveMetrics exposes the scanner's Prometheus metrics on addr/metrics
+func serveMetrics(addr string, s *scanner.Scanner) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fatalf("Cannot listen on --metrics-addr: %v", err)
 	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		s.WriteMetrics(w)
+	})
+	go http.Serve(listener, mux)
+	infof("📈 Serving metrics on http://%s/metrics", listener.Addr())
 }
 
-func checkKnownPatterns(address string) CheckResult {
-	lowerAddr := strings.ToLower(address)
-	
-	for _, pattern := range maliciousPatterns {
-		if strings.Contains(lowerAddr, strings.ToLower(pattern)) {
-			return CheckResult{
-				Name:    "Known Patterns",
-				Status:  "fail",
-				Score:   0,
-				Details: "Matches known malicious pattern",
-			}
+// writeOutput writes data to path, creating parent directories as needed
+func writeOutput(path string, data []byte) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
 		}
 	}
-	
-	return CheckResult{
-		Name:    "Known Patterns",
-		Status:  "pass",
-		Score:   100,
-		Details: "No known malicious patterns detected",
-	}
+	return os.WriteFile(path, data, 0o644)
 }
 
-func calculateOverallScore(checks []CheckResult) int {
-	if len(checks) == 0 {
-		return 0
+// loadConfig reads the config file at path, or the default config file
+// if path is empty (in which case it may be absent)
+func loadConfig(path string) (scanner.Config, error) {
+	if path == "" {
+		return scanner.LoadConfig(scanner.DefaultConfigPath(), true)
 	}
-	
-	total := 0
-	for _, check := range checks {
-		total += check.Score
-	}
-	return total / len(checks)
+	return scanner.LoadConfig(path, false)
 }
 
-func determineRiskLevel(score int) string {
-	switch {
-	case score >= 90:
-		return "low"
-	case score >= 70:
-		return "medium"
-	case score >= 40:
-		return "high"
-	default:
-		return "critical"
+// loadLists loads the default allow-, deny-, sanctions, bytecode hash,
+// signature and label lists (if present) and any extra files
+func loadLists(s *scanner.Scanner, allowlists, denylists, sanctions, bytecodes, signatures, labels []string) error {
+	if _, err := os.Stat(scanner.DefaultAllowlistPath()); err == nil {
+		allowlists = append([]string{scanner.DefaultAllowlistPath()}, allowlists...)
 	}
-}
-
-func generateRecommendations(checks []CheckResult) []string {
-	recommendations := []string{}
-	
-	for _, check := range checks {
-		if check.Status == "fail" {
-			recommendations = append(recommendations, 
-				fmt.Sprintf("⚠️  %s: %s", check.Name, check.Details))
+	if _, err := os.Stat(scanner.DefaultDenylistPath()); err == nil {
+		denylists = append([]string{scanner.DefaultDenylistPath()}, denylists...)
+	}
+	if _, err := os.Stat(scanner.DefaultSanctionsPath()); err == nil {
+		sanctions = append([]string{scanner.DefaultSanctionsPath()}, sanctions...)
+	}
+	if _, err := os.Stat(scanner.DefaultBytecodeHashesPath()); err == nil {
+		bytecodes = append([]string{scanner.DefaultBytecodeHashesPath()}, bytecodes...)
+	}
+	if _, err := os.Stat(scanner.DefaultSignaturesPath()); err == nil {
+		signatures = append([]string{scanner.DefaultSignaturesPath()}, signatures...)
+	}
+	if _, err := os.Stat(scanner.DefaultLabelsPath()); err == nil {
+		labels = append([]string{scanner.DefaultLabelsPath()}, labels...)
+	}
+	for _, path := range allowlists {
+		if _, err := s.LoadAllowlist(path); err != nil {
+			return fmt.Errorf("cannot load allowlist: %w", err)
+		}
+	}
+	for _, path := range denylists {
+		if _, err := s.LoadDenylist(path); err != nil {
+			return fmt.Errorf("cannot load denylist: %w", err)
+		}
+	}
+	for _, path := range sanctions {
+		if _, err := s.LoadSanctions(path); err != nil {
+			return fmt.Errorf("cannot load sanctions list: %w", err)
+		}
+	}
+	for _, path := range bytecodes {
+		if _, err := s.LoadBytecodeHashes(path); err != nil {
+			return fmt.Errorf("cannot load bytecode hash list: %w", err)
 		}
 	}
-	
-	if len(recommendations) == 0 {
-		recommendations = append(recommendations, "✓ Address passed all automated checks")
-		recommendations = append(recommendations, "⚠️  Manual review still recommended for high-value transactions")
+	for _, path := range signatures {
+		if _, err := s.LoadSignatures(path); err != nil {
+			return fmt.Errorf("cannot load signature list: %w", err)
+		}
+	}
+	for _, path := range labels {
+		if _, err := s.LoadLabels(path); err != nil {
+			return fmt.Errorf("cannot load address labels: %w", err)
+		}
 	}
-	
-	return recommendations
+	return nil
 }
 
-func printReport(report ReputationReport) {
-	fmt.Println("═".repeat(60))
-	fmt.Printf("  REPUTATION REPORT\n")
-	fmt.Println("═".repeat(60))
-	fmt.Printf("Address: %s\n", report.Address)
-	fmt.Printf("Network: %s\n", report.Network)
-	fmt.Printf("Time:    %s\n", report.Timestamp.Format("2006-01-02 15:04:05"))
-	fmt.Println()
-	
-	// Score bar
-	fmt.Printf("Overall Score: %d/100\n", report.OverallScore)
-	fmt.Printf("Risk Level:    %s %s\n", getRiskEmoji(report.RiskLevel), strings.ToUpper(report.RiskLevel))
-	fmt.Println()
-	
-	fmt.Println("CHECKS:")
-	fmt.Println("─".repeat(60))
-	for _, check := range report.Checks {
-		statusIcon := "✓"
-		if check.Status == "warning" {
-			statusIcon = "⚠️"
-		} else if check.Status == "fail" {
-			statusIcon = "✗"
-		}
-		fmt.Printf("  %s %-25s [%d%%] %s\n", statusIcon, check.Name, check.Score, check.Status)
-		fmt.Printf("     └─ %s\n", check.Details)
-	}
-	
-	fmt.Println()
-	fmt.Println("RECOMMENDATIONS:")
-	fmt.Println("─".repeat(60))
-	for _, rec := range report.Recommendations {
-		fmt.Printf("  %s\n", rec)
-	}
-	
-	fmt.Println()
-	fmt.Println("═".repeat(60))
-	fmt.Println("⚠️  This is an automated assessment. Always conduct")
-	fmt.Println("   additional due diligence for high-value transactions.")
-	fmt.Println("═".repeat(60))
+// riskExitCode maps a risk level to the process exit code for a --fail-on threshold
+func riskExitCode(level, failOn string) int {
+	if failOn == "none" || scanner.RiskRank(level) < scanner.RiskRank(failOn) {
+		return exitOK
+	}
+	if level == "critical" {
+		return exitCritical
+	}
+	return exitRisk
 }
 
-func getRiskEmoji(level string) string {
-	switch level {
-	case "low":
-		return "🟢"
-	case "medium":
-		return "🟡"
-	case "high":
-		return "🟠"
-	case "critical":
-		return "🔴"
-	default:
-		return "⚪"
+// reportExitCode is riskExitCode for a report; with --baseline, reports
+// without new findings pass whatever their risk level. Failed scans have
+// no risk level and exit with exitError, so they never pass.
+func reportExitCode(report scanner.ReputationReport, failOn string) int {
+	if report.Baseline != nil && report.Baseline.New == 0 && report.Error == "" {
+		return exitOK
 	}
+	return failedExitCode(riskExitCode(report.RiskLevel, failOn), report)
 }
 
-func (s string) repeat(n int) string {
-	result := ""
-	for i := 0; i < n; i++ {
-		result += s
+// failedExitCode raises code to exitError when any of reports is a failed
+// scan, even with --fail-on none
+func failedExitCode(code int, reports ...scanner.ReputationReport) int {
+	for _, report := range reports {
+		if report.Error != "" && code < exitError {
+			return exitError
+		}
 	}
-	return result
+	return code
 }
 
-func getAPIKey(network string) string {
-	// Would load from config file
-	return os.Getenv(strings.ToUpper(network) + "_API_KEY")
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
 }
 
-func batchScan(filename string) {
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		fmt.Printf("❌ Cannot read file: %v\n", err)
-		os.Exit(1)
-	}
-
-	addresses := strings.Split(string(data), "\n")
-	fmt.Printf("🔍 Batch scanning %d addresses...\n\n", len(addresses))
-
-	results := []ReputationReport{}
-	for _, addr := range addresses {
-		addr = strings.TrimSpace(addr)
-		if addr == "" || !strings.HasPrefix(addr, "0x") {
-			continue
-		}
-		
-		report := quickScan(addr, "ethereum")
-		results = append(results, report)
-		
-		// Print summary line
-		fmt.Printf("%s... [%s] Score: %d/100 %s\n", 
-			addr[:20], 
-			report.RiskLevel,
-			report.OverallScore,
-			getRiskEmoji(report.RiskLevel))
-		
-		time.Sleep(200 * time.Millisecond) // Rate limiting
-	}
-
-	// Save results
-	output, _ := json.MarshalIndent(results, "", "  ")
-	os.WriteFile("reputation-results.json", output, 0644)
-	fmt.Printf("\n✅ Results saved to reputation-results.json\n")
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
-func quickScan(address, network string) ReputationReport {
-	report := ReputationReport{
-		Address:   address,
-		Network:   network,
-		Timestamp: time.Now(),
-		Checks: []CheckResult{
-			checkAddressFormat(address),
-			checkKnownPatterns(address),
-		},
-	}
-	
-	report.OverallScore = calculateOverallScore(report.Checks)
-	report.RiskLevel = determineRiskLevel(report.OverallScore)
-	report.Recommendations = generateRecommendations(report.Checks)
-	
-	return report
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"agent-reputation-scanner/scanner"
+)
+
+func TestReportExitCode(t *testing.T) {
+	accepted := &scanner.BaselineSummary{}
+	tests := []struct {
+		name   string
+		report scanner.ReputationReport
+		failOn string
+		want   int
+	}{
+		{"low", scanner.ReputationReport{RiskLevel: "low"}, "high", exitOK},
+		{"medium below threshold", scanner.ReputationReport{RiskLevel: "medium"}, "high", exitOK},
+		{"high", scanner.ReputationReport{RiskLevel: "high"}, "high", exitRisk},
+		{"critical", scanner.ReputationReport{RiskLevel: "critical"}, "high", exitCritical},
+		{"critical with fail-on none", scanner.ReputationReport{RiskLevel: "critical"}, "none", exitOK},
+		{"failed", scanner.ReputationReport{Error: "timed out"}, "high", exitError},
+		{"failed with fail-on none", scanner.ReputationReport{Error: "timed out"}, "none", exitError},
+		{"failed with baseline", scanner.ReputationReport{Error: "timed out", Baseline: accepted}, "high", exitError},
+		{"accepted by baseline", scanner.ReputationReport{RiskLevel: "critical", Baseline: accepted}, "high", exitOK},
+	}
+	for _, tt := range tests {
+		if got := reportExitCode(tt.report, tt.failOn); got != tt.want {
+			t.Errorf("%s: reportExitCode() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFailedExitCode(t *testing.T) {
+	ok := scanner.ReputationReport{RiskLevel: "low"}
+	failed := scanner.ReputationReport{Error: "HTTP 503"}
+	if got := failedExitCode(exitOK, ok, ok); got != exitOK {
+		t.Errorf("failedExitCode(no failures) = %d, want %d", got, exitOK)
+	}
+	if got := failedExitCode(exitOK, ok, failed); got != exitError {
+		t.Errorf("failedExitCode(a failure) = %d, want %d", got, exitError)
+	}
+	if got := failedExitCode(exitCritical, failed); got != exitCritical {
+		t.Errorf("failedExitCode(critical, a failure) = %d, want %d", got, exitCritical)
+	}
+}
+
+func TestDenylistedExitCode(t *testing.T) {
+	const drainer = "0x1111111111111111111111111111111111111111"
+	dir := t.TempDir()
+	t.Setenv("HOME", dir) // no default lists
+	path := filepath.Join(dir, "denylist.txt")
+	if err := os.WriteFile(path, []byte(drainer+" # drainer wallet\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := scanner.NewScanner(scanner.Config{Fixtures: &scanner.Fixtures{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}})
+	if err := loadLists(s, nil, []string{path}, nil, nil, nil, nil); err != nil {
+		t.Fatalf("loadLists() error = %v", err)
+	}
+	report, err := s.Scan(drainer, "ethereum")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if got := reportExitCode(report, "high"); got != exitCritical {
+		t.Errorf("reportExitCode() = %d for a denylisted address (risk %s, score %d), want %d", got, report.RiskLevel, report.OverallScore, exitCritical)
+	}
+}
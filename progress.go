@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"agent-reputation-scanner/internal/term"
+)
+
+// progressWidth is the number of cells in the batch progress bar
+const progressWidth = 24
+
+// progressRefresh is how often the progress line is redrawn between
+// results, so the elapsed rate and ETA keep moving during slow scans
+const progressRefresh = 500 * time.Millisecond
+
+// statusWriter writes log lines above a status line that is redrawn in
+// place, such as the batch progress bar
+type statusWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	status string
+}
+
+// stderr carries the log output, with the batch progress bar below it
+var stderr = &statusWriter{w: os.Stderr}
+
+// clearLine returns the cursor to the start of the line and erases it
+const clearLine = "\r\033[K"
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == "" {
+		return w.w.Write(p)
+	}
+	io.WriteString(w.w, clearLine)
+	n, err := w.w.Write(p)
+	io.WriteString(w.w, w.status)
+	return n, err
+}
+
+// setStatus replaces the status line; "" removes it
+func (w *statusWriter) setStatus(status string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.status == "" && status == "" {
+		return
+	}
+	w.status = status
+	io.WriteString(w.w, clearLine+status)
+}
+
+// batchProgress shows completed/total, the scan rate and the estimated time
+// remaining of a batch on the status line of stderr
+type batchProgress struct {
+	mu    sync.Mutex
+	total int
+	done  int
+	start time.Time
+	stop  chan struct{}
+	ended bool
+}
+
+// progressEnabled reports whether a batch should show a progress bar: not
+// with --quiet, and only when both stdout and stderr are terminals so that
+// redirected logs and results stay clean
+func progressEnabled(opts batchOptions) bool {
+	return opts.format != formatVerdict &&
+		term.IsTerminal(int(os.Stdout.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// startProgress draws the progress bar for a batch of total addresses and
+// keeps it up to date until finish; it returns nil when disabled
+func startProgress(total int, enabled bool) *batchProgress {
+	if !enabled || total == 0 {
+		return nil
+	}
+	p := &batchProgress{total: total, start: time.Now(), stop: make(chan struct{})}
+	p.draw()
+	go func() {
+		ticker := time.NewTicker(progressRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.draw()
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// advance counts one completed address
+func (p *batchProgress) advance() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.done++
+	p.mu.Unlock()
+	p.draw()
+}
+
+// finish removes the progress bar; later calls do nothing
+func (p *batchProgress) finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.ended {
+		p.ended = true
+		close(p.stop)
+		stderr.setStatus("")
+	}
+}
+
+func (p *batchProgress) draw() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.ended {
+		stderr.setStatus(p.line(time.Since(p.start)))
+	}
+}
+
+// line renders the progress after elapsed, e.g.
+// "[██████░░░░░░] 42/100  3.1/s  ETA 19s"
+func (p *batchProgress) line(elapsed time.Duration) string {
+	filled := p.done * progressWidth / p.total
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressWidth-filled)
+	line := fmt.Sprintf("[%s] %d/%d", bar, p.done, p.total)
+	if p.done == 0 || elapsed <= 0 {
+		return line + "  ETA --"
+	}
+	rate := float64(p.done) / elapsed.Seconds()
+	remaining := time.Duration(float64(p.total-p.done) / rate * float64(time.Second))
+	return line + fmt.Sprintf("  %.1f/s  ETA %s", rate, formatETA(remaining))
+}
+
+// formatETA rounds d for display: seconds under a minute, else minutes
+func formatETA(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
@@ -0,0 +1,121 @@
+package main
+
+import "agent-reputation-scanner/scanner"
+
+// The redact methods apply --redact to output about to be written. Status
+// lines on stderr are not redacted.
+
+func (o outputOptions) redactReport(report scanner.ReputationReport) scanner.ReputationReport {
+	if o.redact == nil {
+		return report
+	}
+	return o.redact.Report(report)
+}
+
+func (o outputOptions) redactReports(reports []scanner.ReputationReport) []scanner.ReputationReport {
+	if o.redact == nil {
+		return reports
+	}
+	redacted := make([]scanner.ReputationReport, len(reports))
+	for i, report := range reports {
+		redacted[i] = o.redact.Report(report)
+	}
+	return redacted
+}
+
+func (o outputOptions) redactTexts(texts []string) []string {
+	if o.redact == nil || texts == nil {
+		return texts
+	}
+	redacted := make([]string, len(texts))
+	for i, text := range texts {
+		redacted[i] = o.redact.Text(text)
+	}
+	return redacted
+}
+
+// redactDetails redacts check details copied out of a report
+func (o outputOptions) redactDetails(details string) string {
+	if o.redact.Omits("checks.details") {
+		return ""
+	}
+	return o.redact.Text(details)
+}
+
+func (o outputOptions) redactBatch(out batchOutput) batchOutput {
+	if o.redact == nil {
+		return out
+	}
+	out.Results = o.redactReports(out.Results)
+	out.Summary.CriticalAddresses = o.redactTexts(out.Summary.CriticalAddresses)
+	out.Summary.RiskMismatches = o.redactTexts(out.Summary.RiskMismatches)
+	invalid := make([]invalidLine, len(out.InvalidLines))
+	for i, l := range out.InvalidLines {
+		invalid[i] = invalidLine{Line: l.Line, Input: o.redact.Text(l.Input), Reason: o.redact.Text(l.Reason)}
+	}
+	out.InvalidLines = invalid
+	return out
+}
+
+func (o outputOptions) redactChains(report scanner.MultiChainReport) scanner.MultiChainReport {
+	if o.redact == nil {
+		return report
+	}
+	report.Address = o.redact.Text(report.Address)
+	if o.redact.Omits("ens_name") {
+		report.ENSName = ""
+	}
+	report.Chains = o.redactReports(report.Chains)
+	findings := make([]scanner.ChainFinding, len(report.Findings))
+	for i, f := range report.Findings {
+		f.Details = o.redactDetails(f.Details)
+		findings[i] = f
+	}
+	report.Findings = findings
+	report.Recommendations = o.redactTexts(report.Recommendations)
+	if o.redact.Omits("recommendations") {
+		report.Recommendations = []string{}
+	}
+	return report
+}
+
+func (o outputOptions) redactDiff(out diffOutput) diffOutput {
+	if o.redact == nil {
+		return out
+	}
+	out.Report = o.redactReport(out.Report)
+	if out.Diff == nil {
+		return out
+	}
+	diff := *out.Diff
+	diff.Address = o.redact.Text(diff.Address)
+	for _, changes := range []*[]scanner.CheckChange{&diff.ListHits, &diff.NewlyFailing, &diff.Recovered, &diff.Changed} {
+		redacted := make([]scanner.CheckChange, len(*changes))
+		for i, c := range *changes {
+			c.Details = o.redactDetails(c.Details)
+			redacted[i] = c
+		}
+		*changes = redacted
+	}
+	out.Diff = &diff
+	return out
+}
+
+func (o outputOptions) redactTransaction(report scanner.TransactionReport) scanner.TransactionReport {
+	if o.redact == nil {
+		return report
+	}
+	report.TxHash = scanner.MaskAddress(report.TxHash)
+	report.From = o.redact.Text(report.From)
+	report.To = o.redact.Text(report.To)
+	report.ContractCreated = o.redact.Text(report.ContractCreated)
+	report.RiskiestAddress = o.redact.Text(report.RiskiestAddress)
+	participants := make([]scanner.TxParticipant, len(report.Participants))
+	for i, p := range report.Participants {
+		p.Address = o.redact.Text(p.Address)
+		p.Report = o.redact.Report(p.Report)
+		participants[i] = p
+	}
+	report.Participants = participants
+	return report
+}
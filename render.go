@@ -0,0 +1,411 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// Supported output formats
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+	formatSARIF = "sarif"
+	formatHTML  = "html"
+	// formatNDJSON writes one compact JSON report per line
+	formatNDJSON = "ndjson"
+)
+
+var outputFormats = []string{formatText, formatJSON, formatNDJSON, formatCSV, formatSARIF, formatHTML, formatSQLite}
+
+// formatVerdict is the one-line-per-address text output of --quiet; it is
+// not selectable with --format
+const formatVerdict = "verdict"
+
+var csvHeader = []string{"address", "network", "check_name", "status", "score", "details", "confidence", "data_source", "reason_code"}
+
+func validateFormat(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(outputFormats, ", "))
+}
+
+// formatExtension returns the file extension used for batch output files
+func formatExtension(format string) string {
+	switch format {
+	case formatText, formatVerdict:
+		return "txt"
+	case formatSQLite:
+		return "db"
+	}
+	return format
+}
+
+// renderReport writes a single report to w in the requested format
+func renderReport(w io.Writer, report scanner.ReputationReport, format string) error {
+	switch format {
+	case formatText:
+		writeTextReport(w, report)
+		return nil
+	case formatJSON:
+		return writeJSON(w, report)
+	case formatNDJSON:
+		return json.NewEncoder(w).Encode(report)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(csvHeader)
+		writeCSVRows(cw, report)
+		cw.Flush()
+		return cw.Error()
+	case formatSARIF:
+		return writeSARIF(w, []scanner.ReputationReport{report}, nil)
+	case formatHTML:
+		return writeHTML(w, "Reputation Report", nil, []scanner.ReputationReport{report}, nil)
+	case formatVerdict:
+		writeVerdict(w, report)
+		return nil
+	default:
+		return validateFormat(format)
+	}
+}
+
+// batchOutput is the JSON document written for batch scans
+type batchOutput struct {
+	Summary      batchSummary               `json:"summary"`
+	Results      []scanner.ReputationReport `json:"results"`
+	InvalidLines []invalidLine              `json:"invalid_lines"`
+}
+
+// renderBatch writes the batch summary and results to w, followed by the
+// input lines that were rejected before scanning
+func renderBatch(w io.Writer, out batchOutput, format string) error {
+	if out.InvalidLines == nil {
+		out.InvalidLines = []invalidLine{}
+	}
+	reports, invalid := out.Results, out.InvalidLines
+	switch format {
+	case formatText:
+		writeTextSummary(w, out.Summary)
+		for _, report := range reports {
+			writeTextReport(w, report)
+			fmt.Fprintln(w)
+		}
+		if len(invalid) > 0 {
+			fmt.Fprintln(w, "INVALID INPUT LINES:")
+			fmt.Fprintln(w, strings.Repeat("─", 60))
+			for _, l := range invalid {
+				fmt.Fprintf(w, "  line %d: %s — %s\n", l.Line, l.Input, l.Reason)
+			}
+		}
+		return nil
+	case formatJSON:
+		return writeJSON(w, out)
+	case formatNDJSON:
+		// Reports only; the summary goes to the log
+		enc := json.NewEncoder(w)
+		for _, report := range reports {
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(csvHeader)
+		for _, report := range reports {
+			writeCSVRows(cw, report)
+		}
+		// Rejected lines use the reserved check name "invalid_input"
+		for _, l := range invalid {
+			cw.Write([]string{l.Input, "", "invalid_input", "error", "", fmt.Sprintf("line %d: %s", l.Line, l.Reason), "", ""})
+		}
+		cw.Flush()
+		return cw.Error()
+	case formatSARIF:
+		return writeSARIF(w, reports, invalid)
+	case formatHTML:
+		return writeHTML(w, "Batch Reputation Report", &out.Summary, reports, invalid)
+	case formatVerdict:
+		for _, report := range reports {
+			writeVerdict(w, report)
+		}
+		return nil
+	default:
+		return validateFormat(format)
+	}
+}
+
+func writeTextSummary(w io.Writer, summary batchSummary) {
+	fmt.Fprintln(w, "BATCH SUMMARY:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	fmt.Fprintf(w, "  Addresses:    %d (%d duplicates, %d invalid lines skipped)\n", summary.Total, summary.Duplicates, summary.InvalidLines)
+	for i := len(scanner.RiskLevels) - 1; i >= 0; i-- {
+		level := scanner.RiskLevels[i]
+		fmt.Fprintf(w, "  %s %-10s %d\n", getRiskEmoji(level), strings.ToUpper(level)+":", summary.RiskLevels[level])
+	}
+	if summary.Failed > 0 {
+		fmt.Fprintf(w, "  Failed:       %d\n", summary.Failed)
+	}
+	fmt.Fprintf(w, "  Mean score:   %.1f\n", summary.MeanScore)
+	fmt.Fprintf(w, "  Median score: %.1f\n", summary.MedianScore)
+	for _, addr := range summary.CriticalAddresses {
+		fmt.Fprintf(w, "  🔴 %s\n", addr)
+	}
+	if len(summary.RiskMismatches) > 0 {
+		fmt.Fprintf(w, "  Mismatched:   %d (not at the expected risk level)\n", len(summary.RiskMismatches))
+		for _, addr := range summary.RiskMismatches {
+			fmt.Fprintf(w, "  ≠ %s\n", addr)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	output, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", output)
+	return err
+}
+
+// networkInfo is the `scanner networks` listing entry
+type networkInfo struct {
+	Name     string `json:"name"`
+	ChainID  int64  `json:"chain_id"`
+	Explorer string `json:"explorer"`
+}
+
+// writeNetworks lists the supported networks as a table, JSON or CSV
+func writeNetworks(w io.Writer, s *scanner.Scanner, format string) error {
+	networks := []networkInfo{}
+	for _, name := range s.NetworkNames() {
+		netCfg, _ := s.Network(name)
+		networks = append(networks, networkInfo{Name: name, ChainID: netCfg.ChainID, Explorer: netCfg.ExplorerName})
+	}
+
+	switch format {
+	case formatText:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NETWORK\tCHAIN ID\tEXPLORER")
+		for _, n := range networks {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", n.Name, n.ChainID, n.Explorer)
+		}
+		return tw.Flush()
+	case formatJSON:
+		return writeJSON(w, networks)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"name", "chain_id", "explorer"})
+		for _, n := range networks {
+			cw.Write([]string{n.Name, strconv.FormatInt(n.ChainID, 10), n.Explorer})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return fmt.Errorf("format %q is not supported for networks", format)
+}
+
+func writeCSVRows(cw *csv.Writer, report scanner.ReputationReport) {
+	for _, check := range report.Checks {
+		cw.Write([]string{
+			report.Address,
+			report.Network,
+			check.Name,
+			check.Status,
+			strconv.Itoa(check.Score),
+			check.Details,
+			strconv.Itoa(check.Confidence),
+			check.DataSource,
+			check.ReasonCode,
+		})
+	}
+}
+
+// writeVerdict prints "RISK_LEVEL SCORE ADDRESS"; failed scans print
+// "error - ADDRESS"
+func writeVerdict(w io.Writer, report scanner.ReputationReport) {
+	if report.Error != "" {
+		fmt.Fprintf(w, "error - %s\n", report.Address)
+		return
+	}
+	fmt.Fprintf(w, "%s %d %s\n", report.RiskLevel, report.OverallScore, report.Address)
+}
+
+func writeTextReport(w io.Writer, report scanner.ReputationReport) {
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "  REPUTATION REPORT\n")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "Address: %s\n", report.Address)
+	if report.ENSName != "" {
+		fmt.Fprintf(w, "ENS:     %s\n", report.ENSName)
+	}
+	if report.AllowlistLabel != "" {
+		fmt.Fprintf(w, "Label:   %s\n", report.AllowlistLabel)
+	}
+	if report.Label != "" && report.Label != report.AllowlistLabel {
+		fmt.Fprintf(w, "Label:   %s\n", report.Label)
+	}
+	if report.SmartWallet != "" {
+		fmt.Fprintf(w, "Wallet:  %s\n", report.SmartWallet)
+	}
+	if report.Entity != nil {
+		fmt.Fprintf(w, "Entity:  %s (%s)\n", report.Entity.Name, report.Entity.Category)
+	}
+	if report.Token != nil {
+		fmt.Fprintf(w, "Token:   %s\n", report.Token)
+	}
+	fmt.Fprintf(w, "Network: %s\n", report.Network)
+	fmt.Fprintf(w, "Time:    %s\n", report.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(w)
+
+	// Score bar
+	fmt.Fprintf(w, "Overall Score: %d/100\n", report.OverallScore)
+	fmt.Fprintf(w, "Risk Level:    %s %s", getRiskEmoji(report.RiskLevel), strings.ToUpper(report.RiskLevel))
+	if report.InsufficientData {
+		fmt.Fprint(w, " (insufficient data for a confident low-risk verdict)")
+	}
+	if report.Strict {
+		fmt.Fprint(w, " (strict mode: warnings count as failures)")
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Confidence:    %d%%\n", report.Confidence)
+	if report.RiskMismatch {
+		fmt.Fprintf(w, "Expected:      %s %s (MISMATCH)\n", getRiskEmoji(report.ExpectedRisk), strings.ToUpper(report.ExpectedRisk))
+	}
+	if b := report.Baseline; b != nil {
+		if b.New == 0 {
+			fmt.Fprintf(w, "Baseline:      no new issues (%d accepted findings)\n", b.Accepted)
+		} else {
+			fmt.Fprintf(w, "Baseline:      %d new findings, %d accepted\n", b.New, b.Accepted)
+		}
+	}
+	if r := report.Rescan; r != nil {
+		improved := "no checks improved"
+		if len(r.Improved) > 0 {
+			improved = "improved " + strings.Join(r.Improved, ", ")
+		}
+		fmt.Fprintf(w, "Rescanned:     %d× after low confidence (%d%% → %d%%), %s\n", r.Attempts, r.InitialConfidence, report.Confidence, improved)
+	}
+	fmt.Fprintln(w)
+
+	if len(report.SetupErrors) > 0 {
+		fmt.Fprintln(w, "SETUP ERRORS (results below are incomplete):")
+		fmt.Fprintln(w, strings.Repeat("─", 60))
+		for _, setupErr := range report.SetupErrors {
+			fmt.Fprintf(w, "  ✗ %s\n", setupErr)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w, "CHECKS:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	for _, check := range report.Checks {
+		statusIcon := "✓"
+		if check.Status == "warning" {
+			statusIcon = "⚠️"
+		} else if check.Status == "fail" {
+			statusIcon = "✗"
+		}
+		provenance := ""
+		if check.DataSource != "" && check.DataSource != scanner.DataLive {
+			provenance = fmt.Sprintf(" (%s, %d%% confidence)", check.DataSource, check.Confidence)
+		}
+		if check.Baselined {
+			provenance += " (accepted in baseline)"
+		}
+		if check.NotApplicable {
+			provenance += " (not scored)"
+		}
+		fmt.Fprintf(w, "  %s %-25s [%d%%] %s%s\n", statusIcon, check.Name, check.Score, check.Status, provenance)
+		fmt.Fprintf(w, "     └─ %s\n", check.Details)
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "COULD NOT CHECK (fallback results above):")
+		fmt.Fprintln(w, strings.Repeat("─", 60))
+		for _, line := range checkErrorLines(report.Errors) {
+			fmt.Fprintf(w, "  ⚠️ %s\n", line)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "RECOMMENDATIONS:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	for _, rec := range report.Recommendations {
+		fmt.Fprintf(w, "  %s\n", rec)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintln(w, "⚠️  This is an automated assessment. Always conduct")
+	fmt.Fprintln(w, "   additional due diligence for high-value transactions.")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+}
+
+// checkErrorLines groups check errors by cause, e.g. "rate limited, retry
+// later: Account Age, Transaction Volume"
+func checkErrorLines(errs []scanner.CheckError) []string {
+	var codes []string
+	checks := map[string][]string{}
+	for _, e := range errs {
+		if _, ok := checks[e.Code]; !ok {
+			codes = append(codes, e.Code)
+		}
+		checks[e.Code] = append(checks[e.Code], e.Check)
+	}
+	lines := make([]string, len(codes))
+	for i, code := range codes {
+		lines[i] = describeErrorCode(code) + ": " + strings.Join(checks[code], ", ")
+	}
+	return lines
+}
+
+func describeErrorCode(code string) string {
+	switch code {
+	case scanner.ErrorNoAPIKey:
+		return "no explorer API key configured"
+	case scanner.ErrorNotConfigured:
+		return "data source not configured"
+	case scanner.ErrorAuthFailed:
+		return "credentials rejected (see setup errors)"
+	case scanner.ErrorRateLimited:
+		return "rate limited, retry later"
+	case scanner.ErrorRPCUnavailable:
+		return "RPC endpoint unavailable, retry later"
+	case scanner.ErrorExplorerUnavailable:
+		return "block explorer unavailable, retry later"
+	case scanner.ErrorNotFound:
+		return "data not found"
+	case scanner.ErrorTimeout:
+		return "timed out"
+	default:
+		return "failed"
+	}
+}
+
+func getRiskEmoji(level string) string {
+	switch level {
+	case "low":
+		return "🟢"
+	case "medium":
+		return "🟡"
+	case "high":
+		return "🟠"
+	case "critical":
+		return "🔴"
+	default:
+		return "⚪"
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"agent-reputation-scanner/scanner"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolInfoURI  = "https://github.com/arithmosquillsworth/agent-reputation-scanner"
+)
+
+// Rule descriptions for the built-in checks, in report order
+var sarifRules = []struct {
+	name        string
+	description string
+}{
+	{"Address Format", "Address is malformed or fails its EIP-55 checksum"},
+	{"Contract Check", "Address type could not be confirmed, or the contract appears to have self-destructed"},
+	{"Contract Verification", "Contract source code is not verified on the block explorer"},
+	{"Account Age", "Account is new or its age could not be determined"},
+	{"Contract Age", "Contract was deployed recently or its deployment time could not be determined"},
+	{"Transaction Volume", "Transaction activity is dormant, sparse or spam-like"},
+	{"Known Patterns", "Address is on a denylist or matches a known malicious pattern"},
+	{"Proxy Check", "Contract is an upgradeable proxy whose logic can change"},
+	{"Approval Risk", "Contract exposes token approval or ownership functions"},
+	{"Deployer Reputation", "Contract was deployed by a denylisted or freshly created account"},
+	{"Dangerous Opcodes", "Bytecode contains SELFDESTRUCT or DELEGATECALL"},
+	{"Mixer Exposure", "Address sent funds to or received funds from a mixer such as Tornado Cash"},
+	{"Sanctions", "Address is on the OFAC sanctions list"},
+	{"Address Poisoning", "Recent counterparties include lookalike addresses sharing the same prefix and suffix"},
+	{"Source Heuristics", "Verified source has reentrancy, tx.origin, unbounded loop or delegatecall red flags"},
+	{"Token Metadata", "Token symbol is empty or uses look-alike characters"},
+	{"Bytecode Match", "Contract bytecode matches a known scam contract"},
+	{"Method Profile", "Recent transactions grant approvals to many spenders"},
+	{"NFT Metadata", "NFT metadata is missing, unreachable or lures holders to a scam"},
+	{"Active Approvals", "Account grants unlimited token approvals to risky spenders"},
+	{"Abuse Reports", "Address is flagged by community abuse reports"},
+	{"Fund Tracing", "Address received funds traceable to a denylisted or sanctioned source"},
+	{"Smart Wallet", "Contract could not be checked for a known smart wallet implementation"},
+	{"Token Impersonation", "Token copies the symbol or name of a major token at a different address"},
+	{"Proxy Upgrades", "Proxy implementation was changed recently"},
+	{"Counterparty Reputation", "Many recent counterparties are denylisted or newly created"},
+	{"MEV Activity", "Account sends transactions in sandwich or MEV bot patterns"},
+	{"Honeypot Simulation", "Token can be bought but simulated sells revert or return far less than quoted"},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations,omitempty"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// ruleID turns a check name into a SARIF rule id, e.g. "Known Patterns" -> "KnownPatterns"
+func ruleID(checkName string) string {
+	return strings.ReplaceAll(checkName, " ", "")
+}
+
+// sarifLevel maps a check status to a SARIF level; minor warnings become notes
+func sarifLevel(check scanner.CheckResult) string {
+	switch {
+	case check.Status == "fail":
+		return "error"
+	case check.Score >= 80:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+// writeSARIF writes reports as a single SARIF run. Passing checks are
+// omitted; invalid batch input lines become tool notifications.
+func writeSARIF(w io.Writer, reports []scanner.ReputationReport, invalid []invalidLine) error {
+	driver := sarifDriver{
+		Name:           "agent-reputation-scanner",
+		Version:        version,
+		InformationURI: toolInfoURI,
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := map[string]int{}
+	addRule := func(name, description string) int {
+		if i, ok := ruleIndex[name]; ok {
+			return i
+		}
+		ruleIndex[name] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:               ruleID(name),
+			Name:             ruleID(name),
+			ShortDescription: sarifMessage{Text: description},
+		})
+		return ruleIndex[name]
+	}
+	for _, rule := range sarifRules {
+		addRule(rule.name, rule.description)
+	}
+
+	run := sarifRun{Tool: sarifTool{Driver: driver}, Results: []sarifResult{}}
+	for _, report := range reports {
+		for _, check := range report.Checks {
+			if check.Status == "pass" {
+				continue
+			}
+			properties := map[string]interface{}{
+				"network":       report.Network,
+				"score":         check.Score,
+				"overall_score": report.OverallScore,
+				"risk_level":    report.RiskLevel,
+				"confidence":    check.Confidence,
+				"data_source":   check.DataSource,
+			}
+			if check.ReasonCode != "" {
+				properties["reason_code"] = check.ReasonCode
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    ruleID(check.Name),
+				RuleIndex: addRule(check.Name, check.Name),
+				Level:     sarifLevel(check),
+				Message:   sarifMessage{Text: check.Details},
+				Locations: []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{
+					Name:               report.Address,
+					FullyQualifiedName: report.Network + "/" + report.Address,
+					Kind:               "address",
+				}}}},
+				Properties: properties,
+			})
+		}
+	}
+	run.Tool.Driver = driver
+
+	if len(invalid) > 0 {
+		invocation := sarifInvocation{ExecutionSuccessful: true}
+		for _, l := range invalid {
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:   "warning",
+				Message: sarifMessage{Text: fmt.Sprintf("line %d: %s skipped: %s", l.Line, l.Input, l.Reason)},
+			})
+		}
+		run.Invocations = []sarifInvocation{invocation}
+	}
+
+	return writeJSON(w, sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
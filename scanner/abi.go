@@ -0,0 +1,264 @@
+package scanner
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// decodeAddressWord decodes an ABI-encoded address return value.
+// It returns false for empty or zero results.
+func decodeAddressWord(result []byte) (string, bool) {
+	if len(result) < 32 {
+		return "", false
+	}
+	return slotAddress(result[:32])
+}
+
+// decodeStringResult decodes an ABI-encoded dynamic string return value
+func decodeStringResult(result []byte) (string, error) {
+	if len(result) == 0 {
+		return "", nil
+	}
+	if len(result) < 64 {
+		return "", fmt.Errorf("string result too short (%d bytes)", len(result))
+	}
+	// Bounds are compared before adding, so hostile words cannot overflow
+	offset := new(big.Int).SetBytes(result[:32])
+	if !offset.IsInt64() || offset.Int64() > int64(len(result)-32) {
+		return "", fmt.Errorf("invalid string offset")
+	}
+	start := offset.Int64()
+	length := new(big.Int).SetBytes(result[start : start+32])
+	if !length.IsInt64() || length.Int64() > int64(len(result))-start-32 {
+		return "", fmt.Errorf("invalid string length")
+	}
+	return string(result[start+32 : start+32+length.Int64()]), nil
+}
+
+// abiParam is a function input or output in a contract ABI
+type abiParam struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Components []abiParam `json:"components"`
+}
+
+// abiEntry is a single function, event or error in a contract ABI
+type abiEntry struct {
+	Type            string     `json:"type"`
+	Name            string     `json:"name"`
+	Inputs          []abiParam `json:"inputs"`
+	Outputs         []abiParam `json:"outputs"`
+	StateMutability string     `json:"stateMutability"`
+}
+
+// parseABI decodes a JSON contract ABI
+func parseABI(data string) ([]abiEntry, error) {
+	var entries []abiEntry
+	if err := json.Unmarshal([]byte(data), &entries); err != nil {
+		return nil, fmt.Errorf("invalid ABI: %w", err)
+	}
+	return entries, nil
+}
+
+// signature returns the canonical signature, e.g. "approve(address,uint256)"
+func (e abiEntry) signature() string {
+	return e.Name + "(" + joinParamTypes(e.Inputs) + ")"
+}
+
+func joinParamTypes(params []abiParam) string {
+	types := make([]string, len(params))
+	for i, p := range params {
+		types[i] = p.canonicalType()
+	}
+	return strings.Join(types, ",")
+}
+
+// canonicalType expands tuples into their component types
+func (p abiParam) canonicalType() string {
+	if strings.HasPrefix(p.Type, "tuple") {
+		return "(" + joinParamTypes(p.Components) + ")" + strings.TrimPrefix(p.Type, "tuple")
+	}
+	return p.Type
+}
+
+// selector returns the 4-byte function selector of a signature
+func selector(signature string) []byte {
+	return keccak256([]byte(signature))[:4]
+}
+
+// encodeCall ABI-encodes a call to signature with args. Supported argument
+// types are *big.Int (uint256), string (address) and []string (address[]).
+func encodeCall(signature string, args ...interface{}) string {
+	head := []byte{}
+	tail := []byte{}
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case *big.Int:
+			head = append(head, uintWord(v)...)
+		case string:
+			head = append(head, addressWord(v)...)
+		case []string:
+			offset := big.NewInt(int64(32*len(args) + len(tail)))
+			head = append(head, uintWord(offset)...)
+			tail = append(tail, uintWord(big.NewInt(int64(len(v))))...)
+			for _, addr := range v {
+				tail = append(tail, addressWord(addr)...)
+			}
+		default:
+			panic(fmt.Sprintf("encodeCall: unsupported argument type %T", arg))
+		}
+	}
+	data := append(selector(signature), append(head, tail...)...)
+	return "0x" + hex.EncodeToString(data)
+}
+
+// uintWord left-pads a non-negative integer to a 32-byte word
+func uintWord(v *big.Int) []byte {
+	word := make([]byte, 32)
+	v.FillBytes(word)
+	return word
+}
+
+// addressWord left-pads a hex address to a 32-byte word
+func addressWord(address string) []byte {
+	word := make([]byte, 32)
+	raw, _ := hex.DecodeString(strings.TrimPrefix(strings.ToLower(address), "0x"))
+	copy(word[32-len(raw):], raw)
+	return word
+}
+
+// decodeUintArray decodes an ABI-encoded uint256[] return value
+func decodeUintArray(result []byte) ([]*big.Int, error) {
+	if len(result) < 64 {
+		return nil, fmt.Errorf("array result too short (%d bytes)", len(result))
+	}
+	offset := new(big.Int).SetBytes(result[:32])
+	if !offset.IsInt64() || offset.Int64() > int64(len(result)-32) {
+		return nil, fmt.Errorf("invalid array offset")
+	}
+	start := offset.Int64()
+	length := new(big.Int).SetBytes(result[start : start+32])
+	if !length.IsInt64() || length.Int64() > (int64(len(result))-start-32)/32 {
+		return nil, fmt.Errorf("invalid array length")
+	}
+	values := make([]*big.Int, length.Int64())
+	for i := range values {
+		pos := start + 32 + 32*int64(i)
+		values[i] = new(big.Int).SetBytes(result[pos : pos+32])
+	}
+	return values, nil
+}
+
+// decodeABIValues decodes ABI-encoded values of the given types: addresses
+// as hex strings, integers as *big.Int, bool, fixed bytes and bytes as
+// []byte, string, and arrays of those as []interface{}. Tuples are not
+// supported.
+func decodeABIValues(params []abiParam, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(params))
+	for i, p := range params {
+		value, err := decodeABIValue(p.Type, data, 32*i)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i, p.Type, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// decodeABIValue decodes the value of type typ whose head word is at pos
+func decodeABIValue(typ string, data []byte, pos int) (interface{}, error) {
+	word := func(at int) ([]byte, error) {
+		if at < 0 || at+32 > len(data) {
+			return nil, fmt.Errorf("data too short")
+		}
+		return data[at : at+32], nil
+	}
+	// offset resolves the tail position of a dynamic value
+	offset := func() (int, error) {
+		head, err := word(pos)
+		if err != nil {
+			return 0, err
+		}
+		off := new(big.Int).SetBytes(head)
+		if !off.IsInt64() || off.Int64() > int64(len(data)) {
+			return 0, fmt.Errorf("invalid offset")
+		}
+		return int(off.Int64()), nil
+	}
+
+	switch {
+	case strings.HasSuffix(typ, "[]"):
+		start, err := offset()
+		if err != nil {
+			return nil, err
+		}
+		length, err := word(start)
+		if err != nil {
+			return nil, err
+		}
+		n := new(big.Int).SetBytes(length)
+		if !n.IsInt64() || n.Int64() > int64(len(data)/32) {
+			return nil, fmt.Errorf("invalid array length")
+		}
+		elem := strings.TrimSuffix(typ, "[]")
+		if elem == "string" || elem == "bytes" || strings.HasSuffix(elem, "]") || strings.HasPrefix(elem, "(") {
+			return nil, fmt.Errorf("unsupported array type")
+		}
+		items := make([]interface{}, n.Int64())
+		for i := range items {
+			if items[i], err = decodeABIValue(elem, data, start+32+32*i); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case typ == "string" || typ == "bytes":
+		start, err := offset()
+		if err != nil {
+			return nil, err
+		}
+		length, err := word(start)
+		if err != nil {
+			return nil, err
+		}
+		n := new(big.Int).SetBytes(length)
+		// word(start) succeeded, so the remaining length is not negative
+		if !n.IsInt64() || n.Int64() > int64(len(data)-start-32) {
+			return nil, fmt.Errorf("invalid length")
+		}
+		raw := data[start+32 : start+32+int(n.Int64())]
+		if typ == "string" {
+			return string(raw), nil
+		}
+		return append([]byte(nil), raw...), nil
+	}
+
+	head, err := word(pos)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case typ == "address":
+		return "0x" + hex.EncodeToString(head[12:]), nil
+	case typ == "bool":
+		return head[31] == 1, nil
+	case strings.HasPrefix(typ, "uint"):
+		return new(big.Int).SetBytes(head), nil
+	case strings.HasPrefix(typ, "int"):
+		v := new(big.Int).SetBytes(head)
+		if head[0]&0x80 != 0 {
+			v.Sub(v, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return v, nil
+	case strings.HasPrefix(typ, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(typ, "bytes"))
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type")
+		}
+		return append([]byte(nil), head[:size]...), nil
+	}
+	return nil, fmt.Errorf("unsupported type")
+}
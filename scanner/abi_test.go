@@ -0,0 +1,138 @@
+package scanner
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// abiWords concatenates 32-byte big-endian words
+func abiWords(words ...*big.Int) []byte {
+	var out []byte
+	for _, w := range words {
+		out = append(out, w.FillBytes(make([]byte, 32))...)
+	}
+	return out
+}
+
+func parseWord(s string) *big.Int {
+	w, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("bad hex word " + s)
+	}
+	return w
+}
+
+var maxInt64Word = parseWord("7fffffffffffffff")
+
+func TestDecodeStringResult(t *testing.T) {
+	text := abiWords(big.NewInt(32), big.NewInt(5))
+	text = append(text, []byte("Token")...)
+	text = append(text, make([]byte, 27)...)
+
+	tests := []struct {
+		name    string
+		result  []byte
+		want    string
+		wantErr bool
+	}{
+		{"empty", nil, "", false},
+		{"string", text, "Token", false},
+		{"too short", make([]byte, 40), "", true},
+		{"offset past end", abiWords(big.NewInt(64), big.NewInt(0)), "", true},
+		{"offset overflow", abiWords(maxInt64Word, big.NewInt(0)), "", true},
+		{"length past end", abiWords(big.NewInt(32), big.NewInt(33)), "", true},
+		// A length of 2^63-1 wrapped negative when added to the offset
+		{"length overflow", abiWords(big.NewInt(32), maxInt64Word), "", true},
+		{"length beyond int64", abiWords(big.NewInt(32), parseWord(strings.Repeat("ff", 32))), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeStringResult(tt.result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeStringResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodeStringResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeUintArray(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  []byte
+		want    []int64
+		wantErr bool
+	}{
+		{"empty array", abiWords(big.NewInt(32), big.NewInt(0)), []int64{}, false},
+		{"two values", abiWords(big.NewInt(32), big.NewInt(2), big.NewInt(7), big.NewInt(9)), []int64{7, 9}, false},
+		{"too short", make([]byte, 32), nil, true},
+		{"offset overflow", abiWords(maxInt64Word, big.NewInt(0)), nil, true},
+		{"length past end", abiWords(big.NewInt(32), big.NewInt(2), big.NewInt(7)), nil, true},
+		// 32 times a large length wrapped when added to the offset
+		{"length overflow", abiWords(big.NewInt(32), maxInt64Word), nil, true},
+		{"length times 32 overflow", abiWords(big.NewInt(32), parseWord("0800000000000000")), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeUintArray(tt.result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeUintArray() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("decodeUintArray() = %v, want %v", got, tt.want)
+			}
+			for i, v := range got {
+				if v.Int64() != tt.want[i] {
+					t.Errorf("decodeUintArray()[%d] = %v, want %d", i, v, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeABIValues(t *testing.T) {
+	addressWord := parseWord("dac17f958d2ee523a2206206994597c13d831ec7")
+	tail := append(abiWords(big.NewInt(3)), append([]byte("abc"), make([]byte, 29)...)...)
+
+	tests := []struct {
+		name    string
+		params  []string
+		data    []byte
+		want    []interface{}
+		wantErr bool
+	}{
+		{"address and uint", []string{"address", "uint256"}, abiWords(addressWord, big.NewInt(42)),
+			[]interface{}{"0xdac17f958d2ee523a2206206994597c13d831ec7", big.NewInt(42)}, false},
+		{"bool", []string{"bool"}, abiWords(big.NewInt(1)), []interface{}{true}, false},
+		{"string", []string{"string"}, append(abiWords(big.NewInt(32)), tail...), []interface{}{"abc"}, false},
+		{"bytes", []string{"bytes"}, append(abiWords(big.NewInt(32)), tail...), []interface{}{[]byte("abc")}, false},
+		{"uint array", []string{"uint256[]"}, abiWords(big.NewInt(32), big.NewInt(1), big.NewInt(5)),
+			[]interface{}{[]interface{}{big.NewInt(5)}}, false},
+		{"missing head", []string{"uint256", "uint256"}, abiWords(big.NewInt(1)), nil, true},
+		{"offset past end", []string{"string"}, abiWords(big.NewInt(96)), nil, true},
+		{"string length past end", []string{"string"}, abiWords(big.NewInt(32), big.NewInt(33)), nil, true},
+		// A length of 2^63-1 wrapped negative when added to the offset
+		{"string length overflow", []string{"string"}, abiWords(big.NewInt(32), maxInt64Word), nil, true},
+		{"bytes length overflow", []string{"bytes"}, abiWords(big.NewInt(32), maxInt64Word), nil, true},
+		{"array length overflow", []string{"uint256[]"}, abiWords(big.NewInt(32), maxInt64Word), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := make([]abiParam, len(tt.params))
+			for i, typ := range tt.params {
+				params[i] = abiParam{Type: typ}
+			}
+			got, err := decodeABIValues(params, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeABIValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("decodeABIValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package scanner
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"agent-reputation-scanner/internal/atomicfile"
+)
+
+// contractABI is a parsed verified ABI with its functions indexed by
+// selector, for decoding calldata and return data
+type contractABI struct {
+	entries []abiEntry
+	methods map[string]abiEntry // "0x"-prefixed hex selector -> function
+}
+
+// abiCacheEntry is the on-disk form of a fetched ABI. An empty ABI records
+// an unverified contract.
+type abiCacheEntry struct {
+	StoredAt time.Time `json:"stored_at"`
+	ABI      string    `json:"abi"`
+}
+
+func newContractABI(entries []abiEntry) *contractABI {
+	abi := &contractABI{entries: entries, methods: map[string]abiEntry{}}
+	for _, entry := range entries {
+		if entry.Type == "function" {
+			abi.methods[selectorHex(entry.signature())] = entry
+		}
+	}
+	return abi
+}
+
+// functions returns the ABI's functions in declaration order. A nil ABI,
+// as returned for unverified contracts, has none.
+func (a *contractABI) functions() []abiEntry {
+	if a == nil {
+		return nil
+	}
+	var functions []abiEntry
+	for _, entry := range a.entries {
+		if entry.Type == "function" {
+			functions = append(functions, entry)
+		}
+	}
+	return functions
+}
+
+// method returns the function with the given "0x"-prefixed selector
+func (a *contractABI) method(sel string) (abiEntry, bool) {
+	if a == nil {
+		return abiEntry{}, false
+	}
+	entry, ok := a.methods[strings.ToLower(sel)]
+	return entry, ok
+}
+
+// decodeInput decodes calldata into the called function and its arguments
+func (a *contractABI) decodeInput(calldata []byte) (abiEntry, []interface{}, error) {
+	if len(calldata) < 4 {
+		return abiEntry{}, nil, fmt.Errorf("calldata too short (%d bytes)", len(calldata))
+	}
+	entry, ok := a.method("0x" + hex.EncodeToString(calldata[:4]))
+	if !ok {
+		return abiEntry{}, nil, fmt.Errorf("unknown selector 0x%x", calldata[:4])
+	}
+	args, err := decodeABIValues(entry.Inputs, calldata[4:])
+	return entry, args, err
+}
+
+// getABI returns the verified ABI of a contract, or nil without an error if
+// the contract is not verified. ABIs are fetched with the source code once
+// per scanner and kept in the on-disk cache as long as Contract
+// Verification results.
+func (s *Scanner) getABI(ctx context.Context, address, network string) (*contractABI, error) {
+	key := chainCacheKey(address, network)
+	s.cacheMu.Lock()
+	abi, ok := s.abiCache[key]
+	s.cacheMu.Unlock()
+	if ok {
+		return abi, nil
+	}
+
+	raw, ok := s.readABICache(address, network)
+	if !ok {
+		if !s.hasExplorer(network) {
+			return nil, ErrNoAPIKey
+		}
+		source, err := s.getSourceCode(ctx, address, network)
+		if err != nil {
+			return nil, err
+		}
+		// Unverified contracts carry an explanation instead of an ABI
+		if source.SourceCode != "" {
+			raw = source.ABI
+		}
+		s.writeABICache(address, network, raw)
+	}
+
+	if raw != "" {
+		entries, err := parseABI(raw)
+		if err != nil {
+			return nil, err
+		}
+		abi = newContractABI(entries)
+	}
+	s.cacheMu.Lock()
+	s.abiCache[key] = abi
+	s.cacheMu.Unlock()
+	return abi, nil
+}
+
+func (s *Scanner) abiCachePath(address, network string) string {
+	return s.cachePath("ABI", address, network)
+}
+
+func (s *Scanner) readABICache(address, network string) (string, bool) {
+	if s.cfg.CacheDir == "" {
+		return "", false
+	}
+	data, err := os.ReadFile(s.abiCachePath(address, network))
+	if err != nil {
+		return "", false
+	}
+	var entry abiCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Since(entry.StoredAt) > s.cacheTTL("Contract Verification") {
+		return "", false
+	}
+	return entry.ABI, true
+}
+
+// writeABICache stores a fetched ABI; cache failures are not fatal
+func (s *Scanner) writeABICache(address, network, raw string) {
+	if s.cfg.CacheDir == "" {
+		return
+	}
+	data, err := json.Marshal(abiCacheEntry{StoredAt: time.Now(), ABI: raw})
+	if err != nil {
+		return
+	}
+	atomicfile.Write(s.abiCachePath(address, network), data)
+}
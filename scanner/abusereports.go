@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Report counts at which the Abuse Reports check escalates: a few reports
+// give a warning, many fail the check and make the report high risk
+const (
+	abuseWarnReports = 3
+	abuseFailReports = 10
+)
+
+var errNoAbuseReportsURL = fmt.Errorf("abuse report API %w", ErrNotConfigured)
+
+// abuseSummary is the counted-reports response of an abuse report API:
+//
+//	{"count": 12, "categories": {"phishing": 9, "rug pull": 3}}
+type abuseSummary struct {
+	Count      int            `json:"count"`
+	Categories map[string]int `json:"categories"`
+}
+
+// abuseReport is one entry of a report-list response, such as Chainabuse's
+// [{"scamCategory": "PHISHING", ...}, ...]
+type abuseReport struct {
+	Category     string `json:"category"`
+	ScamCategory string `json:"scamCategory"`
+}
+
+// abuseReportsHeader authenticates abuse report API requests with key
+func abuseReportsHeader(key string) http.Header {
+	if key == "" {
+		return nil
+	}
+	return http.Header{"Authorization": {"Bearer " + key}}
+}
+
+// abuseReportsURL fills in the address: "{address}" in the configured URL,
+// else an address query parameter
+func abuseReportsURL(base, address string) string {
+	address = strings.ToLower(address)
+	if strings.Contains(base, "{address}") {
+		return strings.ReplaceAll(base, "{address}", address)
+	}
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "address=" + url.QueryEscape(address)
+}
+
+// fetchAbuseReports returns the number of reports against address and the
+// reports per category. The API may answer with counts (abuseSummary) or
+// the list of reports (abuseReport); a 404 means no reports.
+func (s *Scanner) fetchAbuseReports(ctx context.Context, address string) (int, map[string]int, error) {
+	data, status, err := s.abuseReports.get(ctx, abuseReportsURL(s.cfg.AbuseReportsURL, address))
+	if err != nil {
+		return 0, nil, err
+	}
+	if status == http.StatusNotFound {
+		return 0, nil, nil
+	}
+	if status != http.StatusOK {
+		return 0, nil, fmt.Errorf("HTTP %d", status)
+	}
+
+	data = []byte(strings.TrimSpace(string(data)))
+	if len(data) > 0 && data[0] == '[' {
+		var reports []abuseReport
+		if err := json.Unmarshal(data, &reports); err != nil {
+			return 0, nil, fmt.Errorf("invalid response: %w", err)
+		}
+		categories := map[string]int{}
+		for _, r := range reports {
+			category := r.Category
+			if category == "" {
+				category = r.ScamCategory
+			}
+			if category != "" {
+				categories[strings.ToLower(strings.ReplaceAll(category, "_", " "))]++
+			}
+		}
+		return len(reports), categories, nil
+	}
+
+	var summary abuseSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return 0, nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return summary.Count, summary.Categories, nil
+}
+
+// topCategory returns the category with the most reports, the first
+// alphabetically on ties
+func topCategory(categories map[string]int) (string, int) {
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	top, count := "", 0
+	for _, name := range names {
+		if categories[name] > count {
+			top, count = name, categories[name]
+		}
+	}
+	return top, count
+}
+
+// checkAbuseReports looks the address up in a community abuse report API
+// and scales the verdict with the number of reports. The API being down
+// only degrades the check.
+func (s *Scanner) checkAbuseReports(ctx context.Context, address, network string) (CheckResult, error) {
+	if s.cfg.AbuseReportsURL == "" {
+		return CheckResult{
+			Name:    "Abuse Reports",
+			Status:  "warning",
+			Score:   50,
+			Details: "No abuse report API configured (set abuse_reports_url)",
+		}, errNoAbuseReportsURL
+	}
+
+	count, categories, err := s.fetchAbuseReports(ctx, address)
+	if err != nil {
+		return CheckResult{
+			Name:    "Abuse Reports",
+			Status:  "warning",
+			Score:   50,
+			Details: "Abuse report API unavailable: " + err.Error(),
+		}, err
+	}
+	if count == 0 {
+		return CheckResult{
+			Name:    "Abuse Reports",
+			Status:  "pass",
+			Score:   100,
+			Details: "No community abuse reports",
+		}, nil
+	}
+
+	details := fmt.Sprintf("%d community abuse reports", count)
+	if count == 1 {
+		details = "1 community abuse report"
+	}
+	if top, n := topCategory(categories); top != "" {
+		details += fmt.Sprintf(", top category %s (%d)", top, n)
+	}
+	switch {
+	case count >= abuseFailReports:
+		return CheckResult{
+			Name:       "Abuse Reports",
+			Status:     "fail",
+			Score:      10,
+			Details:    details,
+			Severity:   "high",
+			ReasonCode: ReasonAbuseReported,
+		}, nil
+	case count >= abuseWarnReports:
+		return CheckResult{
+			Name:       "Abuse Reports",
+			Status:     "warning",
+			Score:      30,
+			Details:    details,
+			ReasonCode: ReasonAbuseReported,
+		}, nil
+	}
+	return CheckResult{
+		Name:       "Abuse Reports",
+		Status:     "warning",
+		Score:      60,
+		Details:    details + " (few reports; may be unsubstantiated)",
+		ReasonCode: ReasonAbuseReported,
+	}, nil
+}
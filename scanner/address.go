@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTruncatedAddress is returned for an address shortened for display,
+// such as 0x1234…abcd, which cannot be scanned
+var ErrTruncatedAddress = errors.New("address is truncated")
+
+// IsTruncatedAddress reports whether input was shortened with an ellipsis,
+// as wallets and explorers display addresses
+func IsTruncatedAddress(input string) bool {
+	return strings.Contains(input, "…") || strings.Contains(input, "...")
+}
+
+// truncatedAddressError explains an ErrTruncatedAddress
+func truncatedAddressError(address string) error {
+	return fmt.Errorf("%w: %s was shortened for display; paste the full 42-character address", ErrTruncatedAddress, address)
+}
+
+// IsHexAddress reports whether address is 0x followed by 40 hex characters.
+func IsHexAddress(address string) bool {
+	if !strings.HasPrefix(address, "0x") || len(address) != 42 {
+		return false
+	}
+	_, err := hex.DecodeString(address[2:])
+	return err == nil
+}
+
+// IsValidChecksum reports whether address matches its EIP-55 mixed-case checksum.
+func IsValidChecksum(address string) bool {
+	return IsHexAddress(address) && address == ToChecksumAddress(address)
+}
+
+// ToChecksumAddress returns the EIP-55 checksummed form of a hex address.
+func ToChecksumAddress(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+
+	digest := keccak256([]byte(lower))
+
+	result := []byte(lower)
+	for i, c := range result {
+		if c < 'a' || c > 'f' {
+			continue
+		}
+		// Each hex character maps to one nibble of the hash
+		nibble := digest[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0x0f >= 8 {
+			result[i] = c - 32
+		}
+	}
+	return "0x" + string(result)
+}
+
+// IsTxHash reports whether input is a transaction hash: 0x followed by 64
+// hex characters, as opposed to the 40 of an address
+func IsTxHash(input string) bool {
+	if !strings.HasPrefix(input, "0x") || len(input) != 66 {
+		return false
+	}
+	_, err := hex.DecodeString(input[2:])
+	return err == nil
+}
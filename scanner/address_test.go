@@ -0,0 +1,111 @@
+package scanner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// The EIP-55 test vectors: all caps, all lowercase and mixed-case
+var eip55Addresses = []string{
+	"0x52908400098527886E0F7030069857D2E4169EE7",
+	"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+	"0xde709f2102306220921060314715629080e2fb77",
+	"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestToChecksumAddress(t *testing.T) {
+	for _, want := range eip55Addresses {
+		for _, in := range []string{want, strings.ToLower(want), "0x" + strings.ToUpper(want[2:])} {
+			if got := ToChecksumAddress(in); got != want {
+				t.Errorf("ToChecksumAddress(%s) = %s, want %s", in, got, want)
+			}
+		}
+	}
+}
+
+func TestIsValidChecksum(t *testing.T) {
+	for _, address := range eip55Addresses {
+		if !IsValidChecksum(address) {
+			t.Errorf("IsValidChecksum(%s) = false", address)
+		}
+	}
+	invalid := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", // last letter's case flipped
+		"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", // not the checksummed case
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA",
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	}
+	for _, address := range invalid {
+		if IsValidChecksum(address) {
+			t.Errorf("IsValidChecksum(%s) = true", address)
+		}
+	}
+}
+
+func TestIsHexAddress(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		{"0x0000000000000000000000000000000000000000", true},
+		{"0X5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+		{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed00", false},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAe", false},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed0", false},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", false},
+		{"0x", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsHexAddress(tt.in); got != tt.want {
+			t.Errorf("IsHexAddress(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsTxHash(t *testing.T) {
+	hash := "0x" + strings.Repeat("ab", 32)
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{hash, true},
+		{strings.ToUpper(hash[:10]) + hash[10:], false}, // 0X prefix
+		{hash[:65], false},
+		{hash + "0", false},
+		{hash[:65] + "z", false},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+	}
+	for _, tt := range tests {
+		if got := IsTxHash(tt.in); got != tt.want {
+			t.Errorf("IsTxHash(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsTruncatedAddress(t *testing.T) {
+	for _, in := range []string{"0x5aAe…BeAed", "0x5aAe...BeAed", "…"} {
+		if !IsTruncatedAddress(in) {
+			t.Errorf("IsTruncatedAddress(%q) = false", in)
+		}
+	}
+	for _, in := range []string{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "vitalik.eth", "0x5aAe..BeAed"} {
+		if IsTruncatedAddress(in) {
+			t.Errorf("IsTruncatedAddress(%q) = true", in)
+		}
+	}
+
+	s := NewScanner(Config{})
+	_, err := s.Scan("0x5aAe…BeAed", "ethereum")
+	if !errors.Is(err, ErrTruncatedAddress) {
+		t.Errorf("Scan(truncated) error = %v, want ErrTruncatedAddress", err)
+	}
+}
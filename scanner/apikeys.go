@@ -0,0 +1,173 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiKeyCooldown is how long an API key the explorer rate limited is
+// skipped when the response does not say when its quota resets
+const apiKeyCooldown = 10 * time.Second
+
+// configuredAPIKeys lists the explorer API keys of a network in the order
+// they are configured: Config.APIKeys and Config.ExtraAPIKeys, else the
+// comma-separated <NETWORK>_API_KEY. Local scans have none.
+func (s *Scanner) configuredAPIKeys(network string) []string {
+	if s.cfg.Local {
+		return nil
+	}
+	if key := s.cfg.APIKeys[network]; key != "" {
+		return uniqueURLs(append([]string{key}, s.cfg.ExtraAPIKeys[network]...))
+	}
+	return splitURLs(os.Getenv(strings.ToUpper(network) + "_API_KEY"))
+}
+
+// explorerRequestsPerSecond is the explorer rate limit: Config.RequestsPerSecond
+// for each API key of the network with the most keys, as rotating keys
+// spreads the requests over their quotas
+func (s *Scanner) explorerRequestsPerSecond() float64 {
+	keys := 1
+	for network := range s.networks {
+		if n := len(s.configuredAPIKeys(network)); n > keys {
+			keys = n
+		}
+	}
+	return s.cfg.RequestsPerSecond * float64(keys)
+}
+
+// nextAPIKey picks the key of the next explorer request on network, taking
+// the keys in turn and skipping those rate limited. When all of them are,
+// it returns the one freed first and how long that takes.
+func (s *Scanner) nextAPIKey(network string) (key string, wait time.Duration) {
+	keys := s.configuredAPIKeys(network)
+	switch len(keys) {
+	case 0:
+		return "", 0
+	case 1:
+		return keys[0], 0
+	}
+
+	now := time.Now()
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	start, first := s.apiKeyTurn[network], -1
+	for i := range keys {
+		j := (start + i) % len(keys)
+		until := s.apiKeyLimits[keys[j]]
+		if !until.After(now) {
+			s.apiKeyTurn[network] = j + 1
+			return keys[j], 0
+		}
+		if first < 0 || until.Before(s.apiKeyLimits[keys[first]]) {
+			first = j
+		}
+	}
+	s.apiKeyTurn[network] = first + 1
+	return keys[first], s.apiKeyLimits[keys[first]].Sub(now)
+}
+
+// recordAPIKeyQuota notes the quota an explorer response reports for the
+// key of rawURL and reports whether the key was rate limited. Limited keys,
+// and keys down to lowQuotaRemaining requests, are skipped until their
+// quota resets. Keys are logged by position only.
+func (s *Scanner) recordAPIKeyQuota(network, rawURL string, status int, header http.Header, body []byte) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	key := u.Query().Get("apikey")
+	if key == "" {
+		return false
+	}
+
+	now := time.Now()
+	limited := status == http.StatusTooManyRequests || rateLimitedBody(body)
+	var pause time.Duration
+	if limited {
+		pause = parseRetryAfter(header.Get("Retry-After"))
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if limited || err == nil && remaining <= lowQuotaRemaining {
+		if reset := parseRateLimitReset(header.Get("X-RateLimit-Reset"), now); reset > pause {
+			pause = reset
+		}
+		if pause <= 0 && limited {
+			pause = apiKeyCooldown
+		} else if pause <= 0 {
+			pause = defaultQuotaPause
+		}
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	if pause <= 0 {
+		delete(s.apiKeyLimits, key)
+		return false
+	}
+	s.apiKeyLimits[key] = now.Add(pause)
+	position := 0
+	for i, k := range s.configuredAPIKeys(network) {
+		if k == key {
+			position = i + 1
+		}
+	}
+	s.logger.Debug("api key rate limited, rotating", "network", network, "key", position, "remaining", header.Get("X-RateLimit-Remaining"), "pause", pause.Round(time.Millisecond))
+	return limited
+}
+
+// rateLimitedBody reports whether an explorer answered HTTP 200 with its
+// rate limit error, as Etherscan does
+func rateLimitedBody(body []byte) bool {
+	var resp explorerResponse
+	if json.Unmarshal(body, &resp) != nil || resp.Status != "0" || resp.Message != "NOTOK" {
+		return false
+	}
+	var reason string
+	json.Unmarshal(resp.Result, &reason)
+	return strings.Contains(strings.ToLower(reason), "rate limit")
+}
+
+// withAPIKey sets the apikey query parameter of rawURL
+func withAPIKey(rawURL, key string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	query.Set("apikey", key)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// explorerKeyedGet fetches rawURL from the network's explorer with its API
+// key. With several keys, each attempt takes the next key that is not rate
+// limited, and a limited key is retried on another at once.
+func (s *Scanner) explorerKeyedGet(ctx context.Context, network, rawURL string) ([]byte, int, error) {
+	keys := s.configuredAPIKeys(network)
+	switch len(keys) {
+	case 0:
+		return s.explorer.get(ctx, rawURL)
+	case 1:
+		return s.explorer.get(ctx, withAPIKey(rawURL, keys[0]))
+	}
+	next := func(ctx context.Context) (string, error) {
+		key, wait := s.nextAPIKey(network)
+		if wait > 0 {
+			s.logger.Debug("all api keys rate limited", "network", network, "keys", len(keys), "delay", wait.Round(time.Millisecond))
+			if err := sleepContext(ctx, wait); err != nil {
+				return "", err
+			}
+		}
+		return withAPIKey(rawURL, key), nil
+	}
+	observe := func(rawURL string, status int, header http.Header, body []byte) bool {
+		return s.recordAPIKeyQuota(network, rawURL, status, header, body)
+	}
+	return s.explorer.getRotating(ctx, next, observe)
+}
@@ -0,0 +1,133 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Canonical signatures of the high-risk functions, used to confirm ABI
+// entries and to find dispatcher selectors in unverified bytecode
+var highRiskSignatures = map[string]string{
+	"approve":           "approve(address,uint256)",
+	"setApprovalForAll": "setApprovalForAll(address,bool)",
+	"transferOwnership": "transferOwnership(address)",
+	"increaseAllowance": "increaseAllowance(address,uint256)",
+	"permit":            "permit(address,address,uint256,uint256,uint8,bytes32,bytes32)",
+}
+
+// Source snippets that grant or check for unlimited allowances
+var unlimitedAllowancePatterns = []string{
+	"type(uint256).max",
+	"uint256(-1)",
+	"uint(-1)",
+	"~uint256(0)",
+	"2**256 - 1",
+	"2 ** 256 - 1",
+}
+
+func (s *Scanner) checkApprovals(ctx context.Context, address, network string) (CheckResult, error) {
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Approval Risk",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+	if len(code) == 0 {
+		return CheckResult{
+			Name:          "Approval Risk",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (approval check skipped)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	// Without verified source, fall back to the dispatcher's selectors
+	var sourceErr error
+	if !s.hasExplorer(network) {
+		sourceErr = ErrNoAPIKey
+	} else if source, err := s.getSourceCode(ctx, address, network); err != nil {
+		sourceErr = err
+	} else if source.SourceCode != "" {
+		abi, err := s.getABI(ctx, address, network)
+		if err != nil {
+			return CheckResult{
+				Name:    "Approval Risk",
+				Status:  "warning",
+				Score:   50,
+				Details: err.Error(),
+			}, err
+		}
+		return approvalsFromSource(source, abi)
+	}
+
+	found := []string{}
+	for _, name := range highRiskFunctions {
+		if sig, ok := highRiskSignatures[name]; ok && hasSelector(code, selector(sig)) {
+			found = append(found, sig)
+		}
+	}
+	if len(found) == 0 {
+		return CheckResult{
+			Name:    "Approval Risk",
+			Status:  "pass",
+			Score:   100,
+			Details: "No approval functions found in bytecode",
+		}, sourceErr
+	}
+	return CheckResult{
+		Name:       "Approval Risk",
+		Status:     "warning",
+		Score:      70,
+		Details:    "Bytecode exposes " + strings.Join(found, ", ") + " (source unverified)",
+		ReasonCode: ReasonPrivilegedFunctions,
+	}, sourceErr
+}
+
+// approvalsFromSource confirms dangerous functions against the verified ABI
+// and looks for unlimited-allowance constants in the source
+func approvalsFromSource(source *sourceCodeResult, abi *contractABI) (CheckResult, error) {
+	dangerous := map[string]bool{}
+	for _, name := range highRiskFunctions {
+		dangerous[name] = true
+	}
+
+	found := []string{}
+	for _, entry := range abi.functions() {
+		if dangerous[entry.Name] {
+			found = append(found, entry.signature())
+		}
+	}
+
+	unlimited := []string{}
+	for _, pattern := range unlimitedAllowancePatterns {
+		if strings.Contains(source.SourceCode, pattern) {
+			unlimited = append(unlimited, pattern)
+		}
+	}
+
+	if len(found) == 0 {
+		return CheckResult{
+			Name:    "Approval Risk",
+			Status:  "pass",
+			Score:   100,
+			Details: "Verified ABI exposes no approval or ownership functions",
+		}, nil
+	}
+
+	details := "Exposes " + strings.Join(found, ", ")
+	if len(unlimited) > 0 {
+		details += fmt.Sprintf("; unlimited allowance patterns in source: %s", strings.Join(unlimited, ", "))
+	}
+	return CheckResult{
+		Name:       "Approval Risk",
+		Status:     "warning",
+		Score:      70,
+		Details:    details,
+		ReasonCode: ReasonPrivilegedFunctions,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// authError records credentials that an RPC endpoint ("rpc") or block
+// explorer ("explorer") rejected. It wraps ErrAuth.
+type authError struct {
+	network string
+	service string
+	reason  string // e.g. "Invalid API Key" or "HTTP 401"
+}
+
+func (e *authError) Error() string {
+	if e.service == "rpc" {
+		return fmt.Sprintf("%s RPC endpoint rejected the request: %s", e.network, e.reason)
+	}
+	return fmt.Sprintf("%s explorer rejected the API key: %s", e.network, e.reason)
+}
+
+func (e *authError) Unwrap() error {
+	return ErrAuth
+}
+
+// guidance names the settings that hold the rejected credentials
+func (e *authError) guidance() string {
+	if e.service == "rpc" {
+		return fmt.Sprintf("check networks.%s.rpc_url in the config file or %s_RPC_URL", e.network, strings.ToUpper(e.network))
+	}
+	return fmt.Sprintf("check networks.%s.api_key in the config file or %s_API_KEY", e.network, strings.ToUpper(e.network))
+}
+
+func isAuthStatus(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
+
+// recordAuthError remembers rejected credentials so later requests fail
+// fast and the report can name the problem
+func (s *Scanner) recordAuthError(network, service, reason string) error {
+	err := &authError{network: network, service: service, reason: reason}
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	if prev, ok := s.authErrors[network+":"+service]; ok {
+		return prev
+	}
+	s.authErrors[network+":"+service] = err
+	s.logger.Debug("credentials rejected", "network", network, "service", service, "reason", reason)
+	return err
+}
+
+// authError returns the first recorded rejection among services on network
+func (s *Scanner) authError(network string, services ...string) error {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	for _, service := range services {
+		if err, ok := s.authErrors[network+":"+service]; ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// setupErrors describes the rejected credentials on network for reports
+func (s *Scanner) setupErrors(network string) []string {
+	var errs []string
+	for _, service := range []string{"rpc", "explorer"} {
+		if err := s.authError(network, service); err != nil {
+			errs = append(errs, failedService(err).Details)
+		}
+	}
+	return errs
+}
+
+// Service statuses of a NetworkDiagnosis
+const (
+	ServiceOK            = "ok"
+	ServiceNotConfigured = "not_configured" // no explorer API key; checks degrade
+	ServiceAuthFailed    = "auth_failed"    // credentials rejected
+	ServiceError         = "error"          // unreachable or misbehaving
+)
+
+// ServiceStatus is the outcome of probing an RPC endpoint or explorer
+type ServiceStatus struct {
+	Status    string `json:"status"`
+	Details   string `json:"details"`
+	LatencyMS int64  `json:"latency_ms,omitempty"`
+}
+
+// NetworkDiagnosis reports whether a network's RPC endpoint and explorer
+// are reachable with the configured credentials
+type NetworkDiagnosis struct {
+	Network  string        `json:"network"`
+	RPCURL   string        `json:"rpc_url"` // API keys redacted
+	RPC      ServiceStatus `json:"rpc"`
+	Explorer ServiceStatus `json:"explorer"`
+}
+
+// OK reports whether nothing on the network is broken. A missing explorer
+// API key is not an error, only a degraded setup.
+func (d NetworkDiagnosis) OK() bool {
+	return d.RPC.Status == ServiceOK && d.Explorer.Status != ServiceAuthFailed && d.Explorer.Status != ServiceError
+}
+
+// Diagnose probes the network's RPC endpoint (chain ID and latest block) and
+// explorer (an account balance query) with the configured credentials
+func (s *Scanner) Diagnose(ctx context.Context, network string) (NetworkDiagnosis, error) {
+	network = strings.ToLower(network)
+	netCfg, err := s.Network(network)
+	if err != nil {
+		return NetworkDiagnosis{}, err
+	}
+	d := NetworkDiagnosis{Network: network}
+
+	start := time.Now()
+	id, err := s.RPCChainID(ctx, network)
+	var height uint64
+	if err == nil {
+		height, err = s.blockNumber(ctx, network)
+	}
+	switch {
+	case err != nil:
+		d.RPC = failedService(err)
+	case id != netCfg.ChainID && !s.cfg.Local:
+		d.RPC = ServiceStatus{Status: ServiceError, Details: fmt.Sprintf("chain ID %d, expected %d", id, netCfg.ChainID)}
+	default:
+		d.RPC = ServiceStatus{Status: ServiceOK, Details: fmt.Sprintf("chain ID %d, block %d", id, height), LatencyMS: time.Since(start).Milliseconds()}
+	}
+	// After the probe, so that it names the endpoint that answered
+	d.RPCURL = redactURL(s.getRPCURL(network))
+
+	if !s.hasExplorer(network) {
+		d.Explorer = ServiceStatus{Status: ServiceNotConfigured, Details: s.noExplorerDetails()}
+		return d, nil
+	}
+	params := url.Values{}
+	params.Set("module", "account")
+	params.Set("action", "balance")
+	params.Set("address", "0x0000000000000000000000000000000000000000")
+	params.Set("tag", "latest")
+	start = time.Now()
+	if _, err := s.explorerCall(ctx, network, params); err != nil {
+		d.Explorer = failedService(err)
+		return d, nil
+	}
+	details := "API key accepted by " + netCfg.ExplorerName
+	if s.getAPIKey(network) == "" {
+		details = netCfg.ExplorerName + " reachable, no API key needed"
+	}
+	d.Explorer = ServiceStatus{Status: ServiceOK, Details: details, LatencyMS: time.Since(start).Milliseconds()}
+	return d, nil
+}
+
+// blockNumber returns the latest block number of the RPC endpoint
+func (s *Scanner) blockNumber(ctx context.Context, network string) (uint64, error) {
+	result, err := s.rpcCall(ctx, network, "eth_blockNumber", nil)
+	if err != nil {
+		return 0, err
+	}
+	var hexBlock string
+	if err := json.Unmarshal(result, &hexBlock); err != nil {
+		return 0, fmt.Errorf("unexpected rpc result: %s", result)
+	}
+	block, err := strconv.ParseUint(strings.TrimPrefix(hexBlock, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid block number %q", hexBlock)
+	}
+	return block, nil
+}
+
+func failedService(err error) ServiceStatus {
+	var authErr *authError
+	if errors.As(err, &authErr) {
+		return ServiceStatus{Status: ServiceAuthFailed, Details: authErr.Error() + " (" + authErr.guidance() + ")"}
+	}
+	return ServiceStatus{Status: ServiceError, Details: err.Error()}
+}
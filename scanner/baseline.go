@@ -0,0 +1,150 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"agent-reputation-scanner/internal/atomicfile"
+)
+
+// BaselineVersion is the format version of baseline files
+const BaselineVersion = 1
+
+// BaselineFinding is an accepted warning or failure of one check
+type BaselineFinding struct {
+	Address    string `json:"address"`
+	Network    string `json:"network"`
+	Check      string `json:"check"`
+	Status     string `json:"status"`
+	ReasonCode string `json:"reason_code,omitempty"`
+}
+
+// Baseline is a set of accepted findings, keyed by FindingFingerprint.
+// Scans with Config.Baseline mark the findings it holds as accepted, so
+// only new ones stand out.
+type Baseline struct {
+	Version  int                        `json:"version"`
+	Updated  time.Time                  `json:"updated"`
+	Findings map[string]BaselineFinding `json:"findings"`
+}
+
+// BaselineSummary compares a report's findings with Config.Baseline
+type BaselineSummary struct {
+	New      int `json:"new"`      // findings the baseline does not hold
+	Accepted int `json:"accepted"` // findings the baseline holds, marked Baselined
+}
+
+// NewBaseline returns an empty baseline
+func NewBaseline() *Baseline {
+	return &Baseline{Version: BaselineVersion, Findings: map[string]BaselineFinding{}}
+}
+
+// LoadBaseline reads a baseline file written by Save
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := NewBaseline()
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %w", path, err)
+	}
+	if b.Version != BaselineVersion {
+		return nil, fmt.Errorf("invalid baseline %s: unsupported version %d (want %d)", path, b.Version, BaselineVersion)
+	}
+	if b.Findings == nil {
+		b.Findings = map[string]BaselineFinding{}
+	}
+	return b, nil
+}
+
+// Save writes the baseline to path atomically
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(path, append(data, '\n'))
+}
+
+// FindingFingerprint identifies a finding across scans: a hash of the
+// lowercase address, the network, the check name, the status and the
+// reason code. Details are left out, as counts and dates in them change
+// from scan to scan while the finding stays the same.
+func FindingFingerprint(address, network string, check CheckResult) string {
+	line := strings.Join([]string{strings.ToLower(address), network, check.Name, check.Status, check.ReasonCode}, "\t")
+	sum := sha256.Sum256([]byte(line))
+	return hex.EncodeToString(sum[:16])
+}
+
+// isFinding reports whether a result is a finding a baseline can hold:
+// a warning or failure on live or cached data. Fallbacks only say the
+// check could not run.
+func isFinding(check CheckResult) bool {
+	return check.Status != "pass" && check.DataSource != DataFallback
+}
+
+// Record replaces the accepted findings of the report's address and
+// network with the findings of the report. Failed scans are skipped.
+func (b *Baseline) Record(report ReputationReport) {
+	if report.Error != "" {
+		return
+	}
+	for fingerprint, finding := range b.Findings {
+		if strings.EqualFold(finding.Address, report.Address) && finding.Network == report.Network {
+			delete(b.Findings, fingerprint)
+		}
+	}
+	for _, check := range report.Checks {
+		if !isFinding(check) {
+			continue
+		}
+		b.Findings[FindingFingerprint(report.Address, report.Network, check)] = BaselineFinding{
+			Address:    report.Address,
+			Network:    report.Network,
+			Check:      check.Name,
+			Status:     check.Status,
+			ReasonCode: check.ReasonCode,
+		}
+	}
+	b.Updated = report.Timestamp
+}
+
+// Addresses returns the addresses the baseline holds findings of on
+// network, sorted
+func (b *Baseline) Addresses(network string) []string {
+	seen := map[string]bool{}
+	var addresses []string
+	for _, finding := range b.Findings {
+		if key := strings.ToLower(finding.Address); finding.Network == network && !seen[key] {
+			seen[key] = true
+			addresses = append(addresses, finding.Address)
+		}
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// apply marks the findings of report that the baseline holds as
+// Baselined and sets report.Baseline
+func (b *Baseline) apply(report *ReputationReport) {
+	summary := &BaselineSummary{}
+	for i, check := range report.Checks {
+		if !isFinding(check) {
+			continue
+		}
+		if _, ok := b.Findings[FindingFingerprint(report.Address, report.Network, check)]; ok {
+			report.Checks[i].Baselined = true
+			summary.Accepted++
+		} else {
+			summary.New++
+		}
+	}
+	report.Baseline = summary
+}
@@ -0,0 +1,159 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// blockscoutExplorer talks to Blockscout, which serves an
+// Etherscan-compatible API at /api with different field names, needs no
+// API key and has contract creation only in its REST API at /api/v2
+type blockscoutExplorer struct {
+	s *Scanner
+}
+
+// blockscoutSource is a getsourcecode entry; proxies are reported with
+// IsProxy and ImplementationAddress instead of Proxy and Implementation
+type blockscoutSource struct {
+	SourceCode            string `json:"SourceCode"`
+	ABI                   string `json:"ABI"`
+	ContractName          string `json:"ContractName"`
+	CompilerVersion       string `json:"CompilerVersion"`
+	IsProxy               string `json:"IsProxy"`
+	ImplementationAddress string `json:"ImplementationAddress"`
+}
+
+// blockscoutTx is a txlist or txlistinternal entry. Internal transactions
+// carry their hash as transactionHash.
+type blockscoutTx struct {
+	explorerTx
+	TransactionHash string `json:"transactionHash"`
+}
+
+// call queries the Etherscan-compatible API. Blockscout reports errors
+// such as an invalid address with status "0" and a null result.
+func (b blockscoutExplorer) call(ctx context.Context, network string, params url.Values) (json.RawMessage, error) {
+	resp, err := b.s.explorerQuery(ctx, network, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status == "0" && (len(resp.Result) == 0 || string(resp.Result) == "null") {
+		return nil, fmt.Errorf("blockscout: %s", resp.Message)
+	}
+	return resp.Result, nil
+}
+
+func (b blockscoutExplorer) sourceCode(ctx context.Context, address, network string) (*sourceCodeResult, error) {
+	params := url.Values{}
+	params.Set("module", "contract")
+	params.Set("action", "getsourcecode")
+	params.Set("address", address)
+
+	result, err := b.call(ctx, network, params)
+	if err != nil {
+		return nil, err
+	}
+	var entries []blockscoutSource
+	if err := json.Unmarshal(result, &entries); err != nil {
+		return nil, fmt.Errorf("unexpected getsourcecode result: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("empty getsourcecode result")
+	}
+	entry := entries[0]
+	source := &sourceCodeResult{
+		SourceCode:      entry.SourceCode,
+		ABI:             entry.ABI,
+		ContractName:    entry.ContractName,
+		CompilerVersion: entry.CompilerVersion,
+	}
+	if entry.IsProxy == "true" {
+		source.Proxy = "1"
+		source.Implementation = entry.ImplementationAddress
+	}
+	return source, nil
+}
+
+func (b blockscoutExplorer) accountTxs(ctx context.Context, action, address, network, sort string, pageSize int) ([]explorerTx, error) {
+	result, err := b.s.explorerCall(ctx, network, accountTxParams(action, address, sort, pageSize))
+	if err != nil {
+		return nil, err
+	}
+	// "No transactions found" comes with an empty list
+	if string(result) == "null" {
+		return nil, nil
+	}
+	var entries []blockscoutTx
+	if err := json.Unmarshal(result, &entries); err != nil {
+		return nil, fmt.Errorf("unexpected %s result: %w", action, err)
+	}
+	txs := make([]explorerTx, len(entries))
+	for i, entry := range entries {
+		txs[i] = entry.explorerTx
+		if txs[i].Hash == "" {
+			txs[i].Hash = entry.TransactionHash
+		}
+	}
+	return txs, nil
+}
+
+func (b blockscoutExplorer) logs(ctx context.Context, network, address, topic0, topic1 string, pageSize int) ([]explorerLog, error) {
+	result, err := b.s.explorerCall(ctx, network, logParams(address, topic0, topic1, pageSize))
+	if err != nil {
+		return nil, err
+	}
+	if string(result) == "null" {
+		return nil, nil
+	}
+	var logs []explorerLog
+	if err := json.Unmarshal(result, &logs); err != nil {
+		return nil, fmt.Errorf("unexpected getLogs result: %w", err)
+	}
+	// Blockscout ignores the page size and returns up to 1000 logs
+	if len(logs) > pageSize {
+		logs = logs[:pageSize]
+	}
+	return logs, nil
+}
+
+func (b blockscoutExplorer) contractCreation(ctx context.Context, address, network string) (*contractCreation, error) {
+	netCfg, err := b.s.Network(network)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimSuffix(strings.TrimSuffix(netCfg.ExplorerAPIURL, "/"), "/api") + "/api/v2/addresses/" + address
+	data, status, err := b.s.explorerFetch(ctx, network, endpoint)
+	// Addresses Blockscout never saw are not found
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var info struct {
+		IsContract      bool   `json:"is_contract"`
+		Creator         string `json:"creator_address_hash"`
+		CreationTx      string `json:"creation_transaction_hash"`
+		CreationTxOlder string `json:"creation_tx_hash"` // older Blockscout versions
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("unexpected blockscout address result: %w", err)
+	}
+	if !info.IsContract || info.Creator == "" {
+		return nil, nil
+	}
+	creation := &contractCreation{ContractAddress: address, ContractCreator: info.Creator, TxHash: info.CreationTx}
+	if creation.TxHash == "" {
+		creation.TxHash = info.CreationTxOlder
+	}
+	if creation.TxHash == "" {
+		return nil, errors.New("blockscout address result has no creation transaction")
+	}
+	return creation, nil
+}
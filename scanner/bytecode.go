@@ -0,0 +1,77 @@
+package scanner
+
+import "bytes"
+
+// EVM opcodes of interest
+const (
+	opPush1        = 0x60
+	opPush4        = 0x63
+	opPush32       = 0x7f
+	opDelegatecall = 0xf4
+	opSelfdestruct = 0xff
+)
+
+// forEachOpcode walks EVM bytecode, calling fn with each instruction's
+// offset, opcode and immediate PUSH data. Walking instructions rather than
+// raw bytes keeps constants embedded in PUSH data from being mistaken for
+// opcodes.
+func forEachOpcode(code []byte, fn func(pc int, op byte, data []byte)) {
+	for pc := 0; pc < len(code); pc++ {
+		op := code[pc]
+		if op >= opPush1 && op <= opPush32 {
+			size := int(op-opPush1) + 1
+			end := pc + 1 + size
+			if end > len(code) {
+				end = len(code)
+			}
+			fn(pc, op, code[pc+1:end])
+			pc += size
+			continue
+		}
+		fn(pc, op, nil)
+	}
+}
+
+// stripMetadata removes the CBOR metadata trailer solc appends to runtime
+// code. Its last two bytes hold the trailer length; the trailer is data,
+// so scanning it could turn up bogus opcodes.
+func stripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+	n := int(code[len(code)-2])<<8 | int(code[len(code)-1])
+	start := len(code) - 2 - n
+	if n == 0 || start < 0 {
+		return code
+	}
+	// CBOR map header with 1-5 entries (a1-a5)
+	if header := code[start]; header < 0xa1 || header > 0xa5 {
+		return code
+	}
+	return code[:start]
+}
+
+// opcodeOffsets returns the offsets of each instruction whose opcode is in ops
+func opcodeOffsets(code []byte, ops ...byte) map[byte][]int {
+	offsets := map[byte][]int{}
+	forEachOpcode(stripMetadata(code), func(pc int, op byte, _ []byte) {
+		for _, want := range ops {
+			if op == want {
+				offsets[op] = append(offsets[op], pc)
+			}
+		}
+	})
+	return offsets
+}
+
+// hasSelector reports whether bytecode pushes the 4-byte selector, which is
+// how Solidity dispatchers match functions
+func hasSelector(code, sel []byte) bool {
+	found := false
+	forEachOpcode(code, func(_ int, op byte, data []byte) {
+		if op == opPush4 && bytes.Equal(data, sel) {
+			found = true
+		}
+	})
+	return found
+}
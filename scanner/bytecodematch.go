@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BytecodeEntry is a known-malicious runtime bytecode
+type BytecodeEntry struct {
+	Family  string // scam family shown in reports
+	Source  string // list file the hash came from
+	Comment string
+}
+
+// DefaultBytecodeHashesPath is the bytecode hash list the CLI loads
+// automatically when it exists
+func DefaultBytecodeHashesPath() string {
+	return filepath.Join(DefaultConfigDir(), "bytecode-hashes.txt")
+}
+
+// LoadBytecodeHashes merges a list of known-malicious bytecode hashes into
+// the scanner and returns the number of entries loaded.
+//
+// Each line holds a 32-byte hash, as printed by the Bytecode Match check,
+// an optional family name and an optional comment:
+//
+//	0x5f3a...   Inferno Drainer   # redeployed weekly
+//
+// Entries without a family are attributed to the file name.
+func (s *Scanner) LoadBytecodeHashes(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	entries := map[string]BytecodeEntry{}
+	lines := bufio.NewScanner(file)
+	for lineNo := 1; lines.Scan(); lineNo++ {
+		line := strings.TrimSpace(lines.Text())
+		comment := ""
+		if i := strings.Index(line, "#"); i >= 0 {
+			comment = strings.TrimSpace(line[i+1:])
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		hash := strings.ToLower(fields[0])
+		if raw, err := hex.DecodeString(strings.TrimPrefix(hash, "0x")); err != nil || len(raw) != 32 || !strings.HasPrefix(hash, "0x") {
+			return 0, fmt.Errorf("%s:%d: invalid bytecode hash %q", path, lineNo, fields[0])
+		}
+		family := strings.Join(fields[1:], " ")
+		if family == "" {
+			family = filepath.Base(path)
+		}
+		entries[hash] = BytecodeEntry{Family: family, Source: filepath.Base(path), Comment: comment}
+	}
+	if err := lines.Err(); err != nil {
+		return 0, err
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	for hash, entry := range entries {
+		s.bytecodeHashes[hash] = entry
+	}
+	return len(entries), nil
+}
+
+// bytecodeHashes returns the keccak256 of the runtime code and of its
+// skeleton: the code without the metadata trailer and with every PUSH32
+// operand zeroed. solc embeds immutables as PUSH32, so copies deployed
+// with different constructor arguments, or recompiled with only comments
+// changed, share the skeleton hash.
+func bytecodeHashes(code []byte) (exact, skeleton string) {
+	stripped := append([]byte(nil), stripMetadata(code)...)
+	forEachOpcode(stripped, func(pc int, op byte, _ []byte) {
+		if op == opPush32 {
+			for i := pc + 1; i < len(stripped) && i <= pc+32; i++ {
+				stripped[i] = 0
+			}
+		}
+	})
+	return "0x" + hex.EncodeToString(keccak256(code)), "0x" + hex.EncodeToString(keccak256(stripped))
+}
+
+// lookupBytecode returns the list entry matching either hash
+func (s *Scanner) lookupBytecode(hashes ...string) (BytecodeEntry, bool, int) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	for _, hash := range hashes {
+		if entry, ok := s.bytecodeHashes[hash]; ok {
+			return entry, true, len(s.bytecodeHashes)
+		}
+	}
+	return BytecodeEntry{}, false, len(s.bytecodeHashes)
+}
+
+// checkBytecodeMatch compares the contract's bytecode hashes against the
+// known-malicious list, which catches verbatim redeployments of scam
+// contracts that address-based lists have not caught up with yet
+func (s *Scanner) checkBytecodeMatch(ctx context.Context, address, network string) (CheckResult, error) {
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Bytecode Match",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+	if len(code) == 0 {
+		return CheckResult{
+			Name:          "Bytecode Match",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (bytecode match not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	exact, skeleton := bytecodeHashes(code)
+	entry, ok, count := s.lookupBytecode(exact, skeleton)
+	if ok {
+		details := fmt.Sprintf("Bytecode matches known scam family %s (list: %s)", entry.Family, entry.Source)
+		if entry.Comment != "" {
+			details += ": " + entry.Comment
+		}
+		return CheckResult{
+			Name:       "Bytecode Match",
+			Status:     "fail",
+			Score:      0,
+			Severity:   "critical",
+			Details:    details,
+			ReasonCode: ReasonScamBytecode,
+		}, nil
+	}
+	if count == 0 {
+		// Nothing to compare with; the contract is unchecked, not cleared
+		return CheckResult{
+			Name:          "Bytecode Match",
+			Status:        "pass",
+			Score:         100,
+			Details:       fmt.Sprintf("No bytecode hash list loaded (not scored; skeleton hash %s)", skeleton),
+			Confidence:    50,
+			DataSource:    DataFallback,
+			NotApplicable: true,
+		}, nil
+	}
+	return CheckResult{
+		Name:    "Bytecode Match",
+		Status:  "pass",
+		Score:   100,
+		Details: fmt.Sprintf("No match among %d known scam bytecodes (skeleton hash %s)", count, skeleton),
+	}, nil
+}
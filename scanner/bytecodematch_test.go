@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckBytecodeMatch(t *testing.T) {
+	const contract = "0x4444444444444444444444444444444444444444"
+	code := []byte{0x60, 0x00, 0x33, 0xff}
+	s := fixtureScanner(t, Config{}, map[string]FixtureAccount{contract: {Code: "0x600033ff"}})
+
+	// Without a list the contract is not vouched for
+	got, err := s.checkBytecodeMatch(context.Background(), contract, "ethereum")
+	if err != nil {
+		t.Fatalf("checkBytecodeMatch() error = %v", err)
+	}
+	if !got.NotApplicable || s.calculateOverallScore([]CheckResult{got, {Name: "Contract Age", Score: 10}}) != 10 {
+		t.Errorf("no list: %s %d %q counts toward the score", got.Status, got.Score, got.Details)
+	}
+
+	_, skeleton := bytecodeHashes(code)
+	path := filepath.Join(t.TempDir(), "bytecode-hashes.txt")
+	if err := os.WriteFile(path, []byte(skeleton+" Inferno # drainer\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.LoadBytecodeHashes(path); err != nil {
+		t.Fatalf("LoadBytecodeHashes() error = %v", err)
+	}
+	got, _ = s.checkBytecodeMatch(context.Background(), contract, "ethereum")
+	if got.Status != "fail" || got.Severity != "critical" || got.NotApplicable || !strings.Contains(got.Details, "Inferno") {
+		t.Errorf("listed: %s, severity %q, %q, want a critical fail naming Inferno", got.Status, got.Severity, got.Details)
+	}
+
+	s = fixtureScanner(t, Config{}, map[string]FixtureAccount{contract: {Code: "0x6080604052"}})
+	if _, err := s.LoadBytecodeHashes(path); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.checkBytecodeMatch(context.Background(), contract, "ethereum")
+	if got.Status != "pass" || got.NotApplicable || !strings.HasPrefix(got.Details, "No match among 1 known scam bytecodes") {
+		t.Errorf("not listed: %s, not applicable %v, %q, want a scored pass", got.Status, got.NotApplicable, got.Details)
+	}
+}
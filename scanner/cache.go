@@ -0,0 +1,135 @@
+package scanner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"agent-reputation-scanner/internal/atomicfile"
+)
+
+// DefaultCacheTTL is how long cached check results stay valid, for checks
+// without an entry in DefaultCacheTTLs
+const DefaultCacheTTL = time.Hour
+
+type cacheEntry struct {
+	StoredAt time.Time   `json:"stored_at"`
+	Result   CheckResult `json:"result"`
+}
+
+// DefaultCacheDir is where the CLI keeps cached check results
+func DefaultCacheDir() string {
+	return filepath.Join(DefaultConfigDir(), "cache")
+}
+
+// cachedCheck returns a fresh cached result for the check if one exists,
+// otherwise runs it and stores the result when it completed without error.
+func (s *Scanner) cachedCheck(ctx context.Context, name, address, network string, run checkFunc) CheckResult {
+	ttl := s.cacheTTL(name)
+	if s.cfg.CacheDir == "" || ttl == 0 {
+		return s.runCheck(ctx, name, address, network, run)
+	}
+
+	path := s.cachePath(name, address, network)
+	if entry, ok := s.readCache(path, ttl); ok {
+		s.logger.Log(ctx, LevelTrace, "cache hit", "check", name, "address", address)
+		s.metrics.recordCache(true)
+		result := withProvenance(entry.Result, nil)
+		result.Confidence = cachedConfidence(result.Confidence, time.Since(entry.StoredAt), ttl)
+		result.DataSource = DataCache
+		return result
+	}
+	s.logger.Log(ctx, LevelTrace, "cache miss", "check", name, "address", address)
+	s.metrics.recordCache(false)
+
+	result, err := run(ctx, address, network)
+	result = withProvenance(result, err)
+	if err == nil {
+		s.writeCache(path, result)
+	}
+	return timedOut(ctx, name, result, err)
+}
+
+func (s *Scanner) cachePath(name, address, network string) string {
+	key := network + "|" + strings.ToLower(address) + "|" + name
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.cfg.CacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *Scanner) readCache(path string, ttl time.Duration) (cacheEntry, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	if time.Since(entry.StoredAt) > ttl {
+		return cacheEntry{}, false
+	}
+	// Warnings and failures cached before reason codes are run again
+	if entry.Result.Status != "pass" && entry.Result.ReasonCode == "" {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// writeCache stores a result atomically; cache failures are not fatal
+func (s *Scanner) writeCache(path string, result CheckResult) {
+	data, err := json.Marshal(cacheEntry{StoredAt: time.Now(), Result: result})
+	if err != nil {
+		return
+	}
+	atomicfile.Write(path, data)
+}
+
+// ClearCache removes all cached check results
+func (s *Scanner) ClearCache() error {
+	if s.cfg.CacheDir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(s.cfg.CacheDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") {
+			if err := os.Remove(filepath.Join(s.cfg.CacheDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Forget drops every cached result for address on network, on disk and in
+// memory, so the next scan queries the APIs again
+func (s *Scanner) Forget(address, network string) {
+	key := chainCacheKey(address, network)
+	s.cacheMu.Lock()
+	delete(s.codeCache, key)
+	delete(s.nonceCache, key)
+	delete(s.sourceCache, key)
+	delete(s.abiCache, key)
+	s.cacheMu.Unlock()
+	if graph, ok := s.source.(*graphSource); ok {
+		graph.forget(address, network)
+	}
+
+	if s.cfg.CacheDir == "" {
+		return
+	}
+	for name := range s.weights {
+		os.Remove(s.cachePath(name, address, network))
+	}
+	os.Remove(s.abiCachePath(address, network))
+}
@@ -0,0 +1,105 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheForever keeps cached results of a check until the cache is cleared
+const CacheForever = time.Duration(math.MaxInt64)
+
+// DefaultCacheTTLs keep results derived from deployed code or verified
+// source, which rarely change, longer than Config.CacheTTL. Checks without
+// an entry, such as Transaction Volume, use Config.CacheTTL.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"Contract Check":        24 * time.Hour,
+	"Contract Verification": 7 * 24 * time.Hour,
+	"Account Age":           24 * time.Hour,
+	"Contract Age":          30 * 24 * time.Hour,
+	"Approval Risk":         7 * 24 * time.Hour,
+	"Dangerous Opcodes":     7 * 24 * time.Hour,
+	"Source Heuristics":     7 * 24 * time.Hour,
+}
+
+// ParseCacheTTL parses a cache lifetime: a Go duration such as "90m", a
+// number of days such as "7d", or "infinite" for CacheForever. "0" turns
+// caching off.
+func ParseCacheTTL(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, "infinite") {
+		return CacheForever, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid cache TTL %q", s)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil || ttl < 0 {
+		return 0, fmt.Errorf("invalid cache TTL %q (use e.g. 90m, 7d or infinite)", s)
+	}
+	return ttl, nil
+}
+
+// ParseCacheTTLs applies a spec such as "verification=7d,volume=1h" to
+// base and returns the result. Checks are named by ID or full name.
+func ParseCacheTTLs(spec string, base map[string]time.Duration) (map[string]time.Duration, error) {
+	ttls := map[string]time.Duration{}
+	for check, ttl := range base {
+		ttls[check] = ttl
+	}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		check, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid cache TTL %q (want check=ttl)", part)
+		}
+		ttl, err := ParseCacheTTL(value)
+		if err != nil {
+			return nil, err
+		}
+		check = strings.TrimSpace(check)
+		if err := ValidateCheckNames([]string{check}); err != nil {
+			return nil, err
+		}
+		ttls[check] = ttl
+	}
+	return ttls, nil
+}
+
+// resolveCacheTTLs returns DefaultCacheTTLs with the overrides, keyed by
+// check ID or name, applied by check name
+func resolveCacheTTLs(overrides map[string]time.Duration) map[string]time.Duration {
+	ttls := map[string]time.Duration{}
+	for name, ttl := range DefaultCacheTTLs {
+		ttls[name] = ttl
+	}
+	if len(overrides) == 0 {
+		return ttls
+	}
+	names := allCheckNames()
+	for selector, ttl := range overrides {
+		for _, name := range names {
+			if selects(selector, name) {
+				ttls[name] = ttl
+			}
+		}
+	}
+	return ttls
+}
+
+// cacheTTL is how long cached results of the named check stay valid
+func (s *Scanner) cacheTTL(name string) time.Duration {
+	if ttl, ok := s.cacheTTLs[name]; ok {
+		return ttl
+	}
+	return s.cfg.CacheTTL
+}
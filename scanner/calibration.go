@@ -0,0 +1,240 @@
+package scanner
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClassifierMetrics is how well one way of flagging addresses as risky
+// separates known scams from legitimate addresses. Scams are the positives.
+type ClassifierMetrics struct {
+	Rule           string  `json:"rule"` // e.g. "score < 70" or "risk >= high"
+	TruePositives  int     `json:"true_positives"`
+	FalsePositives int     `json:"false_positives"`
+	FalseNegatives int     `json:"false_negatives"`
+	TrueNegatives  int     `json:"true_negatives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+	// FalsePositiveRate is the share of legitimate addresses flagged
+	FalsePositiveRate float64 `json:"false_positive_rate"`
+}
+
+// CheckDiscrimination is how well one check on its own tells scams from
+// legitimate addresses. Results without data are left out.
+type CheckDiscrimination struct {
+	Name         string  `json:"name"`
+	Scams        int     `json:"scams"` // labeled scams the check had data for
+	Legit        int     `json:"legit"`
+	ScamFlagged  float64 `json:"scam_flagged"` // share of those scams it warned or failed on
+	LegitFlagged float64 `json:"legit_flagged"`
+	AUC          float64 `json:"auc"` // of the check's score, like Calibration.AUC
+}
+
+// Calibration measures the scores of addresses with known labels against
+// those labels, for tuning weights and thresholds on real data
+type Calibration struct {
+	Network   string    `json:"network"`
+	Timestamp time.Time `json:"timestamp"`
+	Scams     int       `json:"scams"`
+	Legit     int       `json:"legit"`
+	Failed    int       `json:"failed"` // scans that returned an error, left out
+	// AUC is the chance that a random scam scores below a random legitimate
+	// address; 0.5 is no better than chance, 1 separates them perfectly
+	AUC float64 `json:"auc"`
+	// RiskLevels flags the addresses at or above each risk level, as
+	// reported with the current thresholds and severities
+	RiskLevels []ClassifierMetrics `json:"risk_levels"`
+	// Thresholds flags the addresses scoring below each multiple of ten
+	Thresholds []ClassifierMetrics `json:"thresholds"`
+	// BestThreshold is the score threshold of Thresholds with the highest F1
+	BestThreshold int                   `json:"best_threshold"`
+	Checks        []CheckDiscrimination `json:"checks"` // most discriminating first
+	// Missed are scams rated low risk; FalseAlarms are legitimate
+	// addresses rated high or critical
+	Missed      []string `json:"missed"`
+	FalseAlarms []string `json:"false_alarms"`
+}
+
+// Calibrate scores reports against scam, which says for each lowercase
+// address whether it is a known scam (true) or legitimate (false).
+// Reports of unlabeled addresses are ignored.
+func Calibrate(reports []ReputationReport, scam map[string]bool) Calibration {
+	c := Calibration{Missed: []string{}, FalseAlarms: []string{}}
+	var scores scoreHistogram
+	checks := map[string]*checkTally{}
+	var order []string
+	for _, report := range reports {
+		isScam, labeled := scam[strings.ToLower(report.Address)]
+		if !labeled {
+			continue
+		}
+		if c.Network == "" {
+			c.Network, c.Timestamp = report.Network, report.Timestamp
+		}
+		if report.Error != "" {
+			c.Failed++
+			continue
+		}
+		if isScam {
+			c.Scams++
+		} else {
+			c.Legit++
+		}
+		scores.add(report.OverallScore, isScam)
+		switch rank := RiskRank(report.RiskLevel); {
+		case isScam && rank == 0:
+			c.Missed = append(c.Missed, report.Address)
+		case !isScam && rank >= RiskRank("high"):
+			c.FalseAlarms = append(c.FalseAlarms, report.Address)
+		}
+
+		for _, check := range report.Checks {
+			if check.DataSource == DataFallback {
+				continue
+			}
+			tally, ok := checks[check.Name]
+			if !ok {
+				tally = &checkTally{}
+				checks[check.Name] = tally
+				order = append(order, check.Name)
+			}
+			tally.scores.add(check.Score, isScam)
+			if check.Status != "pass" {
+				tally.flagged[boolIndex(isScam)]++
+			}
+		}
+	}
+
+	c.AUC = scores.auc()
+	for _, level := range RiskLevels[1:] {
+		var m ClassifierMetrics
+		for _, report := range reports {
+			isScam, labeled := scam[strings.ToLower(report.Address)]
+			if labeled && report.Error == "" {
+				m.count(RiskRank(report.RiskLevel) >= RiskRank(level), isScam, 1)
+			}
+		}
+		m.Rule = "risk >= " + level
+		c.RiskLevels = append(c.RiskLevels, m.finish())
+	}
+	best := -1.0
+	for threshold := 10; threshold <= 100; threshold += 10 {
+		m := scores.below(threshold)
+		m.Rule = "score < " + strconv.Itoa(threshold)
+		c.Thresholds = append(c.Thresholds, m)
+		if m.F1 > best {
+			best, c.BestThreshold = m.F1, threshold
+		}
+	}
+
+	for _, name := range order {
+		tally := checks[name]
+		d := CheckDiscrimination{
+			Name:  name,
+			Scams: tally.scores.total(true),
+			Legit: tally.scores.total(false),
+			AUC:   tally.scores.auc(),
+		}
+		d.ScamFlagged = ratio(tally.flagged[1], d.Scams)
+		d.LegitFlagged = ratio(tally.flagged[0], d.Legit)
+		c.Checks = append(c.Checks, d)
+	}
+	sort.SliceStable(c.Checks, func(a, b int) bool { return c.Checks[a].AUC > c.Checks[b].AUC })
+	return c
+}
+
+// checkTally accumulates one check's results for Calibrate; flagged
+// counts non-pass results of legitimate addresses and of scams
+type checkTally struct {
+	scores  scoreHistogram
+	flagged [2]int
+}
+
+// scoreHistogram counts the scores 0-100 of legitimate addresses and of
+// scams, which is all that AUC and score thresholds need
+type scoreHistogram [2][101]int
+
+func (h *scoreHistogram) add(score int, isScam bool) {
+	h[boolIndex(isScam)][min(max(score, 0), 100)]++
+}
+
+func (h *scoreHistogram) total(isScam bool) int {
+	n := 0
+	for _, count := range h[boolIndex(isScam)] {
+		n += count
+	}
+	return n
+}
+
+// auc is the chance that a scam scores below a legitimate address, ties
+// counting half, or 0 without both
+func (h *scoreHistogram) auc() float64 {
+	scams, legit := h.total(true), h.total(false)
+	if scams == 0 || legit == 0 {
+		return 0
+	}
+	wins, legitAbove := 0.0, legit
+	for score := 0; score <= 100; score++ {
+		legitAbove -= h[0][score]
+		wins += float64(h[1][score]) * (float64(legitAbove) + float64(h[0][score])/2)
+	}
+	return round3(wins / float64(scams*legit))
+}
+
+// below flags the addresses scoring below threshold
+func (h *scoreHistogram) below(threshold int) ClassifierMetrics {
+	var m ClassifierMetrics
+	for score := 0; score <= 100; score++ {
+		m.count(score < threshold, false, h[0][score])
+		m.count(score < threshold, true, h[1][score])
+	}
+	return m.finish()
+}
+
+// count adds n addresses, flagged or not, to the counts
+func (m *ClassifierMetrics) count(flagged, isScam bool, n int) {
+	switch {
+	case flagged && isScam:
+		m.TruePositives += n
+	case flagged:
+		m.FalsePositives += n
+	case isScam:
+		m.FalseNegatives += n
+	default:
+		m.TrueNegatives += n
+	}
+}
+
+// finish fills in the rates from the counts
+func (m ClassifierMetrics) finish() ClassifierMetrics {
+	m.Precision = ratio(m.TruePositives, m.TruePositives+m.FalsePositives)
+	m.Recall = ratio(m.TruePositives, m.TruePositives+m.FalseNegatives)
+	if m.Precision+m.Recall > 0 {
+		m.F1 = round3(2 * m.Precision * m.Recall / (m.Precision + m.Recall))
+	}
+	m.FalsePositiveRate = ratio(m.FalsePositives, m.FalsePositives+m.TrueNegatives)
+	return m
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ratio is n/total to three decimals, or 0 when total is
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return round3(float64(n) / float64(total))
+}
+
+func round3(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
@@ -0,0 +1,459 @@
+package scanner
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Known risk indicators
+var (
+	// Known phishing/scam contract patterns
+	maliciousPatterns = []string{
+		"0x0000000000000000000000000000000000000000", // Burn address (context dependent)
+	}
+
+	// Known high-risk contract types (simplified)
+	highRiskFunctions = []string{
+		"approve",
+		"setApprovalForAll",
+		"increaseAllowance",
+		"permit",
+		"transferOwnership",
+		"selfdestruct",
+	}
+)
+
+// Storage slots holding a proxy's implementation address
+const (
+	eip1967ImplementationSlot = "0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"
+	eip1822ProxiableSlot      = "0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7"
+)
+
+// Account age thresholds (days) and the scores awarded below each
+const (
+	accountAgeNewDays    = 7
+	accountAgeRecentDays = 30
+	accountAgeMatureDays = 180
+
+	accountAgeNewScore    = 10
+	accountAgeRecentScore = 40
+	accountAgeMatureScore = 70
+)
+
+// Transaction volume heuristics
+const (
+	volumeDormantMax    = 2     // outgoing txs at or below this look new/dormant
+	volumeLowMax        = 9     // below the "established" level
+	volumeSpamMin       = 10000 // counts above this are checked for bursts
+	volumeBurstSample   = 50    // recent txs inspected for burst timing
+	volumeBurstDuration = time.Hour
+)
+
+// checkAddressFormat validates the address and its EIP-55 checksum. A
+// malformed address makes the report critical whatever the other checks
+// score, since funds sent to it are lost. A bad checksum on a well-known
+// address only warns: the hex digits are right, so the case was most
+// likely mangled in copying.
+func (s *Scanner) checkAddressFormat(_ context.Context, address, network string) (CheckResult, error) {
+	if !IsHexAddress(address) {
+		return CheckResult{
+			Name:       "Address Format",
+			Status:     "fail",
+			Score:      0,
+			Details:    fmt.Sprintf("Invalid Ethereum address format (want 0x and 40 hex characters, got %d characters)", len(address)),
+			ReasonCode: ReasonInvalidAddress,
+			Severity:   "critical",
+		}, nil
+	}
+
+	hexPart := address[2:]
+	if hexPart == strings.ToLower(hexPart) || hexPart == strings.ToUpper(hexPart) {
+		return CheckResult{
+			Name:       "Address Format",
+			Status:     "warning",
+			Score:      80,
+			Details:    "Valid format, but checksum could not be verified (address is not mixed-case)",
+			ReasonCode: ReasonUnchecksummedAddress,
+		}, nil
+	}
+
+	if !IsValidChecksum(address) {
+		if name := s.wellKnownName(address, network); name != "" {
+			return CheckResult{
+				Name:       "Address Format",
+				Status:     "warning",
+				Score:      50,
+				Details:    fmt.Sprintf("Same address as %s but with different letter case (expected %s); possible copy error, use the checksummed form", name, ToChecksumAddress(address)),
+				ReasonCode: ReasonMiscasedKnownAddress,
+			}, nil
+		}
+		return CheckResult{
+			Name:       "Address Format",
+			Status:     "fail",
+			Score:      0,
+			Details:    "Invalid EIP-55 checksum (expected " + ToChecksumAddress(address) + ")",
+			ReasonCode: ReasonInvalidAddress,
+		}, nil
+	}
+
+	return CheckResult{
+		Name:    "Address Format",
+		Status:  "pass",
+		Score:   100,
+		Details: "Valid checksummed address",
+	}, nil
+}
+
+// wellKnownName names address when it is one of the network's major tokens
+// or has an entity label, and returns "" otherwise
+func (s *Scanner) wellKnownName(address, network string) string {
+	if netCfg, err := s.Network(network); err == nil {
+		for symbol, token := range netCfg.MajorTokens {
+			if strings.EqualFold(token, address) {
+				return symbol
+			}
+		}
+	}
+	if label := s.entityLabel(address, network); label != nil {
+		return label.Name
+	}
+	return ""
+}
+
+func (s *Scanner) checkIsContract(ctx context.Context, address, network string) (CheckResult, error) {
+	contract, err := s.source.IsContract(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Contract Check",
+			Status:  "warning",
+			Score:   50,
+			Details: "Data source query failed: " + err.Error(),
+		}, err
+	}
+
+	if !contract {
+		return s.checkSelfDestructed(ctx, address, network)
+	}
+	details := "Smart contract"
+	if sizer, ok := s.source.(codeSizeSource); ok {
+		if size, err := sizer.codeSize(ctx, address, network); err == nil {
+			details = fmt.Sprintf("Smart contract, %d bytes of bytecode", size)
+		}
+	}
+	return CheckResult{
+		Name:    "Contract Check",
+		Status:  "pass",
+		Score:   100,
+		Details: details,
+	}, nil
+}
+
+// checkSelfDestructed tells an EOA from a contract that was deployed but has
+// no code now. The nonce cannot: EOAs have nonces too, and a destroyed
+// contract's is reset. The explorer's creation record can.
+func (s *Scanner) checkSelfDestructed(ctx context.Context, address, network string) (CheckResult, error) {
+	eoa := CheckResult{
+		Name:    "Contract Check",
+		Status:  "pass",
+		Score:   100,
+		Details: "Externally owned account",
+	}
+	if !s.hasExplorer(network) {
+		return eoa, nil
+	}
+	creation, err := s.getContractCreation(ctx, address, network)
+	if err != nil {
+		eoa.Details += " (self-destruct check failed: " + err.Error() + ")"
+		return eoa, err
+	}
+	if creation == nil {
+		return eoa, nil
+	}
+	details := "Contract appears to have self-destructed: it was deployed"
+	if IsHexAddress(creation.ContractCreator) {
+		details += " by " + ToChecksumAddress(creation.ContractCreator)
+	}
+	if creation.TxHash != "" {
+		details += " in tx " + creation.TxHash
+	}
+	return CheckResult{
+		Name:       "Contract Check",
+		Status:     "fail",
+		Score:      0,
+		Details:    details + " but has no code now",
+		Severity:   "high",
+		ReasonCode: ReasonSelfDestructed,
+	}, nil
+}
+
+func (s *Scanner) checkVerification(ctx context.Context, address, network string) (CheckResult, error) {
+	// Check the block explorer for verification status
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Contract Verification",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	// EOAs have no source to verify
+	if code, err := s.getCode(ctx, address, network); err == nil && len(code) == 0 {
+		return CheckResult{
+			Name:          "Contract Verification",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (verification not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	source, err := s.getSourceCode(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Contract Verification",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+
+	if source.SourceCode == "" {
+		return CheckResult{
+			Name:       "Contract Verification",
+			Status:     "fail",
+			Score:      0,
+			Details:    "Unverified contract — source code not published",
+			ReasonCode: ReasonUnverifiedSource,
+		}, nil
+	}
+	return CheckResult{
+		Name:    "Contract Verification",
+		Status:  "pass",
+		Score:   100,
+		Details: fmt.Sprintf("Verified as %s (%s)", source.ContractName, source.CompilerVersion),
+	}, nil
+}
+
+func (s *Scanner) checkAccountAge(ctx context.Context, address, network string) (CheckResult, error) {
+	first, ok, err := s.source.FirstTxTime(ctx, address, network)
+	if errors.Is(err, ErrNoAPIKey) {
+		// Without an explorer we can still tell whether the account was ever used
+		if count, err := s.source.TxCount(ctx, address, network); err == nil && count == 0 {
+			return CheckResult{
+				Name:       "Account Age",
+				Status:     "warning",
+				Score:      30,
+				Details:    "No transaction history found",
+				Confidence: 60,
+				DataSource: DataFallback,
+				ReasonCode: ReasonNoHistory,
+			}, nil
+		}
+		return CheckResult{
+			Name:    "Account Age",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+	if err != nil {
+		return CheckResult{
+			Name:    "Account Age",
+			Status:  "warning",
+			Score:   50,
+			Details: "Data source query failed: " + err.Error(),
+		}, err
+	}
+	if !ok {
+		return CheckResult{
+			Name:       "Account Age",
+			Status:     "warning",
+			Score:      30,
+			Details:    "No transaction history found",
+			ReasonCode: ReasonNoHistory,
+		}, nil
+	}
+
+	days := int(s.cfg.Clock().Sub(first).Hours() / 24)
+	details := fmt.Sprintf("First transaction %s (%d days ago)", first.Format("2006-01-02"), days)
+
+	switch {
+	case days < accountAgeNewDays:
+		return CheckResult{Name: "Account Age", Status: "fail", Score: accountAgeNewScore, Details: details, ReasonCode: ReasonFreshAccount}, nil
+	case days < accountAgeRecentDays:
+		return CheckResult{Name: "Account Age", Status: "warning", Score: accountAgeRecentScore, Details: details, ReasonCode: ReasonFreshAccount}, nil
+	case days < accountAgeMatureDays:
+		return CheckResult{Name: "Account Age", Status: "pass", Score: accountAgeMatureScore, Details: details}, nil
+	default:
+		return CheckResult{Name: "Account Age", Status: "pass", Score: 100, Details: details}, nil
+	}
+}
+
+func (s *Scanner) checkTransactionVolume(ctx context.Context, address, network string) (CheckResult, error) {
+	nonce, err := s.source.TxCount(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Transaction Volume",
+			Status:  "warning",
+			Score:   50,
+			Details: "Data source query failed: " + err.Error(),
+		}, err
+	}
+
+	details := fmt.Sprintf("%d outgoing transactions", nonce)
+	bursty := false
+
+	// Recent activity is optional; not every data source can list it
+	if recent, ok := s.source.(recentActivitySource); ok {
+		if times, err := recent.recentTxTimes(ctx, address, network, volumeBurstSample); err == nil && len(times) > 0 {
+			details += fmt.Sprintf(", last active %d days ago", int(s.cfg.Clock().Sub(times[0]).Hours()/24))
+			if len(times) == volumeBurstSample {
+				bursty = times[0].Sub(times[len(times)-1]) < volumeBurstDuration
+			}
+		}
+	}
+
+	switch {
+	case nonce <= volumeDormantMax:
+		return CheckResult{
+			Name:       "Transaction Volume",
+			Status:     "warning",
+			Score:      20,
+			Details:    details + " (new or dormant account)",
+			ReasonCode: ReasonLowActivity,
+		}, nil
+	case nonce >= volumeSpamMin && bursty:
+		return CheckResult{
+			Name:       "Transaction Volume",
+			Status:     "warning",
+			Score:      40,
+			Details:    details + fmt.Sprintf(" (%d txs within %s — possible bot/spam)", volumeBurstSample, volumeBurstDuration),
+			ReasonCode: ReasonBurstActivity,
+		}, nil
+	case nonce <= volumeLowMax:
+		return CheckResult{
+			Name:    "Transaction Volume",
+			Status:  "pass",
+			Score:   70,
+			Details: details,
+		}, nil
+	default:
+		return CheckResult{
+			Name:    "Transaction Volume",
+			Status:  "pass",
+			Score:   100,
+			Details: details,
+		}, nil
+	}
+}
+
+func (s *Scanner) checkProxy(ctx context.Context, address, network string) (CheckResult, error) {
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Proxy Check",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+	if len(code) == 0 {
+		return CheckResult{
+			Name:          "Proxy Check",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (proxy check skipped)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	slots := []struct{ standard, slot string }{
+		{"EIP-1967", eip1967ImplementationSlot},
+		{"EIP-1822", eip1822ProxiableSlot},
+	}
+	for _, slot := range slots {
+		value, err := s.getStorageAt(ctx, address, slot.slot, network)
+		if err != nil {
+			return CheckResult{
+				Name:    "Proxy Check",
+				Status:  "warning",
+				Score:   50,
+				Details: "RPC query failed: " + err.Error(),
+			}, err
+		}
+		if implementation, ok := slotAddress(value); ok {
+			return CheckResult{
+				Name:       "Proxy Check",
+				Status:     "warning",
+				Score:      60,
+				Details:    fmt.Sprintf("Upgradeable %s proxy, implementation %s (scan it too)", slot.standard, implementation),
+				ReasonCode: ReasonProxyUpgradeable,
+			}, nil
+		}
+	}
+
+	return CheckResult{
+		Name:    "Proxy Check",
+		Status:  "pass",
+		Score:   100,
+		Details: "No proxy implementation slot set",
+	}, nil
+}
+
+// slotAddress extracts a non-zero address stored in the low 20 bytes of a slot
+func slotAddress(value []byte) (string, bool) {
+	if len(value) < 20 {
+		return "", false
+	}
+	addr := value[len(value)-20:]
+	for _, b := range addr {
+		if b != 0 {
+			return ToChecksumAddress(hex.EncodeToString(addr)), true
+		}
+	}
+	return "", false
+}
+
+func (s *Scanner) checkKnownPatterns(address string) CheckResult {
+	if entry, ok := s.lookupDenylist(address); ok {
+		details := "Address is on denylist (source: " + entry.Source + ")"
+		if entry.Comment != "" {
+			details += ": " + entry.Comment
+		}
+		// The address itself is known bad, whatever the other checks say
+		return CheckResult{
+			Name:       "Known Patterns",
+			Status:     "fail",
+			Score:      0,
+			Details:    details,
+			Severity:   "critical",
+			ReasonCode: ReasonDenylistHit,
+		}
+	}
+
+	lowerAddr := strings.ToLower(address)
+
+	for _, pattern := range maliciousPatterns {
+		if strings.Contains(lowerAddr, strings.ToLower(pattern)) {
+			return CheckResult{
+				Name:       "Known Patterns",
+				Status:     "fail",
+				Score:      0,
+				Details:    "Matches known malicious pattern",
+				ReasonCode: ReasonMaliciousPattern,
+			}
+		}
+	}
+
+	return CheckResult{
+		Name:    "Known Patterns",
+		Status:  "pass",
+		Score:   100,
+		Details: "No known malicious patterns detected",
+	}
+}
@@ -0,0 +1,194 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckAddressFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		status   string
+		reason   string
+		severity string
+	}{
+		{"checksummed", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "pass", "", ""},
+		{"lowercase", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "warning", ReasonUnchecksummedAddress, ""},
+		{"bad checksum", "0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "fail", ReasonInvalidAddress, ""},
+		{"miscased USDT", "0xDAC17F958D2ee523a2206206994597C13D831ec7", "warning", ReasonMiscasedKnownAddress, ""},
+		{"too short", "0x12345", "fail", ReasonInvalidAddress, "critical"},
+		{"not hex", "0xZZZeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "fail", ReasonInvalidAddress, "critical"},
+	}
+	s := NewScanner(Config{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.checkAddressFormat(context.Background(), tt.address, "ethereum")
+			if err != nil {
+				t.Fatalf("checkAddressFormat() error = %v", err)
+			}
+			if got.Status != tt.status || got.ReasonCode != tt.reason || got.Severity != tt.severity {
+				t.Errorf("checkAddressFormat() = %s %q severity %q, want %s %q severity %q",
+					got.Status, got.ReasonCode, got.Severity, tt.status, tt.reason, tt.severity)
+			}
+		})
+	}
+}
+
+func TestCheckSanctions(t *testing.T) {
+	const listed = "0x8589427373D6D84E98730D7795D8f6f8731FDA16"
+	const other = "0x1111111111111111111111111111111111111111"
+
+	s := NewScanner(Config{})
+	unscreened := s.checkSanctions(other)
+	if unscreened.Status != "warning" || unscreened.DataSource != DataFallback || unscreened.ReasonCode != ReasonDataUnavailable {
+		t.Errorf("without a list: %s from %s (%s), want an unknown warning from fallback data",
+			unscreened.Status, unscreened.DataSource, unscreened.ReasonCode)
+	}
+	if covered, _ := dataCoverage([]CheckResult{unscreened}); covered != 0 {
+		t.Error("an unscreened address counts as covered")
+	}
+
+	s.sanctions[strings.ToLower(listed)] = "sdn.txt"
+	if got := s.checkSanctions(listed); got.Status != "fail" || got.Severity != "critical" || got.ReasonCode != ReasonSanctioned {
+		t.Errorf("listed address: %s severity %q (%s), want a critical fail", got.Status, got.Severity, got.ReasonCode)
+	}
+	if got := withProvenance(s.checkSanctions(other), nil); got.Status != "pass" || got.DataSource != DataLive {
+		t.Errorf("unlisted address: %s from %s, want a live pass", got.Status, got.DataSource)
+	}
+}
+
+func TestCheckIsContract(t *testing.T) {
+	const (
+		eoa      = "0x1111111111111111111111111111111111111111"
+		contract = "0x2222222222222222222222222222222222222222"
+	)
+	s := fixtureScanner(t, Config{}, map[string]FixtureAccount{
+		eoa:      {Nonce: 5},
+		contract: {Code: "0x6080604052600080fd"},
+	})
+	tests := []struct {
+		address string
+		status  string
+		details string
+	}{
+		{eoa, "pass", "Externally owned account"},
+		{contract, "pass", "Smart contract, 9 bytes of bytecode"},
+		// Addresses never used are EOAs too
+		{"0x3333333333333333333333333333333333333333", "pass", "Externally owned account"},
+	}
+	for _, tt := range tests {
+		got, err := s.checkIsContract(context.Background(), tt.address, "ethereum")
+		if err != nil {
+			t.Fatalf("checkIsContract(%s) error = %v", tt.address, err)
+		}
+		if got.Status != tt.status || got.Details != tt.details {
+			t.Errorf("checkIsContract(%s) = %s %q, want %s %q", tt.address, got.Status, got.Details, tt.status, tt.details)
+		}
+	}
+}
+
+// failingSource is a DataSource whose every query fails
+type failingSource struct{ err error }
+
+func (f failingSource) FirstTxTime(context.Context, string, string) (time.Time, bool, error) {
+	return time.Time{}, false, f.err
+}
+func (f failingSource) TxCount(context.Context, string, string) (uint64, error) { return 0, f.err }
+func (f failingSource) IsContract(context.Context, string, string) (bool, error) {
+	return false, f.err
+}
+
+func TestCheckIsContractFallback(t *testing.T) {
+	s := NewScanner(Config{DataSource: failingSource{errors.New("connection refused")}})
+	result := s.runCheck(context.Background(), "Contract Check", "0x1111111111111111111111111111111111111111", "ethereum", s.checkIsContract)
+	if result.Status != "warning" || result.Score != 50 || result.DataSource != DataFallback || result.ReasonCode != ReasonDataUnavailable {
+		t.Errorf("failed query: %s %d from %s (%s), want a fallback warning scoring 50", result.Status, result.Score, result.DataSource, result.ReasonCode)
+	}
+}
+
+// historySource is a DataSource with a fixed first transaction; the zero
+// value has no history
+type historySource struct {
+	first time.Time
+	count uint64
+}
+
+func (h historySource) FirstTxTime(context.Context, string, string) (time.Time, bool, error) {
+	return h.first, !h.first.IsZero(), nil
+}
+func (h historySource) TxCount(context.Context, string, string) (uint64, error) { return h.count, nil }
+func (h historySource) IsContract(context.Context, string, string) (bool, error) {
+	return false, nil
+}
+
+func TestCheckAccountAge(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	tests := []struct {
+		name   string
+		source historySource
+		status string
+		score  int
+		reason string
+	}{
+		{"no history", historySource{}, "warning", 30, ReasonNoHistory},
+		{"a day old", historySource{daysAgo(1), 1}, "fail", 10, ReasonFreshAccount},
+		{"a week old", historySource{daysAgo(7), 1}, "warning", 40, ReasonFreshAccount},
+		{"a month old", historySource{daysAgo(30), 1}, "pass", 70, ""},
+		{"half a year old", historySource{daysAgo(180), 1}, "pass", 100, ""},
+	}
+	for _, tt := range tests {
+		s := NewScanner(Config{DataSource: tt.source, Clock: func() time.Time { return now }})
+		got, err := s.checkAccountAge(context.Background(), "0x1111111111111111111111111111111111111111", "ethereum")
+		if err != nil {
+			t.Fatalf("%s: error = %v", tt.name, err)
+		}
+		if got.Status != tt.status || got.Score != tt.score || got.ReasonCode != tt.reason {
+			t.Errorf("%s: %s %d (%s), want %s %d (%s)", tt.name, got.Status, got.Score, got.ReasonCode, tt.status, tt.score, tt.reason)
+		}
+	}
+
+	s := NewScanner(Config{DataSource: historySource{first: daysAgo(3)}, Clock: func() time.Time { return now }})
+	got, _ := s.checkAccountAge(context.Background(), "0x1111111111111111111111111111111111111111", "ethereum")
+	if want := "First transaction 2025-12-29 (3 days ago)"; got.Details != want {
+		t.Errorf("details = %q, want %q", got.Details, want)
+	}
+}
+
+func TestCheckKnownPatternsDenylist(t *testing.T) {
+	const drainer = "0x1111111111111111111111111111111111111111"
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	if err := os.WriteFile(path, []byte(drainer+" # drainer wallet\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := fixtureScanner(t, Config{}, nil)
+	if _, err := s.LoadDenylist(path); err != nil {
+		t.Fatalf("LoadDenylist() error = %v", err)
+	}
+
+	got := s.checkKnownPatterns(drainer)
+	if got.Status != "fail" || got.Severity != "critical" || got.ReasonCode != ReasonDenylistHit {
+		t.Errorf("denylisted: %s, severity %q (%s), want a critical fail", got.Status, got.Severity, got.ReasonCode)
+	}
+	if want := "Address is on denylist (source: denylist.txt): drainer wallet"; got.Details != want {
+		t.Errorf("details = %q, want %q", got.Details, want)
+	}
+	if got := s.checkKnownPatterns("0x2222222222222222222222222222222222222222"); got.Status != "pass" || got.Severity != "" {
+		t.Errorf("not denylisted: %s, severity %q, want a pass", got.Status, got.Severity)
+	}
+
+	// An otherwise unremarkable EOA is critical risk once denylisted
+	report, err := s.Scan(drainer, "ethereum")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if report.RiskLevel != "critical" {
+		t.Errorf("RiskLevel = %s (score %d), want critical", report.RiskLevel, report.OverallScore)
+	}
+}
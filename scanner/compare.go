@@ -0,0 +1,136 @@
+package scanner
+
+import (
+	"sort"
+	"time"
+)
+
+// RankedAddress is one address of a Comparison, by rank
+type RankedAddress struct {
+	Rank         int    `json:"rank"` // 1 is the most reputable
+	Address      string `json:"address"`
+	ENSName      string `json:"ens_name,omitempty"`
+	OverallScore int    `json:"overall_score"`
+	RiskLevel    string `json:"risk_level"`
+	Confidence   int    `json:"confidence"`
+	ScoreDelta   int    `json:"score_delta"` // against rank 1
+	Error        string `json:"error,omitempty"`
+}
+
+// CheckResultDelta is one address's result of a compared check
+type CheckResultDelta struct {
+	Address    string `json:"address"`
+	Status     string `json:"status"`
+	Score      int    `json:"score"`
+	ScoreDelta int    `json:"score_delta"` // against the top ranked address that ran the check
+}
+
+// CheckComparison lines up one check across the compared addresses, in
+// rank order. Addresses that did not run the check are left out.
+type CheckComparison struct {
+	Name    string             `json:"name"`
+	Differs bool               `json:"differs"` // the statuses or scores are not all the same
+	Results []CheckResultDelta `json:"results"`
+}
+
+// Comparison ranks several addresses of one network by reputation and
+// shows, check by check, where they differ
+type Comparison struct {
+	Network   string             `json:"network"`
+	Timestamp time.Time          `json:"timestamp"`
+	Ranking   []RankedAddress    `json:"ranking"`
+	Checks    []CheckComparison  `json:"checks"`  // in the order the checks ran
+	Reports   []ReputationReport `json:"reports"` // in rank order
+}
+
+// CompareReports ranks reports by overall score, most reputable first.
+// Equal scores are ordered by risk level and then kept in input order;
+// failed scans come last. Score deltas are against the top ranked address.
+func CompareReports(reports []ReputationReport) Comparison {
+	ranked := make([]ReputationReport, len(reports))
+	copy(ranked, reports)
+	sort.SliceStable(ranked, func(a, b int) bool {
+		ra, rb := ranked[a], ranked[b]
+		if (ra.Error == "") != (rb.Error == "") {
+			return ra.Error == ""
+		}
+		if ra.OverallScore != rb.OverallScore {
+			return ra.OverallScore > rb.OverallScore
+		}
+		return RiskRank(ra.RiskLevel) < RiskRank(rb.RiskLevel)
+	})
+
+	c := Comparison{
+		Ranking: make([]RankedAddress, len(ranked)),
+		Checks:  []CheckComparison{},
+		Reports: ranked,
+	}
+	for i, report := range ranked {
+		if c.Network == "" {
+			c.Network = report.Network
+		}
+		if report.Timestamp.After(c.Timestamp) {
+			c.Timestamp = report.Timestamp
+		}
+		c.Ranking[i] = RankedAddress{
+			Rank:         i + 1,
+			Address:      report.Address,
+			ENSName:      report.ENSName,
+			OverallScore: report.OverallScore,
+			RiskLevel:    report.RiskLevel,
+			Confidence:   report.Confidence,
+			ScoreDelta:   report.OverallScore - ranked[0].OverallScore,
+			Error:        report.Error,
+		}
+	}
+
+	// Every check that ran somewhere, in the order it first appears
+	var names []string
+	seen := map[string]bool{}
+	scanned := 0
+	for _, report := range ranked {
+		if report.Error == "" {
+			scanned++
+		}
+		for _, check := range report.Checks {
+			if !seen[check.Name] {
+				seen[check.Name] = true
+				names = append(names, check.Name)
+			}
+		}
+	}
+	for _, name := range names {
+		cmp := CheckComparison{Name: name, Results: []CheckResultDelta{}}
+		for _, report := range ranked {
+			for _, check := range report.Checks {
+				if check.Name != name {
+					continue
+				}
+				delta := CheckResultDelta{Address: report.Address, Status: check.Status, Score: check.Score}
+				if len(cmp.Results) > 0 {
+					first := cmp.Results[0]
+					delta.ScoreDelta = check.Score - first.Score
+					cmp.Differs = cmp.Differs || check.Status != first.Status || delta.ScoreDelta != 0
+				}
+				cmp.Results = append(cmp.Results, delta)
+				break
+			}
+		}
+		// A check only some of the scanned addresses ran differs too
+		cmp.Differs = cmp.Differs || len(cmp.Results) != scanned
+		c.Checks = append(c.Checks, cmp)
+	}
+	return c
+}
+
+// Differing returns the checks whose results are not the same for every
+// compared address
+func (c Comparison) Differing() []CheckComparison {
+	var differing []CheckComparison
+	for _, check := range c.Checks {
+		if check.Differs {
+			differing = append(differing, check)
+		}
+	}
+	return differing
+}
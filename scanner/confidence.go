@@ -0,0 +1,63 @@
+package scanner
+
+import "time"
+
+// Where the data behind a CheckResult came from
+const (
+	DataLive     = "live"     // queried or computed during this scan
+	DataCache    = "cache"    // read from the on-disk cache
+	DataFallback = "fallback" // primary data unavailable; placeholder or partial result
+)
+
+// Confidence of a result that fell back because its data was unavailable
+const fallbackConfidence = 20
+
+// A cached result loses up to this share of its confidence as it
+// approaches the end of the cache TTL
+const cacheConfidenceDecay = 0.5
+
+// withProvenance fills in Confidence and DataSource for a result computed
+// during this scan. Checks that succeed on partial data set both
+// themselves; a check error marks the result as a fallback.
+func withProvenance(result CheckResult, err error) CheckResult {
+	if err != nil {
+		result.DataSource = DataFallback
+		result.err = err
+		if result.ReasonCode == "" && result.Status != "pass" {
+			result.ReasonCode = ReasonDataUnavailable
+		}
+		if result.Confidence == 0 {
+			result.Confidence = fallbackConfidence
+		}
+		return result
+	}
+	if result.DataSource == "" {
+		result.DataSource = DataLive
+	}
+	if result.Confidence == 0 {
+		result.Confidence = 100
+	}
+	return result
+}
+
+// cachedConfidence decays confidence linearly with the age of a cache entry
+func cachedConfidence(confidence int, age, ttl time.Duration) int {
+	if ttl <= 0 {
+		return confidence
+	}
+	fraction := float64(age) / float64(ttl)
+	if fraction > 1 {
+		fraction = 1
+	}
+	return int(float64(confidence)*(1-cacheConfidenceDecay*fraction) + 0.5)
+}
+
+// dataCoverage counts the checks that completed with live or cached data
+func dataCoverage(checks []CheckResult) (covered, total int) {
+	for _, check := range checks {
+		if check.DataSource != DataFallback {
+			covered++
+		}
+	}
+	return covered, len(checks)
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithProvenance(t *testing.T) {
+	failed := errors.New("rate limited")
+	tests := []struct {
+		name       string
+		result     CheckResult
+		err        error
+		source     string
+		confidence int
+		reason     string
+	}{
+		{"live", CheckResult{Status: "pass"}, nil, DataLive, 100, ""},
+		{"partial", CheckResult{Status: "warning", Confidence: 60, DataSource: DataFallback}, nil, DataFallback, 60, ""},
+		{"failed", CheckResult{Status: "warning"}, failed, DataFallback, fallbackConfidence, ReasonDataUnavailable},
+		// A failure keeps a reason and confidence the check gave
+		{"failed with reason", CheckResult{Status: "fail", ReasonCode: ReasonFreshAccount, Confidence: 40}, failed, DataFallback, 40, ReasonFreshAccount},
+		{"failed pass", CheckResult{Status: "pass"}, failed, DataFallback, fallbackConfidence, ""},
+	}
+	for _, tt := range tests {
+		got := withProvenance(tt.result, tt.err)
+		if got.DataSource != tt.source || got.Confidence != tt.confidence || got.ReasonCode != tt.reason {
+			t.Errorf("%s: %s, confidence %d, reason %q, want %s, %d, %q",
+				tt.name, got.DataSource, got.Confidence, got.ReasonCode, tt.source, tt.confidence, tt.reason)
+		}
+		if !errors.Is(got.err, tt.err) {
+			t.Errorf("%s: err = %v, want %v", tt.name, got.err, tt.err)
+		}
+	}
+}
+
+func TestCachedConfidence(t *testing.T) {
+	tests := []struct {
+		age, ttl time.Duration
+		want     int
+	}{
+		{0, time.Hour, 100},
+		{30 * time.Minute, time.Hour, 75},
+		{time.Hour, time.Hour, 50},
+		{48 * time.Hour, time.Hour, 50},
+		// Entries that never expire do not decay
+		{48 * time.Hour, 0, 100},
+	}
+	for _, tt := range tests {
+		if got := cachedConfidence(100, tt.age, tt.ttl); got != tt.want {
+			t.Errorf("cachedConfidence(100, %s, %s) = %d, want %d", tt.age, tt.ttl, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateConfidence(t *testing.T) {
+	s := NewScanner(Config{Weights: map[string]float64{"Sanctions": 3}})
+	checks := []CheckResult{{Name: "Sanctions", Confidence: 100}, {Name: "Account Age", Confidence: fallbackConfidence}}
+	if got, want := s.calculateConfidence(checks), 80; got != want {
+		t.Errorf("calculateConfidence() = %d, want %d", got, want)
+	}
+	if covered, total := dataCoverage([]CheckResult{{DataSource: DataLive}, {DataSource: DataCache}, {DataSource: DataFallback}}); covered != 2 || total != 3 {
+		t.Errorf("dataCoverage() = %d of %d, want 2 of 3", covered, total)
+	}
+}
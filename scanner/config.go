@@ -0,0 +1,255 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NetworkSettings holds per-network values from the config file
+type NetworkSettings struct {
+	APIKey      string `json:"api_key"`
+	RPCURL      string `json:"rpc_url"`
+	WSURL       string `json:"ws_url"`
+	ExplorerURL string `json:"explorer_url"`
+	// ExplorerType is the API explorer_url speaks, one of ExplorerTypes
+	ExplorerType string `json:"explorer_type"`
+	GraphURL     string `json:"graph_url"`
+	// RPCURLs are more endpoints, tried in order after rpc_url fails
+	RPCURLs []string `json:"rpc_urls"`
+	// APIKeys are more explorer API keys, taken in turn with api_key
+	APIKeys []string `json:"api_keys"`
+}
+
+// FileConfig is the on-disk configuration format
+type FileConfig struct {
+	Networks          map[string]NetworkSettings `json:"networks"`
+	Allowlist         map[string]string          `json:"allowlist"`           // address -> label
+	RequestsPerSecond float64                    `json:"requests_per_second"` // explorer quota of the API key tier
+	SanctionsURL      string                     `json:"sanctions_url"`       // compliance feed for update-lists
+	LabelsURL         string                     `json:"labels_url"`          // address label dataset for update-lists
+	WebhookSecret     string                     `json:"webhook_secret"`      // HMAC key for --webhook deliveries
+	RiskThresholds    map[string]int             `json:"risk_thresholds"`     // level -> lowest score, e.g. {"low": 85}
+	Recommendations   RecommendationTemplates    `json:"recommendations"`     // merged into DefaultRecommendationTemplates
+	IPFSGateway       string                     `json:"ipfs_gateway"`        // resolves ipfs:// token URIs
+	ArweaveGateway    string                     `json:"arweave_gateway"`     // resolves ar:// token URIs
+	MinDataCoverage   float64                    `json:"min_data_coverage"`   // share of checks needing data for a low risk level
+	CacheTTLs         map[string]string          `json:"cache_ttls"`          // check -> TTL, e.g. {"volume": "1h", "verification": "7d"}
+	AbuseReportsURL   string                     `json:"abuse_reports_url"`   // community abuse report API, {address} is filled in
+	AbuseReportsKey   string                     `json:"abuse_reports_key"`   // bearer token for abuse_reports_url
+	Profiles          map[string]Profile         `json:"profiles"`            // name -> checks and weights preset by --profile
+	Redaction         RedactionPolicy            `json:"redaction"`           // report fields emptied by --redact
+	Proxy             string                     `json:"proxy"`               // proxy URL for all requests, overrides HTTPS_PROXY
+	CABundle          string                     `json:"ca_bundle"`           // PEM file of extra root certificates
+	UpgradeWindow     string                     `json:"upgrade_window"`      // how recent a flagged proxy upgrade is, e.g. "7d"
+}
+
+// DefaultConfigDir returns the scanner's configuration directory
+func DefaultConfigDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".agent-reputation-scanner"
+	}
+	return filepath.Join(home, ".config", "agent-reputation-scanner")
+}
+
+// DefaultConfigPath is the config file the CLI reads when present: the
+// first of ConfigFileNames in DefaultConfigDir that exists, or
+// config.json
+func DefaultConfigPath() string {
+	dir := DefaultConfigDir()
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, ConfigFileNames[0])
+}
+
+// LoadConfig reads a JSON or YAML config file, as told by ConfigFormat,
+// and returns the resulting Config.
+// A missing file is not an error when allowMissing is set. Environment
+// variables (<NETWORK>_API_KEY, <NETWORK>_RPC_URL, <NETWORK>_WS_URL,
+// <NETWORK>_EXPLORER_URL, <NETWORK>_EXPLORER_TYPE, <NETWORK>_GRAPH_URL,
+// SCANNER_WEBHOOK_SECRET, SCANNER_ABUSE_REPORTS_KEY, SCANNER_PROXY,
+// SCANNER_CA_BUNDLE) override values from the file.
+func LoadConfig(path string, allowMissing bool) (Config, error) {
+	var file FileConfig
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if file, err = decodeFileConfig(path, data); err != nil {
+			return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+		}
+	case os.IsNotExist(err) && allowMissing:
+	default:
+		return Config{}, fmt.Errorf("cannot read config: %w", err)
+	}
+
+	return file.toConfig(path)
+}
+
+func (f FileConfig) toConfig(path string) (Config, error) {
+	cfg := Config{
+		APIKeys:         map[string]string{},
+		RPCURLs:         map[string]string{},
+		FallbackRPCURLs: map[string][]string{},
+		ExtraAPIKeys:    map[string][]string{},
+		WSURLs:          map[string]string{},
+		Networks:        map[string]NetworkConfig{},
+	}
+	for name, network := range DefaultNetworks {
+		cfg.Networks[name] = network
+	}
+
+	for name := range f.Networks {
+		if _, ok := DefaultNetworks[name]; !ok {
+			return Config{}, fmt.Errorf("invalid config %s: unknown network %q (supported: %s)", path, name, strings.Join(sortedKeys(DefaultNetworks), ", "))
+		}
+	}
+
+	for address := range f.Allowlist {
+		if !IsHexAddress(address) {
+			return Config{}, fmt.Errorf("invalid config %s: invalid allowlist address %q", path, address)
+		}
+	}
+	cfg.Allowlist = f.Allowlist
+
+	if f.RequestsPerSecond < 0 {
+		return Config{}, fmt.Errorf("invalid config %s: requests_per_second must be positive", path)
+	}
+	cfg.RequestsPerSecond = f.RequestsPerSecond
+	cfg.SanctionsURL = f.SanctionsURL
+	cfg.LabelsURL = f.LabelsURL
+	cfg.IPFSGateway = f.IPFSGateway
+	cfg.ArweaveGateway = f.ArweaveGateway
+	if f.MinDataCoverage > 1 {
+		return Config{}, fmt.Errorf("invalid config %s: min_data_coverage must be at most 1", path)
+	}
+	cfg.MinDataCoverage = f.MinDataCoverage
+	if f.AbuseReportsURL != "" && !strings.HasPrefix(f.AbuseReportsURL, "http://") && !strings.HasPrefix(f.AbuseReportsURL, "https://") {
+		return Config{}, fmt.Errorf("invalid config %s: abuse_reports_url must be an http(s) URL", path)
+	}
+	cfg.AbuseReportsURL = f.AbuseReportsURL
+	for name, profile := range f.Profiles {
+		if err := profile.Validate(); err != nil {
+			return Config{}, fmt.Errorf("invalid config %s: profile %q: %w", path, name, err)
+		}
+		if cfg.Profiles == nil {
+			cfg.Profiles = map[string]Profile{}
+		}
+		cfg.Profiles[strings.ToLower(name)] = profile
+	}
+	if err := f.Redaction.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: redaction: %w", path, err)
+	}
+	cfg.Redaction = f.Redaction
+	cfg.AbuseReportsKey = envOr("SCANNER_ABUSE_REPORTS_KEY", f.AbuseReportsKey)
+	for check, value := range f.CacheTTLs {
+		ttl, err := ParseCacheTTL(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid config %s: cache_ttls[%q]: %w", path, check, err)
+		}
+		if err := ValidateCheckNames([]string{check}); err != nil {
+			return Config{}, fmt.Errorf("invalid config %s: cache_ttls: %w", path, err)
+		}
+		if cfg.CacheTTLs == nil {
+			cfg.CacheTTLs = map[string]time.Duration{}
+		}
+		cfg.CacheTTLs[check] = ttl
+	}
+	if f.UpgradeWindow != "" {
+		window, err := ParseCacheTTL(f.UpgradeWindow)
+		if err != nil || window <= 0 || window == CacheForever {
+			return Config{}, fmt.Errorf("invalid config %s: upgrade_window must be a duration such as 7d or 48h", path)
+		}
+		cfg.UpgradeWindow = window
+	}
+	thresholds, err := DefaultRiskThresholds.with(f.RiskThresholds)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	cfg.Thresholds = thresholds
+	if err := f.Recommendations.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	cfg.Recommendations = f.Recommendations
+	cfg.Webhook.Secret = envOr("SCANNER_WEBHOOK_SECRET", f.WebhookSecret)
+	cfg.ProxyURL = envOr("SCANNER_PROXY", f.Proxy)
+	cfg.CABundle = envOr("SCANNER_CA_BUNDLE", f.CABundle)
+	if _, err := NewHTTPClient(cfg.ProxyURL, cfg.CABundle); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	for name, network := range cfg.Networks {
+		settings := f.Networks[name]
+		prefix := strings.ToUpper(name)
+
+		keys := uniqueURLs(append([]string{settings.APIKey}, settings.APIKeys...))
+		if env := os.Getenv(prefix + "_API_KEY"); env != "" {
+			keys = splitURLs(env)
+		}
+		if len(keys) > 0 {
+			cfg.APIKeys[name] = keys[0]
+			cfg.ExtraAPIKeys[name] = keys[1:]
+		}
+		urls := uniqueURLs(append([]string{settings.RPCURL}, settings.RPCURLs...))
+		if env := os.Getenv(prefix + "_RPC_URL"); env != "" {
+			urls = splitURLs(env)
+		}
+		if len(urls) > 0 {
+			cfg.RPCURLs[name] = urls[0]
+			cfg.FallbackRPCURLs[name] = urls[1:]
+		}
+		if url := envOr(prefix+"_WS_URL", settings.WSURL); url != "" {
+			cfg.WSURLs[name] = url
+		}
+		if url := envOr(prefix+"_EXPLORER_URL", settings.ExplorerURL); url != "" {
+			network.ExplorerAPIURL = url
+		}
+		if kind := strings.ToLower(envOr(prefix+"_EXPLORER_TYPE", settings.ExplorerType)); kind != "" {
+			if !validExplorerType(kind) {
+				return Config{}, fmt.Errorf("invalid config %s: %s: unknown explorer_type %q (supported: %s)", path, name, kind, strings.Join(ExplorerTypes, ", "))
+			}
+			if kind != network.ExplorerType && kind == ExplorerBlockscout {
+				network.ExplorerName = "Blockscout"
+			}
+			network.ExplorerType = kind
+		}
+		if url := envOr(prefix+"_GRAPH_URL", settings.GraphURL); url != "" {
+			network.GraphURL = url
+		}
+		cfg.Networks[name] = network
+	}
+	return cfg, nil
+}
+
+func validExplorerType(kind string) bool {
+	for _, t := range ExplorerTypes {
+		if kind == t {
+			return true
+		}
+	}
+	return false
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func sortedKeys(m map[string]NetworkConfig) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// clearConfigEnv keeps the environment from overriding config files
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for name := range DefaultNetworks {
+		prefix := strings.ToUpper(name)
+		for _, suffix := range []string{"_API_KEY", "_RPC_URL", "_WS_URL", "_EXPLORER_URL", "_EXPLORER_TYPE", "_GRAPH_URL"} {
+			t.Setenv(prefix+suffix, "")
+		}
+	}
+	for _, name := range []string{"SCANNER_WEBHOOK_SECRET", "SCANNER_ABUSE_REPORTS_KEY", "SCANNER_PROXY", "SCANNER_CA_BUNDLE"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	clearConfigEnv(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+networks:
+  ethereum:
+    api_key: k1
+    api_keys: [k2, k1]
+    rpc_url: https://rpc.example
+    rpc_urls: [https://rpc2.example]
+    explorer_url: https://explorer.example/api
+    explorer_type: blockscout
+risk_thresholds: {low: 85}
+webhook_secret: from-file
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path, false)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.APIKeys["ethereum"] != "k1" || !reflect.DeepEqual(cfg.ExtraAPIKeys["ethereum"], []string{"k2"}) {
+		t.Errorf("API keys = %q then %q, want k1 then [k2]", cfg.APIKeys["ethereum"], cfg.ExtraAPIKeys["ethereum"])
+	}
+	if cfg.RPCURLs["ethereum"] != "https://rpc.example" || !reflect.DeepEqual(cfg.FallbackRPCURLs["ethereum"], []string{"https://rpc2.example"}) {
+		t.Errorf("RPC URLs = %q then %q", cfg.RPCURLs["ethereum"], cfg.FallbackRPCURLs["ethereum"])
+	}
+	if network := cfg.Networks["ethereum"]; network.ExplorerAPIURL != "https://explorer.example/api" || network.ExplorerType != ExplorerBlockscout || network.ExplorerName != "Blockscout" {
+		t.Errorf("explorer = %s %s %s, want the configured Blockscout", network.ExplorerName, network.ExplorerType, network.ExplorerAPIURL)
+	}
+	if want := (RiskThresholds{Low: 85, Medium: 70, High: 40}); cfg.Thresholds != want {
+		t.Errorf("Thresholds = %v, want %v", cfg.Thresholds, want)
+	}
+	if cfg.Webhook.Secret != "from-file" {
+		t.Errorf("webhook secret = %q, want from-file", cfg.Webhook.Secret)
+	}
+	if _, ok := cfg.APIKeys["polygon"]; ok {
+		t.Errorf("polygon got an API key without one configured")
+	}
+
+	// The environment overrides the file
+	t.Setenv("ETHEREUM_API_KEY", "e1,e2")
+	t.Setenv("ETHEREUM_RPC_URL", "https://env.example")
+	t.Setenv("SCANNER_WEBHOOK_SECRET", "from-env")
+	cfg, err = LoadConfig(path, false)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.APIKeys["ethereum"] != "e1" || !reflect.DeepEqual(cfg.ExtraAPIKeys["ethereum"], []string{"e2"}) {
+		t.Errorf("API keys = %q then %q, want e1 then [e2]", cfg.APIKeys["ethereum"], cfg.ExtraAPIKeys["ethereum"])
+	}
+	if cfg.RPCURLs["ethereum"] != "https://env.example" || len(cfg.FallbackRPCURLs["ethereum"]) != 0 {
+		t.Errorf("RPC URLs = %q then %q, want only the one from the environment", cfg.RPCURLs["ethereum"], cfg.FallbackRPCURLs["ethereum"])
+	}
+	if cfg.Webhook.Secret != "from-env" {
+		t.Errorf("webhook secret = %q, want from-env", cfg.Webhook.Secret)
+	}
+}
+
+func TestLoadConfigMissing(t *testing.T) {
+	clearConfigEnv(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg, err := LoadConfig(path, true)
+	if err != nil {
+		t.Fatalf("LoadConfig(missing, true) error = %v", err)
+	}
+	if cfg.Thresholds != DefaultRiskThresholds || len(cfg.Networks) != len(DefaultNetworks) {
+		t.Errorf("missing config gave thresholds %v and %d networks, want the defaults", cfg.Thresholds, len(cfg.Networks))
+	}
+	if _, err := LoadConfig(path, false); err == nil || !strings.HasPrefix(err.Error(), "cannot read config: ") {
+		t.Errorf("LoadConfig(missing, false) error = %v, want cannot read config", err)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	clearConfigEnv(t)
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"syntax", `{"networks": }`, "line 1: "},
+		{"unknown field", `{"netwroks": {}}`, `line 1: unknown field "netwroks"`},
+		{"unknown network", `{"networks": {"ethereum-classic": {}}}`, `unknown network "ethereum-classic" (supported: `},
+		{"allowlist address", `{"allowlist": {"0x1234": "short"}}`, `invalid allowlist address "0x1234"`},
+		{"requests per second", `{"requests_per_second": -1}`, "requests_per_second must be positive"},
+		{"coverage", `{"min_data_coverage": 1.5}`, "min_data_coverage must be at most 1"},
+		{"abuse reports URL", `{"abuse_reports_url": "ftp://reports.example"}`, "abuse_reports_url must be an http(s) URL"},
+		{"thresholds", `{"risk_thresholds": {"low": 50}}`, "thresholds must satisfy low >= medium >= high"},
+		{"cache TTL check", `{"cache_ttls": {"nope": "1h"}}`, "cache_ttls: "},
+		{"upgrade window", `{"upgrade_window": "soon"}`, "upgrade_window must be a duration such as 7d or 48h"},
+		{"explorer type", `{"networks": {"ethereum": {"explorer_type": "etherscan2"}}}`, `ethereum: unknown explorer_type "etherscan2"`},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(path, []byte(tt.in), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadConfig(path, false)
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid config "+path+": ") || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: LoadConfig() error = %v, want invalid config ... %s", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"agent-reputation-scanner/internal/yaml"
+)
+
+// Config file formats
+const (
+	ConfigJSON = "json"
+	ConfigYAML = "yaml"
+)
+
+// ConfigFileNames are the config files looked for in DefaultConfigDir, in
+// order of preference
+var ConfigFileNames = []string{"config.json", "config.yaml", "config.yml"}
+
+// ConfigFormat returns the format of a config file by its extension:
+// .yaml and .yml are YAML, .json is JSON, and other files are JSON when
+// data starts with "{" and YAML otherwise
+func ConfigFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ConfigYAML
+	case ".json":
+		return ConfigJSON
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return ConfigJSON
+	}
+	return ConfigYAML
+}
+
+// decodeFileConfig parses a config file in either format and checks it
+// against FileConfig, so both report unknown fields and mistyped values
+// with their line and field path
+func decodeFileConfig(path string, data []byte) (FileConfig, error) {
+	var root *yaml.Node
+	var err error
+	strict := ConfigFormat(path, data) == ConfigJSON
+	if strict {
+		root, err = jsonNodes(data)
+	} else {
+		root, err = yaml.Parse(data)
+	}
+	if err != nil {
+		return FileConfig{}, err
+	}
+
+	var file FileConfig
+	value, err := configDecoder{strict: strict}.value(root, reflect.TypeOf(file), "")
+	if err != nil {
+		return FileConfig{}, err
+	}
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return FileConfig{}, err
+	}
+	if err := json.Unmarshal(normalized, &file); err != nil {
+		return FileConfig{}, err
+	}
+	return file, nil
+}
+
+// configDecoder converts parsed nodes to the JSON values of a Go type.
+// YAML plain scalars become strings, numbers or booleans as the type
+// needs; strict (JSON) input keeps the types the file gave.
+type configDecoder struct {
+	strict bool
+}
+
+func (d configDecoder) value(n *yaml.Node, t reflect.Type, field string) (interface{}, error) {
+	if n.IsNull() {
+		return nil, nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.Mapping {
+			return nil, d.mismatch(n, field, "an object")
+		}
+		fields := map[string]reflect.StructField{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" || !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			fields[name] = f
+		}
+		obj := map[string]interface{}{}
+		for _, pair := range n.Pairs {
+			f, ok := fields[pair.Key]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown field %q", pair.Line, joinField(field, pair.Key))
+			}
+			value, err := d.value(pair.Value, f.Type, joinField(field, pair.Key))
+			if err != nil {
+				return nil, err
+			}
+			obj[pair.Key] = value
+		}
+		return obj, nil
+	case reflect.Map:
+		if n.Kind != yaml.Mapping {
+			return nil, d.mismatch(n, field, "an object")
+		}
+		obj := map[string]interface{}{}
+		for _, pair := range n.Pairs {
+			value, err := d.value(pair.Value, t.Elem(), joinField(field, pair.Key))
+			if err != nil {
+				return nil, err
+			}
+			obj[pair.Key] = value
+		}
+		return obj, nil
+	case reflect.Slice, reflect.Array:
+		if n.Kind != yaml.Sequence {
+			return nil, d.mismatch(n, field, "a list")
+		}
+		list := make([]interface{}, len(n.Items))
+		for i, item := range n.Items {
+			value, err := d.value(item, t.Elem(), fmt.Sprintf("%s[%d]", field, i))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case reflect.String:
+		if n.Kind != yaml.Scalar || d.strict && !n.Quoted {
+			return nil, d.mismatch(n, field, "a string")
+		}
+		return n.Value, nil
+	case reflect.Bool:
+		if n.Kind == yaml.Scalar && !n.Quoted {
+			switch n.Value {
+			case "true", "True", "TRUE":
+				return true, nil
+			case "false", "False", "FALSE":
+				return false, nil
+			}
+		}
+		return nil, d.mismatch(n, field, "true or false")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n.Kind == yaml.Scalar && !n.Quoted {
+			if i, err := strconv.ParseInt(n.Value, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+		return nil, d.mismatch(n, field, "a whole number")
+	case reflect.Float32, reflect.Float64:
+		if n.Kind == yaml.Scalar && !n.Quoted {
+			if f, err := strconv.ParseFloat(n.Value, 64); err == nil && !strings.ContainsAny(n.Value, "xXnN_") {
+				return f, nil
+			}
+		}
+		return nil, d.mismatch(n, field, "a number")
+	}
+	return nil, fmt.Errorf("line %d: %s: unsupported field type %s", n.Line, field, t)
+}
+
+// mismatch describes a value of the wrong type
+func (d configDecoder) mismatch(n *yaml.Node, field, want string) error {
+	got := "a list"
+	switch {
+	case n.Kind == yaml.Mapping:
+		got = "an object"
+	case n.Kind == yaml.Scalar && n.Quoted:
+		got = strconv.Quote(n.Value)
+	case n.Kind == yaml.Scalar:
+		got = n.Value
+	}
+	if field == "" {
+		return fmt.Errorf("line %d: want %s at the top level, got %s", n.Line, want, got)
+	}
+	return fmt.Errorf("line %d: %s: want %s, got %s", n.Line, field, want, got)
+}
+
+func joinField(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// jsonNodes parses a JSON document into the nodes yaml.Parse returns,
+// keeping the line each value ends on
+func jsonNodes(data []byte) (*yaml.Node, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	node, err := jsonNode(dec, data)
+	if err == nil {
+		if _, extra := dec.Token(); extra != io.EOF {
+			err = fmt.Errorf("line %d: unexpected data after the top-level object", lineAt(data, dec.InputOffset()))
+		}
+	}
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		return nil, fmt.Errorf("line %d: %s", lineAt(data, syntax.Offset), syntax.Error())
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("line %d: unexpected end of JSON", lineAt(data, int64(len(data))))
+	}
+	return node, err
+}
+
+func jsonNode(dec *json.Decoder, data []byte) (*yaml.Node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	line := lineAt(data, dec.InputOffset())
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '[' {
+			node := &yaml.Node{Kind: yaml.Sequence, Line: line}
+			for dec.More() {
+				item, err := jsonNode(dec, data)
+				if err != nil {
+					return nil, err
+				}
+				node.Items = append(node.Items, item)
+			}
+			_, err := dec.Token()
+			return node, err
+		}
+		// Repeated keys are kept; the last one wins, as with encoding/json
+		node := &yaml.Node{Kind: yaml.Mapping, Line: line}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			name, _ := key.(string)
+			keyLine := lineAt(data, dec.InputOffset())
+			value, err := jsonNode(dec, data)
+			if err != nil {
+				return nil, err
+			}
+			node.Pairs = append(node.Pairs, yaml.Pair{Key: name, Line: keyLine, Value: value})
+		}
+		_, err := dec.Token()
+		return node, err
+	case string:
+		return &yaml.Node{Kind: yaml.Scalar, Line: line, Value: token, Quoted: true}, nil
+	case json.Number:
+		return &yaml.Node{Kind: yaml.Scalar, Line: line, Value: token.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.Scalar, Line: line, Value: strconv.FormatBool(token)}, nil
+	}
+	return &yaml.Node{Kind: yaml.Scalar, Line: line, Value: "null"}, nil
+}
+
+// lineAt returns the 1-based line of a byte offset in data
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
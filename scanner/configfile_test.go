@@ -0,0 +1,143 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeFileConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		in   string
+		want FileConfig
+	}{
+		{"yaml", "config.yaml", `
+networks:
+  ethereum:
+    api_key: "0123"          # quoted, so not a number
+    rpc_urls: [https://a.example, 'https://b.example']
+    api_keys:
+      - k1
+      - k2
+allowlist:
+  "0x1111111111111111111111111111111111111111": Treasury
+requests_per_second: 2.5
+risk_thresholds: {low: 85, medium: 60}
+redaction:
+  fields: []
+proxy: ~
+`, FileConfig{
+			Networks: map[string]NetworkSettings{"ethereum": {
+				APIKey:  "0123",
+				RPCURLs: []string{"https://a.example", "https://b.example"},
+				APIKeys: []string{"k1", "k2"},
+			}},
+			Allowlist:         map[string]string{"0x1111111111111111111111111111111111111111": "Treasury"},
+			RequestsPerSecond: 2.5,
+			RiskThresholds:    map[string]int{"low": 85, "medium": 60},
+			Redaction:         RedactionPolicy{Fields: []string{}},
+		}},
+		{"yaml plain scalars as strings", "config.yml", "sanctions_url: https://x.example/list.csv\nwebhook_secret: 12345\n",
+			FileConfig{SanctionsURL: "https://x.example/list.csv", WebhookSecret: "12345"}},
+		{"json", "config.json", `{
+  "networks": {"base": {"api_key": "k", "rpc_urls": ["https://r.example"]}},
+  "min_data_coverage": 0.5,
+  "proxy": null
+}`, FileConfig{
+			Networks:        map[string]NetworkSettings{"base": {APIKey: "k", RPCURLs: []string{"https://r.example"}}},
+			MinDataCoverage: 0.5,
+		}},
+		{"json by content", "config", `{"ipfs_gateway": "https://gw.example"}`, FileConfig{IPFSGateway: "https://gw.example"}},
+		{"yaml by content", "config", "ipfs_gateway: https://gw.example\n", FileConfig{IPFSGateway: "https://gw.example"}},
+		{"empty yaml", "config.yaml", "# nothing yet\n", FileConfig{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeFileConfig(tt.path, []byte(tt.in))
+			if err != nil {
+				t.Fatalf("decodeFileConfig() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeFileConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeFileConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		in   string
+		want string
+	}{
+		{"yaml unknown field", "config.yaml", "proxy: http://p\nnetworks:\n  ethereum:\n    apikey: k\n",
+			`line 4: unknown field "networks.ethereum.apikey"`},
+		{"yaml mistyped number", "config.yaml", "requests_per_second: fast\n",
+			"line 1: requests_per_second: want a number, got fast"},
+		{"yaml quoted number", "config.yaml", "risk_thresholds:\n  low: \"85\"\n",
+			`line 2: risk_thresholds.low: want a whole number, got "85"`},
+		{"yaml fraction for int", "config.yaml", "risk_thresholds: {low: 8.5}\n",
+			"line 1: risk_thresholds.low: want a whole number, got 8.5"},
+		{"yaml list for string", "config.yaml", "proxy:\n  - a\n",
+			"line 2: proxy: want a string, got a list"},
+		{"yaml scalar for list", "config.yaml", "networks:\n  base:\n    rpc_urls: https://r\n",
+			"line 3: networks.base.rpc_urls: want a list, got https://r"},
+		{"yaml mistyped list item", "config.yaml", "redaction:\n  fields:\n    - a\n    - [b]\n",
+			"line 4: redaction.fields[1]: want a string, got a list"},
+		{"yaml top level", "config.yaml", "- a\n", "line 1: want an object at the top level, got a list"},
+		{"yaml duplicate key", "config.yaml", "proxy: a\nca_bundle: b\nproxy: c\n", `line 3: duplicate key "proxy"`},
+		{"yaml bad indentation", "config.yaml", "networks:\n  base:\n    api_key: k\n   rpc_url: r\n", "line 4: unexpected indentation"},
+		{"json unknown field", "config.json", "{\n  \"networks\": {},\n  \"proxi\": \"x\"\n}",
+			`line 3: unknown field "proxi"`},
+		{"json number for string", "config.json", "{\n\n  \"proxy\": 8080\n}", "line 3: proxy: want a string, got 8080"},
+		{"json string for number", "config.json", `{"requests_per_second": "2"}`,
+			`line 1: requests_per_second: want a number, got "2"`},
+		{"json trailing comma", "config.json", "{\n  \"proxy\": \"a\",\n}", "line 2: invalid character ',' looking for beginning of value"},
+		{"json truncated", "config.json", "{\n  \"proxy\": \"a\",\n", "line 3: unexpected end of JSON input"},
+		{"json trailing data", "config.json", "{}\n{}", "line 2: unexpected data after the top-level object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeFileConfig(tt.path, []byte(tt.in))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("decodeFileConfig() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONNodesLines(t *testing.T) {
+	in := `{
+  "a": 1,
+  "b": [
+    "x",
+    true
+  ],
+  "a": null
+}`
+	root, err := jsonNodes([]byte(in))
+	if err != nil {
+		t.Fatalf("jsonNodes() error = %v", err)
+	}
+	type pair struct {
+		key  string
+		line int
+	}
+	var pairs []pair
+	for _, p := range root.Pairs {
+		pairs = append(pairs, pair{p.Key, p.Line})
+	}
+	// Repeated keys are kept for the decoder, where the last one wins
+	if want := []pair{{"a", 2}, {"b", 3}, {"a", 7}}; !reflect.DeepEqual(pairs, want) {
+		t.Errorf("pairs = %v, want %v", pairs, want)
+	}
+	items := root.Pairs[1].Value.Items
+	if len(items) != 2 || items[0].Line != 4 || !items[0].Quoted || items[1].Line != 5 || items[1].Value != "true" {
+		t.Errorf("items = %+v %+v, want \"x\" on line 4 and true on line 5", items[0], items[1])
+	}
+	if !root.Pairs[2].Value.IsNull() {
+		t.Errorf("null decoded as %+v", root.Pairs[2].Value)
+	}
+}
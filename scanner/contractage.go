@@ -0,0 +1,142 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Contracts younger than this get no trust from Contract Age at all
+const contractAgeFresh = 24 * time.Hour
+
+// contractDeployment returns the block and time a contract was deployed.
+// It prefers the explorer's creation record and falls back to looking up
+// the creation transaction over RPC.
+func (s *Scanner) contractDeployment(ctx context.Context, creation *contractCreation, network string) (uint64, time.Time, error) {
+	if creation.BlockNumber != "" && creation.Timestamp != "" {
+		block, err := strconv.ParseUint(creation.BlockNumber, 10, 64)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid creation block %q", creation.BlockNumber)
+		}
+		secs, err := strconv.ParseInt(creation.Timestamp, 10, 64)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid creation timestamp %q", creation.Timestamp)
+		}
+		return block, time.Unix(secs, 0), nil
+	}
+
+	result, err := s.rpcCall(ctx, network, "eth_getTransactionByHash", []interface{}{creation.TxHash})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	var tx struct {
+		BlockNumber string `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(result, &tx); err != nil || tx.BlockNumber == "" {
+		return 0, time.Time{}, fmt.Errorf("creation tx %s %w", creation.TxHash, ErrNotFound)
+	}
+	block, err := strconv.ParseUint(strings.TrimPrefix(tx.BlockNumber, "0x"), 16, 64)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("invalid block number %q", tx.BlockNumber)
+	}
+
+	deployed, err := s.blockTime(ctx, network, tx.BlockNumber)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return block, deployed, nil
+}
+
+// blockTime looks up the time of a block, given as a hex number, over RPC
+func (s *Scanner) blockTime(ctx context.Context, network, block string) (time.Time, error) {
+	result, err := s.rpcCall(ctx, network, "eth_getBlockByNumber", []interface{}{block, false})
+	if err != nil {
+		return time.Time{}, err
+	}
+	var header struct {
+		Timestamp string `json:"timestamp"`
+	}
+	if err := json.Unmarshal(result, &header); err != nil || header.Timestamp == "" {
+		return time.Time{}, fmt.Errorf("block %s %w", block, ErrNotFound)
+	}
+	secs, err := strconv.ParseInt(strings.TrimPrefix(header.Timestamp, "0x"), 16, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid block timestamp %q", header.Timestamp)
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// checkContractAge scores a contract by its deployment time. Unlike
+// Account Age it does not depend on activity, so a contract that was only
+// funded before use is still recognised as new.
+func (s *Scanner) checkContractAge(ctx context.Context, address, network string) (CheckResult, error) {
+	// EOAs are covered by Account Age
+	if code, err := s.getCode(ctx, address, network); err == nil && len(code) == 0 {
+		return CheckResult{
+			Name:          "Contract Age",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (contract age not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Contract Age",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	creation, err := s.getContractCreation(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Contract Age",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+	if creation == nil {
+		return CheckResult{
+			Name:       "Contract Age",
+			Status:     "warning",
+			Score:      50,
+			Details:    "Contract creation not found",
+			ReasonCode: ReasonCreationUnknown,
+		}, nil
+	}
+
+	block, deployed, err := s.contractDeployment(ctx, creation, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Contract Age",
+			Status:  "warning",
+			Score:   50,
+			Details: fmt.Sprintf("Deployed in tx %s; deployment time unknown: %v", creation.TxHash, err),
+		}, err
+	}
+
+	age := s.cfg.Clock().Sub(deployed)
+	days := int(age.Hours() / 24)
+	details := fmt.Sprintf("Deployed %s in block %d (%d days ago)", deployed.Format("2006-01-02"), block, days)
+
+	switch {
+	case age < contractAgeFresh:
+		details = fmt.Sprintf("Deployed %d hours ago in block %d", int(age.Hours()), block)
+		return CheckResult{Name: "Contract Age", Status: "fail", Score: 0, Details: details, ReasonCode: ReasonFreshContract}, nil
+	case days < accountAgeNewDays:
+		return CheckResult{Name: "Contract Age", Status: "fail", Score: accountAgeNewScore, Details: details, ReasonCode: ReasonFreshContract}, nil
+	case days < accountAgeRecentDays:
+		return CheckResult{Name: "Contract Age", Status: "warning", Score: accountAgeRecentScore, Details: details, ReasonCode: ReasonFreshContract}, nil
+	case days < accountAgeMatureDays:
+		return CheckResult{Name: "Contract Age", Status: "pass", Score: accountAgeMatureScore, Details: details}, nil
+	default:
+		return CheckResult{Name: "Contract Age", Status: "pass", Score: 100, Details: details}, nil
+	}
+}
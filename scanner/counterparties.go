@@ -0,0 +1,162 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Recent transactions whose counterparties the Counterparty Reputation
+// check reads, and how many distinct counterparties it looks up; each
+// lookup is an explorer request
+const (
+	counterpartyTxSample = 200
+	counterpartySample   = 20
+)
+
+// Shares of suspicious counterparties that Counterparty Reputation warns
+// and fails on. Fewer than counterpartyMinSample counterparties only warn.
+const (
+	counterpartyWarnShare = 0.2
+	counterpartyFailShare = 0.5
+	counterpartyMinSample = 5
+)
+
+// zeroAddress is the sender of mints and recipient of burns, not a
+// counterparty
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// counterparties returns the distinct accounts of the address's recent
+// transactions, most recent first, up to counterpartySample
+func (s *Scanner) counterparties(ctx context.Context, address, network string) ([]string, error) {
+	txs, err := s.getTxList(ctx, address, network, "desc", counterpartyTxSample)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	seen := map[string]bool{strings.ToLower(address): true}
+	for _, tx := range txs {
+		for _, account := range []string{tx.From, tx.To} {
+			key := strings.ToLower(account)
+			if !IsHexAddress(account) || seen[key] || key == zeroAddress {
+				continue
+			}
+			seen[key] = true
+			list = append(list, ToChecksumAddress(account))
+			if len(list) == counterpartySample {
+				return list, nil
+			}
+		}
+	}
+	return list, nil
+}
+
+// checkCounterparties samples who the address transacts with and flags it
+// when many of them are denylisted, sanctioned or newly created, which is
+// how scam hubs that scam wallets funnel through look. The lookups are the
+// deployer reputation ones, shared with Deployer Reputation.
+func (s *Scanner) checkCounterparties(ctx context.Context, address, network string) (CheckResult, error) {
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Counterparty Reputation",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	counterparties, err := s.counterparties(ctx, address, network)
+	if err != nil {
+		return counterpartyQueryFailed(err)
+	}
+	if len(counterparties) == 0 {
+		return CheckResult{
+			Name:    "Counterparty Reputation",
+			Status:  "pass",
+			Score:   100,
+			Details: "No counterparties in recent transactions",
+		}, nil
+	}
+
+	var denylisted, fresh []string
+	for _, counterparty := range counterparties {
+		if s.isSanctioned(counterparty) {
+			denylisted = append(denylisted, counterparty)
+			continue
+		}
+		info, err := s.deployerReputation(ctx, counterparty, network)
+		if err != nil {
+			return counterpartyQueryFailed(err)
+		}
+		switch {
+		case info.denylisted:
+			denylisted = append(denylisted, counterparty)
+		case !info.firstSeen.IsZero() && s.cfg.Clock().Sub(info.firstSeen).Hours()/24 < accountAgeNewDays:
+			fresh = append(fresh, counterparty)
+		}
+	}
+
+	sampled := len(counterparties)
+	suspicious := len(denylisted) + len(fresh)
+	share := float64(suspicious) / float64(sampled)
+	details := fmt.Sprintf("%d of %d sampled counterparties suspicious (%.0f%%)", suspicious, sampled, share*100)
+	if suspicious == 0 {
+		return CheckResult{
+			Name:    "Counterparty Reputation",
+			Status:  "pass",
+			Score:   100,
+			Details: fmt.Sprintf("None of %d sampled counterparties denylisted or created in the last %d days", sampled, accountAgeNewDays),
+		}, nil
+	}
+	var kinds []string
+	if len(denylisted) > 0 {
+		kinds = append(kinds, fmt.Sprintf("%d denylisted", len(denylisted)))
+	}
+	if len(fresh) > 0 {
+		kinds = append(kinds, fmt.Sprintf("%d created in the last %d days", len(fresh), accountAgeNewDays))
+	}
+	details += fmt.Sprintf(": %s, e.g. %s", strings.Join(kinds, " and "), strings.Join(firstN(append(denylisted, fresh...), 3), ", "))
+
+	switch {
+	case share >= counterpartyFailShare && sampled >= counterpartyMinSample:
+		return CheckResult{
+			Name:       "Counterparty Reputation",
+			Status:     "fail",
+			Score:      10,
+			Severity:   "high",
+			Details:    details,
+			ReasonCode: ReasonSuspiciousCounterparties,
+		}, nil
+	case share >= counterpartyWarnShare:
+		return CheckResult{
+			Name:       "Counterparty Reputation",
+			Status:     "warning",
+			Score:      int(100 * (1 - share)),
+			Details:    details,
+			ReasonCode: ReasonSuspiciousCounterparties,
+		}, nil
+	}
+	return CheckResult{
+		Name:    "Counterparty Reputation",
+		Status:  "pass",
+		Score:   int(100 * (1 - share)),
+		Details: details,
+	}, nil
+}
+
+// firstN returns up to n leading entries of list
+func firstN(list []string, n int) []string {
+	if len(list) > n {
+		return list[:n]
+	}
+	return list
+}
+
+func counterpartyQueryFailed(err error) (CheckResult, error) {
+	return CheckResult{
+		Name:    "Counterparty Reputation",
+		Status:  "warning",
+		Score:   50,
+		Details: "Explorer query failed: " + err.Error(),
+	}, err
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Data sources selectable with Config.Source
+const (
+	SourceExplorer = "explorer" // block explorer API plus the RPC node (default)
+	SourceGraph    = "graph"    // The Graph subgraph, see NetworkConfig.GraphURL
+)
+
+// Sources lists the valid Config.Source values
+var Sources = []string{SourceExplorer, SourceGraph}
+
+// DataSource provides the account history used by the Contract Check,
+// Account Age and Transaction Volume checks. Set Config.DataSource to plug
+// in another backend, e.g. a mock in tests.
+type DataSource interface {
+	// FirstTxTime returns the time of the address's first transaction;
+	// ok is false when it has none
+	FirstTxTime(ctx context.Context, address, network string) (first time.Time, ok bool, err error)
+	// TxCount returns the number of transactions sent from the address
+	TxCount(ctx context.Context, address, network string) (uint64, error)
+	// IsContract reports whether code is deployed at the address
+	IsContract(ctx context.Context, address, network string) (bool, error)
+}
+
+// recentActivitySource is implemented by data sources that can list recent
+// transaction times, newest first, for the burst heuristic
+type recentActivitySource interface {
+	recentTxTimes(ctx context.Context, address, network string, n int) ([]time.Time, error)
+}
+
+// codeSizeSource is implemented by data sources that know the size of
+// deployed bytecode
+type codeSizeSource interface {
+	codeSize(ctx context.Context, address, network string) (int, error)
+}
+
+// newDataSource returns the data source for cfg
+func newDataSource(s *Scanner, cfg Config) DataSource {
+	switch {
+	case cfg.DataSource != nil:
+		return cfg.DataSource
+	case cfg.Source == SourceGraph:
+		return newGraphSource(s)
+	default:
+		return explorerSource{s}
+	}
+}
+
+// explorerSource reads transaction history from the block explorer API and
+// account state from the RPC node
+type explorerSource struct {
+	s *Scanner
+}
+
+func (e explorerSource) FirstTxTime(ctx context.Context, address, network string) (time.Time, bool, error) {
+	if !e.s.hasExplorer(network) {
+		return time.Time{}, false, ErrNoAPIKey
+	}
+	// Earliest transaction: ascending sort, page size 1
+	txs, err := e.s.getTxList(ctx, address, network, "asc", 1)
+	if err != nil || len(txs) == 0 {
+		return time.Time{}, false, err
+	}
+	first, err := txTime(txs[0])
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return first, true, nil
+}
+
+func (e explorerSource) TxCount(ctx context.Context, address, network string) (uint64, error) {
+	return e.s.getNonce(ctx, address, network)
+}
+
+func (e explorerSource) IsContract(ctx context.Context, address, network string) (bool, error) {
+	code, err := e.s.getCode(ctx, address, network)
+	return len(code) > 0, err
+}
+
+func (e explorerSource) recentTxTimes(ctx context.Context, address, network string, n int) ([]time.Time, error) {
+	// Recent activity needs the explorer; skip quietly without a key
+	if !e.s.hasExplorer(network) {
+		return nil, nil
+	}
+	txs, err := e.s.getTxList(ctx, address, network, "desc", n)
+	if err != nil {
+		return nil, err
+	}
+	times := make([]time.Time, 0, len(txs))
+	for _, tx := range txs {
+		t, err := txTime(tx)
+		if err != nil {
+			return nil, fmt.Errorf("tx %s: %w", tx.Hash, err)
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+func (e explorerSource) codeSize(ctx context.Context, address, network string) (int, error) {
+	code, err := e.s.getCode(ctx, address, network)
+	return len(code), err
+}
@@ -0,0 +1,187 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// contractCreation is an entry of the explorer getcontractcreation result
+type contractCreation struct {
+	ContractAddress string `json:"contractAddress"`
+	ContractCreator string `json:"contractCreator"`
+	TxHash          string `json:"txHash"`
+	// ContractFactory is the contract that created it, when set by the
+	// explorer; ContractCreator is then the sender of the creation tx
+	ContractFactory string `json:"contractFactory,omitempty"`
+	// Newer explorer versions include the deployment block and its unix
+	// timestamp; both are empty otherwise
+	BlockNumber string `json:"blockNumber,omitempty"`
+	Timestamp   string `json:"timestamp,omitempty"`
+}
+
+// deployerInfo is the light reputation of a deployer account. It is cached
+// per scanner because many contracts share deployers.
+type deployerInfo struct {
+	denylisted bool
+	entry      DenylistEntry
+	firstSeen  time.Time // zero if unknown
+}
+
+// getContractCreation returns the creator and creation tx of a contract,
+// or nil if address was never deployed to
+func (s *Scanner) getContractCreation(ctx context.Context, address, network string) (*contractCreation, error) {
+	key := chainCacheKey(address, network)
+	s.cacheMu.Lock()
+	creation, ok := s.creationCache[key]
+	s.cacheMu.Unlock()
+	if ok {
+		return creation, nil
+	}
+
+	creation, err := s.explorerFor(network).contractCreation(ctx, address, network)
+	if err != nil {
+		return nil, err
+	}
+	s.cacheMu.Lock()
+	s.creationCache[key] = creation
+	s.cacheMu.Unlock()
+	return creation, nil
+}
+
+func (e etherscanExplorer) contractCreation(ctx context.Context, address, network string) (*contractCreation, error) {
+	params := url.Values{}
+	params.Set("module", "contract")
+	params.Set("action", "getcontractcreation")
+	params.Set("contractaddresses", address)
+
+	result, err := e.s.explorerCall(ctx, network, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Addresses that were never deployed to get "No data found" or null
+	var message string
+	if string(result) == "null" || json.Unmarshal(result, &message) == nil && strings.Contains(strings.ToLower(message), "no data") {
+		return nil, nil
+	}
+	var entries []contractCreation
+	if err := json.Unmarshal(result, &entries); err != nil {
+		return nil, fmt.Errorf("unexpected getcontractcreation result: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[0], nil
+}
+
+// deployerReputation looks up the deployer on the denylist and finds its
+// first transaction
+func (s *Scanner) deployerReputation(ctx context.Context, deployer, network string) (deployerInfo, error) {
+	key := chainCacheKey(deployer, network)
+	s.cacheMu.Lock()
+	info, ok := s.deployerCache[key]
+	s.cacheMu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	info.entry, info.denylisted = s.lookupDenylist(deployer)
+	if !info.denylisted {
+		txs, err := s.getTxList(ctx, deployer, network, "asc", 1)
+		if err != nil {
+			return deployerInfo{}, err
+		}
+		if len(txs) > 0 {
+			if first, err := txTime(txs[0]); err == nil {
+				info.firstSeen = first
+			}
+		}
+	}
+
+	s.cacheMu.Lock()
+	s.deployerCache[key] = info
+	s.cacheMu.Unlock()
+	return info, nil
+}
+
+func (s *Scanner) checkDeployer(ctx context.Context, address, network string) (CheckResult, error) {
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Deployer Reputation",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	// EOAs are not deployed
+	if code, err := s.getCode(ctx, address, network); err == nil && len(code) == 0 {
+		return CheckResult{
+			Name:          "Deployer Reputation",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (deployer not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	creation, err := s.getContractCreation(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Deployer Reputation",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+	if creation == nil || !IsHexAddress(creation.ContractCreator) {
+		return CheckResult{
+			Name:       "Deployer Reputation",
+			Status:     "warning",
+			Score:      50,
+			Details:    "Contract creator not found",
+			ReasonCode: ReasonCreationUnknown,
+		}, nil
+	}
+
+	deployer := ToChecksumAddress(creation.ContractCreator)
+	info, err := s.deployerReputation(ctx, deployer, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Deployer Reputation",
+			Status:  "warning",
+			Score:   50,
+			Details: fmt.Sprintf("Deployed by %s; explorer query failed: %v", deployer, err),
+		}, err
+	}
+
+	if info.denylisted {
+		details := fmt.Sprintf("Contract deployed by high-risk account %s (denylist source: %s)", deployer, info.entry.Source)
+		if info.entry.Comment != "" {
+			details += ": " + info.entry.Comment
+		}
+		return CheckResult{Name: "Deployer Reputation", Status: "fail", Score: 0, Details: details, ReasonCode: ReasonRiskyDeployer}, nil
+	}
+	if !info.firstSeen.IsZero() {
+		if days := int(s.cfg.Clock().Sub(info.firstSeen).Hours() / 24); days < accountAgeNewDays {
+			return CheckResult{
+				Name:       "Deployer Reputation",
+				Status:     "fail",
+				Score:      accountAgeNewScore,
+				Details:    fmt.Sprintf("Contract deployed by high-risk account %s (first seen %d days ago)", deployer, days),
+				ReasonCode: ReasonRiskyDeployer,
+			}, nil
+		}
+	}
+
+	return CheckResult{
+		Name:    "Deployer Reputation",
+		Status:  "pass",
+		Score:   100,
+		Details: fmt.Sprintf("Deployed by %s in tx %s", deployer, creation.TxHash),
+	}, nil
+}
@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// rpcCooldown is how long an RPC endpoint that failed is tried only after
+// the endpoints that did not
+const rpcCooldown = 30 * time.Second
+
+// rpcHealth is the recent record of one RPC endpoint
+type rpcHealth struct {
+	failures  int // consecutive
+	downUntil time.Time
+}
+
+// splitURLs splits a comma-separated endpoint list, dropping empty and
+// repeated entries
+func splitURLs(list string) []string {
+	return uniqueURLs(strings.Split(list, ","))
+}
+
+func uniqueURLs(urls []string) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, url := range urls {
+		if url = strings.TrimSpace(url); url != "" && !seen[url] {
+			seen[url] = true
+			unique = append(unique, url)
+		}
+	}
+	return unique
+}
+
+// configuredRPCURLs lists the JSON-RPC endpoints of a network in the order
+// they are configured: Config.RPCURLs and Config.FallbackRPCURLs, then the
+// comma-separated <NETWORK>_RPC_URL, then the network's DefaultRPC and
+// FallbackRPCs. Local scans use LOCAL_RPC_URL or DefaultLocalRPC instead
+// of the last two, without fallbacks.
+func (s *Scanner) configuredRPCURLs(network string) []string {
+	if url := s.cfg.RPCURLs[network]; url != "" {
+		return uniqueURLs(append([]string{url}, s.cfg.FallbackRPCURLs[network]...))
+	}
+	if s.cfg.Local {
+		if url := os.Getenv("LOCAL_RPC_URL"); url != "" {
+			return []string{url}
+		}
+		return []string{DefaultLocalRPC}
+	}
+	if urls := splitURLs(os.Getenv(strings.ToUpper(network) + "_RPC_URL")); len(urls) > 0 {
+		return urls
+	}
+	netCfg := s.networks[network]
+	return uniqueURLs(append([]string{netCfg.DefaultRPC}, netCfg.FallbackRPCs...))
+}
+
+// rpcEndpoints returns the network's endpoints in the order rpcCall tries
+// them: those that have not failed recently in configuration order, then
+// the others, the one that comes out of its cooldown first leading
+func (s *Scanner) rpcEndpoints(network string) []string {
+	urls := s.configuredRPCURLs(network)
+	if len(urls) < 2 {
+		return urls
+	}
+	now := time.Now()
+	s.cacheMu.Lock()
+	downUntil := make([]time.Time, len(urls))
+	for i, url := range urls {
+		if h := s.rpcHealth[url]; h != nil && h.downUntil.After(now) {
+			downUntil[i] = h.downUntil
+		}
+	}
+	s.cacheMu.Unlock()
+
+	order := make([]int, len(urls))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		da, db := downUntil[order[a]], downUntil[order[b]]
+		if da.IsZero() || db.IsZero() {
+			return da.IsZero() && !db.IsZero()
+		}
+		return da.Before(db)
+	})
+	sorted := make([]string, len(urls))
+	for i, j := range order {
+		sorted[i] = urls[j]
+	}
+	return sorted
+}
+
+// recordRPCHealth notes whether an endpoint answered; each failure in a
+// row puts it behind the others for rpcCooldown
+func (s *Scanner) recordRPCHealth(url string, ok bool) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	h := s.rpcHealth[url]
+	if h == nil {
+		h = &rpcHealth{}
+		s.rpcHealth[url] = h
+	}
+	if ok {
+		h.failures, h.downUntil = 0, time.Time{}
+		return
+	}
+	h.failures++
+	h.downUntil = time.Now().Add(rpcCooldown)
+	if h.failures == 1 {
+		s.logger.Debug("rpc endpoint failing, preferring others", "url", redactURL(url), "cooldown", rpcCooldown)
+	}
+}
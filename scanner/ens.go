@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// ENS registry, deployed at the same address on Ethereum mainnet
+const ensRegistry = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+// Function selectors used for ENS lookups
+const (
+	selectorResolver = "0178b8bf" // resolver(bytes32)
+	selectorAddr     = "3b3b57de" // addr(bytes32)
+	selectorName     = "691f3431" // name(bytes32)
+)
+
+var errNoResolver = errors.New("no resolver set")
+
+// IsENSName reports whether input looks like an ENS name rather than an address
+func IsENSName(input string) bool {
+	return strings.HasSuffix(strings.ToLower(input), ".eth")
+}
+
+// namehash computes the EIP-137 namehash of an ENS name
+func namehash(name string) []byte {
+	node := make([]byte, 32)
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := keccak256([]byte(labels[i]))
+		node = keccak256(append(node, labelHash...))
+	}
+	return node
+}
+
+func keccak256(data []byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// resolveENS resolves an ENS name to a checksummed address
+func (s *Scanner) resolveENS(ctx context.Context, name string) (string, error) {
+	node := namehash(name)
+	resolver, err := s.ensResolver(ctx, node)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := s.ethCall(ctx, "ethereum", resolver, "0x"+selectorAddr+hex.EncodeToString(node))
+	if err != nil {
+		return "", err
+	}
+	address, ok := decodeAddressWord(result)
+	if !ok {
+		return "", fmt.Errorf("%s has no address record", name)
+	}
+	return address, nil
+}
+
+// lookupENSName returns the primary ENS name of an address, verified by
+// resolving the name forward again. It returns "" when none is set.
+func (s *Scanner) lookupENSName(ctx context.Context, address string) (string, error) {
+	reverse := strings.ToLower(strings.TrimPrefix(address, "0x")) + ".addr.reverse"
+	node := namehash(reverse)
+	resolver, err := s.ensResolver(ctx, node)
+	if errors.Is(err, errNoResolver) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	result, err := s.ethCall(ctx, "ethereum", resolver, "0x"+selectorName+hex.EncodeToString(node))
+	if err != nil {
+		return "", err
+	}
+	name, err := decodeStringResult(result)
+	if err != nil || name == "" {
+		return "", err
+	}
+
+	// A reverse record is only trustworthy if the name points back
+	forward, err := s.resolveENS(ctx, name)
+	if err != nil || !strings.EqualFold(forward, address) {
+		return "", nil
+	}
+	return name, nil
+}
+
+func (s *Scanner) ensResolver(ctx context.Context, node []byte) (string, error) {
+	result, err := s.ethCall(ctx, "ethereum", ensRegistry, "0x"+selectorResolver+hex.EncodeToString(node))
+	if err != nil {
+		return "", err
+	}
+	resolver, ok := decodeAddressWord(result)
+	if !ok {
+		return "", errNoResolver
+	}
+	return resolver, nil
+}
@@ -0,0 +1,105 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+)
+
+// Errors wrapped by the failures checks degrade on, for errors.Is. ErrAuth
+// marks rejected credentials.
+var (
+	ErrNoAPIKey            = errors.New("no explorer API key configured")
+	ErrNotConfigured       = errors.New("not configured")
+	ErrRateLimited         = errors.New("rate limit reached")
+	ErrRPCUnavailable      = errors.New("RPC endpoint unavailable")
+	ErrExplorerUnavailable = errors.New("block explorer unavailable")
+	ErrNotFound            = errors.New("not found")
+)
+
+// Codes of a CheckError
+const (
+	ErrorNoAPIKey            = "no_api_key"
+	ErrorNotConfigured       = "not_configured"
+	ErrorAuthFailed          = "auth_failed"
+	ErrorRateLimited         = "rate_limited"
+	ErrorRPCUnavailable      = "rpc_unavailable"
+	ErrorExplorerUnavailable = "explorer_unavailable"
+	ErrorNotFound            = "not_found"
+	ErrorTimeout             = "timeout"
+	ErrorFailed              = "failed" // anything else, e.g. a malformed response
+)
+
+// CheckError records why a check could not run on live data; its result in
+// the report is a fallback, not a verdict on the address
+type CheckError struct {
+	Check   string `json:"check"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Retryable is set for transient failures (rate limits, unavailable
+	// services, timeouts) that a later scan may not hit
+	Retryable bool `json:"retryable"`
+}
+
+// ErrorCode classifies err as one of the CheckError codes
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrAuth):
+		return ErrorAuthFailed
+	case errors.Is(err, ErrNoAPIKey):
+		return ErrorNoAPIKey
+	case errors.Is(err, ErrNotConfigured):
+		return ErrorNotConfigured
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return ErrorTimeout
+	case errors.Is(err, ErrRateLimited):
+		return ErrorRateLimited
+	case errors.Is(err, ErrRPCUnavailable):
+		return ErrorRPCUnavailable
+	case errors.Is(err, ErrExplorerUnavailable):
+		return ErrorExplorerUnavailable
+	case errors.Is(err, ErrNotFound):
+		return ErrorNotFound
+	}
+	return ErrorFailed
+}
+
+// newCheckError describes the error a check failed with
+func newCheckError(check string, err error) CheckError {
+	code := ErrorCode(err)
+	retryable := false
+	switch code {
+	case ErrorRateLimited, ErrorRPCUnavailable, ErrorExplorerUnavailable, ErrorTimeout:
+		retryable = true
+	}
+	return CheckError{Check: check, Code: code, Message: err.Error(), Retryable: retryable}
+}
+
+// checkErrors lists the errors behind the fallback results among checks
+func checkErrors(checks []CheckResult) []CheckError {
+	var errs []CheckError
+	for _, check := range checks {
+		if check.err != nil {
+			errs = append(errs, newCheckError(check.Name, check.err))
+		}
+	}
+	return errs
+}
+
+// classifiedError keeps the message of err while making errors.Is match
+// kind, one of the sentinel errors above
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string   { return e.err.Error() }
+func (e *classifiedError) Unwrap() []error { return []error{e.kind, e.err} }
+
+// classify wraps err so that errors.Is(err, kind) holds; context errors are
+// left alone so they still read as timeouts
+func classify(kind, err error) error {
+	if err == nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return err
+	}
+	return &classifiedError{kind: kind, err: err}
+}
@@ -0,0 +1,116 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// maxScoreDrags is how many of the costliest checks an explanation names
+const maxScoreDrags = 3
+
+// ScoreContribution is one check's part in the overall score
+type ScoreContribution struct {
+	Name   string  `json:"name"`
+	ID     string  `json:"id"`
+	Score  int     `json:"score"`
+	Weight float64 `json:"weight"`
+	// Share is the check's fraction of the total weight
+	Share float64 `json:"share"`
+	// Contribution is Score × Share, the points the check adds to the
+	// overall score; Lost is what it gave up against a perfect 100
+	Contribution float64 `json:"contribution"`
+	Lost         float64 `json:"lost"`
+}
+
+// ScoreExplanation shows how a report's checks produced its overall score
+type ScoreExplanation struct {
+	Score         int                 `json:"score"`
+	RiskLevel     string              `json:"risk_level"`
+	TotalWeight   float64             `json:"total_weight"`
+	Contributions []ScoreContribution `json:"contributions"`
+	// EntityBonus is the points a well-known entity label added on top
+	EntityBonus int `json:"entity_bonus,omitempty"`
+	// Drags are the checks that cost the most points, costliest first
+	Drags []ScoreContribution `json:"drags"`
+	// Notes explain a risk level or score that the weighted mean alone
+	// does not
+	Notes []string `json:"notes"`
+}
+
+// Explain breaks report's overall score down into the weighted
+// contribution of each check, using the scanner's current weights
+func (s *Scanner) Explain(report ReputationReport) ScoreExplanation {
+	e := ScoreExplanation{
+		Score:         report.OverallScore,
+		RiskLevel:     report.RiskLevel,
+		Contributions: []ScoreContribution{},
+		Drags:         []ScoreContribution{},
+		Notes:         []string{},
+	}
+	if report.Error != "" {
+		e.Notes = append(e.Notes, "The scan failed, so no checks contributed: "+report.Error)
+		return e
+	}
+	if len(report.Checks) == 1 && report.Checks[0].Name == "Allowlist" {
+		e.Notes = append(e.Notes, "The address is allowlisted, which sets the score to 100 without running checks")
+	}
+
+	notApplicable := 0
+	for _, check := range report.Checks {
+		e.TotalWeight += s.scoreWeight(check)
+		if check.NotApplicable {
+			notApplicable++
+		}
+	}
+	var sum float64
+	for _, check := range report.Checks {
+		c := ScoreContribution{Name: check.Name, ID: CheckID(check.Name), Score: check.Score, Weight: s.scoreWeight(check)}
+		if e.TotalWeight > 0 {
+			c.Share = c.Weight / e.TotalWeight
+		}
+		c.Contribution = float64(c.Score) * c.Share
+		c.Lost = float64(100-c.Score) * c.Share
+		sum += c.Contribution
+		e.Contributions = append(e.Contributions, c)
+	}
+
+	if notApplicable > 0 && notApplicable == len(report.Checks) {
+		sum = 100
+		e.Notes = append(e.Notes, "None of the checks apply to the address, so nothing counted against it")
+	} else if notApplicable > 0 {
+		e.Notes = append(e.Notes, fmt.Sprintf("%d checks do not apply to the address and carry no weight", notApplicable))
+	}
+
+	if report.Entity != nil && report.Entity.TrustBonus > 0 {
+		e.EntityBonus = report.Entity.TrustBonus
+		sum += float64(e.EntityBonus)
+		e.Notes = append(e.Notes, fmt.Sprintf("The address is a known %s (%s), which added %d points to the weighted score", report.Entity.Category, report.Entity.Name, report.Entity.TrustBonus))
+	}
+
+	for _, c := range e.Contributions {
+		if c.Lost > 0 {
+			e.Drags = append(e.Drags, c)
+		}
+	}
+	sort.SliceStable(e.Drags, func(i, j int) bool { return e.Drags[i].Lost > e.Drags[j].Lost })
+	if len(e.Drags) > maxScoreDrags {
+		e.Drags = e.Drags[:maxScoreDrags]
+	}
+
+	if len(e.Contributions) > 0 && math.Abs(sum-float64(report.OverallScore)) > 0.5+1e-9 {
+		e.Notes = append(e.Notes, fmt.Sprintf("The contributions add up to %.1f, but the report scored %d: the weights changed since the scan", sum, report.OverallScore))
+	}
+	for _, check := range report.Checks {
+		if RiskRank(check.Severity) > RiskRank(s.cfg.Thresholds.level(report.OverallScore)) {
+			e.Notes = append(e.Notes, fmt.Sprintf("%s raises the risk level to at least %s whatever the score", check.Name, check.Severity))
+		}
+	}
+	if report.Strict {
+		e.Notes = append(e.Notes, "Strict mode scored warnings as failures, so checks that could not rule out a risk count against the address")
+	}
+	if report.InsufficientData {
+		e.Notes = append(e.Notes, "Too few checks had live or cached data for a low risk level, so it was raised to medium")
+	}
+	return e
+}
@@ -0,0 +1,289 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAuth is wrapped by errors from an RPC endpoint or block explorer that
+// rejected the configured credentials, as opposed to a missing API key
+var ErrAuth = errors.New("credentials rejected")
+
+type explorerResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+type sourceCodeResult struct {
+	SourceCode      string `json:"SourceCode"`
+	ABI             string `json:"ABI"`
+	ContractName    string `json:"ContractName"`
+	CompilerVersion string `json:"CompilerVersion"`
+	Proxy           string `json:"Proxy"`
+	Implementation  string `json:"Implementation"`
+}
+
+// explorerAPI is the block explorer of a network, mapping its responses to
+// the structures the checks consume. Lookups without a match return nil
+// results rather than errors.
+type explorerAPI interface {
+	sourceCode(ctx context.Context, address, network string) (*sourceCodeResult, error)
+	// accountTxs lists "txlist" (normal) or "txlistinternal" transactions
+	accountTxs(ctx context.Context, action, address, network, sort string, pageSize int) ([]explorerTx, error)
+	// logs lists event logs emitted by address (any contract when empty)
+	// with the given first two topics (any when empty)
+	logs(ctx context.Context, network, address, topic0, topic1 string, pageSize int) ([]explorerLog, error)
+	contractCreation(ctx context.Context, address, network string) (*contractCreation, error)
+}
+
+// explorerFor returns the explorer implementation of network's
+// ExplorerType
+func (s *Scanner) explorerFor(network string) explorerAPI {
+	if netCfg, err := s.Network(network); err == nil && netCfg.ExplorerType == ExplorerBlockscout {
+		return blockscoutExplorer{s}
+	}
+	return etherscanExplorer{s}
+}
+
+// explorerFetch requests rawURL from the network's block explorer, adding
+// the API key, and returns the body of a 200 response. Errors come with the
+// HTTP status.
+func (s *Scanner) explorerFetch(ctx context.Context, network, rawURL string) (_ []byte, status int, err error) {
+	start := time.Now()
+	defer func() { s.metrics.recordRequest("explorer", time.Since(start), err) }()
+
+	// A rejected key fails every request the same way
+	if err := s.authError(network, "explorer"); err != nil {
+		return nil, 0, err
+	}
+	data, status, err := s.explorerKeyedGet(ctx, network, rawURL)
+	if err != nil {
+		if status == http.StatusTooManyRequests {
+			return nil, status, classify(ErrRateLimited, err)
+		}
+		return nil, status, classify(ErrExplorerUnavailable, err)
+	}
+	if isAuthStatus(status) {
+		return nil, status, s.recordAuthError(network, "explorer", fmt.Sprintf("HTTP %d", status))
+	}
+	if status != http.StatusOK {
+		return nil, status, classify(ErrExplorerUnavailable, fmt.Errorf("explorer returned HTTP %d", status))
+	}
+	return data, status, nil
+}
+
+// explorerCall queries the network's Etherscan-compatible explorer API,
+// which Blockscout implements too, and returns the raw result
+func (s *Scanner) explorerCall(ctx context.Context, network string, params url.Values) (json.RawMessage, error) {
+	resp, err := s.explorerQuery(ctx, network, params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result, nil
+}
+
+// explorerQuery is explorerCall returning the whole response envelope
+func (s *Scanner) explorerQuery(ctx context.Context, network string, params url.Values) (explorerResponse, error) {
+	netCfg, err := s.Network(network)
+	if err != nil {
+		return explorerResponse{}, err
+	}
+	data, _, err := s.explorerFetch(ctx, network, netCfg.ExplorerAPIURL+"?"+params.Encode())
+	if err != nil {
+		return explorerResponse{}, err
+	}
+
+	var explorerResp explorerResponse
+	if err := json.Unmarshal(data, &explorerResp); err != nil {
+		return explorerResponse{}, classify(ErrExplorerUnavailable, fmt.Errorf("invalid explorer response: %w", err))
+	}
+
+	if explorerResp.Status == "0" && explorerResp.Message == "NOTOK" {
+		// On errors the result field carries a plain string explanation
+		var reason string
+		json.Unmarshal(explorerResp.Result, &reason)
+		// Etherscan-style explorers answer "Invalid API Key" or
+		// "Missing/Invalid API Key" with HTTP 200
+		if strings.Contains(strings.ToLower(reason), "api key") {
+			return explorerResponse{}, s.recordAuthError(network, "explorer", reason)
+		}
+		return explorerResponse{}, classify(ErrRateLimited, fmt.Errorf("explorer API rate limit reached: %s", reason))
+	}
+	return explorerResp, nil
+}
+
+// getSourceCode fetches verified source metadata for a contract
+func (s *Scanner) getSourceCode(ctx context.Context, address, network string) (*sourceCodeResult, error) {
+	key := chainCacheKey(address, network)
+	s.cacheMu.Lock()
+	source, ok := s.sourceCache[key]
+	s.cacheMu.Unlock()
+	if ok {
+		return source, nil
+	}
+
+	source, err := s.explorerFor(network).sourceCode(ctx, address, network)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMu.Lock()
+	s.sourceCache[key] = source
+	s.cacheMu.Unlock()
+	return source, nil
+}
+
+type explorerTx struct {
+	Hash             string `json:"hash"`
+	BlockNumber      string `json:"blockNumber"`
+	TransactionIndex string `json:"transactionIndex"`
+	TimeStamp        string `json:"timeStamp"`
+	From             string `json:"from"`
+	To               string `json:"to"`
+	Value            string `json:"value"`
+	GasPrice         string `json:"gasPrice"`
+	Input            string `json:"input"`
+	IsError          string `json:"isError"`
+}
+
+// getTxList fetches one page of normal transactions for an address
+func (s *Scanner) getTxList(ctx context.Context, address, network, sort string, pageSize int) ([]explorerTx, error) {
+	return s.accountTxs(ctx, "txlist", address, network, sort, pageSize)
+}
+
+// getInternalTxList fetches one page of internal transactions (value
+// transfers made by contract calls) for an address
+func (s *Scanner) getInternalTxList(ctx context.Context, address, network, sort string, pageSize int) ([]explorerTx, error) {
+	return s.accountTxs(ctx, "txlistinternal", address, network, sort, pageSize)
+}
+
+func (s *Scanner) accountTxs(ctx context.Context, action, address, network, sort string, pageSize int) ([]explorerTx, error) {
+	return s.explorerFor(network).accountTxs(ctx, action, address, network, sort, pageSize)
+}
+
+// getLogs fetches up to pageSize event logs, oldest first, with the given
+// first two topics from any contract
+func (s *Scanner) getLogs(ctx context.Context, network, topic0, topic1 string, pageSize int) ([]explorerLog, error) {
+	return s.explorerFor(network).logs(ctx, network, "", topic0, topic1, pageSize)
+}
+
+// getContractLogs fetches up to pageSize event logs emitted by address,
+// oldest first, with the given first topic
+func (s *Scanner) getContractLogs(ctx context.Context, network, address, topic0 string, pageSize int) ([]explorerLog, error) {
+	return s.explorerFor(network).logs(ctx, network, address, topic0, "", pageSize)
+}
+
+// etherscanExplorer talks to Etherscan and the explorers copying its API
+type etherscanExplorer struct {
+	s *Scanner
+}
+
+func (e etherscanExplorer) sourceCode(ctx context.Context, address, network string) (*sourceCodeResult, error) {
+	params := url.Values{}
+	params.Set("module", "contract")
+	params.Set("action", "getsourcecode")
+	params.Set("address", address)
+
+	result, err := e.s.explorerCall(ctx, network, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// result is an array with a single entry per address
+	var entries []sourceCodeResult
+	if err := json.Unmarshal(result, &entries); err != nil {
+		return nil, fmt.Errorf("unexpected getsourcecode result: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("empty getsourcecode result")
+	}
+	return &entries[0], nil
+}
+
+// accountTxParams are the query of one page of an account's transactions
+func accountTxParams(action, address, sort string, pageSize int) url.Values {
+	params := url.Values{}
+	params.Set("module", "account")
+	params.Set("action", action)
+	params.Set("address", address)
+	params.Set("startblock", "0")
+	params.Set("endblock", "99999999")
+	params.Set("page", "1")
+	params.Set("offset", strconv.Itoa(pageSize))
+	params.Set("sort", sort)
+	return params
+}
+
+func (e etherscanExplorer) accountTxs(ctx context.Context, action, address, network, sort string, pageSize int) ([]explorerTx, error) {
+	result, err := e.s.explorerCall(ctx, network, accountTxParams(action, address, sort, pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []explorerTx
+	if err := json.Unmarshal(result, &txs); err != nil {
+		return nil, fmt.Errorf("unexpected %s result: %w", action, err)
+	}
+	return txs, nil
+}
+
+// explorerLog is an event log entry of the explorer's getLogs action
+type explorerLog struct {
+	Address         string   `json:"address"`
+	Topics          []string `json:"topics"`
+	Data            string   `json:"data"`
+	BlockNumber     string   `json:"blockNumber"`
+	TimeStamp       string   `json:"timeStamp"` // hex unix time; some explorers omit it
+	TransactionHash string   `json:"transactionHash"`
+}
+
+// logParams are the query of the first pageSize logs of address with the
+// given first two topics; empty ones are left out
+func logParams(address, topic0, topic1 string, pageSize int) url.Values {
+	params := url.Values{}
+	params.Set("module", "logs")
+	params.Set("action", "getLogs")
+	params.Set("fromBlock", "0")
+	params.Set("toBlock", "latest")
+	if address != "" {
+		params.Set("address", address)
+	}
+	params.Set("topic0", topic0)
+	if topic1 != "" {
+		params.Set("topic0_1_opr", "and")
+		params.Set("topic1", topic1)
+	}
+	params.Set("page", "1")
+	params.Set("offset", strconv.Itoa(pageSize))
+	return params
+}
+
+func (e etherscanExplorer) logs(ctx context.Context, network, address, topic0, topic1 string, pageSize int) ([]explorerLog, error) {
+	result, err := e.s.explorerCall(ctx, network, logParams(address, topic0, topic1, pageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []explorerLog
+	if err := json.Unmarshal(result, &logs); err != nil {
+		return nil, fmt.Errorf("unexpected getLogs result: %w", err)
+	}
+	return logs, nil
+}
+
+// txTime converts an explorer unix timestamp string to time.Time
+func txTime(tx explorerTx) (time.Time, error) {
+	secs, err := strconv.ParseInt(tx.TimeStamp, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", tx.TimeStamp)
+	}
+	return time.Unix(secs, 0), nil
+}
@@ -0,0 +1,502 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Host of the fixture RPC and explorer URLs; requests to other hosts, such
+// as webhooks, go to the network
+const fixtureHost = "fixtures.invalid"
+
+// Fixture scans make no real requests, so the explorer rate limit is lifted
+const fixtureRequestsPerSecond = 1000
+
+// Fixtures are canned chain and explorer data for offline, reproducible
+// scans. Set Config.Fixtures to answer every RPC and explorer request from
+// them; the same accounts apply on every network.
+type Fixtures struct {
+	// Time is the clock of fixture scans, so report timestamps and account
+	// ages are stable; zero means the real time
+	Time time.Time `json:"time"`
+	// Accounts by address. Addresses without an entry are unused EOAs.
+	Accounts map[string]FixtureAccount `json:"accounts"`
+	// Metadata maps NFT metadata URLs, after gateway resolution, to their
+	// documents; other URLs answer HTTP 404
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Logs are the event logs the explorer's getLogs action searches
+	Logs []FixtureLog `json:"logs,omitempty"`
+}
+
+// FixtureLog is an event log emitted by a contract
+type FixtureLog struct {
+	Address string   `json:"address"` // emitting contract
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data,omitempty"`
+	Block   uint64   `json:"block"`
+	TxHash  string   `json:"tx_hash,omitempty"`
+	// Time is the block time; zero leaves timeStamp out, as some
+	// explorers do
+	Time time.Time `json:"time"`
+}
+
+// FixtureAccount is the state and history of one address
+type FixtureAccount struct {
+	Code                 string            `json:"code,omitempty"` // deployed bytecode, hex
+	Nonce                uint64            `json:"nonce"`
+	Transactions         []FixtureTx       `json:"transactions,omitempty"`
+	InternalTransactions []FixtureTx       `json:"internal_transactions,omitempty"`
+	Source               *FixtureSource    `json:"source,omitempty"`  // verified source, if any
+	Creator              string            `json:"creator,omitempty"` // deployer of a contract
+	CreationTx           string            `json:"creation_tx,omitempty"`
+	CreationBlock        uint64            `json:"creation_block,omitempty"`
+	CreationTime         time.Time         `json:"creation_time"`     // zero if the explorer omits it
+	Storage              map[string]string `json:"storage,omitempty"` // slot -> 32-byte word, hex
+	// Calls maps eth_call calldata to its hex return data; calls without
+	// an entry revert
+	Calls map[string]string `json:"calls,omitempty"`
+}
+
+// FixtureTx is a transaction in an account's history
+type FixtureTx struct {
+	Hash   string    `json:"hash"`
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Value  string    `json:"value,omitempty"` // wei, decimal
+	Input  string    `json:"input,omitempty"`
+	Time   time.Time `json:"time"`
+	Failed bool      `json:"failed,omitempty"`
+	Block  uint64    `json:"block,omitempty"`
+	// Index is the position in the block; GasPrice is wei, decimal
+	Index    uint64 `json:"index,omitempty"`
+	GasPrice string `json:"gas_price,omitempty"`
+}
+
+// FixtureSource is verified contract source as returned by the explorer
+type FixtureSource struct {
+	SourceCode      string `json:"source_code"`
+	ABI             string `json:"abi,omitempty"` // JSON, defaults to []
+	ContractName    string `json:"contract_name"`
+	CompilerVersion string `json:"compiler_version,omitempty"`
+	Implementation  string `json:"implementation,omitempty"` // set for proxies
+}
+
+// LoadFixtures reads a fixtures JSON file
+func LoadFixtures(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read fixtures: %w", err)
+	}
+	var f Fixtures
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("invalid fixtures %s: %w", path, err)
+	}
+	for address := range f.Accounts {
+		if !IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid fixtures %s: invalid address %q", path, address)
+		}
+	}
+	return &f, nil
+}
+
+// now returns the fixture clock
+func (f *Fixtures) now() time.Time {
+	if f.Time.IsZero() {
+		return time.Now()
+	}
+	return f.Time
+}
+
+// account looks up address ignoring case
+func (f *Fixtures) account(address string) FixtureAccount {
+	if account, ok := f.Accounts[address]; ok {
+		return account
+	}
+	for key, account := range f.Accounts {
+		if strings.EqualFold(key, address) {
+			return account
+		}
+	}
+	return FixtureAccount{}
+}
+
+// transaction looks up a transaction by hash in the account histories, and
+// the creation transactions of contracts, which return the contract
+func (f *Fixtures) transaction(hash string) (tx FixtureTx, created string, ok bool) {
+	for address, account := range f.Accounts {
+		for _, tx := range account.Transactions {
+			if strings.EqualFold(tx.Hash, hash) {
+				return tx, "", true
+			}
+		}
+		if account.CreationTx != "" && strings.EqualFold(account.CreationTx, hash) {
+			return FixtureTx{Hash: account.CreationTx, From: account.Creator, Block: account.CreationBlock, Input: "0x"}, address, true
+		}
+	}
+	return FixtureTx{}, "", false
+}
+
+// transactionAt looks up the transaction at index of block in the account
+// histories
+func (f *Fixtures) transactionAt(block, index uint64) (FixtureTx, bool) {
+	for _, account := range f.Accounts {
+		for _, tx := range account.Transactions {
+			if tx.Block == block && tx.Index == index {
+				return tx, true
+			}
+		}
+	}
+	return FixtureTx{}, false
+}
+
+// Fixtures implement DataSource, so the history checks read them like any
+// other backend
+
+func (f *Fixtures) FirstTxTime(ctx context.Context, address, network string) (time.Time, bool, error) {
+	txs := f.account(address).Transactions
+	if len(txs) == 0 {
+		return time.Time{}, false, nil
+	}
+	first := txs[0].Time
+	for _, tx := range txs[1:] {
+		if tx.Time.Before(first) {
+			first = tx.Time
+		}
+	}
+	return first, true, nil
+}
+
+func (f *Fixtures) TxCount(ctx context.Context, address, network string) (uint64, error) {
+	return f.account(address).Nonce, nil
+}
+
+func (f *Fixtures) IsContract(ctx context.Context, address, network string) (bool, error) {
+	code, err := decodeHex(f.account(address).Code)
+	return len(code) > 0, err
+}
+
+func (f *Fixtures) recentTxTimes(ctx context.Context, address, network string, n int) ([]time.Time, error) {
+	txs := sortedFixtureTxs(f.account(address).Transactions, "desc", n)
+	times := make([]time.Time, len(txs))
+	for i, tx := range txs {
+		times[i] = tx.Time
+	}
+	return times, nil
+}
+
+func (f *Fixtures) codeSize(ctx context.Context, address, network string) (int, error) {
+	code, err := decodeHex(f.account(address).Code)
+	return len(code), err
+}
+
+// sortedFixtureTxs returns up to n transactions by time, "asc" or "desc"
+func sortedFixtureTxs(txs []FixtureTx, order string, n int) []FixtureTx {
+	sorted := append([]FixtureTx(nil), txs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if order == "desc" {
+			return sorted[i].Time.After(sorted[j].Time)
+		}
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// sortedFixtureLogs orders logs by block, keeping the file order within one
+func sortedFixtureLogs(logs []FixtureLog) []FixtureLog {
+	sorted := append([]FixtureLog(nil), logs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Block < sorted[j].Block })
+	return sorted
+}
+
+// topicMatches reports whether topic i of a log is want; empty matches any
+func topicMatches(topics []string, i int, want string) bool {
+	return want == "" || i < len(topics) && strings.EqualFold(topics[i], want)
+}
+
+func decodeHex(data string) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeHexResult(raw)
+}
+
+// fixtureURL is the RPC endpoint of network under fixtures; the explorer
+// API is at the same URL plus "/api"
+func fixtureURL(network string) string {
+	return "http://" + fixtureHost + "/" + network
+}
+
+// withFixtures points cfg's endpoints at the fixture transport
+func withFixtures(cfg Config) Config {
+	networks := make(map[string]NetworkConfig, len(cfg.Networks))
+	cfg.APIKeys = map[string]string{}
+	cfg.ExtraAPIKeys = nil
+	cfg.RPCURLs = map[string]string{}
+	for name, network := range cfg.Networks {
+		network.ExplorerAPIURL = fixtureURL(name) + "/api"
+		// Fixtures answer in the Etherscan format on every network
+		network.ExplorerType = ExplorerEtherscan
+		networks[name] = network
+		cfg.APIKeys[name] = "fixtures"
+		cfg.RPCURLs[name] = fixtureURL(name)
+	}
+	cfg.Networks = networks
+	cfg.HTTPClient = &http.Client{Transport: fixtureTransport{fixtures: cfg.Fixtures, networks: networks}}
+	if cfg.DataSource == nil {
+		cfg.DataSource = cfg.Fixtures
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = cfg.Fixtures.now
+	}
+	cfg.RequestsPerSecond = fixtureRequestsPerSecond
+	cfg.CacheDir = ""
+	cfg.Local = false
+	return cfg
+}
+
+// fixtureTransport answers JSON-RPC and explorer requests from fixtures
+type fixtureTransport struct {
+	fixtures *Fixtures
+	networks map[string]NetworkConfig
+}
+
+func (t fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != fixtureHost {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	network, explorer := strings.CutSuffix(strings.Trim(req.URL.Path, "/"), "/api")
+	if _, ok := t.networks[network]; !ok {
+		return fixtureResponse(http.StatusNotFound, map[string]string{"error": "unknown network " + network}), nil
+	}
+	if explorer {
+		return fixtureResponse(http.StatusOK, t.explorer(req)), nil
+	}
+
+	var call struct {
+		ID     int               `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &call); err != nil {
+		return fixtureResponse(http.StatusBadRequest, map[string]string{"error": err.Error()}), nil
+	}
+	result, rpcErr := t.rpc(network, call.Method, call.Params)
+	resp := map[string]interface{}{"jsonrpc": "2.0", "id": call.ID}
+	if rpcErr != nil {
+		resp["error"] = rpcErr
+	} else {
+		resp["result"] = result
+	}
+	return fixtureResponse(http.StatusOK, resp), nil
+}
+
+// rpc serves the JSON-RPC methods the checks use
+func (t fixtureTransport) rpc(network, method string, params []json.RawMessage) (interface{}, *rpcError) {
+	param := func(i int) string {
+		var value string
+		if i < len(params) {
+			json.Unmarshal(params[i], &value)
+		}
+		return value
+	}
+	switch method {
+	case "eth_chainId":
+		return "0x" + strconv.FormatInt(t.networks[network].ChainID, 16), nil
+	case "eth_getCode":
+		if code := t.fixtures.account(param(0)).Code; code != "" {
+			return code, nil
+		}
+		return "0x", nil
+	case "eth_getTransactionCount":
+		return "0x" + strconv.FormatUint(t.fixtures.account(param(0)).Nonce, 16), nil
+	case "eth_getStorageAt":
+		slot, _ := new(big.Int).SetString(strings.TrimPrefix(param(1), "0x"), 16)
+		for key, word := range t.fixtures.account(param(0)).Storage {
+			if k, ok := new(big.Int).SetString(strings.TrimPrefix(key, "0x"), 16); ok && slot != nil && k.Cmp(slot) == 0 {
+				return word, nil
+			}
+		}
+		return "0x" + strings.Repeat("0", 64), nil
+	case "eth_call":
+		var msg callMsg
+		if len(params) > 0 {
+			json.Unmarshal(params[0], &msg)
+		}
+		for data, result := range t.fixtures.account(msg.To).Calls {
+			if strings.EqualFold(data, msg.Data) {
+				return result, nil
+			}
+		}
+		return nil, &rpcError{Code: 3, Message: "execution reverted"}
+	case "eth_getTransactionByHash", "eth_getTransactionReceipt", "eth_getTransactionByBlockNumberAndIndex":
+		var tx FixtureTx
+		var created string
+		var ok bool
+		if method == "eth_getTransactionByBlockNumberAndIndex" {
+			block, _ := strconv.ParseUint(strings.TrimPrefix(param(0), "0x"), 16, 64)
+			index, _ := strconv.ParseUint(strings.TrimPrefix(param(1), "0x"), 16, 64)
+			tx, ok = t.fixtures.transactionAt(block, index)
+		} else {
+			tx, created, ok = t.fixtures.transaction(param(0))
+		}
+		if !ok {
+			return nil, nil
+		}
+		block := "0x" + strconv.FormatUint(tx.Block, 16)
+		if method == "eth_getTransactionReceipt" {
+			status := "0x1"
+			if tx.Failed {
+				status = "0x0"
+			}
+			receipt := map[string]interface{}{"status": status, "blockNumber": block, "contractAddress": nil}
+			if created != "" {
+				receipt["contractAddress"] = created
+			}
+			return receipt, nil
+		}
+		value, _ := new(big.Int).SetString(tx.Value, 10)
+		if value == nil {
+			value = new(big.Int)
+		}
+		entry := map[string]interface{}{"hash": tx.Hash, "blockNumber": block, "from": tx.From, "to": tx.To,
+			"value": "0x" + value.Text(16), "input": tx.Input}
+		if created != "" {
+			entry["to"] = nil
+		}
+		return entry, nil
+	default:
+		return nil, &rpcError{Code: -32601, Message: "method " + method + " not available in fixtures"}
+	}
+}
+
+// explorer serves the explorer API actions the checks use
+func (t fixtureTransport) explorer(req *http.Request) map[string]interface{} {
+	query := req.URL.Query()
+	ok := func(result interface{}) map[string]interface{} {
+		return map[string]interface{}{"status": "1", "message": "OK", "result": result}
+	}
+	switch query.Get("action") {
+	case "getsourcecode":
+		entry := sourceCodeResult{}
+		if source := t.fixtures.account(query.Get("address")).Source; source != nil {
+			entry = sourceCodeResult{
+				SourceCode:      source.SourceCode,
+				ABI:             "[]",
+				ContractName:    source.ContractName,
+				CompilerVersion: source.CompilerVersion,
+				Implementation:  source.Implementation,
+			}
+			if source.ABI != "" {
+				entry.ABI = source.ABI
+			}
+			if source.Implementation != "" {
+				entry.Proxy = "1"
+			}
+		}
+		return ok([]sourceCodeResult{entry})
+	case "getcontractcreation":
+		address := query.Get("contractaddresses")
+		account := t.fixtures.account(address)
+		if account.Creator == "" {
+			return map[string]interface{}{"status": "0", "message": "No data found", "result": []contractCreation{}}
+		}
+		creation := contractCreation{ContractAddress: address, ContractCreator: account.Creator, TxHash: account.CreationTx}
+		if !account.CreationTime.IsZero() {
+			creation.BlockNumber = strconv.FormatUint(account.CreationBlock, 10)
+			creation.Timestamp = strconv.FormatInt(account.CreationTime.Unix(), 10)
+		}
+		return ok([]contractCreation{creation})
+	case "txlist", "txlistinternal":
+		account := t.fixtures.account(query.Get("address"))
+		txs := account.Transactions
+		if query.Get("action") == "txlistinternal" {
+			txs = account.InternalTransactions
+		}
+		n, _ := strconv.Atoi(query.Get("offset"))
+		entries := []explorerTx{}
+		for _, tx := range sortedFixtureTxs(txs, query.Get("sort"), n) {
+			isError := "0"
+			if tx.Failed {
+				isError = "1"
+			}
+			entry := explorerTx{
+				Hash:      tx.Hash,
+				TimeStamp: strconv.FormatInt(tx.Time.Unix(), 10),
+				From:      tx.From,
+				To:        tx.To,
+				Value:     tx.Value,
+				GasPrice:  tx.GasPrice,
+				Input:     tx.Input,
+				IsError:   isError,
+			}
+			if tx.Block > 0 {
+				entry.BlockNumber = strconv.FormatUint(tx.Block, 10)
+				entry.TransactionIndex = strconv.FormatUint(tx.Index, 10)
+			}
+			entries = append(entries, entry)
+		}
+		if len(entries) == 0 {
+			return map[string]interface{}{"status": "0", "message": "No transactions found", "result": entries}
+		}
+		return ok(entries)
+	case "getLogs":
+		logs := []explorerLog{}
+		for _, log := range sortedFixtureLogs(t.fixtures.Logs) {
+			if address := query.Get("address"); address != "" && !strings.EqualFold(log.Address, address) {
+				continue
+			}
+			if !topicMatches(log.Topics, 0, query.Get("topic0")) || !topicMatches(log.Topics, 1, query.Get("topic1")) {
+				continue
+			}
+			entry := explorerLog{
+				Address:         log.Address,
+				Topics:          log.Topics,
+				Data:            log.Data,
+				BlockNumber:     "0x" + strconv.FormatUint(log.Block, 16),
+				TransactionHash: log.TxHash,
+			}
+			if !log.Time.IsZero() {
+				entry.TimeStamp = "0x" + strconv.FormatInt(log.Time.Unix(), 16)
+			}
+			logs = append(logs, entry)
+		}
+		if n, _ := strconv.Atoi(query.Get("offset")); n > 0 && len(logs) > n {
+			logs = logs[:n]
+		}
+		if len(logs) == 0 {
+			return map[string]interface{}{"status": "0", "message": "No records found", "result": logs}
+		}
+		return ok(logs)
+	default:
+		return map[string]interface{}{"status": "0", "message": "Unknown action", "result": "action " + query.Get("action") + " not available in fixtures"}
+	}
+}
+
+func fixtureResponse(status int, body interface{}) *http.Response {
+	data, _ := json.Marshal(body)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+	}
+}
@@ -0,0 +1,259 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Fund tracing defaults: the depth used when the Fund Tracing check is
+// selected without Config.TraceDepth, and the budget of a trace
+const (
+	DefaultTraceDepth    = 2
+	DefaultTraceMaxNodes = 100
+	DefaultTraceTimeout  = 20 * time.Second
+)
+
+// Recent transactions of each traced address read for incoming funds, and
+// the senders among them that are followed
+const (
+	traceTxSample = 100
+	traceFanout   = 20
+)
+
+// traceNode is an address reached by walking incoming transfers back from
+// the scanned address; next is the address it sent funds to
+type traceNode struct {
+	address string
+	next    *traceNode
+	depth   int
+}
+
+// path lists the addresses from the node to the scanned address
+func (n *traceNode) path() []string {
+	var path []string
+	for node := n; node != nil; node = node.next {
+		path = append(path, ToChecksumAddress(node.address))
+	}
+	return path
+}
+
+// taintSource names why address is tainted: sanctioned or denylisted
+func (s *Scanner) taintSource(address string) (string, bool) {
+	if source, ok := s.sanctionSource(address); ok {
+		return "sanctioned by " + source, true
+	}
+	if entry, ok := s.lookupDenylist(address); ok {
+		return "denylisted by " + entry.Source, true
+	}
+	return "", false
+}
+
+// senders returns the distinct addresses that most recently sent native
+// currency to address, directly or through internal transactions
+func (s *Scanner) senders(ctx context.Context, address, network string) ([]string, error) {
+	txs, err := s.getTxList(ctx, address, network, "desc", traceTxSample)
+	if err != nil {
+		return nil, err
+	}
+	internal, err := s.getInternalTxList(ctx, address, network, "desc", traceTxSample)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var senders []string
+	for _, tx := range append(txs, internal...) {
+		from := strings.ToLower(tx.From)
+		if !strings.EqualFold(tx.To, address) || tx.Value == "" || tx.Value == "0" || tx.IsError == "1" || seen[from] || !IsHexAddress(from) {
+			continue
+		}
+		seen[from] = true
+		senders = append(senders, from)
+		if len(senders) == traceFanout {
+			break
+		}
+	}
+	return senders, nil
+}
+
+// traceResult is the outcome of a breadth-first walk of incoming funds
+type traceResult struct {
+	tainted  *traceNode // nearest tainted sender, nil if none
+	source   string     // why tainted is tainted
+	visited  int
+	depth    int  // deepest level fully searched
+	exceeded bool // the node or time budget ran out first
+}
+
+// traceFunds walks the senders of address breadth-first, up to depth hops,
+// and stops at the first tainted sender, which is therefore one of the
+// nearest. Contracts and allowlisted addresses are checked but not walked
+// through, since exchanges and routers mix everyone's funds.
+func (s *Scanner) traceFunds(ctx context.Context, address, network string, depth int) (traceResult, error) {
+	maxNodes := s.cfg.TraceMaxNodes
+	if maxNodes <= 0 {
+		maxNodes = DefaultTraceMaxNodes
+	}
+	timeout := s.cfg.TraceTimeout
+	if timeout <= 0 {
+		timeout = DefaultTraceTimeout
+	}
+	traceCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var result traceResult
+	seen := map[string]bool{strings.ToLower(address): true}
+	level := []*traceNode{{address: strings.ToLower(address)}}
+	for hop := 1; hop <= depth && len(level) > 0; hop++ {
+		var next []*traceNode
+		for _, node := range level {
+			if node.depth > 0 {
+				if ok, _ := s.isAllowlisted(node.address); ok {
+					continue
+				}
+				code, err := s.getCode(traceCtx, node.address, network)
+				if err != nil {
+					return s.traceStopped(ctx, traceCtx, result, err)
+				}
+				if len(code) > 0 {
+					continue
+				}
+			}
+			if result.visited >= maxNodes {
+				result.exceeded = true
+				return result, nil
+			}
+			result.visited++
+			senders, err := s.senders(traceCtx, node.address, network)
+			if err != nil {
+				return s.traceStopped(ctx, traceCtx, result, err)
+			}
+			for _, sender := range senders {
+				if seen[sender] {
+					continue
+				}
+				seen[sender] = true
+				reached := &traceNode{address: sender, next: node, depth: hop}
+				if source, ok := s.taintSource(sender); ok {
+					result.tainted, result.source = reached, source
+					return result, nil
+				}
+				next = append(next, reached)
+			}
+		}
+		result.depth = hop
+		level = next
+	}
+	return result, nil
+}
+
+// traceStopped ends a trace on a lookup error: running out of the trace's
+// own time budget keeps the partial result, anything else is an error
+func (s *Scanner) traceStopped(ctx, traceCtx context.Context, result traceResult, err error) (traceResult, error) {
+	if ctx.Err() == nil && traceCtx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
+		result.exceeded = true
+		return result, nil
+	}
+	return result, err
+}
+
+// traceDepth is the number of hops the Fund Tracing check walks
+func (s *Scanner) traceDepth() int {
+	if s.cfg.TraceDepth > 0 {
+		return s.cfg.TraceDepth
+	}
+	return DefaultTraceDepth
+}
+
+// taintPath returns the path from a tainted source to the address found
+// by the Fund Tracing check of this scanner, or nil
+func (s *Scanner) taintPath(address, network string) []string {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	return s.taintCache[chainCacheKey(address, network)]
+}
+
+// checkFundTracing walks incoming native-currency transfers back from the
+// address for up to Config.TraceDepth hops, looking for funds that came
+// from a denylisted or sanctioned address. The shortest path found is
+// added to the report as TaintPath.
+func (s *Scanner) checkFundTracing(ctx context.Context, address, network string) (CheckResult, error) {
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Fund Tracing",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	depth := s.traceDepth()
+	trace, err := s.traceFunds(ctx, address, network, depth)
+	if err != nil {
+		return CheckResult{
+			Name:    "Fund Tracing",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+
+	var path []string
+	if trace.tainted != nil {
+		path = trace.tainted.path()
+	}
+	s.cacheMu.Lock()
+	s.taintCache[chainCacheKey(address, network)] = path
+	s.cacheMu.Unlock()
+
+	if trace.tainted == nil {
+		if trace.exceeded {
+			return CheckResult{
+				Name:       "Fund Tracing",
+				Status:     "pass",
+				Score:      100,
+				Details:    fmt.Sprintf("No tainted funds at trace depth %d of %d (trace budget ran out after %d addresses)", trace.depth, depth, trace.visited),
+				Confidence: 60,
+			}, nil
+		}
+		return CheckResult{
+			Name:    "Fund Tracing",
+			Status:  "pass",
+			Score:   100,
+			Details: fmt.Sprintf("No tainted funds at trace depth %d (%d addresses traced)", depth, trace.visited),
+		}, nil
+	}
+
+	hops := trace.tainted.depth
+	details := fmt.Sprintf("Funds from %s (%s) %d hops away: %s", path[0], trace.source, hops, strings.Join(path, " → "))
+	switch {
+	case hops == 1:
+		return CheckResult{
+			Name:       "Fund Tracing",
+			Status:     "fail",
+			Score:      10,
+			Details:    fmt.Sprintf("Received funds directly from %s (%s)", path[0], trace.source),
+			Severity:   "high",
+			ReasonCode: ReasonTaintedFunds,
+		}, nil
+	case hops == 2:
+		return CheckResult{
+			Name:       "Fund Tracing",
+			Status:     "fail",
+			Score:      30,
+			Details:    details,
+			Severity:   "medium",
+			ReasonCode: ReasonTaintedFunds,
+		}, nil
+	}
+	return CheckResult{
+		Name:       "Fund Tracing",
+		Status:     "warning",
+		Score:      50,
+		Details:    details,
+		ReasonCode: ReasonTaintedFunds,
+	}, nil
+}
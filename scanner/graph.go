@@ -0,0 +1,174 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// graphAccountQuery fetches the Account entity the graph source expects:
+//
+//	type Account @entity {
+//	  id: ID!                     # lowercase address
+//	  firstTxTimestamp: BigInt    # unix seconds; null without transactions
+//	  txCount: BigInt!            # transactions sent
+//	  isContract: Boolean!
+//	}
+const graphAccountQuery = `query Account($id: ID!) {
+  account(id: $id) { firstTxTimestamp txCount isContract }
+}`
+
+type graphRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphResponse struct {
+	Data struct {
+		Account *graphAccount `json:"account"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type graphAccount struct {
+	FirstTxTimestamp *string `json:"firstTxTimestamp"`
+	TxCount          string  `json:"txCount"`
+	IsContract       bool    `json:"isContract"`
+}
+
+// graphSource reads account history from a The Graph subgraph. One query
+// per address answers all three DataSource methods.
+type graphSource struct {
+	s *Scanner
+
+	mu       sync.Mutex
+	accounts map[string]*graphAccount // nil entry: unknown to the subgraph
+}
+
+func newGraphSource(s *Scanner) *graphSource {
+	return &graphSource{s: s, accounts: map[string]*graphAccount{}}
+}
+
+func (g *graphSource) FirstTxTime(ctx context.Context, address, network string) (time.Time, bool, error) {
+	account, err := g.account(ctx, address, network)
+	if err != nil || account == nil || account.FirstTxTimestamp == nil {
+		return time.Time{}, false, err
+	}
+	secs, err := strconv.ParseInt(*account.FirstTxTimestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid firstTxTimestamp %q", *account.FirstTxTimestamp)
+	}
+	return time.Unix(secs, 0), true, nil
+}
+
+func (g *graphSource) TxCount(ctx context.Context, address, network string) (uint64, error) {
+	account, err := g.account(ctx, address, network)
+	if err != nil || account == nil {
+		return 0, err
+	}
+	count, err := strconv.ParseUint(account.TxCount, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid txCount %q", account.TxCount)
+	}
+	return count, nil
+}
+
+func (g *graphSource) IsContract(ctx context.Context, address, network string) (bool, error) {
+	account, err := g.account(ctx, address, network)
+	if err != nil || account == nil {
+		return false, err
+	}
+	return account.IsContract, nil
+}
+
+func (g *graphSource) forget(address, network string) {
+	g.mu.Lock()
+	delete(g.accounts, chainCacheKey(address, network))
+	g.mu.Unlock()
+}
+
+// account returns the subgraph's Account entity, or nil if it has none
+func (g *graphSource) account(ctx context.Context, address, network string) (*graphAccount, error) {
+	key := chainCacheKey(address, network)
+	g.mu.Lock()
+	account, ok := g.accounts[key]
+	g.mu.Unlock()
+	if ok {
+		return account, nil
+	}
+
+	account, err := g.s.graphQuery(ctx, network, strings.ToLower(address))
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	g.accounts[key] = account
+	g.mu.Unlock()
+	return account, nil
+}
+
+// graphQuery runs graphAccountQuery against the network's subgraph
+func (s *Scanner) graphQuery(ctx context.Context, network, id string) (_ *graphAccount, err error) {
+	began := time.Now()
+	defer func() { s.metrics.recordRequest("graph", time.Since(began), err) }()
+
+	netCfg, err := s.Network(network)
+	if err != nil {
+		return nil, err
+	}
+	url := netCfg.GraphURL
+	if url == "" {
+		return nil, fmt.Errorf("no subgraph configured for %s (set %s_GRAPH_URL)", network, strings.ToUpper(network))
+	}
+
+	body, err := json.Marshal(graphRequest{Query: graphAccountQuery, Variables: map[string]interface{}{"id": id}})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, redactError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	s.logger.Debug("http request", "method", http.MethodPost, "url", redactURL(url), "graphql", "account")
+	s.logger.Log(ctx, LevelTrace, "graphql payload", "body", string(body))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		err = redactError(err)
+		s.logger.Debug("http error", "url", redactURL(url), "err", err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Debug("http response", "status", resp.StatusCode, "bytes", len(data), "elapsed", time.Since(start).Round(time.Millisecond))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("subgraph returned HTTP %d", resp.StatusCode)
+	}
+
+	var graphResp graphResponse
+	if err := json.Unmarshal(data, &graphResp); err != nil {
+		return nil, fmt.Errorf("invalid subgraph response: %w", err)
+	}
+	if len(graphResp.Errors) > 0 {
+		return nil, fmt.Errorf("subgraph error: %s", graphResp.Errors[0].Message)
+	}
+	return graphResp.Data.Account, nil
+}
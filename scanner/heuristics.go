@@ -0,0 +1,157 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Hits listed in the details of the Source Heuristics check
+const maxHeuristicHits = 5
+
+var (
+	txOriginAuth   = regexp.MustCompile(`tx\.origin\s*[!=]=|[!=]=\s*tx\.origin`)
+	delegateTarget = regexp.MustCompile(`\b([A-Za-z_]\w*)\s*\.\s*delegatecall\s*\(|functionDelegateCall\s*\(\s*([A-Za-z_]\w*)`)
+	lengthBound    = regexp.MustCompile(`\bfor\s*\(.*;\s*\w+\s*<=?\s*([A-Za-z_]\w*)\s*\.\s*length\b`)
+	externalCall   = regexp.MustCompile(`\.\s*call\s*(\{|\.value|\()`)
+	stateAssign    = regexp.MustCompile(`(?:^|[^\w.])([A-Za-z_]\w*)\s*(?:\[[^\]]*\]\s*)*(?:\.\s*\w+\s*)*(?:[-+*/|&]?=[^=]|\+\+|--)`)
+	stateDelete    = regexp.MustCompile(`\bdelete\s+([A-Za-z_]\w*)`)
+)
+
+// heuristicHit is one red-flag pattern at a source line
+type heuristicHit struct {
+	kind string
+	file string
+	line int
+	note string
+}
+
+func (h heuristicHit) String() string {
+	return fmt.Sprintf("%s at %s:%d (%s)", h.kind, h.file, h.line, h.note)
+}
+
+// checkSourceHeuristics looks for red-flag patterns in verified Solidity
+// source: external calls before state updates, tx.origin authorization,
+// loops bounded by user-controlled arrays and delegatecall to caller
+// supplied addresses. It is a heuristic, not an analyzer.
+func (s *Scanner) checkSourceHeuristics(ctx context.Context, address, network string) (CheckResult, error) {
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Source Heuristics",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+	source, err := s.getSourceCode(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Source Heuristics",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+	if source.SourceCode == "" {
+		return CheckResult{
+			Name:          "Source Heuristics",
+			Status:        "pass",
+			Score:         100,
+			Details:       "No verified source (source heuristics not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	hits := sourceHeuristics(parseSolidity(solidityFiles(source)))
+	if len(hits) == 0 {
+		return CheckResult{
+			Name:    "Source Heuristics",
+			Status:  "pass",
+			Score:   100,
+			Details: "No reentrancy, tx.origin, unbounded loop or delegatecall patterns found",
+		}, nil
+	}
+
+	kinds := map[string]bool{}
+	listed := make([]string, 0, maxHeuristicHits)
+	for i, hit := range hits {
+		kinds[hit.kind] = true
+		if i < maxHeuristicHits {
+			listed = append(listed, hit.String())
+		}
+	}
+	details := strings.Join(listed, "; ")
+	if len(hits) > maxHeuristicHits {
+		details += fmt.Sprintf("; and %d more", len(hits)-maxHeuristicHits)
+	}
+	return CheckResult{
+		Name:       "Source Heuristics",
+		Status:     "warning",
+		Score:      85 - 15*len(kinds),
+		Details:    details,
+		ReasonCode: ReasonSourceRedFlags,
+	}, nil
+}
+
+// sourceHeuristics returns the hits outside vendored libraries in source order
+func sourceHeuristics(contracts solContracts) []heuristicHit {
+	var hits []heuristicHit
+	for _, fn := range contracts.functions {
+		if fn.vendored {
+			continue
+		}
+		hit := func(kind string, line int, note string) {
+			hits = append(hits, heuristicHit{kind: kind, file: fn.file, line: line, note: note})
+		}
+
+		var pendingCall *solLine
+		for i, line := range fn.body {
+			if txOriginAuth.MatchString(line.text) {
+				hit("tx.origin auth", line.n, "phishable; use msg.sender")
+			}
+			for _, m := range delegateTarget.FindAllStringSubmatch(line.text, -1) {
+				target := m[1] + m[2]
+				if strings.HasPrefix(fn.params[target], "address") {
+					hit("delegatecall to user-supplied address", line.n, "target is parameter "+target)
+				}
+			}
+			if m := lengthBound.FindStringSubmatch(line.text); m != nil {
+				array := m[1]
+				switch {
+				case strings.Contains(fn.params[array], "[]"):
+					hit("unbounded loop", line.n, "over caller-supplied array "+array)
+				case contracts.stateArrays[array] && contracts.pushed[array]:
+					hit("unbounded loop", line.n, "over growing array "+array)
+				}
+			}
+
+			if strings.Contains(fn.modifiers, "nonReentrant") {
+				continue
+			}
+			if pendingCall == nil && externalCall.MatchString(line.text) {
+				pendingCall = &fn.body[i]
+				continue
+			}
+			if pendingCall != nil {
+				if name, ok := stateWrite(line.text, contracts.stateVars); ok {
+					hit("reentrancy", pendingCall.n, fmt.Sprintf("external call before %s is updated on line %d", name, line.n))
+					pendingCall = nil
+				}
+			}
+		}
+	}
+	return hits
+}
+
+// stateWrite reports the first state variable assigned or deleted on line
+func stateWrite(line string, stateVars map[string]bool) (string, bool) {
+	for _, re := range []*regexp.Regexp{stateAssign, stateDelete} {
+		for _, m := range re.FindAllStringSubmatch(line, -1) {
+			if stateVars[m[1]] {
+				return m[1], true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Checks backed by address lists; a new failure is reported as a list hit
+var listChecks = map[string]bool{
+	"Known Patterns": true,
+	"Mixer Exposure": true,
+	"Sanctions":      true,
+}
+
+// DefaultHistoryDir is where `scanner diff` keeps past reports
+func DefaultHistoryDir() string {
+	return filepath.Join(DefaultConfigDir(), "history")
+}
+
+// historyPath is the append-only JSON lines file of an address's reports
+func historyPath(dir, address string) string {
+	return filepath.Join(dir, strings.ToLower(address)+".jsonl")
+}
+
+// AppendHistory adds report as one line to its address's history file in dir
+func AppendHistory(dir string, report ReputationReport) error {
+	line, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(historyPath(dir, report.Address), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadHistory returns the stored reports of address on network, oldest
+// first. A missing history file is not an error.
+func LoadHistory(dir, address, network string) ([]ReputationReport, error) {
+	path := historyPath(dir, address)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var reports []ReputationReport
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(strings.TrimSpace(sc.Text())) == 0 {
+			continue
+		}
+		var report ReputationReport
+		if err := json.Unmarshal(sc.Bytes(), &report); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if report.Network == network {
+			reports = append(reports, report)
+		}
+	}
+	return reports, sc.Err()
+}
+
+// CheckChange is a check whose status differs between two reports. Before
+// is empty for a check the previous report did not run.
+type CheckChange struct {
+	Name        string `json:"name"`
+	Before      string `json:"before,omitempty"`
+	After       string `json:"after"`
+	ScoreBefore int    `json:"score_before"`
+	ScoreAfter  int    `json:"score_after"`
+	Details     string `json:"details"` // from the current report
+}
+
+// ReportDiff describes how an address's reputation changed between scans
+type ReportDiff struct {
+	Address      string        `json:"address"`
+	Network      string        `json:"network"`
+	Previous     time.Time     `json:"previous"`
+	Current      time.Time     `json:"current"`
+	ScoreBefore  int           `json:"score_before"`
+	ScoreAfter   int           `json:"score_after"`
+	ScoreDelta   int           `json:"score_delta"`
+	RiskBefore   string        `json:"risk_before"`
+	RiskAfter    string        `json:"risk_after"`
+	ListHits     []CheckChange `json:"list_hits"`     // newly failing list-backed checks
+	NewlyFailing []CheckChange `json:"newly_failing"` // other checks that now fail
+	Recovered    []CheckChange `json:"recovered"`     // failing or warning before, passing now
+	Changed      []CheckChange `json:"changed"`       // any other status change
+}
+
+// Unchanged reports whether the score, risk level and every check status
+// are the same in both reports
+func (d ReportDiff) Unchanged() bool {
+	return d.ScoreDelta == 0 && d.RiskBefore == d.RiskAfter &&
+		len(d.ListHits)+len(d.NewlyFailing)+len(d.Recovered)+len(d.Changed) == 0
+}
+
+// DiffReports compares two reports of the same address, in check order of
+// the current report
+func DiffReports(previous, current ReputationReport) ReportDiff {
+	diff := ReportDiff{
+		Address:      current.Address,
+		Network:      current.Network,
+		Previous:     previous.Timestamp,
+		Current:      current.Timestamp,
+		ScoreBefore:  previous.OverallScore,
+		ScoreAfter:   current.OverallScore,
+		ScoreDelta:   current.OverallScore - previous.OverallScore,
+		RiskBefore:   previous.RiskLevel,
+		RiskAfter:    current.RiskLevel,
+		ListHits:     []CheckChange{},
+		NewlyFailing: []CheckChange{},
+		Recovered:    []CheckChange{},
+		Changed:      []CheckChange{},
+	}
+
+	before := map[string]CheckResult{}
+	for _, check := range previous.Checks {
+		before[check.Name] = check
+	}
+	for _, check := range current.Checks {
+		old, ran := before[check.Name]
+		if ran && old.Status == check.Status {
+			continue
+		}
+		change := CheckChange{
+			Name:        check.Name,
+			Before:      old.Status,
+			After:       check.Status,
+			ScoreBefore: old.Score,
+			ScoreAfter:  check.Score,
+			Details:     check.Details,
+		}
+		switch {
+		case check.Status == "fail" && listChecks[check.Name]:
+			diff.ListHits = append(diff.ListHits, change)
+		case check.Status == "fail":
+			diff.NewlyFailing = append(diff.NewlyFailing, change)
+		case check.Status == "pass" && ran:
+			diff.Recovered = append(diff.Recovered, change)
+		case ran || check.Status != "pass":
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,264 @@
+package scanner
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Honeypot simulation parameters
+const (
+	// Simulated trade size: 0.1 of the native token
+	honeypotTradeWei = 100_000_000_000_000_000
+	// A simulated swap must return at least this share of the router quote
+	honeypotMinOutPercent = 50
+	// Storage slots probed when locating the balanceOf/allowance mappings
+	honeypotMaxSlotProbe = 12
+)
+
+// honeypotTrader is the simulated wallet; its balances are injected with
+// state overrides so it never needs real funds
+const honeypotTrader = "0x5eed5eed5eed5eed5eed5eed5eed5eed5eed5eed"
+
+// Function signatures used by the simulation
+const (
+	sigDecimals          = "decimals()"
+	sigBalanceOf         = "balanceOf(address)"
+	sigAllowance         = "allowance(address,address)"
+	sigGetAmountsOut     = "getAmountsOut(uint256,address[])"
+	sigBuySupportingFee  = "swapExactETHForTokensSupportingFeeOnTransferTokens(uint256,address[],address,uint256)"
+	sigSellSupportingFee = "swapExactTokensForETHSupportingFeeOnTransferTokens(uint256,uint256,address[],address,uint256)"
+)
+
+// checkHoneypot simulates a buy and a sell of the token through the
+// network's V2 router with eth_call. Sells that revert or return far less
+// than quoted indicate a honeypot.
+func (s *Scanner) checkHoneypot(ctx context.Context, address, network string) (CheckResult, error) {
+	netCfg, err := s.Network(network)
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Honeypot Simulation",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+	if len(code) == 0 {
+		return CheckResult{
+			Name:          "Honeypot Simulation",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (honeypot simulation not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+	if netCfg.SwapRouter == "" || netCfg.WrappedNative == "" {
+		return CheckResult{
+			Name:          "Honeypot Simulation",
+			Status:        "pass",
+			Score:         100,
+			Details:       fmt.Sprintf("No swap router configured for %s (simulation not applicable)", network),
+			NotApplicable: true,
+		}, nil
+	}
+	// A revert or a short answer means no ERC-20; other errors say nothing
+	// about the token and must not pass it
+	result, err := s.ethCall(ctx, network, address, encodeCall(sigDecimals))
+	if err != nil && !isRevert(err) {
+		return simulationFailed(err)
+	}
+	if err != nil || len(result) < 32 {
+		return CheckResult{
+			Name:          "Honeypot Simulation",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not an ERC-20 token (simulation not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	router, weth := netCfg.SwapRouter, netCfg.WrappedNative
+	tradeWei := big.NewInt(honeypotTradeWei)
+	deadline := big.NewInt(time.Now().Add(time.Hour).Unix())
+
+	// Buy: quote, then simulate with a funded trader
+	quote, err := s.amountsOut(ctx, network, router, tradeWei, weth, address)
+	if err != nil {
+		if isRevert(err) {
+			return CheckResult{
+				Name:       "Honeypot Simulation",
+				Status:     "warning",
+				Score:      60,
+				Details:    "No router liquidity for this token; simulation skipped",
+				ReasonCode: ReasonSimulationIncomplete,
+			}, nil
+		}
+		return simulationFailed(err)
+	}
+	_, err = s.simulateCall(ctx, network, callMsg{
+		From:  honeypotTrader,
+		To:    router,
+		Value: hexBig(tradeWei),
+		Data:  encodeCall(sigBuySupportingFee, minOut(quote), []string{weth, address}, honeypotTrader, deadline),
+	}, map[string]accountOverride{
+		honeypotTrader: {Balance: hexBig(new(big.Int).Mul(tradeWei, big.NewInt(10)))},
+	})
+	if isRevert(err) {
+		return honeypotDetected("buy simulation failed")
+	} else if err != nil {
+		return simulationFailed(err)
+	}
+
+	// Sell: give the trader the bought tokens and a router allowance by
+	// writing the token's storage, then simulate the swap back
+	balanceSlot, ok, err := s.findMappingSlot(ctx, network, address, quote, func(slot int) (string, string) {
+		return mappingKey(honeypotTrader, slot), encodeCall(sigBalanceOf, honeypotTrader)
+	})
+	if err != nil {
+		return simulationFailed(err)
+	}
+	if !ok {
+		return CheckResult{
+			Name:       "Honeypot Simulation",
+			Status:     "warning",
+			Score:      60,
+			Details:    "Buy simulation succeeded; token balance storage not found, sell not simulated",
+			Confidence: 50,
+			ReasonCode: ReasonSimulationIncomplete,
+		}, nil
+	}
+	allowanceSlot, ok, err := s.findMappingSlot(ctx, network, address, quote, func(slot int) (string, string) {
+		return nestedMappingKey(honeypotTrader, router, slot), encodeCall(sigAllowance, honeypotTrader, router)
+	})
+	if err != nil {
+		return simulationFailed(err)
+	}
+	if !ok {
+		return CheckResult{
+			Name:       "Honeypot Simulation",
+			Status:     "warning",
+			Score:      60,
+			Details:    "Buy simulation succeeded; token allowance storage not found, sell not simulated",
+			Confidence: 50,
+			ReasonCode: ReasonSimulationIncomplete,
+		}, nil
+	}
+
+	sellQuote, err := s.amountsOut(ctx, network, router, quote, address, weth)
+	if isRevert(err) {
+		return honeypotDetected("sell quote failed")
+	} else if err != nil {
+		return simulationFailed(err)
+	}
+	_, err = s.simulateCall(ctx, network, callMsg{
+		From: honeypotTrader,
+		To:   router,
+		Data: encodeCall(sigSellSupportingFee, quote, minOut(sellQuote), []string{address, weth}, honeypotTrader, deadline),
+	}, map[string]accountOverride{
+		address: {StateDiff: map[string]string{
+			balanceSlot:   hexWord(quote),
+			allowanceSlot: hexWord(quote),
+		}},
+	})
+	if isRevert(err) {
+		return honeypotDetected("sell simulation failed")
+	} else if err != nil {
+		return simulationFailed(err)
+	}
+
+	return CheckResult{
+		Name:    "Honeypot Simulation",
+		Status:  "pass",
+		Score:   100,
+		Details: "Buy and sell simulations succeeded via " + router,
+	}, nil
+}
+
+// amountsOut returns the router quote for the last hop of path
+func (s *Scanner) amountsOut(ctx context.Context, network, router string, amountIn *big.Int, path ...string) (*big.Int, error) {
+	result, err := s.ethCall(ctx, network, router, encodeCall(sigGetAmountsOut, amountIn, path))
+	if err != nil {
+		return nil, err
+	}
+	amounts, err := decodeUintArray(result)
+	if err != nil {
+		return nil, err
+	}
+	if len(amounts) != len(path) || amounts[len(amounts)-1].Sign() == 0 {
+		return nil, fmt.Errorf("unexpected getAmountsOut result")
+	}
+	return amounts[len(amounts)-1], nil
+}
+
+// findMappingSlot locates a Solidity mapping by writing value to each
+// candidate storage key and reading it back through the token's getter.
+// keyFor returns the storage key and getter calldata for a slot index.
+func (s *Scanner) findMappingSlot(ctx context.Context, network, token string, value *big.Int, keyFor func(slot int) (string, string)) (string, bool, error) {
+	for slot := 0; slot < honeypotMaxSlotProbe; slot++ {
+		key, data := keyFor(slot)
+		result, err := s.simulateCall(ctx, network, callMsg{To: token, Data: data}, map[string]accountOverride{
+			token: {StateDiff: map[string]string{key: hexWord(value)}},
+		})
+		if err != nil {
+			if isRevert(err) {
+				continue
+			}
+			return "", false, err
+		}
+		if len(result) >= 32 && new(big.Int).SetBytes(result[:32]).Cmp(value) == 0 {
+			return key, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// mappingKey is the storage key of mapping(address => ...) at slot
+func mappingKey(address string, slot int) string {
+	return "0x" + hex.EncodeToString(keccak256(append(addressWord(address), uintWord(big.NewInt(int64(slot)))...)))
+}
+
+// nestedMappingKey is the storage key of mapping(address => mapping(address => ...))[outer][inner] at slot
+func nestedMappingKey(outer, inner string, slot int) string {
+	outerKey := keccak256(append(addressWord(outer), uintWord(big.NewInt(int64(slot)))...))
+	return "0x" + hex.EncodeToString(keccak256(append(addressWord(inner), outerKey...)))
+}
+
+func minOut(quote *big.Int) *big.Int {
+	out := new(big.Int).Mul(quote, big.NewInt(honeypotMinOutPercent))
+	return out.Div(out, big.NewInt(100))
+}
+
+func hexBig(v *big.Int) string {
+	return "0x" + v.Text(16)
+}
+
+func hexWord(v *big.Int) string {
+	return "0x" + hex.EncodeToString(uintWord(v))
+}
+
+func honeypotDetected(reason string) (CheckResult, error) {
+	return CheckResult{
+		Name:       "Honeypot Simulation",
+		Status:     "fail",
+		Score:      0,
+		Severity:   "critical",
+		Details:    "Possible honeypot — " + reason,
+		ReasonCode: ReasonHoneypotSuspected,
+	}, nil
+}
+
+func simulationFailed(err error) (CheckResult, error) {
+	return CheckResult{
+		Name:    "Honeypot Simulation",
+		Status:  "warning",
+		Score:   50,
+		Details: "Simulation failed: " + err.Error(),
+	}, err
+}
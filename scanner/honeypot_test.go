@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// rpcStub answers eth_getCode with contract code and eth_call with call
+func rpcStub(t *testing.T, call func(w http.ResponseWriter, id json.RawMessage)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch req.Method {
+		case "eth_getCode":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":"0x6080604052"}`))
+		case "eth_call":
+			call(w, req.ID)
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":-32601,"message":"method not found"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckHoneypotDecimalsProbe(t *testing.T) {
+	tests := []struct {
+		name          string
+		call          func(w http.ResponseWriter, id json.RawMessage)
+		notApplicable bool
+		failed        bool
+	}{
+		{"revert", func(w http.ResponseWriter, id json.RawMessage) {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"error":{"code":3,"message":"execution reverted"}}`))
+		}, true, false},
+		{"short result", func(w http.ResponseWriter, id json.RawMessage) {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":"0x"}`))
+		}, true, false},
+		{"rate limited", func(w http.ResponseWriter, id json.RawMessage) {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(id) + `,"error":{"code":-32005,"message":"rate limit exceeded"}}`))
+		}, false, true},
+		{"outage", func(w http.ResponseWriter, _ json.RawMessage) {
+			w.WriteHeader(http.StatusBadGateway)
+		}, false, true},
+	}
+	for _, tt := range tests {
+		server := rpcStub(t, tt.call)
+		s := NewScanner(Config{RPCURLs: map[string]string{"ethereum": server.URL}, MaxRetries: -1, RetryDelay: -1})
+		got, err := s.checkHoneypot(context.Background(), "0x4444444444444444444444444444444444444444", "ethereum")
+		if got.NotApplicable != tt.notApplicable || (err != nil) != tt.failed {
+			t.Errorf("%s: %s %d %q, error %v; want not applicable %v, failed %v",
+				tt.name, got.Status, got.Score, got.Details, err, tt.notApplicable, tt.failed)
+		}
+		if tt.failed && (got.Status != "warning" || got.Score != 50) {
+			t.Errorf("%s: %s %d, want the simulation failure warning", tt.name, got.Status, got.Score)
+		}
+	}
+}
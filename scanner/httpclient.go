@@ -0,0 +1,230 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Upper bound on time spent retrying a single request
+const maxRetryElapsed = 30 * time.Second
+
+// proxyEnv are the standard proxy variables, in the order net/http
+// consults them for https URLs, then http ones
+var proxyEnv = []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"}
+
+// NewHTTPClient returns a client for all RPC, explorer and other HTTP
+// requests. Requests go through proxyURL, else the proxy named by
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY. caBundle is a PEM file of root
+// certificates trusted besides the system ones, such as that of a
+// TLS-intercepting gateway.
+func NewHTTPClient(proxyURL, caBundle string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", proxyURL)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", u.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	if caBundle != "" {
+		pem, err := os.ReadFile(caBundle)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s holds no PEM certificates", caBundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// logProxy logs at debug which proxy and CA bundle a client built by
+// NewHTTPClient uses
+func logProxy(logger *slog.Logger, proxyURL, caBundle string) {
+	proxy, source := proxyURL, "config"
+	for _, name := range proxyEnv {
+		if proxy != "" {
+			break
+		}
+		proxy, source = os.Getenv(name), name
+	}
+	if proxy == "" {
+		logger.Debug("no http proxy")
+	} else if u, err := url.Parse(proxy); err == nil {
+		logger.Debug("http proxy", "proxy", u.Redacted(), "from", source, "no_proxy", os.Getenv("NO_PROXY"))
+	}
+	if caBundle != "" {
+		logger.Debug("extra root certificates", "ca_bundle", caBundle)
+	}
+}
+
+// retryClient retries idempotent GET requests on transient failures
+type retryClient struct {
+	client     *http.Client
+	maxRetries int
+	baseDelay  time.Duration
+	maxElapsed time.Duration // upper bound on total time spent, including retries
+	timeout    time.Duration // per-attempt timeout
+	limiter    *rateLimiter  // waited on before every attempt and fed each response's headers
+	header     http.Header   // sent with every request, e.g. Authorization
+	logger     *slog.Logger
+}
+
+// get fetches url and returns the response body of the first non-transient
+// response. 429 and 5xx responses and network errors are retried with
+// exponential backoff and jitter, honoring Retry-After when present.
+// Retrying stops as soon as ctx is done.
+func (c *retryClient) get(ctx context.Context, url string) ([]byte, int, error) {
+	return c.getRotating(ctx, func(context.Context) (string, error) { return url, nil }, nil)
+}
+
+// getRotating is get asking next for the URL of each attempt, e.g. with
+// another API key. observe, when set, is given each response instead of the
+// shared rate limiter and reports whether the URL was rate limited, even
+// with HTTP 200; limited attempts are retried at once, leaving next to wait
+// when all it has left are limited URLs. The body of a limited HTTP 200 is
+// returned once the retries run out.
+func (c *retryClient) getRotating(ctx context.Context, next func(ctx context.Context) (string, error), observe func(url string, status int, header http.Header, body []byte) bool) ([]byte, int, error) {
+	deadline := time.Now().Add(c.maxElapsed)
+
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, 0, err
+			}
+		}
+		url, err := next(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		body, status, header, err := c.do(ctx, url)
+		if ctx.Err() != nil {
+			return nil, status, ctx.Err()
+		}
+		limited := false
+		if observe != nil {
+			limited = observe(url, status, header, body)
+		} else if c.limiter != nil && header != nil {
+			if pause := c.limiter.observe(header); pause > 0 {
+				c.logger.Debug("rate limit pause", "delay", pause.Round(time.Millisecond), "remaining", header.Get("X-RateLimit-Remaining"))
+			}
+		}
+		if err == nil && !isTransientStatus(status) && (!limited || attempt >= c.maxRetries) {
+			return body, status, nil
+		}
+		if err == nil && limited {
+			err = fmt.Errorf("rate limited (HTTP %d)", status)
+		} else if err == nil {
+			err = fmt.Errorf("HTTP %d", status)
+		}
+		if attempt >= c.maxRetries {
+			return nil, status, fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+		}
+
+		var delay time.Duration
+		if !limited {
+			delay = parseRetryAfter(header.Get("Retry-After"))
+			if delay == 0 {
+				delay = c.backoff(attempt)
+			}
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return nil, status, fmt.Errorf("retry budget of %s exhausted: %w", c.maxElapsed, err)
+		}
+		c.logger.Info("retrying request", "attempt", attempt+1, "delay", delay.Round(time.Millisecond), "err", err)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, status, err
+		}
+	}
+}
+
+func (c *retryClient) do(ctx context.Context, url string) ([]byte, int, http.Header, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, nil, redactError(err)
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+
+	start := time.Now()
+	c.logger.Debug("http request", "method", http.MethodGet, "url", redactURL(url))
+	resp, err := c.client.Do(req)
+	if err != nil {
+		err = redactError(err)
+		c.logger.Debug("http error", "url", redactURL(url), "err", err)
+		return nil, 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, resp.Header, err
+	}
+	c.logger.Debug("http response", "status", resp.StatusCode, "bytes", len(body), "elapsed", time.Since(start).Round(time.Millisecond))
+	return body, resp.StatusCode, resp.Header, nil
+}
+
+// backoff returns base * 2^attempt plus up to 50% random jitter
+func (c *retryClient) backoff(attempt int) time.Duration {
+	delay := c.baseDelay << attempt
+	if delay <= 0 {
+		return c.baseDelay
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// sleepContext sleeps for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isTransientStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// parseRetryAfter accepts both delay-seconds and HTTP-date forms
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// majorTokenNames are the names the major tokens go by on every network,
+// by symbol; a token taking one of these names impersonates the symbol
+var majorTokenNames = map[string][]string{
+	"DAI":   {"Dai Stablecoin", "Dai"},
+	"USDC":  {"USD Coin"},
+	"USDT":  {"Tether USD", "Tether"},
+	"WBTC":  {"Wrapped BTC", "Wrapped Bitcoin"},
+	"WETH":  {"Wrapped Ether", "Wrapped ETH"},
+	"WXDAI": {"Wrapped XDAI"},
+}
+
+// tokenKey folds a token name or symbol for comparison: lowercase letters
+// and digits only, so "USD Coin", "usd-coin" and "USDCoin" match
+func tokenKey(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// impersonatedToken returns the major token of the network whose symbol or
+// name info copies, and its canonical address
+func impersonatedToken(netCfg NetworkConfig, info TokenInfo) (symbol, canonical string, ok bool) {
+	symbols := make([]string, 0, len(netCfg.MajorTokens))
+	for major := range netCfg.MajorTokens {
+		symbols = append(symbols, major)
+	}
+	sort.Strings(symbols)
+
+	symbolKey, nameKey := tokenKey(info.Symbol), tokenKey(info.Name)
+	for _, major := range symbols {
+		keys := []string{tokenKey(major)}
+		for _, name := range majorTokenNames[strings.ToUpper(major)] {
+			keys = append(keys, tokenKey(name))
+		}
+		for _, key := range keys {
+			if key != "" && (key == symbolKey || key == nameKey) {
+				return major, netCfg.MajorTokens[major], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// checkTokenImpersonation flags tokens whose symbol or name copies one of
+// the network's major tokens (NetworkConfig.MajorTokens) while the
+// address is not the canonical one, a common way to pass off a worthless
+// token as USDC or WETH
+func (s *Scanner) checkTokenImpersonation(ctx context.Context, address, network string) (CheckResult, error) {
+	netCfg, err := s.Network(network)
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	info, err := s.tokenInfo(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Token Impersonation",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+	if info == nil {
+		return CheckResult{
+			Name:          "Token Impersonation",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not an ERC-20 token (impersonation not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	symbol, canonical, ok := impersonatedToken(netCfg, *info)
+	if !ok {
+		return CheckResult{
+			Name:    "Token Impersonation",
+			Status:  "pass",
+			Score:   100,
+			Details: fmt.Sprintf("%s does not copy a major %s token", info.Symbol, network),
+		}, nil
+	}
+	if strings.EqualFold(canonical, address) {
+		return CheckResult{
+			Name:    "Token Impersonation",
+			Status:  "pass",
+			Score:   100,
+			Details: fmt.Sprintf("Canonical %s token on %s", symbol, network),
+		}, nil
+	}
+	label := info.Symbol
+	if info.Name != "" {
+		label = fmt.Sprintf("%s (%s)", info.Name, info.Symbol)
+	}
+	return CheckResult{
+		Name:       "Token Impersonation",
+		Status:     "fail",
+		Score:      0,
+		Severity:   "high",
+		Details:    fmt.Sprintf("%s impersonates %s: the canonical %s token on %s is %s", label, symbol, symbol, network, canonical),
+		ReasonCode: ReasonTokenImpersonation,
+	}, nil
+}
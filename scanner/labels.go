@@ -0,0 +1,215 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"agent-reputation-scanner/internal/atomicfile"
+)
+
+// entityTrustBonus is the number of points a recognized well-known entity
+// adds to the overall score
+const entityTrustBonus = 10
+
+// Entity categories of the bundled label dataset
+const (
+	EntityExchange       = "exchange"
+	EntityBridge         = "bridge"
+	EntityProtocol       = "protocol"
+	EntityInfrastructure = "infrastructure"
+)
+
+//go:embed labels.csv
+var builtinLabelList string
+
+// builtinLabels are the well-known entities known without a labels file
+var builtinLabels = mustParseLabels(builtinLabelList)
+
+// EntityLabel names a well-known address such as an exchange hot wallet
+// or a protocol router
+type EntityLabel struct {
+	Name     string `json:"name"`     // e.g. "Uniswap V3 Router"
+	Category string `json:"category"` // e.g. EntityExchange
+	Source   string `json:"source"`   // "builtin" or the labels file name
+	// TrustBonus is the number of points the label added to the overall
+	// score; 0 when a check with a severity overrode it
+	TrustBonus int `json:"trust_bonus"`
+}
+
+// labelEntry is a label and the networks it applies to (nil for all)
+type labelEntry struct {
+	label    EntityLabel
+	networks []string
+}
+
+// DefaultLabelsPath is where `scanner update-lists` stores the address
+// labels; the CLI loads them automatically when they exist
+func DefaultLabelsPath() string {
+	return filepath.Join(DefaultConfigDir(), "labels.csv")
+}
+
+// parseLabels reads a label dataset: CSV rows of address, name, category
+// and optional ;-separated networks, with an optional header row and #
+// comment lines. source is the Source of the labels and prefixes errors.
+func parseLabels(r io.Reader, source string) (map[string]labelEntry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	labels := map[string]labelEntry{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if strings.EqualFold(strings.TrimSpace(record[0]), "address") {
+			continue
+		}
+		if len(record) < 3 || len(record) > 4 {
+			return nil, fmt.Errorf("%s:%d: want address,name,category[,networks], got %d fields", source, line, len(record))
+		}
+		address := strings.TrimSpace(record[0])
+		if !IsHexAddress(address) {
+			return nil, fmt.Errorf("%s:%d: invalid address %q", source, line, address)
+		}
+		entry := labelEntry{label: EntityLabel{
+			Name:     strings.TrimSpace(record[1]),
+			Category: strings.ToLower(strings.TrimSpace(record[2])),
+			Source:   source,
+		}}
+		if entry.label.Name == "" {
+			return nil, fmt.Errorf("%s:%d: missing name for %s", source, line, address)
+		}
+		if len(record) == 4 {
+			for _, network := range strings.Split(record[3], ";") {
+				if network = strings.ToLower(strings.TrimSpace(network)); network != "" {
+					entry.networks = append(entry.networks, network)
+				}
+			}
+		}
+		labels[strings.ToLower(address)] = entry
+	}
+	return labels, nil
+}
+
+func mustParseLabels(list string) map[string]labelEntry {
+	labels, err := parseLabels(strings.NewReader(list), "builtin")
+	if err != nil {
+		panic(err)
+	}
+	return labels
+}
+
+// LoadLabels merges a label dataset file over the built-in labels and
+// returns the number of labels loaded. Each row holds an address, a name,
+// a category and optionally the networks it applies to:
+//
+//	address,name,category,networks
+//	0x28C6c06298d514Db089934071355E5743bf21d60,Binance hot wallet,exchange,ethereum
+//	0x000000000022D473030F116dDEE9F6B43aC78BA3,Uniswap Permit2,protocol,
+func (s *Scanner) LoadLabels(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	labels, err := parseLabels(bytes.NewReader(data), filepath.Base(path))
+	if err != nil {
+		return 0, err
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	for address, entry := range labels {
+		s.labels[address] = entry
+	}
+	return len(labels), nil
+}
+
+// UpdateLabels downloads the label dataset from url (Config.LabelsURL when
+// empty) and stores it at path. Like UpdateSanctions it validates the
+// dataset first so a bad download never replaces a good one.
+func (s *Scanner) UpdateLabels(ctx context.Context, url, path string) (int, error) {
+	if url == "" {
+		url = s.cfg.LabelsURL
+	}
+	if url == "" {
+		return 0, errors.New("no labels URL configured (set labels_url)")
+	}
+
+	var data []byte
+	var status int
+	var err error
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		data, status, err = s.explorer.get(ctx, url)
+		if err == nil && status != http.StatusOK {
+			err = fmt.Errorf("HTTP %d", status)
+		}
+	} else {
+		data, err = os.ReadFile(url)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot fetch labels from %s: %w", redactURL(url), err)
+	}
+
+	labels, err := parseLabels(bytes.NewReader(data), "labels")
+	if err != nil {
+		return 0, fmt.Errorf("invalid labels from %s: %w", redactURL(url), err)
+	}
+	if len(labels) == 0 {
+		return 0, fmt.Errorf("labels from %s are empty", redactURL(url))
+	}
+	if err := atomicfile.Write(path, data); err != nil {
+		return 0, err
+	}
+	return len(labels), nil
+}
+
+// entityLabel returns the well-known entity at address on network, if any
+func (s *Scanner) entityLabel(address, network string) *EntityLabel {
+	s.cacheMu.Lock()
+	entry, ok := s.labels[strings.ToLower(address)]
+	s.cacheMu.Unlock()
+	if !ok {
+		return nil
+	}
+	if len(entry.networks) > 0 {
+		found := false
+		for _, n := range entry.networks {
+			found = found || n == network
+		}
+		if !found {
+			return nil
+		}
+	}
+	label := entry.label
+	return &label
+}
+
+// entityBonus returns the points a well-known entity adds to score, capped
+// at 100. A check with a severity, such as a sanctions or denylist hit,
+// outweighs the label, so it adds nothing then.
+func entityBonus(score int, checks []CheckResult) int {
+	for _, check := range checks {
+		if check.Severity != "" {
+			return 0
+		}
+	}
+	if score+entityTrustBonus > 100 {
+		return 100 - score
+	}
+	return entityTrustBonus
+}
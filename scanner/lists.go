@@ -0,0 +1,171 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DenylistEntry records where a denylisted address came from
+type DenylistEntry struct {
+	Source  string
+	Comment string
+}
+
+// AllowlistEntry is a trusted address. Label is shown in reports.
+type AllowlistEntry struct {
+	Label  string
+	Source string
+}
+
+// DefaultDenylistPath is the denylist the CLI loads automatically when it exists
+func DefaultDenylistPath() string {
+	return filepath.Join(DefaultConfigDir(), "denylist.txt")
+}
+
+// DefaultAllowlistPath is the allowlist the CLI loads automatically when it exists
+func DefaultAllowlistPath() string {
+	return filepath.Join(DefaultConfigDir(), "allowlist.txt")
+}
+
+// listLine is one parsed address list entry
+type listLine struct {
+	address string // lowercase
+	lineNo  int
+	rest    string // fields after the address
+	comment string
+}
+
+// readListFile parses an address list file. Each line holds an address,
+// optional free-form fields and an optional # comment.
+func readListFile(path string) ([]listLine, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseList(file, path)
+}
+
+// parseList parses address list lines from r; name prefixes errors
+func parseList(r io.Reader, name string) ([]listLine, error) {
+	var lines []listLine
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		comment := ""
+		if i := strings.Index(line, "#"); i >= 0 {
+			comment = strings.TrimSpace(line[i+1:])
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if !IsHexAddress(fields[0]) {
+			return nil, fmt.Errorf("%s:%d: invalid address %q", name, lineNo, fields[0])
+		}
+		lines = append(lines, listLine{
+			address: strings.ToLower(fields[0]),
+			lineNo:  lineNo,
+			rest:    strings.Join(fields[1:], " "),
+			comment: comment,
+		})
+	}
+	return lines, scanner.Err()
+}
+
+// LoadDenylist merges a denylist file into the scanner's denylist and
+// returns the number of entries loaded.
+//
+// Each line holds an address, an optional source and an optional comment:
+//
+//	0xabc...              # comment only
+//	0xabc... chainabuse   # phishing drainer
+//
+// Entries without a source are attributed to the file name. An address
+// that is already allowlisted is an error.
+func (s *Scanner) LoadDenylist(path string) (int, error) {
+	lines, err := readListFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	entries := map[string]DenylistEntry{}
+	for _, line := range lines {
+		source := line.rest
+		if source == "" {
+			source = filepath.Base(path)
+		}
+		entries[line.address] = DenylistEntry{Source: source, Comment: line.comment}
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	for address, entry := range entries {
+		if allowed, ok := s.allowlist[address]; ok {
+			return 0, listConflict(address, allowed, entry)
+		}
+	}
+	for address, entry := range entries {
+		s.denylist[address] = entry
+	}
+	return len(entries), nil
+}
+
+// LoadAllowlist merges an allowlist file into the scanner's allowlist and
+// returns the number of entries loaded.
+//
+// Each line holds an address and an optional label:
+//
+//	0x7a25...  Uniswap V2 Router   # comment
+//
+// An address that is already denylisted is an error.
+func (s *Scanner) LoadAllowlist(path string) (int, error) {
+	lines, err := readListFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	entries := map[string]AllowlistEntry{}
+	for _, line := range lines {
+		entries[line.address] = AllowlistEntry{Label: line.rest, Source: filepath.Base(path)}
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	for address, entry := range entries {
+		if denied, ok := s.denylist[address]; ok {
+			return 0, listConflict(address, entry, denied)
+		}
+	}
+	for address, entry := range entries {
+		s.allowlist[address] = entry
+	}
+	return len(entries), nil
+}
+
+func listConflict(address string, allowed AllowlistEntry, denied DenylistEntry) error {
+	return fmt.Errorf("address %s is on both the allowlist (%s) and the denylist (%s)",
+		ToChecksumAddress(address), allowed.Source, denied.Source)
+}
+
+// lookupDenylist returns the denylist entry for address, if any
+func (s *Scanner) lookupDenylist(address string) (DenylistEntry, bool) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	entry, ok := s.denylist[strings.ToLower(address)]
+	return entry, ok
+}
+
+// isAllowlisted reports whether address is trusted and returns its label
+func (s *Scanner) isAllowlisted(address string) (bool, string) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	entry, ok := s.allowlist[strings.ToLower(address)]
+	return ok, entry.Label
+}
@@ -0,0 +1,65 @@
+package scanner
+
+import (
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// LevelTrace is below slog.LevelDebug and logs request payloads and cache
+// lookups in addition to the debug-level request log
+const LevelTrace = slog.LevelDebug - 4
+
+// Query parameters that carry credentials
+var secretParams = []string{"apikey", "api_key", "key", "token", "access_token"}
+
+// Path segments that look like provider keys, e.g. https://host/v2/<key>
+var secretSegment = regexp.MustCompile(`^[A-Za-z0-9_-]{20,}$`)
+
+const redacted = "REDACTED"
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// redactURL hides API keys in query parameters and key-like path segments
+// so URLs can be logged or returned in errors
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	query := u.Query()
+	changed := false
+	for name := range query {
+		for _, secret := range secretParams {
+			if strings.EqualFold(name, secret) {
+				query.Set(name, redacted)
+				changed = true
+			}
+		}
+	}
+	if changed {
+		u.RawQuery = query.Encode()
+	}
+
+	segments := strings.Split(u.Path, "/")
+	for i, seg := range segments {
+		if secretSegment.MatchString(seg) && !IsHexAddress(seg) {
+			segments[i] = redacted
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+	return u.String()
+}
+
+// redactError rewrites the URL inside *url.Error so keys do not leak into
+// check details or logs
+func redactError(err error) error {
+	if uerr, ok := err.(*url.Error); ok {
+		return &url.Error{Op: uerr.Op, URL: redactURL(uerr.URL), Err: uerr.Err}
+	}
+	return err
+}
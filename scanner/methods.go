@@ -0,0 +1,275 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Recent transactions whose function selectors are profiled
+const methodTxSample = 1000
+
+// Approval calls among the profiled transactions that suggest drainer
+// behavior: at least methodApprovalMin of them, making up at least
+// methodApprovalShare of the calls, to methodSpenderMin distinct spenders
+const (
+	methodApprovalMin   = 10
+	methodApprovalShare = 0.5
+	methodSpenderMin    = 5
+	methodDrainerScore  = 30
+)
+
+// At most this many methods are named in Details; the rest count as other
+const maxListedMethods = 4
+
+//go:embed signatures.txt
+var builtinSignatureList string
+
+// builtinSignatures maps "0x"-prefixed hex selectors to signatures
+var builtinSignatures = mustParseSignatures(builtinSignatureList)
+
+// Approvals whose first argument is the spender or operator
+var approvalABI = newContractABI([]abiEntry{
+	signatureEntry("approve(address,uint256)"),
+	signatureEntry("increaseAllowance(address,uint256)"),
+	signatureEntry("setApprovalForAll(address,bool)"),
+})
+
+// signatureEntry builds the ABI entry of a function signature with
+// elementary parameter types
+func signatureEntry(signature string) abiEntry {
+	name, params, _ := strings.Cut(strings.TrimSuffix(signature, ")"), "(")
+	entry := abiEntry{Type: "function", Name: name}
+	for _, typ := range strings.Split(params, ",") {
+		entry.Inputs = append(entry.Inputs, abiParam{Type: typ})
+	}
+	return entry
+}
+
+func selectorHex(signature string) string {
+	return "0x" + hex.EncodeToString(selector(signature))
+}
+
+// DefaultSignaturesPath is the function signature list the CLI loads
+// automatically when it exists
+func DefaultSignaturesPath() string {
+	return filepath.Join(DefaultConfigDir(), "signatures.txt")
+}
+
+// parseSignatures reads a signature list: one signature per line, such as
+// "approve(address,uint256)", optionally preceded by its selector for
+// signatures only known by selector. name prefixes errors.
+func parseSignatures(r io.Reader, name string) (map[string]string, error) {
+	signatures := map[string]string{}
+	lines := bufio.NewScanner(r)
+	for lineNo := 1; lines.Scan(); lineNo++ {
+		line := lines.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		sel, signature := "", fields[0]
+		if len(fields) == 2 && strings.HasPrefix(fields[0], "0x") {
+			sel, signature = strings.ToLower(fields[0]), fields[1]
+			if raw, err := hex.DecodeString(sel[2:]); err != nil || len(raw) != 4 {
+				return nil, fmt.Errorf("%s:%d: invalid selector %q", name, lineNo, fields[0])
+			}
+		} else if len(fields) > 1 {
+			signature = ""
+		}
+		if !strings.Contains(signature, "(") || !strings.HasSuffix(signature, ")") {
+			return nil, fmt.Errorf("%s:%d: invalid signature %q (want name(types) without spaces)", name, lineNo, strings.TrimSpace(line))
+		}
+		if sel == "" {
+			sel = selectorHex(signature)
+		}
+		signatures[sel] = signature
+	}
+	return signatures, lines.Err()
+}
+
+func mustParseSignatures(list string) map[string]string {
+	signatures, err := parseSignatures(strings.NewReader(list), "signatures.txt")
+	if err != nil {
+		panic(err)
+	}
+	return signatures
+}
+
+// LoadSignatures adds the function signatures in a file to the built-in
+// ones used by the Method Profile check and returns the number loaded.
+// Each line holds a signature, optionally preceded by its selector:
+//
+//	claimRewards(address[])
+//	0x5ae401dc  multicall(uint256,bytes[])
+func (s *Scanner) LoadSignatures(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	signatures, err := parseSignatures(bytes.NewReader(data), path)
+	if err != nil {
+		return 0, err
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	for sel, signature := range signatures {
+		s.signatures[sel] = signature
+	}
+	return len(signatures), nil
+}
+
+// methodName labels a transaction by the function it called: the name
+// from the called contract's verified ABI or the signature list, the raw
+// selector if unknown, or "ETH transfer"
+func (s *Scanner) methodName(abi *contractABI, input string) string {
+	input = strings.ToLower(input)
+	if len(input) < 10 {
+		return "ETH transfer"
+	}
+	if entry, ok := abi.method(input[:10]); ok {
+		return entry.Name
+	}
+	s.cacheMu.Lock()
+	signature, ok := s.signatures[input[:10]]
+	s.cacheMu.Unlock()
+	if !ok {
+		return input[:10]
+	}
+	name, _, _ := strings.Cut(signature, "(")
+	return name
+}
+
+// checkMethodProfile decodes the function selectors of recent transactions
+// and summarizes which methods are called: those sent by an account, or
+// those received by a contract. Accounts that mostly grant approvals to
+// many different spenders get a warning.
+func (s *Scanner) checkMethodProfile(ctx context.Context, address, network string) (CheckResult, error) {
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Method Profile",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Method Profile",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+	txs, err := s.getTxList(ctx, address, network, "desc", methodTxSample)
+	if err != nil {
+		return CheckResult{
+			Name:    "Method Profile",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+
+	isContract := len(code) > 0
+	direction := "outgoing"
+	// Calls to a verified contract are named from its own ABI
+	var abi *contractABI
+	if isContract {
+		direction = "incoming"
+		abi, _ = s.getABI(ctx, address, network)
+	}
+	counts := map[string]int{}
+	total, approvals := 0, 0
+	spenders := map[string]bool{}
+	for _, tx := range txs {
+		if isContract && !strings.EqualFold(tx.To, address) || !isContract && !strings.EqualFold(tx.From, address) {
+			continue
+		}
+		total++
+		counts[s.methodName(abi, tx.Input)]++
+
+		calldata, err := hex.DecodeString(strings.TrimPrefix(tx.Input, "0x"))
+		if err != nil {
+			continue
+		}
+		if _, args, err := approvalABI.decodeInput(calldata); err == nil {
+			approvals++
+			spenders[args[0].(string)] = true
+		}
+	}
+	if total == 0 {
+		return CheckResult{
+			Name:          "Method Profile",
+			Status:        "pass",
+			Score:         100,
+			Details:       fmt.Sprintf("No %s transactions to profile", direction),
+			NotApplicable: true,
+		}, nil
+	}
+
+	details := fmt.Sprintf("Last %d %s transactions: %s", total, direction, formatMethodShares(counts, total))
+	if !isContract && approvals >= methodApprovalMin && float64(approvals) >= methodApprovalShare*float64(total) && len(spenders) >= methodSpenderMin {
+		return CheckResult{
+			Name:       "Method Profile",
+			Status:     "warning",
+			Score:      methodDrainerScore,
+			Details:    fmt.Sprintf("%s (%d approvals to %d different spenders, possible drainer behavior)", details, approvals, len(spenders)),
+			ReasonCode: ReasonDrainerBehavior,
+		}, nil
+	}
+	return CheckResult{
+		Name:    "Method Profile",
+		Status:  "pass",
+		Score:   100,
+		Details: details,
+	}, nil
+}
+
+// formatMethodShares renders method counts as percentages, most common
+// first, e.g. "70% approve, 20% transfer, 10% other"
+func formatMethodShares(counts map[string]int, total int) string {
+	methods := make([]string, 0, len(counts))
+	for method := range counts {
+		methods = append(methods, method)
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		if counts[methods[i]] != counts[methods[j]] {
+			return counts[methods[i]] > counts[methods[j]]
+		}
+		return methods[i] < methods[j]
+	})
+
+	var parts []string
+	other := 0
+	for i, method := range methods {
+		if i >= maxListedMethods {
+			other += counts[method]
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%d%% %s", percent(counts[method], total), method))
+	}
+	if other > 0 {
+		parts = append(parts, fmt.Sprintf("%d%% other", percent(other, total)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func percent(n, total int) int {
+	return (n*100 + total/2) / total
+}
@@ -0,0 +1,139 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Histogram buckets for request latency, in seconds
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metrics holds the counters exported by WriteMetrics
+type metrics struct {
+	mu          sync.Mutex
+	scans       int64
+	scansByRisk map[string]int64
+	errors      map[string]int64 // keyed by kind: scan, explorer, rpc
+	cacheHits   int64
+	cacheMisses int64
+	latency     map[string]*histogram // keyed by api: explorer, rpc
+}
+
+type histogram struct {
+	counts []int64 // per bucket, cumulative on output
+	sum    float64
+	count  int64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		scansByRisk: map[string]int64{},
+		errors:      map[string]int64{},
+		latency:     map[string]*histogram{},
+	}
+}
+
+func (m *metrics) recordScan(report ReputationReport, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scans++
+	if err != nil {
+		m.errors["scan"]++
+		return
+	}
+	m.scansByRisk[report.RiskLevel]++
+}
+
+func (m *metrics) recordRequest(api string, elapsed time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.latency[api]
+	if !ok {
+		h = &histogram{counts: make([]int64, len(latencyBuckets))}
+		m.latency[api] = h
+	}
+	secs := elapsed.Seconds()
+	for i, bound := range latencyBuckets {
+		if secs <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += secs
+	h.count++
+	if err != nil {
+		m.errors[api]++
+	}
+}
+
+func (m *metrics) recordCache(hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.cacheHits++
+	} else {
+		m.cacheMisses++
+	}
+}
+
+// WriteMetrics writes the scanner's metrics in the Prometheus text
+// exposition format
+func (s *Scanner) WriteMetrics(w io.Writer) error {
+	m := s.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	metric("reputation_scanner_scans_total", "counter", "Scans started, including failed scans.")
+	fmt.Fprintf(&b, "reputation_scanner_scans_total %d\n", m.scans)
+
+	metric("reputation_scanner_scans_by_risk_total", "counter", "Completed scans by resulting risk level.")
+	for _, level := range RiskLevels {
+		fmt.Fprintf(&b, "reputation_scanner_scans_by_risk_total{risk_level=%q} %d\n", level, m.scansByRisk[level])
+	}
+
+	metric("reputation_scanner_errors_total", "counter", "Failed scans and API requests by kind.")
+	for _, kind := range []string{"scan", "explorer", "rpc", "graph"} {
+		fmt.Fprintf(&b, "reputation_scanner_errors_total{kind=%q} %d\n", kind, m.errors[kind])
+	}
+
+	metric("reputation_scanner_cache_hits_total", "counter", "Check results served from the disk cache.")
+	fmt.Fprintf(&b, "reputation_scanner_cache_hits_total %d\n", m.cacheHits)
+	metric("reputation_scanner_cache_misses_total", "counter", "Check results not found in the disk cache.")
+	fmt.Fprintf(&b, "reputation_scanner_cache_misses_total %d\n", m.cacheMisses)
+	metric("reputation_scanner_cache_hit_ratio", "gauge", "Share of cache lookups that were hits.")
+	ratio := 0.0
+	if lookups := m.cacheHits + m.cacheMisses; lookups > 0 {
+		ratio = float64(m.cacheHits) / float64(lookups)
+	}
+	fmt.Fprintf(&b, "reputation_scanner_cache_hit_ratio %g\n", ratio)
+
+	metric("reputation_scanner_request_duration_seconds", "histogram", "Latency of explorer and RPC requests, including retries.")
+	apis := make([]string, 0, len(m.latency))
+	for api := range m.latency {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+	for _, api := range apis {
+		h := m.latency[api]
+		var cumulative int64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "reputation_scanner_request_duration_seconds_bucket{api=%q,le=\"%g\"} %d\n", api, bound, cumulative)
+		}
+		fmt.Fprintf(&b, "reputation_scanner_request_duration_seconds_bucket{api=%q,le=\"+Inf\"} %d\n", api, h.count)
+		fmt.Fprintf(&b, "reputation_scanner_request_duration_seconds_sum{api=%q} %g\n", api, h.sum)
+		fmt.Fprintf(&b, "reputation_scanner_request_duration_seconds_count{api=%q} %d\n", api, h.count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
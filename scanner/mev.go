@@ -0,0 +1,268 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Recent transactions the MEV Activity check reads, how many sandwich
+// candidates it confirms and how many transactions may sit between a
+// frontrun and its backrun. Each confirmation reads the transactions in
+// between over RPC.
+const (
+	mevTxSample      = 200
+	mevConfirmSample = 5
+	mevMaxGap        = 3
+)
+
+// Thresholds of the MEV bot pattern: accounts with at least mevMinSent
+// sent transactions, many of them sharing a block with another of their
+// own, landing first in their block, or pairing up like sandwiches
+const (
+	mevMinSent          = 10
+	mevSharedBlockShare = 0.3
+	mevTopOfBlockShare  = 0.3
+	mevMinCandidates    = 3
+)
+
+// mevTx is a sent transaction with its position in the chain
+type mevTx struct {
+	hash     string
+	to       string
+	block    uint64
+	index    uint64
+	gasPrice *big.Int // nil if the explorer omits it
+}
+
+// sandwichCandidate is a pair of transactions of the account in one block
+// calling the same contract with a few transactions between them
+type sandwichCandidate struct {
+	front, back mevTx
+}
+
+// sentMEVTxs returns the successful transactions address sent that come
+// with block data, grouped by block and ordered by index
+func sentMEVTxs(address string, txs []explorerTx) map[uint64][]mevTx {
+	blocks := map[uint64][]mevTx{}
+	for _, tx := range txs {
+		if !strings.EqualFold(tx.From, address) || tx.IsError == "1" {
+			continue
+		}
+		block, err := strconv.ParseUint(tx.BlockNumber, 10, 64)
+		if err != nil {
+			continue
+		}
+		index, err := strconv.ParseUint(tx.TransactionIndex, 10, 64)
+		if err != nil {
+			continue
+		}
+		entry := mevTx{hash: tx.Hash, to: strings.ToLower(tx.To), block: block, index: index}
+		if price, ok := new(big.Int).SetString(tx.GasPrice, 10); ok {
+			entry.gasPrice = price
+		}
+		blocks[block] = append(blocks[block], entry)
+	}
+	for _, list := range blocks {
+		sort.Slice(list, func(a, b int) bool { return list[a].index < list[b].index })
+	}
+	return blocks
+}
+
+// sandwichCandidates pairs the account's transactions in each block that
+// call the same contract, 1 to mevMaxGap transactions apart, with the
+// first paying at least the gas price of the second, as a frontrun
+// outbidding its victim and a backrun following it do
+func sandwichCandidates(blocks map[uint64][]mevTx) []sandwichCandidate {
+	var candidates []sandwichCandidate
+	for _, list := range blocks {
+		used := map[int]bool{}
+		for i, front := range list {
+			if used[i] || front.to == "" {
+				continue
+			}
+			for j := i + 1; j < len(list); j++ {
+				back := list[j]
+				gap := back.index - front.index - 1
+				if used[j] || back.to != front.to || gap < 1 || gap > mevMaxGap {
+					continue
+				}
+				if front.gasPrice != nil && back.gasPrice != nil && front.gasPrice.Cmp(back.gasPrice) < 0 {
+					continue
+				}
+				used[i], used[j] = true, true
+				candidates = append(candidates, sandwichCandidate{front, back})
+				break
+			}
+		}
+	}
+	sort.Slice(candidates, func(a, b int) bool { return candidates[a].front.block > candidates[b].front.block })
+	return candidates
+}
+
+// isSwapCall reports whether a transaction calls a DEX swap: a swap method
+// by name, or anything sent to the network's swap router
+func (s *Scanner) isSwapCall(to, input, router string) bool {
+	if router != "" && strings.EqualFold(to, router) {
+		return true
+	}
+	name := strings.ToLower(s.methodName(nil, input))
+	return strings.HasPrefix(name, "swap") || strings.HasPrefix(name, "exactinput") || strings.HasPrefix(name, "exactoutput")
+}
+
+// confirmSandwich reads the transactions between a candidate pair and
+// reports whether one of them is a swap by another account, the victim
+func (s *Scanner) confirmSandwich(ctx context.Context, address, network, router string, c sandwichCandidate) (bool, error) {
+	for index := c.front.index + 1; index < c.back.index; index++ {
+		result, err := s.rpcCall(ctx, network, "eth_getTransactionByBlockNumberAndIndex",
+			[]interface{}{"0x" + strconv.FormatUint(c.front.block, 16), "0x" + strconv.FormatUint(index, 16)})
+		if err != nil {
+			return false, err
+		}
+		var tx *rpcTransaction
+		if err := json.Unmarshal(result, &tx); err != nil {
+			return false, fmt.Errorf("unexpected transaction result: %w", err)
+		}
+		if tx == nil || tx.To == nil || strings.EqualFold(tx.From, address) {
+			continue
+		}
+		if s.isSwapCall(*tx.To, tx.Input, router) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkMEV looks for the footprints of MEV bots and sandwich attackers in
+// the recent transactions an account sent: frontrun and backrun pairs
+// around another account's swap in the same block, and many transactions
+// bundled into shared blocks or landing first in theirs. MEV is context
+// about a counterparty rather than a sign of a scam, so it only warns.
+func (s *Scanner) checkMEV(ctx context.Context, address, network string) (CheckResult, error) {
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "MEV Activity",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+	netCfg, err := s.Network(network)
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	contract, err := s.source.IsContract(ctx, address, network)
+	if err != nil {
+		return mevQueryFailed(err)
+	}
+	if contract {
+		return CheckResult{
+			Name:    "MEV Activity",
+			Status:  "pass",
+			Score:   100,
+			Details: "Contract; MEV patterns are checked for accounts",
+		}, nil
+	}
+
+	txs, err := s.getTxList(ctx, address, network, "desc", mevTxSample)
+	if err != nil {
+		return mevQueryFailed(err)
+	}
+	blocks := sentMEVTxs(address, txs)
+	sent, shared, topOfBlock := 0, 0, 0
+	for _, list := range blocks {
+		sent += len(list)
+		if len(list) > 1 {
+			shared += len(list)
+		}
+		for _, tx := range list {
+			if tx.index == 0 {
+				topOfBlock++
+			}
+		}
+	}
+	if sent == 0 {
+		return CheckResult{
+			Name:    "MEV Activity",
+			Status:  "pass",
+			Score:   100,
+			Details: "No recent sent transactions with block data",
+		}, nil
+	}
+
+	candidates := sandwichCandidates(blocks)
+	confirmed, checked := 0, 0
+	var example string
+	for _, c := range firstCandidates(candidates, mevConfirmSample) {
+		ok, err := s.confirmSandwich(ctx, address, network, netCfg.SwapRouter, c)
+		if err != nil {
+			return mevQueryFailed(err)
+		}
+		checked++
+		if ok {
+			confirmed++
+			if example == "" {
+				example = fmt.Sprintf(", e.g. block %d: %s and %s", c.front.block, c.front.hash, c.back.hash)
+			}
+		}
+	}
+
+	if confirmed > 0 {
+		return CheckResult{
+			Name:   "MEV Activity",
+			Status: "warning",
+			Score:  40,
+			Details: fmt.Sprintf("Sandwich pattern: %d of %d checked same-block pairs wrap another account's swap (%d pairs in %d recent sent transactions)%s",
+				confirmed, checked, len(candidates), sent, example),
+			ReasonCode: ReasonMEVActivity,
+		}, nil
+	}
+
+	var signals []string
+	if share := float64(shared) / float64(sent); share >= mevSharedBlockShare {
+		signals = append(signals, fmt.Sprintf("%.0f%% share a block with another of its own", share*100))
+	}
+	if share := float64(topOfBlock) / float64(sent); share >= mevTopOfBlockShare {
+		signals = append(signals, fmt.Sprintf("%.0f%% are first in their block", share*100))
+	}
+	if len(candidates) >= mevMinCandidates {
+		signals = append(signals, fmt.Sprintf("%d unconfirmed frontrun/backrun pairs", len(candidates)))
+	}
+	if sent >= mevMinSent && len(signals) > 0 {
+		return CheckResult{
+			Name:       "MEV Activity",
+			Status:     "warning",
+			Score:      60,
+			Details:    fmt.Sprintf("MEV bot pattern in %d recent sent transactions: %s", sent, strings.Join(signals, ", ")),
+			ReasonCode: ReasonMEVActivity,
+		}, nil
+	}
+	return CheckResult{
+		Name:    "MEV Activity",
+		Status:  "pass",
+		Score:   100,
+		Details: fmt.Sprintf("No sandwich or MEV bot pattern in %d recent sent transactions", sent),
+	}, nil
+}
+
+// firstCandidates returns up to n leading candidates
+func firstCandidates(candidates []sandwichCandidate, n int) []sandwichCandidate {
+	if len(candidates) > n {
+		return candidates[:n]
+	}
+	return candidates
+}
+
+func mevQueryFailed(err error) (CheckResult, error) {
+	return CheckResult{
+		Name:    "MEV Activity",
+		Status:  "warning",
+		Score:   50,
+		Details: "Query failed: " + err.Error(),
+	}, err
+}
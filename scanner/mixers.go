@@ -0,0 +1,144 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MixerSource is the denylist source that marks an entry as a mixer. Add
+// mixers to a denylist file with this source and the name as comment:
+//
+//	0xabc...  mixer  # Example Mixer
+const MixerSource = "mixer"
+
+// Recent normal and internal transactions inspected for mixer counterparties
+const mixerTxSample = 1000
+
+// knownMixers are the OFAC-sanctioned Tornado Cash contracts on Ethereum,
+// loaded into every scanner's denylist
+var knownMixers = map[string]string{
+	"0xd90e2f925da726b50c4ed8d0fb90ad053324f31b": "Tornado Cash Router",
+	"0x722122df12d4e14e13ac3b6895a86e84145b6967": "Tornado Cash Proxy",
+	"0x12d66f87a04a9e220743712ce6d9bb1b5616b8fc": "Tornado Cash 0.1 ETH",
+	"0x47ce0c6ed5b0ce3d3a51fdb1c52dc66a7c3c2936": "Tornado Cash 1 ETH",
+	"0x910cbd523d972eb0a6f4cae4618ad62622b39dbf": "Tornado Cash 10 ETH",
+	"0xa160cdab225685da1d56aa342ad8841c3b53f291": "Tornado Cash 100 ETH",
+}
+
+// lookupMixer returns the mixer name if address is denylisted as a mixer
+func (s *Scanner) lookupMixer(address string) (string, bool) {
+	entry, ok := s.lookupDenylist(address)
+	if !ok || entry.Source != MixerSource {
+		return "", false
+	}
+	if entry.Comment == "" {
+		return ToChecksumAddress(address), true
+	}
+	return entry.Comment, true
+}
+
+// mixerFlow is one direction of transfers between the address and a mixer
+type mixerFlow struct {
+	mixer    string
+	received bool
+}
+
+// checkMixerExposure looks for mixers among the counterparties of the
+// address's recent transactions. Internal transactions are included since
+// mixer withdrawals arrive as contract calls.
+func (s *Scanner) checkMixerExposure(ctx context.Context, address, network string) (CheckResult, error) {
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Mixer Exposure",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	txs, err := s.getTxList(ctx, address, network, "desc", mixerTxSample)
+	if err != nil {
+		return mixerQueryFailed(err)
+	}
+	internal, err := s.getInternalTxList(ctx, address, network, "desc", mixerTxSample)
+	if err != nil {
+		return mixerQueryFailed(err)
+	}
+	txs = append(txs, internal...)
+
+	flows := map[mixerFlow]int{}
+	total := 0
+	for _, tx := range txs {
+		var counterparty string
+		var received bool
+		switch {
+		case strings.EqualFold(tx.From, address):
+			counterparty = tx.To
+		case strings.EqualFold(tx.To, address):
+			counterparty, received = tx.From, true
+		default:
+			continue
+		}
+		if name, ok := s.lookupMixer(counterparty); ok {
+			flows[mixerFlow{mixer: name, received: received}]++
+			total++
+		}
+	}
+
+	if total == 0 {
+		return CheckResult{
+			Name:          "Mixer Exposure",
+			Status:        "pass",
+			Score:         100,
+			Details:       fmt.Sprintf("No mixer counterparties in %d recent transactions", len(txs)),
+			NotApplicable: len(txs) == 0,
+		}, nil
+	}
+	return CheckResult{
+		Name:       "Mixer Exposure",
+		Status:     "fail",
+		Score:      10,
+		Severity:   "high",
+		Details:    fmt.Sprintf("%d transactions with mixers: %s", total, formatMixerFlows(flows)),
+		ReasonCode: ReasonMixerExposure,
+	}, nil
+}
+
+// formatMixerFlows lists flows by count, e.g. "received 2 from Tornado
+// Cash 1 ETH, sent 1 to Tornado Cash Router"
+func formatMixerFlows(flows map[mixerFlow]int) string {
+	list := make([]mixerFlow, 0, len(flows))
+	for flow := range flows {
+		list = append(list, flow)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if flows[list[i]] != flows[list[j]] {
+			return flows[list[i]] > flows[list[j]]
+		}
+		if list[i].mixer != list[j].mixer {
+			return list[i].mixer < list[j].mixer
+		}
+		return list[i].received
+	})
+
+	parts := make([]string, len(list))
+	for i, flow := range list {
+		if flow.received {
+			parts[i] = fmt.Sprintf("received %d from %s", flows[flow], flow.mixer)
+		} else {
+			parts[i] = fmt.Sprintf("sent %d to %s", flows[flow], flow.mixer)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func mixerQueryFailed(err error) (CheckResult, error) {
+	return CheckResult{
+		Name:    "Mixer Exposure",
+		Status:  "warning",
+		Score:   50,
+		Details: "Explorer query failed: " + err.Error(),
+	}, err
+}
@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ChainFinding is a failed or warning check of a multi-chain scan, tagged
+// with the network it came from
+type ChainFinding struct {
+	Network string `json:"network"`
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Score   int    `json:"score"`
+	Details string `json:"details"`
+}
+
+// MultiChainReport assesses one address across several networks. The
+// aggregate is as risky as the riskiest chain, so an address that is
+// clean on one chain but dealt with a scam on another is reported risky.
+type MultiChainReport struct {
+	Address         string             `json:"address"`
+	ENSName         string             `json:"ens_name,omitempty"`
+	Networks        []string           `json:"networks"`
+	Timestamp       time.Time          `json:"timestamp"`
+	OverallScore    int                `json:"overall_score"`    // lowest score of the scanned chains
+	RiskLevel       string             `json:"risk_level"`       // highest risk level of the scanned chains
+	Confidence      int                `json:"confidence"`       // of the riskiest chain
+	RiskiestNetwork string             `json:"riskiest_network"` // empty if every chain failed
+	Chains          []ReputationReport `json:"chains"`           // in Networks order
+	Findings        []ChainFinding     `json:"findings"`
+	Recommendations []string           `json:"recommendations"` // prefixed with [network]
+	Incomplete      bool               `json:"incomplete,omitempty"`
+}
+
+// ScanChains scans address on each of networks and combines the reports
+func (s *Scanner) ScanChains(address string, networks []string) (MultiChainReport, error) {
+	return s.ScanChainsContext(context.Background(), address, networks)
+}
+
+// ScanChainsContext is ScanChains with a context. The networks are scanned
+// in parallel; a network whose scan fails gets a report with Error set and
+// is left out of the aggregate. It returns an error if every scan failed.
+func (s *Scanner) ScanChainsContext(ctx context.Context, address string, networks []string) (MultiChainReport, error) {
+	if len(networks) == 0 {
+		return MultiChainReport{}, errors.New("no networks to scan")
+	}
+	for _, network := range networks {
+		if _, err := s.Network(network); err != nil {
+			return MultiChainReport{}, err
+		}
+	}
+
+	// Resolve once so every chain scans the same address
+	ensName := ""
+	if IsENSName(address) {
+		resolved, err := s.resolveENS(ctx, address)
+		if err != nil {
+			return MultiChainReport{}, fmt.Errorf("cannot resolve ENS name %s: %w", address, err)
+		}
+		ensName, address = address, resolved
+	}
+
+	chains := make([]ReputationReport, len(networks))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, s.cfg.Concurrency)
+	for i, network := range networks {
+		wg.Add(1)
+		go func(i int, network string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			chains[i], _ = s.safeScan(ctx, address, network)
+		}(i, network)
+	}
+	wg.Wait()
+
+	report := MultiChainReport{
+		Address:         address,
+		ENSName:         ensName,
+		Networks:        networks,
+		Timestamp:       s.cfg.Clock(),
+		Chains:          chains,
+		Findings:        []ChainFinding{},
+		Recommendations: []string{},
+	}
+	var riskiest *ReputationReport
+	for i := range chains {
+		chain := &chains[i]
+		if chain.Error != "" {
+			continue
+		}
+		if report.ENSName == "" {
+			report.ENSName = chain.ENSName
+		}
+		report.Incomplete = report.Incomplete || chain.Incomplete
+		if riskiest == nil || RiskRank(chain.RiskLevel) > RiskRank(riskiest.RiskLevel) ||
+			chain.RiskLevel == riskiest.RiskLevel && chain.OverallScore < riskiest.OverallScore {
+			riskiest = chain
+		}
+		for _, check := range chain.Checks {
+			// Same rule as the recommendations: skipped checks say nothing
+			if check.Status == "fail" || check.Status == "warning" && check.DataSource != DataFallback {
+				report.Findings = append(report.Findings, ChainFinding{
+					Network: chain.Network,
+					Check:   check.Name,
+					Status:  check.Status,
+					Score:   check.Score,
+					Details: check.Details,
+				})
+			}
+		}
+		for _, rec := range chain.Recommendations {
+			report.Recommendations = append(report.Recommendations, "["+chain.Network+"] "+rec)
+		}
+	}
+	if riskiest == nil {
+		return report, fmt.Errorf("scan of %s failed on every network", address)
+	}
+
+	report.OverallScore = riskiest.OverallScore
+	for _, chain := range chains {
+		if chain.Error == "" && chain.OverallScore < report.OverallScore {
+			report.OverallScore = chain.OverallScore
+		}
+	}
+	report.RiskLevel = riskiest.RiskLevel
+	report.Confidence = riskiest.Confidence
+	report.RiskiestNetwork = riskiest.Network
+	return report, nil
+}
@@ -0,0 +1,177 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Explorer types: the API a network's ExplorerAPIURL speaks
+const (
+	ExplorerEtherscan  = "etherscan"
+	ExplorerBlockscout = "blockscout"
+)
+
+// ExplorerTypes lists the supported explorer types
+var ExplorerTypes = []string{ExplorerEtherscan, ExplorerBlockscout}
+
+// NetworkConfig describes how to reach a supported chain
+type NetworkConfig struct {
+	ExplorerAPIURL string
+	ExplorerName   string
+	// ExplorerType is one of ExplorerTypes; empty means ExplorerEtherscan
+	ExplorerType string
+	DefaultRPC   string
+	// FallbackRPCs are public endpoints tried in order when DefaultRPC
+	// fails; they are not used once an RPC URL is configured
+	FallbackRPCs []string
+	ChainID      int64
+	// NativeSymbol is the symbol of the native currency, e.g. "ETH"
+	NativeSymbol string
+
+	// Uniswap V2 compatible router and wrapped native token used by the
+	// --deep honeypot simulation; empty disables it on this network
+	SwapRouter    string
+	WrappedNative string
+
+	// MajorTokens maps the symbols of widely held tokens to their
+	// canonical addresses; other tokens using these symbols are flagged
+	MajorTokens map[string]string
+
+	// GraphURL is the subgraph queried by the graph data source; there is
+	// no default
+	GraphURL string
+}
+
+// DefaultNetworks lists the chains supported out of the box
+var DefaultNetworks = map[string]NetworkConfig{
+	"ethereum": {
+		ExplorerAPIURL: "https://api.etherscan.io/api",
+		ExplorerName:   "Etherscan",
+		DefaultRPC:     "https://eth.drpc.org",
+		FallbackRPCs:   []string{"https://ethereum-rpc.publicnode.com", "https://eth.llamarpc.com"},
+		ChainID:        1,
+		NativeSymbol:   "ETH",
+		SwapRouter:     "0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D",
+		WrappedNative:  "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+		MajorTokens: map[string]string{
+			"DAI":  "0x6B175474E89094C44Da98b954EedeAC495271d0F",
+			"USDC": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+			"USDT": "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			"WBTC": "0x2260FAC5E5542a773Aa44fBCfeDf7C193bc2C599",
+			"WETH": "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+		},
+	},
+	"base": {
+		ExplorerAPIURL: "https://api.basescan.org/api",
+		ExplorerName:   "BaseScan",
+		DefaultRPC:     "https://base.drpc.org",
+		FallbackRPCs:   []string{"https://mainnet.base.org", "https://base-rpc.publicnode.com"},
+		ChainID:        8453,
+		NativeSymbol:   "ETH",
+		SwapRouter:     "0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24",
+		WrappedNative:  "0x4200000000000000000000000000000000000006",
+		MajorTokens: map[string]string{
+			"DAI":  "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb",
+			"USDC": "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
+			"WETH": "0x4200000000000000000000000000000000000006",
+		},
+	},
+	"polygon": {
+		ExplorerAPIURL: "https://api.polygonscan.com/api",
+		ExplorerName:   "PolygonScan",
+		DefaultRPC:     "https://polygon-rpc.com",
+		FallbackRPCs:   []string{"https://polygon-bor-rpc.publicnode.com", "https://polygon.drpc.org"},
+		ChainID:        137,
+		NativeSymbol:   "POL",
+		SwapRouter:     "0xa5E0829CaCEd8fFDD4De3c43696c57F7D7A678ff",
+		WrappedNative:  "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270",
+		MajorTokens: map[string]string{
+			"DAI":  "0x8f3Cf7ad23Cd3CaDbD9735AFf958023239c6A063",
+			"USDC": "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359",
+			"USDT": "0xc2132D05D31c914a87C6611C10748AEb04B58e8F",
+			"WBTC": "0x1BFD67037B42Cf73acF2047067bd4F2C47D9BfD6",
+			"WETH": "0x7ceB23fD6bC0adD59E62ac25578270cFf1b9f619",
+		},
+	},
+	"arbitrum": {
+		ExplorerAPIURL: "https://api.arbiscan.io/api",
+		ExplorerName:   "Arbiscan",
+		DefaultRPC:     "https://arb1.arbitrum.io/rpc",
+		FallbackRPCs:   []string{"https://arbitrum-one-rpc.publicnode.com", "https://arbitrum.drpc.org"},
+		ChainID:        42161,
+		NativeSymbol:   "ETH",
+		SwapRouter:     "0x1b02dA8Cb0d097eB8D57A175b88c7D8b47997506",
+		WrappedNative:  "0x82aF49447D8a07e3bd95BD0d56f35241523fBab1",
+		MajorTokens: map[string]string{
+			"DAI":  "0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1",
+			"USDC": "0xaf88d065e77c8cC2239327C5EDb3A432268e5831",
+			"USDT": "0xFd086bC7CD5C481DCC9C85ebE478A1C0b69FCbb9",
+			"WBTC": "0x2f2a2543B76A4166549F7aaB2e75Bef0aefC5B0f",
+			"WETH": "0x82aF49447D8a07e3bd95BD0d56f35241523fBab1",
+		},
+	},
+	"gnosis": {
+		ExplorerAPIURL: "https://gnosis.blockscout.com/api",
+		ExplorerName:   "Blockscout",
+		ExplorerType:   ExplorerBlockscout,
+		DefaultRPC:     "https://rpc.gnosischain.com",
+		FallbackRPCs:   []string{"https://gnosis-rpc.publicnode.com", "https://gnosis.drpc.org"},
+		ChainID:        100,
+		NativeSymbol:   "xDAI",
+		MajorTokens: map[string]string{
+			"USDC":  "0xDDAfbb505ad214D7b80b1f830fcCc89B60fb7A83",
+			"USDT":  "0x4ECaBa5870353805a9F068101A763e9E7F9822F6",
+			"WETH":  "0x6A023CCd1ff6F2045C3309768eAd9E68F978f6e1",
+			"WXDAI": "0xe91D153E0b41518A2Ce8Dd3D7944Fa863463a97d",
+		},
+	},
+	"optimism": {
+		ExplorerAPIURL: "https://api-optimistic.etherscan.io/api",
+		ExplorerName:   "Optimistic Etherscan",
+		DefaultRPC:     "https://mainnet.optimism.io",
+		FallbackRPCs:   []string{"https://optimism-rpc.publicnode.com", "https://optimism.drpc.org"},
+		ChainID:        10,
+		NativeSymbol:   "ETH",
+		MajorTokens: map[string]string{
+			"DAI":  "0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1",
+			"USDC": "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85",
+			"USDT": "0x94b008aA00579c1307B0EF2c499aD98a8ce58e58",
+			"WBTC": "0x68f180fcCe6836688e9084f035309E29Bf0A2095",
+			"WETH": "0x4200000000000000000000000000000000000006",
+		},
+	},
+}
+
+// NetworkNames returns the scanner's network names in sorted order
+func (s *Scanner) NetworkNames() []string {
+	names := make([]string, 0, len(s.networks))
+	for name := range s.networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Network returns the config for a network name
+func (s *Scanner) Network(name string) (NetworkConfig, error) {
+	cfg, ok := s.networks[strings.ToLower(name)]
+	if !ok {
+		return NetworkConfig{}, fmt.Errorf("unsupported network %q (supported: %s)", name, strings.Join(s.NetworkNames(), ", "))
+	}
+	return cfg, nil
+}
+
+// NetworkByChainID returns the name of the network with the given chain ID
+func (s *Scanner) NetworkByChainID(id int64) (string, error) {
+	for _, name := range s.NetworkNames() {
+		if s.networks[name].ChainID == id {
+			return name, nil
+		}
+	}
+	chains := make([]string, 0, len(s.networks))
+	for _, name := range s.NetworkNames() {
+		chains = append(chains, fmt.Sprintf("%d (%s)", s.networks[name].ChainID, name))
+	}
+	return "", fmt.Errorf("unsupported chain ID %d (supported: %s)", id, strings.Join(chains, ", "))
+}
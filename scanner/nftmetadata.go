@@ -0,0 +1,319 @@
+package scanner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Default gateways for ipfs:// and ar:// token URIs
+const (
+	DefaultIPFSGateway    = "https://ipfs.io"
+	DefaultArweaveGateway = "https://arweave.net"
+)
+
+// ERC-165 interface IDs of the NFT standards
+const (
+	erc721InterfaceID  = "80ac58cd"
+	erc1155InterfaceID = "d9b67a26"
+)
+
+// Largest metadata document read
+const maxMetadataBytes = 1 << 20
+
+// Longest token URI shown in Details
+const maxShownURILen = 80
+
+// Phrases of the fake airdrop and "claim your reward" NFTs sent to lure
+// holders to drainer sites
+var nftScamPhrases = []string{"claim", "airdrop", "reward", "voucher", "redeem", "giveaway", "free mint", "bonus"}
+
+// Links in a name or trait, where legitimate collections have none
+var nftLinkPattern = regexp.MustCompile(`(?i)https?://\S+|www\.\S+|\b[a-z0-9-]+\.(com|io|xyz|net|org|app|site|online|live|top|pro|fun|gift)\b`)
+
+// nftMetadata is the ERC-721/ERC-1155 metadata JSON schema
+type nftMetadata struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+	ImageData   string `json:"image_data"`
+	Attributes  []struct {
+		TraitType string      `json:"trait_type"`
+		Value     interface{} `json:"value"`
+	} `json:"attributes"`
+}
+
+// supportsInterface calls ERC-165 supportsInterface(bytes4); contracts
+// without it report false
+func (s *Scanner) supportsInterface(ctx context.Context, address, network, interfaceID string) (bool, error) {
+	id, _ := hex.DecodeString(interfaceID)
+	word := make([]byte, 32)
+	copy(word, id)
+	data := "0x" + hex.EncodeToString(append(selector("supportsInterface(bytes4)"), word...))
+	result, err := s.ethCall(ctx, network, address, data)
+	if isRevert(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(result) >= 32 && result[31] == 1, nil
+}
+
+// tokenURI returns the metadata URI of token 1, else token 0, else the
+// collection's baseURI(); empty if the contract answers none of them
+func (s *Scanner) tokenURI(ctx context.Context, address, network string, erc1155 bool) (string, error) {
+	sig := "tokenURI(uint256)"
+	if erc1155 {
+		sig = "uri(uint256)"
+	}
+	for _, id := range []int64{1, 0} {
+		result, err := s.ethCall(ctx, network, address, encodeCall(sig, big.NewInt(id)))
+		if isRevert(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if uri, err := decodeStringResult(result); err == nil && uri != "" {
+			// ERC-1155 clients substitute the hex token ID
+			return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id)), nil
+		}
+	}
+
+	result, err := s.ethCall(ctx, network, address, encodeCall("baseURI()"))
+	if isRevert(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	uri, _ := decodeStringResult(result)
+	return uri, nil
+}
+
+// metadataURL maps a token URI to the HTTP URL it is fetched from,
+// resolving ipfs:// and ar:// links through the configured gateways
+func (s *Scanner) metadataURL(uri string) (string, error) {
+	ipfsGateway := strings.TrimRight(s.cfg.IPFSGateway, "/")
+	if ipfsGateway == "" {
+		ipfsGateway = DefaultIPFSGateway
+	}
+	arweaveGateway := strings.TrimRight(s.cfg.ArweaveGateway, "/")
+	if arweaveGateway == "" {
+		arweaveGateway = DefaultArweaveGateway
+	}
+
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		return ipfsGateway + "/ipfs/" + strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/"), nil
+	case strings.HasPrefix(uri, "ar://"):
+		return arweaveGateway + "/" + strings.TrimPrefix(uri, "ar://"), nil
+	case strings.HasPrefix(uri, "https://"), strings.HasPrefix(uri, "http://"):
+		return uri, nil
+	}
+	return "", fmt.Errorf("unsupported token URI scheme")
+}
+
+// fetchMetadata returns the metadata document a token URI points to.
+// data: URIs are decoded in place; fixture scans look URLs up in
+// Fixtures.Metadata instead of fetching them.
+func (s *Scanner) fetchMetadata(ctx context.Context, uri string) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(uri, "data:"); ok {
+		mediaType, payload, found := strings.Cut(rest, ",")
+		if !found {
+			return nil, errors.New("malformed data URI")
+		}
+		if strings.HasSuffix(mediaType, ";base64") {
+			return base64.StdEncoding.DecodeString(payload)
+		}
+		text, err := url.PathUnescape(payload)
+		return []byte(text), err
+	}
+
+	target, err := s.metadataURL(uri)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.Fixtures != nil {
+		if doc, ok := s.cfg.Fixtures.Metadata[target]; ok {
+			return []byte(doc), nil
+		}
+		return nil, fmt.Errorf("HTTP %d", http.StatusNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, redactError(err)
+	}
+	s.logger.Debug("http request", "method", http.MethodGet, "url", redactURL(target))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, redactError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxMetadataBytes))
+}
+
+// suspiciousTraits flags scam phrases in the metadata and links in the
+// name or trait values
+func suspiciousTraits(meta nftMetadata) []string {
+	flags := append(scamPhrases("name", meta.Name), scamPhrases("description", meta.Description)...)
+	if link := nftLinkPattern.FindString(meta.Name); link != "" {
+		flags = append(flags, "name links to "+link)
+	}
+	for _, attr := range meta.Attributes {
+		field, value := "trait "+attr.TraitType, fmt.Sprint(attr.Value)
+		flags = append(flags, scamPhrases(field, attr.TraitType+" "+value)...)
+		if link := nftLinkPattern.FindString(value); link != "" {
+			flags = append(flags, fmt.Sprintf("%s links to %s", field, link))
+		}
+	}
+	return flags
+}
+
+func scamPhrases(field, text string) []string {
+	lower := strings.ToLower(text)
+	for _, phrase := range nftScamPhrases {
+		if strings.Contains(lower, phrase) {
+			return []string{fmt.Sprintf("%s mentions %q", field, phrase)}
+		}
+	}
+	return nil
+}
+
+func shortURI(uri string) string {
+	if len(uri) > maxShownURILen {
+		return uri[:maxShownURILen] + "..."
+	}
+	return uri
+}
+
+// checkNFTMetadata reads the token URI of an ERC-721 or ERC-1155
+// collection, fetches the off-chain metadata and flags missing or broken
+// metadata and lure phrases or links typical of airdropped scam NFTs
+func (s *Scanner) checkNFTMetadata(ctx context.Context, address, network string) (CheckResult, error) {
+	rpcFailed := func(err error) (CheckResult, error) {
+		return CheckResult{
+			Name:    "NFT Metadata",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return rpcFailed(err)
+	}
+	erc721, erc1155 := false, false
+	if len(code) > 0 {
+		if erc721, err = s.supportsInterface(ctx, address, network, erc721InterfaceID); err != nil {
+			return rpcFailed(err)
+		}
+		if !erc721 {
+			if erc1155, err = s.supportsInterface(ctx, address, network, erc1155InterfaceID); err != nil {
+				return rpcFailed(err)
+			}
+		}
+	}
+	if !erc721 && !erc1155 {
+		return CheckResult{
+			Name:          "NFT Metadata",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not an NFT contract (NFT metadata not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	uri, err := s.tokenURI(ctx, address, network, erc1155)
+	if err != nil {
+		return rpcFailed(err)
+	}
+	if uri == "" {
+		return CheckResult{
+			Name:       "NFT Metadata",
+			Status:     "warning",
+			Score:      40,
+			Details:    "NFT contract returns no token URI",
+			ReasonCode: ReasonNFTMetadataIncomplete,
+		}, nil
+	}
+
+	data, err := s.fetchMetadata(ctx, uri)
+	if err != nil {
+		// The scan deadline is not the gateway's fault
+		if ctx.Err() != nil {
+			return CheckResult{
+				Name:    "NFT Metadata",
+				Status:  "warning",
+				Score:   50,
+				Details: "Metadata fetch failed: " + err.Error(),
+			}, ctx.Err()
+		}
+		return CheckResult{
+			Name:       "NFT Metadata",
+			Status:     "warning",
+			Score:      40,
+			Details:    fmt.Sprintf("Metadata unreachable at %s: %v (legitimate collections pin their metadata)", shortURI(uri), err),
+			ReasonCode: ReasonNFTMetadataIncomplete,
+		}, nil
+	}
+	var meta nftMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return CheckResult{
+			Name:       "NFT Metadata",
+			Status:     "warning",
+			Score:      40,
+			Details:    fmt.Sprintf("Metadata at %s is not valid JSON", shortURI(uri)),
+			ReasonCode: ReasonNFTMetadataIncomplete,
+		}, nil
+	}
+
+	if flags := suspiciousTraits(meta); len(flags) > 0 {
+		return CheckResult{
+			Name:       "NFT Metadata",
+			Status:     "fail",
+			Score:      20,
+			Details:    "Suspicious NFT metadata: " + strings.Join(flags, "; ") + " (" + shortURI(uri) + ")",
+			ReasonCode: ReasonSuspiciousNFTMetadata,
+		}, nil
+	}
+	var missing []string
+	if meta.Name == "" {
+		missing = append(missing, "name")
+	}
+	if meta.Image == "" && meta.ImageData == "" {
+		missing = append(missing, "image")
+	}
+	if len(missing) > 0 {
+		return CheckResult{
+			Name:       "NFT Metadata",
+			Status:     "warning",
+			Score:      60,
+			Details:    fmt.Sprintf("Metadata at %s has no %s", shortURI(uri), strings.Join(missing, " or ")),
+			ReasonCode: ReasonNFTMetadataIncomplete,
+		}, nil
+	}
+	return CheckResult{
+		Name:    "NFT Metadata",
+		Status:  "pass",
+		Score:   100,
+		Details: fmt.Sprintf("Metadata of %q with %d traits at %s", meta.Name, len(meta.Attributes), shortURI(uri)),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// At most this many offsets per opcode are listed in Details
+const maxListedOffsets = 5
+
+// Scores for contracts containing dangerous opcodes
+const (
+	selfdestructScore = 40
+	delegatecallScore = 60
+	bothOpcodesScore  = 30
+)
+
+// checkDangerousOpcodes disassembles the runtime bytecode and flags
+// SELFDESTRUCT and DELEGATECALL, which let a contract be destroyed or run
+// foreign code with its own storage and balance. Works without verified
+// source.
+func (s *Scanner) checkDangerousOpcodes(ctx context.Context, address, network string) (CheckResult, error) {
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Dangerous Opcodes",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+	if len(code) == 0 {
+		return CheckResult{
+			Name:          "Dangerous Opcodes",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (bytecode scan not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	offsets := opcodeOffsets(code, opSelfdestruct, opDelegatecall)
+	selfdestructs, delegatecalls := offsets[opSelfdestruct], offsets[opDelegatecall]
+
+	var findings []string
+	if len(selfdestructs) > 0 {
+		findings = append(findings, "SELFDESTRUCT at "+formatOffsets(selfdestructs))
+	}
+	if len(delegatecalls) > 0 {
+		findings = append(findings, "DELEGATECALL at "+formatOffsets(delegatecalls))
+	}
+
+	var score int
+	switch {
+	case len(selfdestructs) > 0 && len(delegatecalls) > 0:
+		score = bothOpcodesScore
+	case len(selfdestructs) > 0:
+		score = selfdestructScore
+	case len(delegatecalls) > 0:
+		score = delegatecallScore
+	default:
+		return CheckResult{
+			Name:    "Dangerous Opcodes",
+			Status:  "pass",
+			Score:   100,
+			Details: "No SELFDESTRUCT or DELEGATECALL in bytecode",
+		}, nil
+	}
+
+	return CheckResult{
+		Name:       "Dangerous Opcodes",
+		Status:     "warning",
+		Score:      score,
+		Details:    strings.Join(findings, "; "),
+		ReasonCode: ReasonDangerousOpcodes,
+	}, nil
+}
+
+// formatOffsets renders bytecode offsets as hex, e.g. "0x1a2, 0x3f0 (+2 more)"
+func formatOffsets(offsets []int) string {
+	shown := offsets
+	if len(shown) > maxListedOffsets {
+		shown = shown[:maxListedOffsets]
+	}
+	parts := make([]string, len(shown))
+	for i, pc := range shown {
+		parts[i] = fmt.Sprintf("0x%x", pc)
+	}
+	result := strings.Join(parts, ", ")
+	if extra := len(offsets) - len(shown); extra > 0 {
+		result += fmt.Sprintf(" (+%d more)", extra)
+	}
+	return result
+}
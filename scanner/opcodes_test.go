@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"context"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+func TestOpcodeOffsets(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want map[byte][]int
+	}{
+		{"plain", "f400ff", map[byte][]int{opDelegatecall: {0}, opSelfdestruct: {2}}},
+		// 0xff is the operand of PUSH1 and 0xf4 sits in PUSH2 data
+		{"push data", "60ff61f4f400", map[byte][]int{}},
+		{"after push", "60ff00ff", map[byte][]int{opSelfdestruct: {3}}},
+		// A truncated PUSH at the end of the code swallows what is left
+		{"truncated push", "7fffff", map[byte][]int{}},
+		// The solc metadata trailer (a1 ... then its length) is not code
+		{"metadata", "00a1ff0002", map[byte][]int{}},
+	}
+	for _, tt := range tests {
+		code, err := hex.DecodeString(tt.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := opcodeOffsets(code, opSelfdestruct, opDelegatecall)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: opcodeOffsets(%s) = %v, want %v", tt.name, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestFormatOffsets(t *testing.T) {
+	tests := []struct {
+		offsets []int
+		want    string
+	}{
+		{[]int{0x1a2}, "0x1a2"},
+		{[]int{1, 2, 3, 4, 5}, "0x1, 0x2, 0x3, 0x4, 0x5"},
+		{[]int{1, 2, 3, 4, 5, 6, 7}, "0x1, 0x2, 0x3, 0x4, 0x5 (+2 more)"},
+	}
+	for _, tt := range tests {
+		if got := formatOffsets(tt.offsets); got != tt.want {
+			t.Errorf("formatOffsets(%v) = %q, want %q", tt.offsets, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDangerousOpcodes(t *testing.T) {
+	accounts := map[string]FixtureAccount{
+		"0x1111111111111111111111111111111111111111": {Code: "0x6080604052600080fd"},
+		"0x2222222222222222222222222222222222222222": {Code: "0x600033ff"},
+		"0x3333333333333333333333333333333333333333": {Code: "0x6000f4"},
+		"0x4444444444444444444444444444444444444444": {Code: "0xf433ff"},
+	}
+	tests := []struct {
+		address string
+		status  string
+		score   int
+		details string
+	}{
+		{"0x1111111111111111111111111111111111111111", "pass", 100, "No SELFDESTRUCT or DELEGATECALL in bytecode"},
+		{"0x2222222222222222222222222222222222222222", "warning", 40, "SELFDESTRUCT at 0x3"},
+		{"0x3333333333333333333333333333333333333333", "warning", 60, "DELEGATECALL at 0x2"},
+		{"0x4444444444444444444444444444444444444444", "warning", 30, "SELFDESTRUCT at 0x2; DELEGATECALL at 0x0"},
+		{"0x5555555555555555555555555555555555555555", "pass", 100, "Not a contract (bytecode scan not applicable)"},
+	}
+	s := fixtureScanner(t, Config{}, accounts)
+	for _, tt := range tests {
+		got, err := s.checkDangerousOpcodes(context.Background(), tt.address, "ethereum")
+		if err != nil {
+			t.Fatalf("%s: error = %v", tt.address, err)
+		}
+		if got.Status != tt.status || got.Score != tt.score || got.Details != tt.details {
+			t.Errorf("%s: %s %d %q, want %s %d %q", tt.address, got.Status, got.Score, got.Details, tt.status, tt.score, tt.details)
+		}
+	}
+}
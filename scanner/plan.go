@@ -0,0 +1,208 @@
+package scanner
+
+import "time"
+
+// CheckCost counts the requests a check makes for one address: calls to
+// the RPC endpoint, to the block explorer and to other services (NFT
+// metadata gateways, the abuse report API)
+type CheckCost struct {
+	RPC      int `json:"rpc"`
+	Explorer int `json:"explorer"`
+	Other    int `json:"other"`
+}
+
+func (c CheckCost) add(other CheckCost) CheckCost {
+	return CheckCost{RPC: c.RPC + other.RPC, Explorer: c.Explorer + other.Explorer, Other: c.Other + other.Other}
+}
+
+func (c CheckCost) times(n int) CheckCost {
+	return CheckCost{RPC: c.RPC * n, Explorer: c.Explorer * n, Other: c.Other * n}
+}
+
+// Lookups that checks share through the per-scan caches; each is counted
+// for the first check in report order that makes it
+var sharedLookupCosts = map[string]CheckCost{
+	"code":     {RPC: 1},
+	"nonce":    {RPC: 1},
+	"token":    {RPC: 4},
+	"source":   {Explorer: 1},
+	"abi":      {Explorer: 1},
+	"creation": {Explorer: 1},
+	"firsttx":  {Explorer: 1},
+}
+
+// checkCosts estimates the requests of each built-in check: the shared
+// lookups it needs and the requests of its own. Counts are typical, not
+// worst case; a check that stops early (e.g. on an account with no code)
+// makes fewer.
+var checkCosts = map[string]struct {
+	shared []string
+	own    CheckCost
+}{
+	"Address Format":        {},
+	"Contract Check":        {shared: []string{"code", "creation"}},
+	"Contract Verification": {shared: []string{"code", "source"}},
+	"Account Age":           {shared: []string{"firsttx"}},
+	"Contract Age":          {shared: []string{"code", "creation"}, own: CheckCost{RPC: 1}},
+	"Transaction Volume":    {shared: []string{"nonce"}, own: CheckCost{Explorer: 1}},
+	"Known Patterns":        {},
+	"Proxy Check":           {shared: []string{"code"}, own: CheckCost{RPC: 2}},
+	"Approval Risk":         {shared: []string{"code", "source", "abi"}},
+	"Deployer Reputation":   {shared: []string{"code", "creation"}, own: CheckCost{Explorer: 1}},
+	"Dangerous Opcodes":     {shared: []string{"code"}},
+	"Mixer Exposure":        {own: CheckCost{Explorer: 2}},
+	"Sanctions":             {},
+	"Address Poisoning":     {own: CheckCost{Explorer: 1}},
+	"Source Heuristics":     {shared: []string{"source"}},
+	"Token Metadata":        {shared: []string{"token"}},
+	"Bytecode Match":        {shared: []string{"code"}},
+	"Method Profile":        {shared: []string{"code", "abi"}, own: CheckCost{Explorer: 1}},
+	"Honeypot Simulation":   {shared: []string{"code", "token"}, own: CheckCost{RPC: 6}},
+	"NFT Metadata":          {shared: []string{"code"}, own: CheckCost{RPC: 3, Other: 1}},
+	"Active Approvals":      {shared: []string{"code"}, own: CheckCost{RPC: 3, Explorer: 1}},
+	"Abuse Reports":         {own: CheckCost{Other: 1}},
+	"Smart Wallet":          {shared: []string{"code", "creation"}, own: CheckCost{RPC: 2}},
+	"Token Impersonation":   {shared: []string{"token"}},
+	"Proxy Upgrades":        {shared: []string{"code"}, own: CheckCost{Explorer: 1}},
+	// One lookup per sampled counterparty
+	"Counterparty Reputation": {own: CheckCost{Explorer: 1 + counterpartySample}},
+	// Up to mevMaxGap transactions read per confirmed candidate
+	"MEV Activity": {shared: []string{"code"}, own: CheckCost{Explorer: 1, RPC: mevConfirmSample * mevMaxGap}},
+}
+
+// Checks that return without requests when the network has no usable
+// explorer
+var explorerOnlyChecks = map[string]bool{
+	"Deployer Reputation": true,
+	"Method Profile":      true,
+	"Active Approvals":    true,
+	"Fund Tracing":        true,
+	"Proxy Upgrades":      true,
+	// Counterparty Reputation runs with --deep
+	"Counterparty Reputation": true,
+	"MEV Activity":            true,
+}
+
+// Requests every scan makes besides its checks: the reverse ENS lookup
+var scanOverheadCost = CheckCost{RPC: 2}
+
+// Typical latencies of one request, used to estimate wall time
+const (
+	planRPCLatency      = 200 * time.Millisecond
+	planExplorerLatency = 300 * time.Millisecond
+	planOtherLatency    = 500 * time.Millisecond
+)
+
+// PlannedCheck is a check a scan would run and what it would cost
+type PlannedCheck struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+	CheckCost
+	// Unknown is set for registered checks, whose requests are not known
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// ScanPlan estimates the requests and time of scanning a number of
+// addresses, without making any
+type ScanPlan struct {
+	Addresses  int            `json:"addresses"`
+	Network    string         `json:"network"`
+	Checks     []PlannedCheck `json:"checks"`
+	PerAddress CheckCost      `json:"per_address"`
+	Total      CheckCost      `json:"total"`
+	// EstimatedTime assumes typical latencies and the explorer rate limit;
+	// cached results make scans faster. EstimatedSeconds is the same.
+	EstimatedTime     time.Duration `json:"-"`
+	EstimatedSeconds  float64       `json:"estimated_seconds"`
+	RequestsPerSecond float64       `json:"requests_per_second"`
+	Concurrency       int           `json:"concurrency"`
+	// NoExplorer is set when the network's explorer needs an API key that
+	// is missing, in which case explorer-backed checks fall back without
+	// requests
+	NoExplorer bool `json:"no_explorer,omitempty"`
+}
+
+// Plan estimates what scanning addresses addresses on network would run
+// and cost, from typical per-check request counts
+func (s *Scanner) Plan(addresses int, network string) ScanPlan {
+	plan := ScanPlan{
+		Addresses:         addresses,
+		Network:           network,
+		Checks:            []PlannedCheck{},
+		PerAddress:        scanOverheadCost,
+		RequestsPerSecond: s.explorerRequestsPerSecond(),
+		Concurrency:       s.cfg.Concurrency,
+		NoExplorer:        !s.hasExplorer(network),
+	}
+	looked := map[string]bool{}
+	for _, c := range s.checks {
+		planned := PlannedCheck{Name: c.Name(), ID: CheckID(c.Name())}
+		cost, ok := checkCosts[c.Name()]
+		switch {
+		case plan.NoExplorer && explorerOnlyChecks[c.Name()]:
+		case c.Name() == "Fund Tracing":
+			planned.CheckCost = s.traceCost()
+		case ok:
+			planned.CheckCost = cost.own
+			for _, lookup := range cost.shared {
+				if !looked[lookup] {
+					looked[lookup] = true
+					planned.CheckCost = planned.CheckCost.add(sharedLookupCosts[lookup])
+				}
+			}
+		default:
+			planned.Unknown = true
+		}
+		if plan.NoExplorer {
+			planned.Explorer = 0
+		}
+		plan.Checks = append(plan.Checks, planned)
+		plan.PerAddress = plan.PerAddress.add(planned.CheckCost)
+	}
+	plan.Total = plan.PerAddress.times(addresses)
+	plan.EstimatedTime = s.planTime(plan)
+	plan.EstimatedSeconds = plan.EstimatedTime.Seconds()
+	return plan
+}
+
+// traceCost is the most the Fund Tracing check can request: two explorer
+// lists per address walked, and a code lookup for each but the first
+func (s *Scanner) traceCost() CheckCost {
+	maxNodes := s.cfg.TraceMaxNodes
+	if maxNodes <= 0 {
+		maxNodes = DefaultTraceMaxNodes
+	}
+	nodes, level := 0, 1
+	for hop := 0; hop < s.traceDepth() && nodes < maxNodes; hop++ {
+		nodes += level
+		level *= traceFanout
+	}
+	if nodes > maxNodes {
+		nodes = maxNodes
+	}
+	return CheckCost{RPC: nodes - 1, Explorer: 2 * nodes}
+}
+
+// planTime estimates the wall time of a plan: each worker scans its share
+// of the addresses one request at a time, and explorer requests of all
+// workers share the rate limit
+func (s *Scanner) planTime(plan ScanPlan) time.Duration {
+	workers := plan.Concurrency
+	if workers > plan.Addresses {
+		workers = plan.Addresses
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	perAddress := time.Duration(plan.PerAddress.RPC)*planRPCLatency +
+		time.Duration(plan.PerAddress.Explorer)*planExplorerLatency +
+		time.Duration(plan.PerAddress.Other)*planOtherLatency
+	addressesPerWorker := (plan.Addresses + workers - 1) / workers
+	estimate := time.Duration(addressesPerWorker) * perAddress
+	if plan.RequestsPerSecond > 0 {
+		if limited := time.Duration(float64(plan.Total.Explorer) / plan.RequestsPerSecond * float64(time.Second)); limited > estimate {
+			estimate = limited
+		}
+	}
+	return estimate
+}
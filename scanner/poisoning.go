@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLookalikeChars is how many leading and trailing hex characters two
+// addresses must share to count as lookalikes
+const DefaultLookalikeChars = 4
+
+// MaxLookalikeChars keeps part of the middle of the address to differ in
+const MaxLookalikeChars = 19
+
+// Recent transactions inspected for lookalike counterparties
+const poisoningTxSample = 1000
+
+// checkAddressPoisoning looks for counterparties that imitate the address
+// or each other: same first and last Config.LookalikeChars hex characters,
+// different middle. Poisoners send dust from such addresses hoping the
+// victim copies the wrong one from their history.
+func (s *Scanner) checkAddressPoisoning(ctx context.Context, address, network string) (CheckResult, error) {
+	if !IsHexAddress(address) {
+		return CheckResult{
+			Name:          "Address Poisoning",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Invalid address (poisoning check not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Address Poisoning",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	txs, err := s.getTxList(ctx, address, network, "desc", poisoningTxSample)
+	if err != nil {
+		return CheckResult{
+			Name:    "Address Poisoning",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+
+	self := strings.ToLower(address)
+	counterparties := map[string]bool{}
+	for _, tx := range txs {
+		for _, party := range []string{tx.From, tx.To} {
+			party = strings.ToLower(party)
+			if party != self && IsHexAddress(party) {
+				counterparties[party] = true
+			}
+		}
+	}
+
+	groups := lookalikeGroups(self, counterparties, s.cfg.LookalikeChars)
+	if len(groups) == 0 {
+		return CheckResult{
+			Name:          "Address Poisoning",
+			Status:        "pass",
+			Score:         100,
+			Details:       fmt.Sprintf("No lookalikes among %d recent counterparties", len(counterparties)),
+			NotApplicable: len(counterparties) == 0,
+		}, nil
+	}
+
+	parts := make([]string, len(groups))
+	for i, group := range groups {
+		names := make([]string, len(group))
+		for j, member := range group {
+			names[j] = ToChecksumAddress(member)
+			if member == self {
+				names[j] += " (this address)"
+			}
+		}
+		parts[i] = strings.Join(names, " ~ ")
+	}
+	return CheckResult{
+		Name:       "Address Poisoning",
+		Status:     "warning",
+		Score:      40,
+		Details:    "Lookalike counterparties, likely address poisoning: " + strings.Join(parts, "; "),
+		ReasonCode: ReasonAddressPoisoning,
+	}, nil
+}
+
+// lookalikeGroups groups self and the counterparties by their first and
+// last n hex characters and returns the groups with more than one member,
+// each sorted with self first
+func lookalikeGroups(self string, counterparties map[string]bool, n int) [][]string {
+	key := func(address string) string {
+		hex := address[2:]
+		return hex[:n] + hex[len(hex)-n:]
+	}
+
+	byKey := map[string][]string{key(self): {self}}
+	for party := range counterparties {
+		byKey[key(party)] = append(byKey[key(party)], party)
+	}
+
+	var groups [][]string
+	for _, group := range byKey {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Slice(group, func(i, j int) bool {
+			if (group[i] == self) != (group[j] == self) {
+				return group[i] == self
+			}
+			return group[i] < group[j]
+		})
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Profile presets the checks and weights of a scan for one kind of user.
+// Checks and weights are named by check ID or full name.
+type Profile struct {
+	Description string `json:"description,omitempty"`
+	// Checks run instead of the default set; empty runs the default set
+	Checks []string `json:"checks,omitempty"`
+	// Weights override DefaultCheckWeights
+	Weights map[string]float64 `json:"weights,omitempty"`
+	// Deep also runs the expensive checks, as with Config.Deep
+	Deep bool `json:"deep,omitempty"`
+}
+
+// DefaultProfiles are the built-in profiles. Config.Profiles may add to
+// or replace them.
+var DefaultProfiles = map[string]Profile{
+	"compliance": {
+		Description: "Sanctions, mixer and counterparty exposure",
+		Checks:      []string{"address", "contract", "age", "volume", "patterns", "sanctions", "mixers", "deployer", "poisoning", "counterparties"},
+		Weights:     map[string]float64{"sanctions": 5, "mixers": 4, "patterns": 3, "deployer": 2},
+	},
+	"dev": {
+		Description: "Contract code: verification, upgradeability and bytecode",
+		Checks:      []string{"address", "contract", "verification", "contract-age", "proxy", "upgrades", "approvals", "deployer", "opcodes", "heuristics", "bytecode", "wallet"},
+		Weights:     map[string]float64{"verification": 3, "opcodes": 2, "heuristics": 2, "bytecode": 3},
+	},
+	"trading": {
+		Description: "Token traps: honeypots, approvals and fake tokens",
+		Checks:      []string{"address", "contract", "verification", "contract-age", "patterns", "proxy", "upgrades", "approvals", "token", "impersonation", "bytecode", "methods", "honeypot", "allowances"},
+		Weights:     map[string]float64{"honeypot": 4, "approvals": 2, "token": 2, "allowances": 2},
+	},
+	"full": {
+		Description: "Every check, including the expensive ones",
+		Deep:        true,
+	},
+}
+
+// ProfileNames lists the built-in and configured profiles, sorted
+func (c Config) ProfileNames() []string {
+	seen := map[string]bool{}
+	for name := range DefaultProfiles {
+		seen[name] = true
+	}
+	for name := range c.Profiles {
+		seen[name] = true
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the named profile from Config.Profiles, else
+// DefaultProfiles
+func (c Config) Profile(name string) (Profile, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if p, ok := c.Profiles[name]; ok {
+		return p, nil
+	}
+	if p, ok := DefaultProfiles[name]; ok {
+		return p, nil
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+}
+
+// ApplyProfile presets the checks and weights of the named profile.
+// Checks already set in Config.Checks take precedence over the profile's;
+// Config.SkipChecks still applies on top.
+func (c *Config) ApplyProfile(name string) error {
+	p, err := c.Profile(name)
+	if err != nil {
+		return err
+	}
+	if len(c.Checks) == 0 {
+		c.Checks = p.Checks
+	}
+	c.Deep = c.Deep || p.Deep
+	if len(p.Weights) > 0 {
+		c.Weights = resolveWeights(c.Weights, p.Weights)
+	}
+	return nil
+}
+
+// Validate reports unknown check names and negative weights
+func (p Profile) Validate() error {
+	if err := ValidateCheckNames(p.Checks); err != nil {
+		return err
+	}
+	for check, weight := range p.Weights {
+		if err := ValidateCheckNames([]string{check}); err != nil {
+			return err
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight of %s must not be negative", check)
+		}
+	}
+	return nil
+}
+
+// resolveWeights returns base, DefaultCheckWeights if nil, with the
+// overrides, keyed by check ID or name, applied by check name
+func resolveWeights(base, overrides map[string]float64) map[string]float64 {
+	if base == nil {
+		base = DefaultCheckWeights
+	}
+	weights := map[string]float64{}
+	for name, weight := range base {
+		weights[name] = weight
+	}
+	names := allCheckNames()
+	for selector, weight := range overrides {
+		for _, name := range names {
+			if selects(selector, name) {
+				weights[name] = weight
+			}
+		}
+	}
+	return weights
+}
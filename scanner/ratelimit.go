@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Pause before the next request once the server reports this many or fewer
+// remaining requests in its quota window
+const lowQuotaRemaining = 1
+
+// Pause used when a server reports an exhausted quota without a reset time
+const defaultQuotaPause = time.Second
+
+// rateLimiter is a token bucket shared by all goroutines making API calls.
+// Servers can additionally pause it through rate-limit response headers.
+type rateLimiter struct {
+	mu          sync.Mutex
+	tokens      float64
+	burst       float64
+	rate        float64 // tokens per second
+	lastFill    time.Time
+	pausedUntil time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		tokens:   float64(burst),
+		burst:    float64(burst),
+		rate:     perSecond,
+		lastFill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available and consumes it, or until ctx
+// is done
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.lastFill).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.lastFill = now
+
+		if pause := l.pausedUntil.Sub(now); pause > 0 {
+			l.mu.Unlock()
+			if err := sleepContext(ctx, pause); err != nil {
+				return err
+			}
+			continue
+		}
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// observe inspects a response's rate-limit headers and pauses every caller
+// when the server asks for it: on Retry-After, or when X-RateLimit-Remaining
+// drops to lowQuotaRemaining, until X-RateLimit-Reset. It returns the pause
+// applied, or zero.
+func (l *rateLimiter) observe(header http.Header) time.Duration {
+	now := time.Now()
+	pause := parseRetryAfter(header.Get("Retry-After"))
+	if remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err == nil && remaining <= lowQuotaRemaining {
+		reset := parseRateLimitReset(header.Get("X-RateLimit-Reset"), now)
+		if reset == 0 {
+			reset = defaultQuotaPause
+		}
+		if reset > pause {
+			pause = reset
+		}
+	}
+	if pause <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := now.Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	return pause
+}
+
+// parseRateLimitReset accepts both a unix timestamp and a number of seconds
+// until the quota window resets
+func parseRateLimitReset(value string, now time.Time) time.Duration {
+	secs, err := strconv.ParseFloat(value, 64)
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	// Anything past 2001 is a timestamp rather than a delay
+	if secs > 1e9 {
+		return time.Unix(int64(secs), 0).Sub(now)
+	}
+	return time.Duration(secs * float64(time.Second))
+}
@@ -0,0 +1,112 @@
+package scanner
+
+// Reason codes of CheckResult.ReasonCode: why a check warned or failed, as
+// a stable code for policies to key off. New codes may be added; existing
+// ones keep their meaning.
+const (
+	// The check could not run on live or cached data; the report's Errors
+	// say why
+	ReasonDataUnavailable = "DATA_UNAVAILABLE"
+
+	// Address Format
+	ReasonInvalidAddress       = "INVALID_ADDRESS"
+	ReasonUnchecksummedAddress = "UNCHECKSUMMED_ADDRESS"
+	ReasonMiscasedKnownAddress = "MISCASED_KNOWN_ADDRESS"
+	// Contract Check
+	ReasonSelfDestructed = "SELF_DESTRUCTED"
+	// Contract Verification
+	ReasonUnverifiedSource = "UNVERIFIED_SOURCE"
+	// Account Age
+	ReasonNoHistory    = "NO_HISTORY"
+	ReasonFreshAccount = "FRESH_ACCOUNT"
+	// Contract Age, and Deployer Reputation for ReasonCreationUnknown
+	ReasonFreshContract   = "FRESH_CONTRACT"
+	ReasonCreationUnknown = "CREATION_UNKNOWN"
+	// Transaction Volume
+	ReasonLowActivity   = "LOW_ACTIVITY"
+	ReasonBurstActivity = "BURST_ACTIVITY"
+	// Known Patterns
+	ReasonDenylistHit      = "DENYLIST_HIT"
+	ReasonMaliciousPattern = "MALICIOUS_PATTERN"
+	// Proxy Check and Proxy Upgrades
+	ReasonProxyUpgradeable = "PROXY_UPGRADEABLE"
+	ReasonRecentUpgrade    = "RECENT_UPGRADE"
+	// Approval Risk
+	ReasonPrivilegedFunctions = "PRIVILEGED_FUNCTIONS"
+	// Deployer Reputation
+	ReasonRiskyDeployer = "RISKY_DEPLOYER"
+	// Dangerous Opcodes
+	ReasonDangerousOpcodes = "DANGEROUS_OPCODES"
+	// Mixer Exposure
+	ReasonMixerExposure = "MIXER_EXPOSURE"
+	// Sanctions
+	ReasonSanctioned = "SANCTIONED"
+	// Address Poisoning
+	ReasonAddressPoisoning = "ADDRESS_POISONING"
+	// Source Heuristics
+	ReasonSourceRedFlags = "SOURCE_RED_FLAGS"
+	// Token Metadata
+	ReasonSuspiciousTokenMetadata = "SUSPICIOUS_TOKEN_METADATA"
+	// Bytecode Match
+	ReasonScamBytecode = "SCAM_BYTECODE"
+	// Method Profile
+	ReasonDrainerBehavior = "DRAINER_BEHAVIOR"
+	// Honeypot Simulation
+	ReasonHoneypotSuspected    = "HONEYPOT_SUSPECTED"
+	ReasonSimulationIncomplete = "SIMULATION_INCOMPLETE"
+	// NFT Metadata
+	ReasonSuspiciousNFTMetadata = "SUSPICIOUS_NFT_METADATA"
+	ReasonNFTMetadataIncomplete = "NFT_METADATA_INCOMPLETE"
+	// Active Approvals
+	ReasonRiskyApprovals = "RISKY_APPROVALS"
+	// Abuse Reports
+	ReasonAbuseReported = "ABUSE_REPORTED"
+	// Fund Tracing
+	ReasonTaintedFunds = "TAINTED_FUNDS"
+	// Token Impersonation
+	ReasonTokenImpersonation = "TOKEN_IMPERSONATION"
+	// Counterparty Reputation
+	ReasonSuspiciousCounterparties = "SUSPICIOUS_COUNTERPARTIES"
+	// MEV Activity
+	ReasonMEVActivity = "MEV_ACTIVITY"
+)
+
+// ReasonCodes lists the reason codes of the built-in checks
+var ReasonCodes = []string{
+	ReasonDataUnavailable,
+	ReasonInvalidAddress,
+	ReasonUnchecksummedAddress,
+	ReasonMiscasedKnownAddress,
+	ReasonSelfDestructed,
+	ReasonUnverifiedSource,
+	ReasonNoHistory,
+	ReasonFreshAccount,
+	ReasonFreshContract,
+	ReasonCreationUnknown,
+	ReasonLowActivity,
+	ReasonBurstActivity,
+	ReasonDenylistHit,
+	ReasonMaliciousPattern,
+	ReasonProxyUpgradeable,
+	ReasonRecentUpgrade,
+	ReasonPrivilegedFunctions,
+	ReasonRiskyDeployer,
+	ReasonDangerousOpcodes,
+	ReasonMixerExposure,
+	ReasonSanctioned,
+	ReasonAddressPoisoning,
+	ReasonSourceRedFlags,
+	ReasonSuspiciousTokenMetadata,
+	ReasonScamBytecode,
+	ReasonDrainerBehavior,
+	ReasonHoneypotSuspected,
+	ReasonSimulationIncomplete,
+	ReasonSuspiciousNFTMetadata,
+	ReasonNFTMetadataIncomplete,
+	ReasonRiskyApprovals,
+	ReasonAbuseReported,
+	ReasonTaintedFunds,
+	ReasonTokenImpersonation,
+	ReasonSuspiciousCounterparties,
+	ReasonMEVActivity,
+}
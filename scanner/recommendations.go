@@ -0,0 +1,190 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RecommendationTemplates word the recommendations added to reports. Fail
+// and Warning map a check name to the recommendation for a failed or
+// borderline result; checks without an entry use the "*" entry, and an
+// empty template adds nothing. "{name}" and "{details}" are replaced with
+// the check's name and details.
+type RecommendationTemplates struct {
+	Fail    map[string]string `json:"fail"`
+	Warning map[string]string `json:"warning"`
+	// Passed is used when no check produced a recommendation
+	Passed []string `json:"passed"`
+	// Allowlisted is the only recommendation of allowlisted addresses;
+	// {details} is "Address on trusted allowlist (label)"
+	Allowlisted string `json:"allowlisted"`
+	// InsufficientData leads the recommendations of reports whose low risk
+	// level was capped at medium for lack of data, in place of Passed;
+	// {details} says how many checks had data
+	InsufficientData string `json:"insufficient_data"`
+	// Revoke is added for each approval the Active Approvals check
+	// recommends revoking; {details} names the token, the spender and the
+	// approve(spender, 0) calldata
+	Revoke string `json:"revoke"`
+	// ReplaceDefaults drops the built-in Fail and Warning entries instead
+	// of adding to them, e.g. to reword every recommendation
+	ReplaceDefaults bool `json:"replace_defaults"`
+}
+
+// DefaultRecommendationTemplates are the built-in English recommendations
+var DefaultRecommendationTemplates = RecommendationTemplates{
+	Fail: map[string]string{
+		"*": "⚠️  {name}: {details}",
+	},
+	Warning: map[string]string{
+		"*": "🔎 {name}: {details} — review before transacting",
+		// Addresses are routinely pasted in lowercase
+		"Address Format":        "",
+		"Contract Verification": "🔎 {name}: {details} — ask for verified source or review the bytecode before interacting",
+		"Account Age":           "🔎 {name}: {details} — new accounts warrant extra scrutiny; start with a small amount",
+		"Contract Age":          "🔎 {name}: {details} — recently deployed contracts have no track record yet",
+		"Transaction Volume":    "🔎 {name}: {details} — little or unusual activity; send a small test transaction first",
+		"Proxy Check":           "🔎 {name}: {details} — the code can be replaced; check who controls upgrades",
+		"Approval Risk":         "🔎 {name}: {details} — approve only the amount you need and revoke it afterwards",
+		"Deployer Reputation":   "🔎 {name}: {details} — look into the deployer's other contracts",
+		"Dangerous Opcodes":     "🔎 {name}: {details} — check who can trigger these code paths",
+		"Address Poisoning":     "🔎 {name}: {details} — compare the full address and never copy it from transaction history",
+		"Source Heuristics":     "🔎 {name}: {details} — have the flagged code reviewed",
+		"Token Metadata":        "🔎 {name}: {details} — confirm the token address with an official source",
+		"Method Profile":        "🔎 {name}: {details} — revoke approvals you do not recognize",
+		"NFT Metadata":          "🔎 {name}: {details} — check the collection on its official marketplace page",
+		"Abuse Reports":         "🔎 {name}: {details} — read the reports before transacting",
+		"Fund Tracing":          "🔎 {name}: {details} — funds may be proceeds of the flagged source",
+		// Each approval gets its own Revoke recommendation
+		"Active Approvals": "",
+	},
+	Passed: []string{
+		"✓ Address passed all automated checks",
+		"⚠️  Manual review still recommended for high-value transactions",
+	},
+	Allowlisted:      "✓ {details}",
+	Revoke:           "🧹 Revoke {details}",
+	InsufficientData: "⚠️  Insufficient data for a confident low-risk verdict: {details} — rescan once the data sources are reachable",
+}
+
+var templatePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// with returns t with the entries of other added or replaced; Passed,
+// Allowlisted, InsufficientData and Revoke are replaced when set
+func (t RecommendationTemplates) with(other RecommendationTemplates) RecommendationTemplates {
+	merged := RecommendationTemplates{
+		Fail:             map[string]string{},
+		Warning:          map[string]string{},
+		Passed:           t.Passed,
+		Allowlisted:      t.Allowlisted,
+		InsufficientData: t.InsufficientData,
+		Revoke:           t.Revoke,
+	}
+	if other.ReplaceDefaults {
+		t.Fail, t.Warning = nil, nil
+	}
+	for _, m := range []map[string]string{t.Fail, other.Fail} {
+		for name, tmpl := range m {
+			merged.Fail[name] = tmpl
+		}
+	}
+	for _, m := range []map[string]string{t.Warning, other.Warning} {
+		for name, tmpl := range m {
+			merged.Warning[name] = tmpl
+		}
+	}
+	if other.Passed != nil {
+		merged.Passed = other.Passed
+	}
+	if other.Allowlisted != "" {
+		merged.Allowlisted = other.Allowlisted
+	}
+	if other.InsufficientData != "" {
+		merged.InsufficientData = other.InsufficientData
+	}
+	if other.Revoke != "" {
+		merged.Revoke = other.Revoke
+	}
+	return merged
+}
+
+// Validate rejects templates with placeholders other than {name} and
+// {details}
+func (t RecommendationTemplates) Validate() error {
+	check := func(where, tmpl string) error {
+		for _, p := range templatePlaceholder.FindAllString(tmpl, -1) {
+			if p != "{name}" && p != "{details}" {
+				return fmt.Errorf("recommendation template %s: unknown placeholder %s (use {name} or {details})", where, p)
+			}
+		}
+		return nil
+	}
+	for status, m := range map[string]map[string]string{"fail": t.Fail, "warning": t.Warning} {
+		for name, tmpl := range m {
+			if err := check(fmt.Sprintf("%s[%q]", status, name), tmpl); err != nil {
+				return err
+			}
+		}
+	}
+	for i, tmpl := range t.Passed {
+		if err := check(fmt.Sprintf("passed[%d]", i), tmpl); err != nil {
+			return err
+		}
+	}
+	if err := check("allowlisted", t.Allowlisted); err != nil {
+		return err
+	}
+	if err := check("insufficient_data", t.InsufficientData); err != nil {
+		return err
+	}
+	return check("revoke", t.Revoke)
+}
+
+func expandTemplate(tmpl, name, details string) string {
+	return strings.NewReplacer("{name}", name, "{details}", details).Replace(tmpl)
+}
+
+// template returns the entry for check, else the "*" entry
+func template(templates map[string]string, check string) string {
+	if tmpl, ok := templates[check]; ok {
+		return tmpl
+	}
+	return templates["*"]
+}
+
+// generate returns the recommendations for a report's checks. Warnings
+// from checks that could not query their data source are skipped, as they
+// say nothing about the address. A non-empty coverage note, for reports
+// capped at medium risk for lack of data, leads the list instead of Passed.
+// Each revocation adds a Revoke recommendation.
+func (t RecommendationTemplates) generate(checks []CheckResult, revocations []Revocation, coverage string) []string {
+	recommendations := []string{}
+	if coverage != "" && t.InsufficientData != "" {
+		recommendations = append(recommendations, expandTemplate(t.InsufficientData, "", coverage))
+	}
+	for _, check := range checks {
+		var tmpl string
+		switch {
+		case check.Status == "fail":
+			tmpl = template(t.Fail, check.Name)
+		case check.Status == "warning" && check.DataSource != DataFallback:
+			tmpl = template(t.Warning, check.Name)
+		}
+		if tmpl != "" {
+			recommendations = append(recommendations, expandTemplate(tmpl, check.Name, check.Details))
+		}
+	}
+	if t.Revoke != "" {
+		for _, revocation := range revocations {
+			recommendations = append(recommendations, expandTemplate(t.Revoke, "Active Approvals", revocation.String()))
+		}
+	}
+
+	if len(recommendations) == 0 && coverage == "" {
+		for _, tmpl := range t.Passed {
+			recommendations = append(recommendations, expandTemplate(tmpl, "", ""))
+		}
+	}
+	return recommendations
+}
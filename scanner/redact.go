@@ -0,0 +1,157 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// RedactionPolicy sanitizes reports shared outside the team. Redacting
+// always masks addresses and removes URLs and credentials from text; the
+// policy adds report fields to leave empty.
+type RedactionPolicy struct {
+	// Fields are report fields emptied by JSON name, nested with dots:
+	// "ens_name", "token", "checks.details". Required fields stay in the
+	// JSON with empty values, so redacted reports still match the schema.
+	Fields []string `json:"fields,omitempty"`
+}
+
+var (
+	redactAddressPattern = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+	// URLs, key=value credentials and bearer tokens
+	redactSecretPattern = regexp.MustCompile(`(?i)\b(?:https?|wss?)://[^\s"'<>]+|\b(?:api_?key|key|token|secret|password)=[^\s&]+|\bbearer\s+\S+`)
+)
+
+// MaskAddress keeps the first four and last four hex digits of address:
+// 0xabcd…1234
+func MaskAddress(address string) string {
+	if len(address) < 12 {
+		return address
+	}
+	return address[:6] + "…" + address[len(address)-4:]
+}
+
+// Text masks the addresses in s and replaces URLs and credentials with
+// REDACTED
+func (p RedactionPolicy) Text(s string) string {
+	s = redactSecretPattern.ReplaceAllString(s, redacted)
+	return redactAddressPattern.ReplaceAllStringFunc(s, MaskAddress)
+}
+
+// Omits reports whether the policy empties field
+func (p RedactionPolicy) Omits(field string) bool {
+	for _, f := range p.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Report returns a redacted copy of r, sealed again so that its content
+// hash verifies
+func (p RedactionPolicy) Report(r ReputationReport) ReputationReport {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return r
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return r
+	}
+	for _, field := range p.Fields {
+		omitField(tree, strings.Split(field, "."))
+	}
+	tree = p.redactTree(tree)
+	if data, err = json.Marshal(tree); err != nil {
+		return r
+	}
+	var out ReputationReport
+	if err := json.Unmarshal(data, &out); err != nil {
+		return r
+	}
+	out.Seal()
+	return out
+}
+
+// redactTree applies Text to every string in a decoded JSON value
+func (p RedactionPolicy) redactTree(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return p.Text(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = p.redactTree(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = p.redactTree(v[key])
+		}
+	}
+	return v
+}
+
+// omitField deletes the field at path from a decoded JSON value, in every
+// element of the arrays along the way
+func omitField(v interface{}, path []string) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, elem := range v {
+			omitField(elem, path)
+		}
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(v, path[0])
+			return
+		}
+		omitField(v[path[0]], path[1:])
+	}
+}
+
+// Validate reports fields that name no report field
+func (p RedactionPolicy) Validate() error {
+	for _, field := range p.Fields {
+		if !hasJSONField(reflect.TypeOf(ReputationReport{}), strings.Split(field, ".")) {
+			return fmt.Errorf("unknown report field %q", field)
+		}
+	}
+	return nil
+}
+
+// hasJSONField reports whether path names a field of t by JSON name
+func hasJSONField(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" {
+			if hasJSONField(f.Type, path) {
+				return true
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == path[0] && hasJSONField(f.Type, path[1:]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+)
+
+// Check is a single reputation check. Implement it and call RegisterCheck
+// to add org-specific risk signals to every Scanner.
+type Check interface {
+	// Name identifies the check in reports and in Config.Weights; checks
+	// without a weight count once
+	Name() string
+	// Run checks address on network. Results without a DataSource are
+	// reported as live data with full confidence.
+	Run(ctx context.Context, address, network string) CheckResult
+}
+
+var (
+	registryMu sync.Mutex
+	registered []Check
+)
+
+// RegisterCheck adds c to the checks run by Scanners created afterwards,
+// after the built-in checks. Call it before NewScanner, typically from an
+// init function. It panics if c is nil or another registered check has
+// the same name.
+func RegisterCheck(c Check) {
+	if c == nil {
+		panic("scanner: RegisterCheck of nil check")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, other := range registered {
+		if other.Name() == c.Name() {
+			panic("scanner: RegisterCheck called twice for " + c.Name())
+		}
+	}
+	registered = append(registered, c)
+}
+
+// registeredChecks returns a snapshot of the checks added with RegisterCheck
+func registeredChecks() []Check {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	checks := make([]Check, 0, len(registered))
+	for _, c := range registered {
+		checks = append(checks, customCheck{c})
+	}
+	return checks
+}
+
+// builtinCheck adapts one of the Scanner's check methods to Check
+type builtinCheck struct {
+	s     *Scanner
+	name  string
+	run   checkFunc
+	cache bool // keep results in the on-disk cache
+}
+
+func (c builtinCheck) Name() string { return c.name }
+
+func (c builtinCheck) Run(ctx context.Context, address, network string) CheckResult {
+	if c.cache {
+		return c.s.cachedCheck(ctx, c.name, address, network, c.run)
+	}
+	return c.s.runCheck(ctx, c.name, address, network, c.run)
+}
+
+// local adapts a check that needs no network access
+func local(check func(address string) CheckResult) checkFunc {
+	return func(_ context.Context, address, _ string) (CheckResult, error) {
+		return check(address), nil
+	}
+}
+
+// builtinChecks returns the built-in checks in report order
+func (s *Scanner) builtinChecks() []Check {
+	checks := []Check{
+		// Check 1: Address format
+		builtinCheck{s, "Address Format", s.checkAddressFormat, false},
+		// Check 2: Is contract
+		builtinCheck{s, "Contract Check", s.checkIsContract, true},
+		// Check 3: Contract verification
+		builtinCheck{s, "Contract Verification", s.checkVerification, true},
+		// Check 4: Account age
+		builtinCheck{s, "Account Age", s.checkAccountAge, true},
+		// Check 5: Contract age from the creation block
+		builtinCheck{s, "Contract Age", s.checkContractAge, true},
+		// Check 6: Transaction volume
+		builtinCheck{s, "Transaction Volume", s.checkTransactionVolume, true},
+		// Check 7: Known patterns
+		builtinCheck{s, "Known Patterns", local(s.checkKnownPatterns), false},
+		// Check 8: Upgradeable proxy
+		builtinCheck{s, "Proxy Check", s.checkProxy, true},
+		// Check 9: Token approval functions
+		builtinCheck{s, "Approval Risk", s.checkApprovals, true},
+		// Check 10: Deployer reputation. Not cached on disk since the
+		// verdict depends on the loaded denylists.
+		builtinCheck{s, "Deployer Reputation", s.checkDeployer, false},
+		// Check 11: SELFDESTRUCT / DELEGATECALL in bytecode
+		builtinCheck{s, "Dangerous Opcodes", s.checkDangerousOpcodes, true},
+		// Check 12: Mixer counterparties. Not cached on disk since mixers
+		// are configured through the denylists.
+		builtinCheck{s, "Mixer Exposure", s.checkMixerExposure, false},
+		// Check 13: Sanctions list
+		builtinCheck{s, "Sanctions", local(s.checkSanctions), false},
+		// Check 14: Lookalike counterparties. Not cached on disk since the
+		// verdict depends on Config.LookalikeChars.
+		builtinCheck{s, "Address Poisoning", s.checkAddressPoisoning, false},
+		// Check 15: Red-flag patterns in verified source
+		builtinCheck{s, "Source Heuristics", s.checkSourceHeuristics, true},
+		// Check 16: ERC-20 metadata. Not cached on disk since the report's
+		// token field is filled from the same lookup.
+		builtinCheck{s, "Token Metadata", s.checkTokenMetadata, false},
+		// Check 17: Known scam bytecode. Not cached on disk since the
+		// verdict depends on the loaded hash lists.
+		builtinCheck{s, "Bytecode Match", s.checkBytecodeMatch, false},
+		// Check 18: Methods called by recent transactions. Not cached on
+		// disk since the names depend on the loaded signature lists.
+		builtinCheck{s, "Method Profile", s.checkMethodProfile, false},
+	}
+	// Check 19: Honeypot simulation (--deep only, or when selected)
+	if s.cfg.Deep || selectsAny(s.cfg.Checks, "Honeypot Simulation") {
+		checks = append(checks, builtinCheck{s, "Honeypot Simulation", s.checkHoneypot, true})
+	}
+	// Check 20: Off-chain NFT metadata (--deep only, or when selected).
+	// Not cached on disk since gateway outages are transient.
+	if s.cfg.Deep || selectsAny(s.cfg.Checks, "NFT Metadata") {
+		checks = append(checks, builtinCheck{s, "NFT Metadata", s.checkNFTMetadata, false})
+	}
+	// Check 21: Unlimited approvals an account should revoke (--deep or
+	// Config.Revocations only, or when selected). Not cached on disk since
+	// the report's revocations come from the same lookup.
+	if s.cfg.Deep || s.cfg.Revocations || selectsAny(s.cfg.Checks, "Active Approvals") {
+		checks = append(checks, builtinCheck{s, "Active Approvals", s.checkActiveApprovals, false})
+	}
+	// Check 22: Community abuse reports (Config.AbuseReportsURL only, or
+	// when selected)
+	if s.cfg.AbuseReportsURL != "" || selectsAny(s.cfg.Checks, "Abuse Reports") {
+		checks = append(checks, builtinCheck{s, "Abuse Reports", s.checkAbuseReports, true})
+	}
+	// Check 23: Tainted funds within Config.TraceDepth hops (only when
+	// set, or when selected). Not cached on disk since the verdict
+	// depends on the loaded denylists.
+	if s.cfg.TraceDepth > 0 || selectsAny(s.cfg.Checks, "Fund Tracing") {
+		checks = append(checks, builtinCheck{s, "Fund Tracing", s.checkFundTracing, false})
+	}
+	// Check 24: Smart contract wallets. Not cached on disk since the
+	// adjustment of the code checks comes from the same lookup.
+	checks = append(checks, builtinCheck{s, "Smart Wallet", s.checkSmartWallet, false})
+	// Check 25: Tokens copying a major token. Not cached on disk since the
+	// canonical addresses come from the network config.
+	checks = append(checks, builtinCheck{s, "Token Impersonation", s.checkTokenImpersonation, false})
+	// Check 26: Recent proxy implementation swaps. Not cached on disk since
+	// the verdict depends on the time and Config.UpgradeWindow.
+	checks = append(checks, builtinCheck{s, "Proxy Upgrades", s.checkProxyUpgrades, false})
+	// Check 27: Denylisted and new accounts among the counterparties
+	// (--deep only, or when selected). Not cached on disk since the
+	// verdict depends on the loaded denylists.
+	if s.cfg.Deep || selectsAny(s.cfg.Checks, "Counterparty Reputation") {
+		checks = append(checks, builtinCheck{s, "Counterparty Reputation", s.checkCounterparties, false})
+	}
+	// Check 28: Sandwich and MEV bot patterns of accounts (--deep only, or
+	// when selected). Not cached on disk since each scan samples the
+	// latest transactions.
+	if s.cfg.Deep || selectsAny(s.cfg.Checks, "MEV Activity") {
+		checks = append(checks, builtinCheck{s, "MEV Activity", s.checkMEV, false})
+	}
+	return checks
+}
+
+// customCheck fills in the fields a registered check may leave empty
+type customCheck struct {
+	Check
+}
+
+func (c customCheck) Run(ctx context.Context, address, network string) CheckResult {
+	result := c.Check.Run(ctx, address, network)
+	if result.Name == "" {
+		result.Name = c.Name()
+	}
+	return withProvenance(result, nil)
+}
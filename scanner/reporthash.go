@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReportHashVersion prefixes report IDs; it changes, with Version, whenever
+// the fields or encoding covered by ReportID or ContentHash change
+const ReportHashVersion = "v1"
+
+// ErrContentHashMismatch is returned by VerifyContentHash for reports
+// altered after they were written
+var ErrContentHashMismatch = errors.New("content hash mismatch")
+
+// reportID hashes what a scan found, not when: the lowercase address, the
+// network and, sorted by check name, each check's name, status, score,
+// severity and details, one tab-separated line per check. Rescans that
+// find the same get the same ID.
+func reportID(r ReputationReport) string {
+	checks := append([]CheckResult(nil), r.Checks...)
+	sort.SliceStable(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+
+	var b strings.Builder
+	b.WriteString(ReportHashVersion + "\n" + strings.ToLower(r.Address) + "\n" + r.Network + "\n")
+	for _, c := range checks {
+		b.WriteString(strings.Join([]string{c.Name, c.Status, strconv.Itoa(c.Score), c.Severity, c.Details}, "\t") + "\n")
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return ReportHashVersion + ":" + hex.EncodeToString(sum[:16])
+}
+
+// contentHash is the SHA-256 of the report's compact JSON encoding, keys
+// in the order they are written and without HTML escaping, with
+// content_hash left out
+func contentHash(r ReputationReport) (string, error) {
+	r.ContentHash = ""
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// Seal sets ReportID and ContentHash. Scans seal their reports; call it
+// again after changing a report, e.g. to add a Label.
+func (r *ReputationReport) Seal() {
+	r.ReportID = reportID(*r)
+	r.ContentHash, _ = contentHash(*r)
+}
+
+// VerifyContentHash checks that a JSON encoded ReputationReport still
+// matches its content_hash, wrapping ErrContentHashMismatch if not
+func VerifyContentHash(data []byte) error {
+	var report ReputationReport
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&report); err != nil {
+		return fmt.Errorf("invalid report: %w", err)
+	}
+	if report.ContentHash == "" {
+		return errors.New("report has no content_hash")
+	}
+	hash, err := contentHash(report)
+	if err != nil {
+		return err
+	}
+	if hash != report.ContentHash {
+		return fmt.Errorf("%w: report hashes to %s, content_hash is %s", ErrContentHashMismatch, hash, report.ContentHash)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"context"
+	"time"
+)
+
+// RescanSummary says how the automatic rescans of a low confidence report
+// went (see Config.RescanLowConfidence)
+type RescanSummary struct {
+	Attempts          int `json:"attempts"`
+	InitialConfidence int `json:"initial_confidence"` // before the first rescan
+	// Rescanned are the checks run again; Improved are those of them that
+	// got live or cached data on a rescan
+	Rescanned []string `json:"rescanned"`
+	Improved  []string `json:"improved"`
+}
+
+// retryableFallback reports whether a result fell back on a failure a
+// later attempt may not hit: a rate limit, an outage or a timeout
+func retryableFallback(check CheckResult) bool {
+	return check.err != nil && newCheckError(check.Name, check.err).Retryable
+}
+
+// lowConfidence reports whether checks, the results of s.checks in order,
+// warrant a rescan: their confidence is below Config.RescanConfidence and
+// some fell back on retryable failures
+func (s *Scanner) lowConfidence(checks []CheckResult) bool {
+	if s.calculateConfidence(checks) >= s.cfg.RescanConfidence {
+		return false
+	}
+	for _, check := range checks {
+		if retryableFallback(check) {
+			return true
+		}
+	}
+	return false
+}
+
+// rescanLowConfidence runs the checks that fell back on retryable
+// failures again, after Config.RescanDelay, up to
+// Config.RescanLowConfidence times while checks have low confidence.
+// Results with more confidence replace the fallbacks in checks. It
+// returns nil when no rescan ran.
+func (s *Scanner) rescanLowConfidence(ctx context.Context, address, network string, checks []CheckResult) *RescanSummary {
+	if s.cfg.RescanLowConfidence <= 0 || !s.lowConfidence(checks) {
+		return nil
+	}
+	summary := &RescanSummary{InitialConfidence: s.calculateConfidence(checks), Rescanned: []string{}, Improved: []string{}}
+	rescanned, improved := map[string]bool{}, map[string]bool{}
+	for summary.Attempts < s.cfg.RescanLowConfidence && s.lowConfidence(checks) {
+		select {
+		case <-ctx.Done():
+			return summary
+		case <-time.After(s.cfg.RescanDelay):
+		}
+		summary.Attempts++
+		for i, check := range s.checks {
+			if !retryableFallback(checks[i]) {
+				continue
+			}
+			result := check.Run(ctx, address, network)
+			if !rescanned[result.Name] {
+				rescanned[result.Name] = true
+				summary.Rescanned = append(summary.Rescanned, result.Name)
+			}
+			if result.Confidence <= checks[i].Confidence {
+				continue
+			}
+			checks[i] = result
+			if result.DataSource != DataFallback && !improved[result.Name] {
+				improved[result.Name] = true
+				summary.Improved = append(summary.Improved, result.Name)
+			}
+		}
+	}
+	return summary
+}
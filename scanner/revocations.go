@@ -0,0 +1,280 @@
+package scanner
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Approval(address,address,uint256), the ERC-20 approval event. ERC-721
+// emits the same event with the token ID as a third indexed topic.
+var approvalTopic = "0x" + hex.EncodeToString(keccak256([]byte("Approval(address,address,uint256)")))
+
+// Approval events read per account
+const approvalLogSample = 1000
+
+// Allowances at or above the largest uint96, which no real balance comes
+// close to, count as unlimited; tokens with 96-bit balances such as UNI
+// cap "infinite" approvals there instead of at the uint256 maximum
+var unlimitedAllowance = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(1))
+
+// At most this many unlimited approvals are checked for risky spenders
+const maxRevocationCandidates = 20
+
+// Revocation is an unlimited token approval to a risky spender that the
+// account should revoke by sending Calldata, approve(spender, 0), to Token
+type Revocation struct {
+	Token       string `json:"token"`
+	TokenSymbol string `json:"token_symbol,omitempty"`
+	Spender     string `json:"spender"`
+	Reason      string `json:"reason"` // why the spender is risky, e.g. "EOA"
+	Calldata    string `json:"calldata"`
+}
+
+// String is the revocation guidance of recommendations, e.g. "the
+// unlimited USDC approval of 0x... (EOA): call approve(0x..., 0) on 0x..."
+func (r Revocation) String() string {
+	token := r.TokenSymbol
+	if token == "" {
+		token = r.Token
+	}
+	return fmt.Sprintf("the unlimited %s approval of %s (%s): call approve(%s, 0) on %s, calldata %s",
+		token, r.Spender, r.Reason, r.Spender, r.Token, r.Calldata)
+}
+
+// tokenApproval is the latest approval event of one token and spender
+type tokenApproval struct {
+	token, spender string
+	amount         *big.Int
+}
+
+// unlimitedApprovals returns the token approvals an account last set to
+// an unlimited amount, from its ERC-20 approval events
+func (s *Scanner) unlimitedApprovals(ctx context.Context, owner, network string) ([]tokenApproval, int, error) {
+	logs, err := s.getLogs(ctx, network, approvalTopic, "0x"+hex.EncodeToString(addressWord(owner)), approvalLogSample)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Later events replace earlier ones; the explorer returns logs oldest first
+	latest := map[string]tokenApproval{}
+	var order []string
+	for _, log := range logs {
+		if len(log.Topics) != 3 {
+			continue
+		}
+		data, err := hex.DecodeString(strings.TrimPrefix(log.Data, "0x"))
+		if err != nil || len(data) < 32 {
+			continue
+		}
+		spender, ok := decodeAddressWord(topicWord(log.Topics[2]))
+		if !ok {
+			continue
+		}
+		approval := tokenApproval{token: strings.ToLower(log.Address), spender: spender, amount: new(big.Int).SetBytes(data[:32])}
+		key := approval.token + "|" + approval.spender
+		if _, ok := latest[key]; !ok {
+			order = append(order, key)
+		}
+		latest[key] = approval
+	}
+
+	var unlimited []tokenApproval
+	for _, key := range order {
+		if approval := latest[key]; approval.amount.Cmp(unlimitedAllowance) >= 0 {
+			unlimited = append(unlimited, approval)
+		}
+	}
+	return unlimited, len(logs), nil
+}
+
+// topicWord decodes a 32-byte hex topic; malformed topics decode to zeros
+func topicWord(topic string) []byte {
+	word, err := hex.DecodeString(strings.TrimPrefix(topic, "0x"))
+	if err != nil || len(word) != 32 {
+		return make([]byte, 32)
+	}
+	return word
+}
+
+// allowanceActive reports whether the allowance is still unlimited, e.g.
+// not spent down by transfers that emit no approval event. Tokens whose
+// allowance() cannot be read are taken at their last event.
+func (s *Scanner) allowanceActive(ctx context.Context, owner, network string, approval tokenApproval) (bool, error) {
+	result, err := s.ethCall(ctx, network, approval.token, encodeCall("allowance(address,address)", owner, approval.spender))
+	if isRevert(err) || err == nil && len(result) < 32 {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return new(big.Int).SetBytes(result[:32]).Cmp(unlimitedAllowance) >= 0, nil
+}
+
+// spenderRisk explains why an unlimited allowance to spender is risky, and
+// whether the spender is denylisted: denylisted addresses, accounts, which
+// can move the tokens without any code constraining them, and unverified
+// contracts are. It returns "" for verified contracts.
+func (s *Scanner) spenderRisk(ctx context.Context, spender, network string) (string, bool, error) {
+	if entry, ok := s.lookupDenylist(spender); ok {
+		reason := "denylisted by " + entry.Source
+		if entry.Comment != "" {
+			reason += ": " + entry.Comment
+		}
+		return reason, true, nil
+	}
+	code, err := s.getCode(ctx, spender, network)
+	if err != nil {
+		return "", false, err
+	}
+	if len(code) == 0 {
+		return "EOA", false, nil
+	}
+	source, err := s.getSourceCode(ctx, spender, network)
+	if err != nil {
+		return "", false, err
+	}
+	if source.SourceCode == "" {
+		return "unverified contract", false, nil
+	}
+	return "", false, nil
+}
+
+// revocations returns the approvals the Active Approvals check of this
+// scanner found worth revoking, or nil if the check did not run
+func (s *Scanner) revocations(address, network string) []Revocation {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	return s.revocationCache[chainCacheKey(address, network)]
+}
+
+// checkActiveApprovals reads the ERC-20 approval events of an account and
+// flags unlimited allowances it still grants to denylisted addresses,
+// EOAs or unverified contracts. The approvals to revoke are added to the
+// report with the approve(spender, 0) calldata that revokes them.
+func (s *Scanner) checkActiveApprovals(ctx context.Context, address, network string) (CheckResult, error) {
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Active Approvals",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+	rpcFailed := func(err error) (CheckResult, error) {
+		return CheckResult{
+			Name:    "Active Approvals",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return rpcFailed(err)
+	}
+	if len(code) > 0 {
+		return CheckResult{
+			Name:          "Active Approvals",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not an account (active approvals not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	approvals, events, err := s.unlimitedApprovals(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Active Approvals",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+	if len(approvals) > maxRevocationCandidates {
+		approvals = approvals[len(approvals)-maxRevocationCandidates:]
+	}
+
+	revocations := []Revocation{}
+	active, denylisted := 0, false
+	for _, approval := range approvals {
+		ok, err := s.allowanceActive(ctx, address, network, approval)
+		if err != nil {
+			return rpcFailed(err)
+		}
+		if !ok {
+			continue
+		}
+		active++
+		reason, denied, err := s.spenderRisk(ctx, approval.spender, network)
+		if err != nil {
+			return CheckResult{
+				Name:    "Active Approvals",
+				Status:  "warning",
+				Score:   50,
+				Details: "Spender lookup failed: " + err.Error(),
+			}, err
+		}
+		if reason == "" {
+			continue
+		}
+		denylisted = denylisted || denied
+		revocation := Revocation{
+			Token:    ToChecksumAddress(approval.token),
+			Spender:  ToChecksumAddress(approval.spender),
+			Reason:   reason,
+			Calldata: encodeCall("approve(address,uint256)", approval.spender, big.NewInt(0)),
+		}
+		if info, _ := s.tokenInfo(ctx, approval.token, network); info != nil {
+			revocation.TokenSymbol = info.Symbol
+		}
+		revocations = append(revocations, revocation)
+	}
+
+	s.cacheMu.Lock()
+	s.revocationCache[chainCacheKey(address, network)] = revocations
+	s.cacheMu.Unlock()
+
+	if len(revocations) == 0 {
+		details := fmt.Sprintf("No unlimited approvals among %d approval events", events)
+		if active > 0 {
+			details = fmt.Sprintf("%d unlimited approvals, all to verified contracts", active)
+		}
+		return CheckResult{
+			Name:    "Active Approvals",
+			Status:  "pass",
+			Score:   100,
+			Details: details,
+		}, nil
+	}
+
+	var flagged []string
+	for _, r := range revocations {
+		token := r.TokenSymbol
+		if token == "" {
+			token = r.Token
+		}
+		flagged = append(flagged, fmt.Sprintf("%s to %s (%s)", token, r.Spender, r.Reason))
+	}
+	details := fmt.Sprintf("%d unlimited approvals to risky spenders: %s", len(revocations), strings.Join(flagged, "; "))
+	if denylisted {
+		return CheckResult{
+			Name:       "Active Approvals",
+			Status:     "fail",
+			Score:      20,
+			Details:    details,
+			ReasonCode: ReasonRiskyApprovals,
+		}, nil
+	}
+	return CheckResult{
+		Name:       "Active Approvals",
+		Status:     "warning",
+		Score:      40,
+		Details:    details,
+		ReasonCode: ReasonRiskyApprovals,
+	}, nil
+}
@@ -0,0 +1,291 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// rpcCall performs a JSON-RPC request and returns the raw result. It tries
+// the network's endpoints in order of health and fails over to the next
+// one when an endpoint is unreachable, rate limited or rejects the
+// credentials; JSON-RPC errors such as reverts are answers and end the
+// call.
+func (s *Scanner) rpcCall(ctx context.Context, network, method string, params []interface{}) (_ json.RawMessage, err error) {
+	began := time.Now()
+	defer func() {
+		// Reverts are call outcomes, not endpoint failures
+		if isRevert(err) {
+			s.metrics.recordRequest("rpc", time.Since(began), nil)
+			return
+		}
+		s.metrics.recordRequest("rpc", time.Since(began), err)
+	}()
+
+	endpoints := s.rpcEndpoints(network)
+	if len(endpoints) == 0 {
+		return nil, classify(ErrNotConfigured, fmt.Errorf("no RPC endpoint configured for %s (set %s_RPC_URL)", network, strings.ToUpper(network)))
+	}
+	if err := s.authError(network, "rpc"); err != nil {
+		return nil, err
+	}
+
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; ; i++ {
+		url := endpoints[i]
+		result, status, err := s.rpcAttempt(ctx, url, method, body)
+		if err == nil || !failsOver(status, err) {
+			s.recordRPCHealth(url, true)
+			return result, err
+		}
+		s.recordRPCHealth(url, false)
+		if i == len(endpoints)-1 || ctx.Err() != nil {
+			if isAuthStatus(status) {
+				return nil, s.recordAuthError(network, "rpc", fmt.Sprintf("HTTP %d", status))
+			}
+			return nil, err
+		}
+		s.logger.Debug("rpc failover", "network", network, "from", redactURL(url), "to", redactURL(endpoints[i+1]), "err", err)
+	}
+}
+
+// failsOver reports whether a failed attempt should be retried on the next
+// endpoint: transport errors, timeouts, HTTP errors and rejected
+// credentials, but not JSON-RPC errors
+func failsOver(status int, err error) bool {
+	var rpcErr *rpcError
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	return isAuthStatus(status) || errors.Is(err, ErrRPCUnavailable) || errors.Is(err, ErrRateLimited)
+}
+
+// rpcAttempt sends a JSON-RPC request body to one endpoint. status is the
+// HTTP status, or 0 if there was no response.
+func (s *Scanner) rpcAttempt(ctx context.Context, url, method string, body []byte) (json.RawMessage, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, redactError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	s.logger.Debug("http request", "method", http.MethodPost, "url", redactURL(url), "rpc", method)
+	s.logger.Log(ctx, LevelTrace, "rpc payload", "body", string(body))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		err = redactError(err)
+		s.logger.Debug("http error", "url", redactURL(url), "err", err)
+		return nil, 0, classify(ErrRPCUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, classify(ErrRPCUnavailable, err)
+	}
+	s.logger.Debug("http response", "status", resp.StatusCode, "bytes", len(data), "elapsed", time.Since(start).Round(time.Millisecond))
+	if isAuthStatus(resp.StatusCode) {
+		return nil, resp.StatusCode, fmt.Errorf("rpc returned HTTP %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, resp.StatusCode, classify(ErrRateLimited, fmt.Errorf("rpc returned HTTP %d", resp.StatusCode))
+		}
+		return nil, resp.StatusCode, classify(ErrRPCUnavailable, fmt.Errorf("rpc returned HTTP %d", resp.StatusCode))
+	}
+
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(data, &rpcResp); err != nil {
+		return nil, resp.StatusCode, classify(ErrRPCUnavailable, fmt.Errorf("invalid rpc response: %w", err))
+	}
+	if rpcResp.Error != nil {
+		return nil, resp.StatusCode, rpcResp.Error
+	}
+	return rpcResp.Result, resp.StatusCode, nil
+}
+
+func chainCacheKey(address, network string) string {
+	return network + ":" + strings.ToLower(address)
+}
+
+// getCode returns the deployed bytecode at address (empty for EOAs)
+func (s *Scanner) getCode(ctx context.Context, address, network string) ([]byte, error) {
+	key := chainCacheKey(address, network)
+	s.cacheMu.Lock()
+	code, ok := s.codeCache[key]
+	s.cacheMu.Unlock()
+	if ok {
+		return code, nil
+	}
+
+	result, err := s.rpcCall(ctx, network, "eth_getCode", []interface{}{address, "latest"})
+	if err != nil {
+		return nil, err
+	}
+	code, err = decodeHexResult(result)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMu.Lock()
+	s.codeCache[key] = code
+	s.cacheMu.Unlock()
+	return code, nil
+}
+
+// getNonce returns the number of transactions sent from address
+func (s *Scanner) getNonce(ctx context.Context, address, network string) (uint64, error) {
+	key := chainCacheKey(address, network)
+	s.cacheMu.Lock()
+	nonce, ok := s.nonceCache[key]
+	s.cacheMu.Unlock()
+	if ok {
+		return nonce, nil
+	}
+
+	result, err := s.rpcCall(ctx, network, "eth_getTransactionCount", []interface{}{address, "latest"})
+	if err != nil {
+		return 0, err
+	}
+	var hexNonce string
+	if err := json.Unmarshal(result, &hexNonce); err != nil {
+		return 0, fmt.Errorf("unexpected rpc result: %s", result)
+	}
+	nonce, err = strconv.ParseUint(strings.TrimPrefix(hexNonce, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid nonce %q", hexNonce)
+	}
+
+	s.cacheMu.Lock()
+	s.nonceCache[key] = nonce
+	s.cacheMu.Unlock()
+	return nonce, nil
+}
+
+// RPCURL returns the JSON-RPC endpoint used for network
+func (s *Scanner) RPCURL(network string) string {
+	return s.getRPCURL(strings.ToLower(network))
+}
+
+// RPCChainID asks the network's RPC endpoint for its chain ID, to catch an
+// endpoint configured for the wrong network
+func (s *Scanner) RPCChainID(ctx context.Context, network string) (int64, error) {
+	result, err := s.rpcCall(ctx, network, "eth_chainId", nil)
+	if err != nil {
+		return 0, err
+	}
+	var hexID string
+	if err := json.Unmarshal(result, &hexID); err != nil {
+		return 0, fmt.Errorf("unexpected rpc result: %s", result)
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(hexID, "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid chain ID %q", hexID)
+	}
+	return id, nil
+}
+
+// decodeHexResult decodes a JSON "0x..." string into bytes
+func decodeHexResult(result json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(result, &s); err != nil {
+		return nil, fmt.Errorf("unexpected rpc result: %s", result)
+	}
+	s = strings.TrimPrefix(s, "0x")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	return hex.DecodeString(s)
+}
+
+// getStorageAt reads a 32-byte storage slot of a contract
+func (s *Scanner) getStorageAt(ctx context.Context, address, slot, network string) ([]byte, error) {
+	result, err := s.rpcCall(ctx, network, "eth_getStorageAt", []interface{}{address, slot, "latest"})
+	if err != nil {
+		return nil, err
+	}
+	return decodeHexResult(result)
+}
+
+// ethCall executes a read-only contract call against the latest block
+func (s *Scanner) ethCall(ctx context.Context, network, to, data string) ([]byte, error) {
+	call := map[string]string{"to": to, "data": data}
+	result, err := s.rpcCall(ctx, network, "eth_call", []interface{}{call, "latest"})
+	if err != nil {
+		return nil, err
+	}
+	return decodeHexResult(result)
+}
+
+// callMsg is an eth_call transaction object
+type callMsg struct {
+	From  string `json:"from,omitempty"`
+	To    string `json:"to"`
+	Value string `json:"value,omitempty"`
+	Data  string `json:"data"`
+}
+
+// accountOverride replaces account state for a single eth_call
+type accountOverride struct {
+	Balance   string            `json:"balance,omitempty"`
+	StateDiff map[string]string `json:"stateDiff,omitempty"`
+}
+
+// simulateCall executes eth_call with state overrides against the latest block
+func (s *Scanner) simulateCall(ctx context.Context, network string, call callMsg, overrides map[string]accountOverride) ([]byte, error) {
+	params := []interface{}{call, "latest"}
+	if len(overrides) > 0 {
+		params = append(params, overrides)
+	}
+	result, err := s.rpcCall(ctx, network, "eth_call", params)
+	if err != nil {
+		return nil, err
+	}
+	return decodeHexResult(result)
+}
+
+// isRevert reports whether err is an execution revert rather than a
+// transport or unsupported-method failure
+func isRevert(err error) bool {
+	var rpcErr *rpcError
+	return errors.As(err, &rpcErr) && strings.Contains(strings.ToLower(rpcErr.Message), "revert")
+}
@@ -0,0 +1,127 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"agent-reputation-scanner/internal/atomicfile"
+)
+
+// DefaultSanctionsURL serves the Ethereum addresses of the OFAC SDN list,
+// one per line, extracted from the official SDN data
+const DefaultSanctionsURL = "https://raw.githubusercontent.com/0xB10C/ofac-sanctioned-digital-currency-addresses/lists/sanctioned_addresses_ETH.txt"
+
+// DefaultSanctionsPath is where `scanner update-lists` stores the sanctions
+// list; the CLI loads it automatically when it exists
+func DefaultSanctionsPath() string {
+	return filepath.Join(DefaultConfigDir(), "sanctions.txt")
+}
+
+// LoadSanctions merges a sanctions list file into the scanner and returns
+// the number of addresses loaded. The format is the denylist format; only
+// the addresses are used.
+func (s *Scanner) LoadSanctions(path string) (int, error) {
+	lines, err := readListFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	for _, line := range lines {
+		s.sanctions[line.address] = filepath.Base(path)
+	}
+	return len(lines), nil
+}
+
+// UpdateSanctions downloads the sanctions list from url (Config.SanctionsURL
+// or DefaultSanctionsURL when empty) and stores it at path. The list is
+// validated first so a bad download never replaces a good list.
+func (s *Scanner) UpdateSanctions(ctx context.Context, url, path string) (int, error) {
+	if url == "" {
+		url = s.cfg.SanctionsURL
+	}
+	if url == "" {
+		url = DefaultSanctionsURL
+	}
+
+	var data []byte
+	var status int
+	var err error
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		data, status, err = s.explorer.get(ctx, url)
+		if err == nil && status != http.StatusOK {
+			err = fmt.Errorf("HTTP %d", status)
+		}
+	} else {
+		data, err = os.ReadFile(url)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot fetch sanctions list from %s: %w", redactURL(url), err)
+	}
+
+	lines, err := parseList(bytes.NewReader(data), "sanctions list")
+	if err != nil {
+		return 0, fmt.Errorf("invalid sanctions list from %s: %w", redactURL(url), err)
+	}
+	if len(lines) == 0 {
+		return 0, fmt.Errorf("sanctions list from %s is empty", redactURL(url))
+	}
+	if err := atomicfile.Write(path, data); err != nil {
+		return 0, err
+	}
+	return len(lines), nil
+}
+
+// sanctionSource returns the list an address is sanctioned by, if any
+func (s *Scanner) sanctionSource(address string) (string, bool) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	source, ok := s.sanctions[strings.ToLower(address)]
+	return source, ok
+}
+
+func (s *Scanner) isSanctioned(address string) bool {
+	_, ok := s.sanctionSource(address)
+	return ok
+}
+
+func (s *Scanner) checkSanctions(address string) CheckResult {
+	if source, ok := s.sanctionSource(address); ok {
+		return CheckResult{
+			Name:       "Sanctions",
+			Status:     "fail",
+			Score:      0,
+			Severity:   "critical",
+			Details:    "OFAC sanctioned address (list: " + source + ")",
+			ReasonCode: ReasonSanctioned,
+		}
+	}
+
+	s.cacheMu.Lock()
+	count := len(s.sanctions)
+	s.cacheMu.Unlock()
+	if count == 0 {
+		// Without a list the address is unscreened, not cleared
+		return CheckResult{
+			Name:       "Sanctions",
+			Status:     "warning",
+			Score:      50,
+			Details:    "Unknown: no sanctions list loaded (run scanner update-lists)",
+			Confidence: fallbackConfidence,
+			DataSource: DataFallback,
+			ReasonCode: ReasonDataUnavailable,
+		}
+	}
+	return CheckResult{
+		Name:    "Sanctions",
+		Status:  "pass",
+		Score:   100,
+		Details: fmt.Sprintf("Not on the sanctions list (%d addresses)", count),
+	}
+}
@@ -0,0 +1,966 @@
+// Package scanner assesses the on-chain reputation of EVM addresses.
+//
+// A Scanner runs a set of checks against RPC and block explorer data and
+// combines them into a ReputationReport. Most checks always run, from
+// address format, contract verification and age to sanctions, smart
+// wallet detection, token impersonation and recent proxy upgrades. Others
+// are opt-in:
+//
+//   - Config.Deep adds honeypot simulation, NFT metadata, active
+//     approvals, counterparty reputation and MEV activity
+//   - Config.Revocations adds active approvals alone
+//   - Config.AbuseReportsURL adds community abuse reports
+//   - Config.TraceDepth adds fund tracing
+//
+// Config.Checks selects checks by ID or name, opt-in ones included, and
+// CheckIDs lists the IDs. Custom checks can be added with RegisterCheck.
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Risk levels from least to most severe
+var RiskLevels = []string{"low", "medium", "high", "critical"}
+
+// DefaultCheckWeights sets how much each check contributes to the overall
+// score. Checks missing from the map get a weight of 1.
+var DefaultCheckWeights = map[string]float64{
+	"Address Format":        0.5,
+	"Contract Check":        0.5,
+	"Contract Verification": 2,
+	"Account Age":           1,
+	"Contract Age":          1,
+	"Transaction Volume":    1,
+	"Known Patterns":        3,
+	"Proxy Check":           1,
+	"Approval Risk":         1,
+	"Deployer Reputation":   1,
+	"Honeypot Simulation":   2,
+	"Dangerous Opcodes":     1,
+	"Mixer Exposure":        2,
+	"Sanctions":             3,
+	"Address Poisoning":     1,
+	"Source Heuristics":     1,
+	"Token Metadata":        1,
+	"Bytecode Match":        3,
+	"Method Profile":        1,
+	"NFT Metadata":          1,
+	"Active Approvals":      1,
+	"Abuse Reports":         2,
+	"Fund Tracing":          2,
+	"Smart Wallet":          0.5,
+	"Token Impersonation":   2,
+	"Proxy Upgrades":        2,
+	// Counterparty Reputation runs with Config.Deep
+	"Counterparty Reputation": 2,
+	// MEV Activity runs with Config.Deep; MEV is context, not a scam sign
+	"MEV Activity": 0.5,
+}
+
+// Defaults applied by NewScanner for zero Config fields
+const (
+	DefaultConcurrency       = 4
+	DefaultMaxRetries        = 3
+	DefaultRetryDelay        = 500 * time.Millisecond
+	DefaultRequestsPerSecond = 5 // explorer free tiers allow roughly 5 req/s
+	DefaultRequestTimeout    = 15 * time.Second
+	DefaultMinDataCoverage   = 0.6 // share of checks needing data for a low risk level
+	DefaultRescanConfidence  = 80  // Config.RescanLowConfidence rescans reports below it
+	DefaultRescanDelay       = 5 * time.Second
+
+	// DefaultLocalRPC is the JSON-RPC port of anvil and hardhat node
+	DefaultLocalRPC = "http://127.0.0.1:8545"
+)
+
+// timedOutDetails marks checks that did not finish before the scan deadline
+const timedOutDetails = "timed out"
+
+type ReputationReport struct {
+	// ReportID identifies the findings of a scan and ContentHash the
+	// report as written; see Seal
+	ReportID        string        `json:"report_id,omitempty"`
+	Address         string        `json:"address"`
+	Network         string        `json:"network"`
+	ENSName         string        `json:"ens_name,omitempty"`
+	AllowlistLabel  string        `json:"allowlist_label,omitempty"`
+	Token           *TokenInfo    `json:"token,omitempty"` // set for ERC-20 contracts
+	Timestamp       time.Time     `json:"timestamp"`
+	OverallScore    int           `json:"overall_score"` // 0-100, higher = more trustworthy
+	RiskLevel       string        `json:"risk_level"`    // low, medium, high, critical
+	Confidence      int           `json:"confidence"`    // 0-100, weighted like the score
+	Checks          []CheckResult `json:"checks"`
+	Recommendations []string      `json:"recommendations"`
+	Incomplete      bool          `json:"incomplete,omitempty"` // some checks timed out
+	// InsufficientData marks a low risk level capped at medium because too
+	// few checks had data (see Config.MinDataCoverage)
+	InsufficientData bool `json:"insufficient_data,omitempty"`
+	// Strict marks a scan whose warnings were scored as failures (see
+	// Config.Strict)
+	Strict bool `json:"strict,omitempty"`
+	// Baseline counts the findings that are new and those accepted by
+	// Config.Baseline; nil without one
+	Baseline *BaselineSummary `json:"baseline,omitempty"`
+	// Rescan reports the automatic rescans of a low confidence scan (see
+	// Config.RescanLowConfidence); nil if none ran
+	Rescan *RescanSummary `json:"rescan,omitempty"`
+	// Label and ExpectedRisk are the caller's annotations of the address,
+	// e.g. from a CSV batch file; RiskMismatch is set when RiskLevel
+	// differs from ExpectedRisk
+	Label        string `json:"label,omitempty"`
+	ExpectedRisk string `json:"expected_risk,omitempty"`
+	RiskMismatch bool   `json:"risk_mismatch,omitempty"`
+	// SmartWallet is the kind of a recognized smart contract wallet, e.g.
+	// "Safe v1.3.0"
+	SmartWallet string `json:"smart_wallet,omitempty"`
+	// Entity is the well-known exchange, bridge or protocol at the address,
+	// from the label dataset
+	Entity *EntityLabel `json:"entity,omitempty"`
+	// Revocations are the unlimited token approvals the Active Approvals
+	// check recommends revoking
+	Revocations []Revocation `json:"revocations,omitempty"`
+	// TaintPath is the shortest path of transfers the Fund Tracing check
+	// found from a denylisted or sanctioned source to the address
+	TaintPath []string `json:"taint_path,omitempty"`
+	// SetupErrors report credentials the RPC endpoint or explorer rejected;
+	// the checks that needed them fell back to placeholder results
+	SetupErrors []string `json:"setup_errors,omitempty"`
+	// Errors are the failures behind fallback check results, so a result
+	// that could not be checked is not mistaken for a clean one
+	Errors      []CheckError `json:"errors,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	ContentHash string       `json:"content_hash,omitempty"`
+}
+
+type CheckResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"` // pass, warning, fail
+	Score   int    `json:"score"`  // 0-100
+	Details string `json:"details"`
+	// Severity, if set, is the minimum risk level of the report, e.g.
+	// "critical" for a detected honeypot regardless of the overall score
+	Severity string `json:"severity,omitempty"`
+	// ReasonCode classifies a warning or failure for automation, one of
+	// ReasonCodes for the built-in checks; Details explains it to humans.
+	// Passing results leave it empty.
+	ReasonCode string `json:"reason_code,omitempty"`
+	// Confidence (0-100) reflects how complete and fresh the data behind
+	// the result was; DataSource is DataLive, DataCache or DataFallback
+	Confidence int    `json:"confidence"`
+	DataSource string `json:"data_source"`
+	// Baselined marks a finding that Config.Baseline accepts
+	Baselined bool `json:"baselined,omitempty"`
+	// NotApplicable marks a pass for a check that does not apply to the
+	// address, e.g. the Honeypot Simulation of an EOA. It carries no weight
+	// in the overall score.
+	NotApplicable bool `json:"not_applicable,omitempty"`
+
+	err error // why the result is a fallback, for ReputationReport.Errors
+}
+
+// Config controls how a Scanner reaches the network. Zero values fall back
+// to the package defaults.
+type Config struct {
+	// APIKeys maps network name to explorer API key. Networks without an
+	// entry fall back to the <NETWORK>_API_KEY environment variable (a
+	// comma-separated list).
+	APIKeys map[string]string
+	// ExtraAPIKeys are more explorer API keys of a network with an APIKeys
+	// entry. Requests take the keys in turn, skipping those rate limited.
+	ExtraAPIKeys map[string][]string
+	// RPCURLs maps network name to JSON-RPC endpoint. Networks without an
+	// entry fall back to <NETWORK>_RPC_URL (a comma-separated list), then
+	// the network's DefaultRPC and FallbackRPCs.
+	RPCURLs map[string]string
+	// FallbackRPCURLs are more endpoints of a network with an RPCURLs
+	// entry, tried in order when the ones before fail
+	FallbackRPCURLs map[string][]string
+	// WSURLs maps network name to the WebSocket JSON-RPC endpoint used by
+	// Watch. Networks without an entry fall back to <NETWORK>_WS_URL, then
+	// the RPC endpoint with a ws:// or wss:// scheme.
+	WSURLs map[string]string
+
+	Networks   map[string]NetworkConfig // defaults to DefaultNetworks
+	Weights    map[string]float64       // defaults to DefaultCheckWeights
+	Thresholds RiskThresholds           // defaults to DefaultRiskThresholds
+
+	// Profiles are named presets of checks and weights, from the config
+	// file, that add to or replace DefaultProfiles; see ApplyProfile
+	Profiles map[string]Profile
+	// Redaction lists the report fields that --redact leaves empty; see
+	// RedactionPolicy
+	Redaction RedactionPolicy
+
+	// MinDataCoverage is the fraction of checks that must complete with
+	// live or cached data for a "low" risk level; below it the level is
+	// capped at "medium". Defaults to DefaultMinDataCoverage; negative
+	// disables the gate.
+	MinDataCoverage float64
+
+	// Recommendations add to or replace DefaultRecommendationTemplates
+	Recommendations RecommendationTemplates
+
+	// HTTPClient sends every HTTP request; nil builds one with
+	// NewHTTPClient from ProxyURL and CABundle
+	HTTPClient *http.Client
+	// ProxyURL is the proxy for all requests, overriding HTTPS_PROXY and
+	// HTTP_PROXY; CABundle is a PEM file of extra root certificates
+	ProxyURL string
+	CABundle string
+
+	RequestTimeout    time.Duration // per HTTP request, defaults to DefaultRequestTimeout
+	Timeout           time.Duration // overall deadline for each Scan, and each address of a batch; 0 means none
+	MaxRetries        int           // retries for transient explorer failures; 0 takes the default, negative means none
+	RetryDelay        time.Duration // base delay for exponential backoff; 0 takes the default, negative retries at once
+	RequestsPerSecond float64       // base explorer rate limit per API key, shared by all scans; depends on the key tier
+	Concurrency       int           // workers used by ScanBatch
+
+	// Checks, when set, limits scans to the named checks; SkipChecks
+	// leaves checks out. Both take check IDs such as "age" (see CheckIDs)
+	// or full names. The overall score is weighted over the checks run.
+	Checks     []string
+	SkipChecks []string
+
+	// StrictAuth makes scans fail with an error wrapping ErrAuth when the
+	// RPC endpoint or explorer rejects the credentials, instead of
+	// returning a report with SetupErrors
+	StrictAuth bool
+
+	// Strict scores every warning as a failure with at least a high risk
+	// level, so a check that could not rule out a risk counts against the
+	// address
+	Strict bool
+
+	// Baseline, when set, marks the findings it accepts as Baselined and
+	// counts the others as new in ReputationReport.Baseline. Scores and
+	// risk levels are not affected.
+	Baseline *Baseline
+
+	// RescanLowConfidence, when above zero, is how many times a scan whose
+	// confidence is below RescanConfidence because checks fell back on
+	// retryable failures (rate limits, outages, timeouts) runs those checks
+	// again, RescanDelay apart. Better results replace the fallbacks and
+	// ReputationReport.Rescan says which checks improved. Rescans count
+	// against Timeout.
+	RescanLowConfidence int
+	RescanConfidence    int
+	RescanDelay         time.Duration
+
+	// Deep enables expensive checks (honeypot swap simulation, NFT
+	// metadata, active approvals, counterparty reputation, MEV activity)
+	Deep bool
+	// Revocations enables the Active Approvals check alone: it reads the
+	// token approval events of accounts, an extra explorer query per
+	// scan, to recommend revoking unlimited approvals to risky spenders
+	Revocations bool
+
+	// TraceDepth enables the Fund Tracing check: it follows incoming
+	// transfers up to this many hops back looking for denylisted or
+	// sanctioned sources. Each trace stops after TraceMaxNodes addresses
+	// or TraceTimeout, defaulting to DefaultTraceMaxNodes and
+	// DefaultTraceTimeout.
+	TraceDepth    int
+	TraceMaxNodes int
+	TraceTimeout  time.Duration
+
+	// UpgradeWindow is how recent a proxy implementation change has to be
+	// for the Proxy Upgrades check to flag it; defaults to
+	// DefaultUpgradeWindow
+	UpgradeWindow time.Duration
+
+	// AbuseReportsURL enables the Abuse Reports check against a community
+	// abuse report API. "{address}" in the URL is replaced with the
+	// lowercase address, else it is sent as the address query parameter.
+	// AbuseReportsKey, if set, is sent as a bearer token.
+	AbuseReportsURL string
+	AbuseReportsKey string
+
+	// IPFSGateway and ArweaveGateway resolve ipfs:// and ar:// token URIs
+	// for the NFT Metadata check; default to DefaultIPFSGateway and
+	// DefaultArweaveGateway
+	IPFSGateway    string
+	ArweaveGateway string
+
+	// Local scans against a development node such as an anvil or hardhat
+	// fork. Networks without an RPCURLs entry use LOCAL_RPC_URL, else
+	// DefaultLocalRPC, and checks that need a block explorer are skipped.
+	Local bool
+
+	// Allowlist maps trusted addresses to an optional label. Allowlisted
+	// addresses skip all checks and are reported as low risk.
+	Allowlist map[string]string
+
+	// Source selects the data source for account history: SourceExplorer
+	// (default) or SourceGraph. DataSource, when set, replaces both.
+	Source     string
+	DataSource DataSource
+
+	// LookalikeChars is how many leading and trailing hex characters the
+	// Address Poisoning check compares; defaults to DefaultLookalikeChars
+	LookalikeChars int
+
+	// Webhook receives the reports of scans at or above its risk threshold
+	Webhook WebhookConfig
+
+	// SanctionsURL is where UpdateSanctions downloads the sanctions list
+	// from; defaults to DefaultSanctionsURL. May also be a local file.
+	SanctionsURL string
+
+	// LabelsURL is where UpdateLabels downloads the address label dataset
+	// from. May also be a local file; there is no default.
+	LabelsURL string
+
+	// Fixtures, when set, answer every RPC and explorer request and serve
+	// as the DataSource, so scans are offline and reproducible. The disk
+	// cache is not used.
+	Fixtures *Fixtures
+
+	// Clock returns the current time; defaults to time.Now, or the
+	// fixture time with Fixtures
+	Clock func() time.Time
+
+	// CacheDir enables the on-disk check result cache when non-empty
+	CacheDir string
+	CacheTTL time.Duration // defaults to DefaultCacheTTL
+	// CacheTTLs override the TTL per check, keyed by check ID or name;
+	// they add to or replace DefaultCacheTTLs, and 0 disables the cache
+	// for that check
+	CacheTTLs map[string]time.Duration
+
+	// Logger receives request and retry logs; nil discards them. API keys
+	// are redacted from logged URLs.
+	Logger *slog.Logger
+}
+
+// Scanner runs reputation checks. It is safe for concurrent use.
+type Scanner struct {
+	cfg        Config
+	httpClient *http.Client
+	explorer   *retryClient
+	logger     *slog.Logger
+	networks   map[string]NetworkConfig
+	weights    map[string]float64
+
+	metrics *metrics
+	source  DataSource
+	checks  []Check // built-in checks, then registered ones
+
+	denylist       map[string]DenylistEntry
+	sanctions      map[string]string // address -> list file name
+	bytecodeHashes map[string]BytecodeEntry
+	signatures     map[string]string // selector -> function signature
+	labels         map[string]labelEntry
+	allowlist      map[string]AllowlistEntry
+
+	// Per-scanner caches so several checks can share chain lookups
+	cacheMu       sync.Mutex
+	codeCache     map[string][]byte
+	nonceCache    map[string]uint64
+	sourceCache   map[string]*sourceCodeResult
+	abiCache      map[string]*contractABI      // nil for unverified contracts
+	creationCache map[string]*contractCreation // nil for never-deployed addresses
+	deployerCache map[string]deployerInfo
+	tokenCache    map[string]*TokenInfo // nil for non-tokens
+	// abuseReports queries the abuse report API, which has its own rate
+	// limits and credentials
+	abuseReports *retryClient
+
+	// Approvals to revoke found by the Active Approvals check
+	revocationCache map[string][]Revocation
+	taintCache      map[string][]string      // paths to tainted sources found by the Fund Tracing check
+	walletCache     map[string]walletInfo    // smart wallets found by the Smart Wallet check
+	rpcHealth       map[string]*rpcHealth    // by endpoint URL
+	apiKeyLimits    map[string]time.Time     // by API key, rate limited until
+	apiKeyTurn      map[string]int           // by network, index of the next key
+	cacheTTLs       map[string]time.Duration // check name -> TTL
+	authErrors      map[string]*authError    // network:service -> rejected credentials
+}
+
+// NewScanner returns a Scanner for cfg
+func NewScanner(cfg Config) *Scanner {
+	if cfg.Networks == nil {
+		cfg.Networks = DefaultNetworks
+	}
+	if cfg.Weights == nil {
+		cfg.Weights = DefaultCheckWeights
+	}
+	if cfg.Thresholds == (RiskThresholds{}) {
+		cfg.Thresholds = DefaultRiskThresholds
+	}
+	cfg.Recommendations = DefaultRecommendationTemplates.with(cfg.Recommendations)
+	if cfg.Fixtures != nil {
+		cfg = withFixtures(cfg)
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = discardLogger
+	}
+	if cfg.HTTPClient == nil {
+		client, err := NewHTTPClient(cfg.ProxyURL, cfg.CABundle)
+		if err != nil {
+			// LoadConfig rejects such settings; library callers get a warning
+			cfg.Logger.Warn("ignoring proxy settings", "err", err)
+			client = &http.Client{}
+		}
+		logProxy(cfg.Logger, cfg.ProxyURL, cfg.CABundle)
+		cfg.HTTPClient = client
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+	if cfg.RequestTimeout == 0 {
+		cfg.RequestTimeout = DefaultRequestTimeout
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.RetryDelay == 0 {
+		cfg.RetryDelay = DefaultRetryDelay
+	}
+	maxRetries, retryDelay := max(cfg.MaxRetries, 0), max(cfg.RetryDelay, 0)
+	if cfg.RequestsPerSecond == 0 {
+		cfg.RequestsPerSecond = DefaultRequestsPerSecond
+	}
+	if cfg.Concurrency < 0 {
+		// Without a worker a batch would never finish; the CLI rejects this
+		cfg.Logger.Warn("ignoring negative concurrency", "concurrency", cfg.Concurrency)
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = DefaultConcurrency
+	}
+	if cfg.CacheTTL == 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+	if cfg.MinDataCoverage == 0 {
+		cfg.MinDataCoverage = DefaultMinDataCoverage
+	}
+	if cfg.RescanConfidence == 0 {
+		cfg.RescanConfidence = DefaultRescanConfidence
+	}
+	if cfg.RescanDelay == 0 {
+		cfg.RescanDelay = DefaultRescanDelay
+	}
+	if cfg.LookalikeChars <= 0 {
+		cfg.LookalikeChars = DefaultLookalikeChars
+	}
+	if cfg.LookalikeChars > MaxLookalikeChars {
+		cfg.LookalikeChars = MaxLookalikeChars
+	}
+	if cfg.Webhook.Threshold == "" {
+		cfg.Webhook.Threshold = DefaultWebhookThreshold
+	}
+
+	allowlist := map[string]AllowlistEntry{}
+	for address, label := range cfg.Allowlist {
+		allowlist[strings.ToLower(address)] = AllowlistEntry{Label: label, Source: "config"}
+	}
+
+	denylist := map[string]DenylistEntry{}
+	for address, name := range knownMixers {
+		denylist[address] = DenylistEntry{Source: MixerSource, Comment: name}
+	}
+
+	signatures := make(map[string]string, len(builtinSignatures))
+	for sel, signature := range builtinSignatures {
+		signatures[sel] = signature
+	}
+
+	labels := make(map[string]labelEntry, len(builtinLabels))
+	for address, entry := range builtinLabels {
+		labels[address] = entry
+	}
+
+	s := &Scanner{
+		cfg:        cfg,
+		httpClient: cfg.HTTPClient,
+		explorer: &retryClient{
+			client:     cfg.HTTPClient,
+			maxRetries: maxRetries,
+			baseDelay:  retryDelay,
+			maxElapsed: maxRetryElapsed,
+			timeout:    cfg.RequestTimeout,
+			logger:     cfg.Logger,
+		},
+		abuseReports: &retryClient{
+			client:     cfg.HTTPClient,
+			maxRetries: maxRetries,
+			baseDelay:  retryDelay,
+			maxElapsed: maxRetryElapsed,
+			timeout:    cfg.RequestTimeout,
+			header:     abuseReportsHeader(cfg.AbuseReportsKey),
+			logger:     cfg.Logger,
+		},
+		logger:          cfg.Logger,
+		metrics:         newMetrics(),
+		networks:        cfg.Networks,
+		weights:         cfg.Weights,
+		denylist:        denylist,
+		sanctions:       map[string]string{},
+		bytecodeHashes:  map[string]BytecodeEntry{},
+		signatures:      signatures,
+		labels:          labels,
+		allowlist:       allowlist,
+		codeCache:       map[string][]byte{},
+		nonceCache:      map[string]uint64{},
+		sourceCache:     map[string]*sourceCodeResult{},
+		abiCache:        map[string]*contractABI{},
+		creationCache:   map[string]*contractCreation{},
+		deployerCache:   map[string]deployerInfo{},
+		tokenCache:      map[string]*TokenInfo{},
+		cacheTTLs:       resolveCacheTTLs(cfg.CacheTTLs),
+		revocationCache: map[string][]Revocation{},
+		taintCache:      map[string][]string{},
+		walletCache:     map[string]walletInfo{},
+		rpcHealth:       map[string]*rpcHealth{},
+		apiKeyLimits:    map[string]time.Time{},
+		apiKeyTurn:      map[string]int{},
+		authErrors:      map[string]*authError{},
+	}
+	perSecond := s.explorerRequestsPerSecond()
+	s.explorer.limiter = newRateLimiter(perSecond, int(perSecond+0.5))
+	s.source = newDataSource(s, cfg)
+	s.checks = s.selectChecks(append(s.builtinChecks(), registeredChecks()...))
+	return s
+}
+
+// Scan runs all checks against address on network. address may also be an
+// ENS name ending in .eth, which is resolved on Ethereum mainnet first.
+func (s *Scanner) Scan(address, network string) (ReputationReport, error) {
+	return s.ScanContext(context.Background(), address, network)
+}
+
+// ScanContext is Scan with a context. Checks still running when ctx is done
+// (or Config.Timeout elapses) are reported as warnings with details
+// "timed out" instead of blocking. Reports at or above the webhook
+// threshold are posted to Config.Webhook before ScanContext returns.
+func (s *Scanner) ScanContext(ctx context.Context, address, network string) (ReputationReport, error) {
+	report, err := s.scan(ctx, address, network)
+	s.metrics.recordScan(report, err)
+	if err == nil {
+		s.notify(ctx, report)
+	}
+	return report, err
+}
+
+func (s *Scanner) scan(ctx context.Context, address, network string) (ReputationReport, error) {
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	network = strings.ToLower(network)
+	if _, err := s.Network(network); err != nil {
+		return ReputationReport{}, err
+	}
+
+	if IsTruncatedAddress(address) {
+		return ReputationReport{}, truncatedAddressError(address)
+	}
+	ensName := ""
+	if IsENSName(address) {
+		resolved, err := s.resolveENS(ctx, address)
+		if err != nil {
+			return ReputationReport{}, fmt.Errorf("cannot resolve ENS name %s: %w", address, err)
+		}
+		ensName, address = address, resolved
+	}
+
+	report := ReputationReport{
+		Address:   address,
+		Network:   network,
+		ENSName:   ensName,
+		Timestamp: s.cfg.Clock(),
+		Checks:    []CheckResult{},
+		Strict:    s.cfg.Strict,
+	}
+
+	// Trusted addresses short-circuit the scan, unless they are sanctioned
+	if ok, label := s.isAllowlisted(address); ok && !s.isSanctioned(address) {
+		return s.allowlistedReport(report, label), nil
+	}
+
+	// Primary ENS name for the header; lookup failures are not fatal
+	if ensName == "" && IsHexAddress(address) {
+		report.ENSName, _ = s.lookupENSName(ctx, address)
+	}
+
+	for _, check := range s.checks {
+		report.Checks = append(report.Checks, check.Run(ctx, address, network))
+	}
+	report.Rescan = s.rescanLowConfidence(ctx, address, network, report.Checks)
+	report.SmartWallet, report.Checks = s.adjustForWallet(report.Checks, address, network)
+	report.Entity = s.entityLabel(address, network)
+	if report.Strict {
+		report.Checks = strictResults(report.Checks)
+	}
+
+	// Token metadata, usually already fetched by the Token Metadata check
+	if IsHexAddress(address) {
+		report.Token, _ = s.tokenInfo(ctx, address, network)
+	}
+
+	for _, check := range report.Checks {
+		if check.Details == timedOutDetails {
+			report.Incomplete = true
+		}
+	}
+	report.SetupErrors = s.setupErrors(network)
+	report.Errors = checkErrors(report.Checks)
+	if err := s.authError(network, "rpc", "explorer"); err != nil && s.cfg.StrictAuth {
+		return ReputationReport{}, fmt.Errorf("%w (%s)", err, err.(*authError).guidance())
+	}
+
+	// Calculate overall score
+	report.OverallScore = s.calculateOverallScore(report.Checks)
+	if report.Entity != nil {
+		report.Entity.TrustBonus = entityBonus(report.OverallScore, report.Checks)
+		report.OverallScore += report.Entity.TrustBonus
+	}
+	report.Confidence = s.calculateConfidence(report.Checks)
+	report.RiskLevel = applySeverity(s.cfg.Thresholds.level(report.OverallScore), report.Checks)
+	coverage := ""
+	if report.RiskLevel == "low" {
+		if covered, total := dataCoverage(report.Checks); float64(covered) < s.cfg.MinDataCoverage*float64(total) {
+			report.RiskLevel = "medium"
+			report.InsufficientData = true
+			coverage = fmt.Sprintf("only %d of %d checks had live or cached data, %.0f%% required", covered, total, s.cfg.MinDataCoverage*100)
+		}
+	}
+	report.Revocations = s.revocations(address, network)
+	report.TaintPath = s.taintPath(address, network)
+	report.Recommendations = s.cfg.Recommendations.generate(report.Checks, report.Revocations, coverage)
+	if s.cfg.Baseline != nil {
+		s.cfg.Baseline.apply(&report)
+	}
+	report.Seal()
+
+	return report, nil
+}
+
+// checkFunc is the signature of the network-backed checks. A non-nil error
+// means the returned result is a degraded fallback.
+type checkFunc func(ctx context.Context, address, network string) (CheckResult, error)
+
+// runCheck runs a check without the disk cache
+func (s *Scanner) runCheck(ctx context.Context, name, address, network string, run checkFunc) CheckResult {
+	result, err := run(ctx, address, network)
+	return timedOut(ctx, name, withProvenance(result, err), err)
+}
+
+// timedOut replaces the fallback result of a check that failed because the
+// scan deadline passed or ctx was cancelled
+func timedOut(ctx context.Context, name string, result CheckResult, err error) CheckResult {
+	if err == nil || ctx.Err() == nil && !errors.Is(err, context.DeadlineExceeded) {
+		return result
+	}
+	return CheckResult{Name: name, Status: "warning", Score: 50, Details: timedOutDetails, Confidence: 0, DataSource: DataFallback, err: context.DeadlineExceeded, ReasonCode: ReasonDataUnavailable}
+}
+
+// allowlistedReport fills in a low risk report for a trusted address
+func (s *Scanner) allowlistedReport(report ReputationReport, label string) ReputationReport {
+	details := "Address on trusted allowlist"
+	if label != "" {
+		details += " (" + label + ")"
+	}
+	report.AllowlistLabel = label
+	report.Checks = append(report.Checks, CheckResult{
+		Name:       "Allowlist",
+		Status:     "pass",
+		Score:      100,
+		Details:    details,
+		Confidence: 100,
+		DataSource: DataLive,
+	})
+	report.OverallScore = 100
+	report.Confidence = 100
+	report.RiskLevel = "low"
+	report.Recommendations = []string{}
+	if tmpl := s.cfg.Recommendations.Allowlisted; tmpl != "" {
+		report.Recommendations = append(report.Recommendations, expandTemplate(tmpl, "Allowlist", details))
+	}
+	if s.cfg.Baseline != nil {
+		s.cfg.Baseline.apply(&report)
+	}
+	report.Seal()
+	return report
+}
+
+// ScanBatch scans addresses with a pool of Config.Concurrency workers.
+// Reports are returned in input order; an address whose scan fails gets a
+// report with Error set and its error is collected. Config.Timeout bounds
+// each address, so one that hangs does not hold up a worker. onResult, if non-nil,
+// is called (serially) as each scan completes.
+func (s *Scanner) ScanBatch(addresses []string, network string, onResult func(ReputationReport)) ([]ReputationReport, []error) {
+	return s.ScanBatchContext(context.Background(), addresses, network, onResult)
+}
+
+// ScanBatchContext is ScanBatch with a context shared by all scans
+func (s *Scanner) ScanBatchContext(ctx context.Context, addresses []string, network string, onResult func(ReputationReport)) ([]ReputationReport, []error) {
+	results := make([]ReputationReport, len(addresses))
+	var errs []error
+
+	jobs := make(chan string)
+	go func() {
+		for _, address := range addresses {
+			jobs <- address
+		}
+		close(jobs)
+	}()
+	s.ScanStreamFunc(ctx, jobs, network, func(i int, report ReputationReport, err error) {
+		results[i] = report
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", addresses[i], err))
+		}
+		if onResult != nil {
+			onResult(report)
+		}
+	})
+	return results, errs
+}
+
+// ScanStreamFunc scans addresses from jobs with a pool of
+// Config.Concurrency workers until jobs is closed, and calls onResult
+// (serially) as each scan completes. i is the address's position in jobs.
+// Reports are not kept, so memory does not grow with the number of
+// addresses.
+func (s *Scanner) ScanStreamFunc(ctx context.Context, jobs <-chan string, network string, onResult func(i int, report ReputationReport, err error)) {
+	type job struct {
+		i       int
+		address string
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	indexed := make(chan job)
+	for w := 0; w < s.cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range indexed {
+				report, err := s.boundedScan(ctx, j.address, network)
+				mu.Lock()
+				onResult(j.i, report, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	i := 0
+	for address := range jobs {
+		indexed <- job{i, address}
+		i++
+	}
+	close(indexed)
+	wg.Wait()
+}
+
+// scanAbandonGrace is how long past Config.Timeout a batch worker waits
+// for a scan whose checks ignore the deadline before moving on
+const scanAbandonGrace = 5 * time.Second
+
+// boundedScan is safeScan for batch workers: with Config.Timeout set, a
+// scan that overruns the deadline by scanAbandonGrace, because a check is
+// stuck in a call that does not honor ctx, is left to finish in the
+// background and reported as failed, so the worker goes on to the next
+// address
+func (s *Scanner) boundedScan(ctx context.Context, address, network string) (ReputationReport, error) {
+	if s.cfg.Timeout <= 0 {
+		return s.safeScan(ctx, address, network)
+	}
+	type result struct {
+		report ReputationReport
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		report, err := s.safeScan(ctx, address, network)
+		done <- result{report, err}
+	}()
+	timer := time.NewTimer(s.cfg.Timeout + scanAbandonGrace)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.report, r.err
+	case <-timer.C:
+	}
+	err := fmt.Errorf("scan did not finish within %s: %w", s.cfg.Timeout, context.DeadlineExceeded)
+	s.logger.Warn("abandoning stuck scan", "address", address, "network", network, "timeout", s.cfg.Timeout)
+	return ReputationReport{
+		Address:         address,
+		Network:         network,
+		Timestamp:       s.cfg.Clock(),
+		Checks:          []CheckResult{},
+		Recommendations: []string{},
+		Error:           err.Error(),
+		Incomplete:      true,
+	}, err
+}
+
+// safeScan keeps a single misbehaving address from aborting a batch
+func (s *Scanner) safeScan(ctx context.Context, address, network string) (report ReputationReport, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("scan panicked: %v", r)
+		}
+		if err != nil {
+			report = ReputationReport{
+				Address:         address,
+				Network:         network,
+				Timestamp:       s.cfg.Clock(),
+				Checks:          []CheckResult{},
+				Recommendations: []string{},
+				Error:           err.Error(),
+			}
+		}
+	}()
+	return s.ScanContext(ctx, address, network)
+}
+
+func (s *Scanner) calculateOverallScore(checks []CheckResult) int {
+	if len(checks) == 0 {
+		return 0
+	}
+
+	var total, totalWeight float64
+	applicable := false
+	for _, check := range checks {
+		weight := s.scoreWeight(check)
+		total += float64(check.Score) * weight
+		totalWeight += weight
+		applicable = applicable || !check.NotApplicable
+	}
+	if !applicable {
+		return 100 // nothing that ran applies, so nothing counts against the address
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+
+	score := int(total/totalWeight + 0.5)
+	switch {
+	case score < 0:
+		return 0
+	case score > 100:
+		return 100
+	}
+	return score
+}
+
+// calculateConfidence is the weighted mean of the checks' confidence
+func (s *Scanner) calculateConfidence(checks []CheckResult) int {
+	var total, totalWeight float64
+	for _, check := range checks {
+		weight := s.checkWeight(check.Name)
+		total += float64(check.Confidence) * weight
+		totalWeight += weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return int(total/totalWeight + 0.5)
+}
+
+// scoreWeight is the weight of a result in the overall score: that of its
+// check, or 0 when the check does not apply
+func (s *Scanner) scoreWeight(check CheckResult) float64 {
+	if check.NotApplicable {
+		return 0
+	}
+	return s.checkWeight(check.Name)
+}
+
+func (s *Scanner) checkWeight(name string) float64 {
+	if weight, ok := s.weights[name]; ok && weight >= 0 {
+		return weight
+	}
+	return 1
+}
+
+// applySeverity raises level to the highest Severity among checks
+func applySeverity(level string, checks []CheckResult) string {
+	for _, check := range checks {
+		if RiskRank(check.Severity) > RiskRank(level) {
+			level = check.Severity
+		}
+	}
+	return level
+}
+
+// strictResults turns warnings into failures scored 0 that raise the risk
+// level to at least high
+func strictResults(checks []CheckResult) []CheckResult {
+	for i, check := range checks {
+		if check.Status != "warning" {
+			continue
+		}
+		checks[i].Status = "fail"
+		checks[i].Score = 0
+		if RiskRank(check.Severity) < RiskRank("high") {
+			checks[i].Severity = "high"
+		}
+	}
+	return checks
+}
+
+// RiskRank returns the position of level in RiskLevels, or -1 if unknown
+func RiskRank(level string) int {
+	for i, l := range RiskLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// Now returns the current time of the scanner's clock (Config.Clock)
+func (s *Scanner) Now() time.Time {
+	return s.cfg.Clock()
+}
+
+// getAPIKey returns the first explorer API key of network; local nodes have
+// no explorer indexing their chain
+func (s *Scanner) getAPIKey(network string) string {
+	if keys := s.configuredAPIKeys(network); len(keys) > 0 {
+		return keys[0]
+	}
+	return ""
+}
+
+// hasExplorer reports whether explorer-backed checks can run on network:
+// Etherscan-style explorers need an API key, Blockscout works without one
+func (s *Scanner) hasExplorer(network string) bool {
+	if s.cfg.Local {
+		return false
+	}
+	if s.getAPIKey(network) != "" {
+		return true
+	}
+	netCfg, err := s.Network(network)
+	return err == nil && netCfg.ExplorerType == ExplorerBlockscout
+}
+
+// noExplorerDetails explains why a check that needs the explorer was skipped
+func (s *Scanner) noExplorerDetails() string {
+	if s.cfg.Local {
+		return "Explorer unavailable on local network"
+	}
+	return "No API key configured"
+}
+
+// getRPCURL returns the JSON-RPC endpoint rpcCall tries first for a
+// network; see configuredRPCURLs
+func (s *Scanner) getRPCURL(network string) string {
+	if urls := s.rpcEndpoints(network); len(urls) > 0 {
+		return urls[0]
+	}
+	return ""
+}
@@ -0,0 +1,180 @@
+package scanner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureScanner returns a scanner answering every request from fixtures
+func fixtureScanner(t *testing.T, cfg Config, accounts map[string]FixtureAccount) *Scanner {
+	t.Helper()
+	cfg.Fixtures = &Fixtures{
+		Time:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Accounts: accounts,
+	}
+	return NewScanner(cfg)
+}
+
+func TestScanBatchConcurrency(t *testing.T) {
+	addresses := []string{
+		"0x1111111111111111111111111111111111111111",
+		"0x2222222222222222222222222222222222222222",
+		"0x3333333333333333333333333333333333333333",
+	}
+	for _, concurrency := range []int{-1, 0, 1, 8} {
+		s := fixtureScanner(t, Config{Concurrency: concurrency, Checks: []string{"nonce"}}, nil)
+		if s.cfg.Concurrency < 1 {
+			t.Fatalf("Concurrency %d: NewScanner kept %d workers", concurrency, s.cfg.Concurrency)
+		}
+
+		done := make(chan struct{})
+		var reports []ReputationReport
+		var errs []error
+		go func() {
+			reports, errs = s.ScanBatch(addresses, "ethereum", nil)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Concurrency %d: batch did not finish", concurrency)
+		}
+		if len(errs) > 0 {
+			t.Fatalf("Concurrency %d: errors %v", concurrency, errs)
+		}
+		for i, report := range reports {
+			if report.Address != addresses[i] {
+				t.Errorf("Concurrency %d: report %d is for %s, want %s", concurrency, i, report.Address, addresses[i])
+			}
+		}
+	}
+}
+
+func TestScanInvalidAddressIsCritical(t *testing.T) {
+	s := fixtureScanner(t, Config{}, nil)
+	report, err := s.Scan("0x12345", "ethereum")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if report.RiskLevel != "critical" {
+		t.Errorf("RiskLevel = %q (score %d), want critical", report.RiskLevel, report.OverallScore)
+	}
+}
+
+func TestCalculateOverallScore(t *testing.T) {
+	s := NewScanner(Config{Weights: map[string]float64{"Sanctions": 3, "Ignored": 0, "Negative": -2}})
+	tests := []struct {
+		name   string
+		checks []CheckResult
+		want   int
+	}{
+		{"no checks", nil, 0},
+		{"flat", []CheckResult{{Name: "A", Score: 100}, {Name: "B", Score: 50}}, 75},
+		// Sanctions counts three times as much as A
+		{"weighted", []CheckResult{{Name: "Sanctions", Score: 0}, {Name: "A", Score: 100}}, 25},
+		{"zero weight", []CheckResult{{Name: "Ignored", Score: 0}, {Name: "A", Score: 80}}, 80},
+		// Negative weights fall back to 1
+		{"negative weight", []CheckResult{{Name: "Negative", Score: 0}, {Name: "A", Score: 100}}, 50},
+		{"all zero weight", []CheckResult{{Name: "Ignored", Score: 40}}, 0},
+		{"rounds", []CheckResult{{Name: "A", Score: 100}, {Name: "B", Score: 100}, {Name: "C", Score: 99}}, 100},
+		// Checks that do not apply carry no weight
+		{"not applicable", []CheckResult{{Name: "Sanctions", Score: 100, NotApplicable: true}, {Name: "A", Score: 20}}, 20},
+		{"nothing applies", []CheckResult{{Name: "A", Score: 100, NotApplicable: true}}, 100},
+	}
+	for _, tt := range tests {
+		if got := s.calculateOverallScore(tt.checks); got != tt.want {
+			t.Errorf("%s: calculateOverallScore = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplySeverity(t *testing.T) {
+	tests := []struct {
+		level  string
+		checks []CheckResult
+		want   string
+	}{
+		{"low", nil, "low"},
+		{"low", []CheckResult{{Severity: "high"}}, "high"},
+		{"low", []CheckResult{{Severity: "medium"}, {Severity: "critical"}, {}}, "critical"},
+		// Severity only raises the level
+		{"high", []CheckResult{{Severity: "medium"}}, "high"},
+	}
+	for _, tt := range tests {
+		if got := applySeverity(tt.level, tt.checks); got != tt.want {
+			t.Errorf("applySeverity(%s, %v) = %s, want %s", tt.level, tt.checks, got, tt.want)
+		}
+	}
+}
+
+func TestScanDataCoverage(t *testing.T) {
+	const address = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	// The Address Format check always has data; the Contract Check falls
+	// back when the source fails, leaving half the checks covered and an
+	// overall score of 75, which these thresholds call low risk
+	thresholds := RiskThresholds{Low: 70, Medium: 60, High: 40}
+	failing := failingSource{errors.New("connection refused")}
+	tests := []struct {
+		name         string
+		source       DataSource
+		coverage     float64
+		level        string
+		insufficient bool
+	}{
+		{"live data", nil, 0, "low", false},
+		{"default coverage", failing, 0, "medium", true},
+		{"lower coverage", failing, 0.5, "low", false},
+		{"no coverage needed", failing, -1, "low", false},
+	}
+	for _, tt := range tests {
+		s := fixtureScanner(t, Config{
+			Checks:          []string{"address", "contract"},
+			DataSource:      tt.source,
+			Thresholds:      thresholds,
+			MinDataCoverage: tt.coverage,
+		}, nil)
+		report, err := s.Scan(address, "ethereum")
+		if err != nil {
+			t.Fatalf("%s: Scan() error = %v", tt.name, err)
+		}
+		if report.RiskLevel != tt.level || report.InsufficientData != tt.insufficient {
+			t.Errorf("%s: risk %s (score %d), insufficient data %v, want %s, %v",
+				tt.name, report.RiskLevel, report.OverallScore, report.InsufficientData, tt.level, tt.insufficient)
+		}
+		if got := len(report.Recommendations) > 0 && strings.Contains(report.Recommendations[0], "Insufficient data"); got != tt.insufficient {
+			t.Errorf("%s: recommendations %q, want insufficient data first: %v", tt.name, report.Recommendations, tt.insufficient)
+		}
+	}
+}
+
+func TestScanNewSelfDestructingContract(t *testing.T) {
+	const (
+		contract = "0x4444444444444444444444444444444444444444"
+		deployer = "0x5555555555555555555555555555555555555555"
+	)
+	yesterday := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)
+	s := fixtureScanner(t, Config{}, map[string]FixtureAccount{
+		// Unverified, deployed yesterday, and destroyable: PUSH1 0, CALLER, SELFDESTRUCT
+		contract: {Code: "0x600033ff", Creator: deployer, CreationTx: "0xaa", CreationBlock: 100, CreationTime: yesterday},
+		deployer: {Nonce: 1, Transactions: []FixtureTx{{Hash: "0xaa", From: deployer, Time: yesterday}}},
+	})
+	report, err := s.Scan(contract, "ethereum")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if RiskRank(report.RiskLevel) < RiskRank("high") {
+		t.Errorf("RiskLevel = %s (score %d), want high or above", report.RiskLevel, report.OverallScore)
+	}
+	for _, note := range s.Explain(report).Notes {
+		if strings.Contains(note, "weights changed") {
+			t.Errorf("Explain() disagrees with the score: %s", note)
+		}
+	}
+	for _, check := range report.Checks {
+		if check.Name == "Token Metadata" && !check.NotApplicable {
+			t.Errorf("Token Metadata of a contract that is not a token applies: %s", check.Details)
+		}
+	}
+}
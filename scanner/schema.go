@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	_ "embed"
+	"strings"
+
+	"agent-reputation-scanner/internal/jsonschema"
+)
+
+// Version is the scanner release. The report schema is versioned with it.
+const Version = "0.1.0"
+
+//go:embed report.schema.json
+var reportSchema string
+
+// ReportSchema returns the JSON Schema describing ReputationReport and
+// CheckResult, plus the multi-address scan output under
+// $defs/ReputationReports, the multi-chain scan output under
+// $defs/MultiChainReport, the transaction scan output under
+// $defs/TransactionReport, the compare output under $defs/Comparison and
+// the batch output document under $defs/BatchOutput
+func ReportSchema() []byte {
+	return []byte(strings.ReplaceAll(reportSchema, "{{VERSION}}", Version))
+}
+
+// ValidateReportJSON checks a JSON encoded ReputationReport against the schema
+func ValidateReportJSON(data []byte) error {
+	return validateAgainst("#", data)
+}
+
+// ValidateReportsJSON checks a JSON array of ReputationReports, as written
+// by a scan of several addresses, against the schema
+func ValidateReportsJSON(data []byte) error {
+	return validateAgainst("#/$defs/ReputationReports", data)
+}
+
+// ValidateMultiChainJSON checks a JSON encoded MultiChainReport against the schema
+func ValidateMultiChainJSON(data []byte) error {
+	return validateAgainst("#/$defs/MultiChainReport", data)
+}
+
+// ValidateTransactionJSON checks a JSON encoded TransactionReport against the schema
+func ValidateTransactionJSON(data []byte) error {
+	return validateAgainst("#/$defs/TransactionReport", data)
+}
+
+// ValidateComparisonJSON checks a JSON encoded Comparison against the schema
+func ValidateComparisonJSON(data []byte) error {
+	return validateAgainst("#/$defs/Comparison", data)
+}
+
+// ValidateBatchJSON checks a JSON encoded batch output document against the schema
+func ValidateBatchJSON(data []byte) error {
+	return validateAgainst("#/$defs/BatchOutput", data)
+}
+
+func validateAgainst(ref string, data []byte) error {
+	schema, err := jsonschema.Parse(ReportSchema())
+	if err != nil {
+		return err
+	}
+	return schema.ValidateRef(ref, data)
+}
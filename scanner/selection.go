@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// builtinCheckIDs are the short names that select built-in checks in
+// Config.Checks and Config.SkipChecks
+var builtinCheckIDs = map[string]string{
+	"Address Format":        "address",
+	"Contract Check":        "contract",
+	"Contract Verification": "verification",
+	"Account Age":           "age",
+	"Contract Age":          "contract-age",
+	"Transaction Volume":    "volume",
+	"Known Patterns":        "patterns",
+	"Proxy Check":           "proxy",
+	"Approval Risk":         "approvals",
+	"Deployer Reputation":   "deployer",
+	"Dangerous Opcodes":     "opcodes",
+	"Mixer Exposure":        "mixers",
+	"Sanctions":             "sanctions",
+	"Address Poisoning":     "poisoning",
+	"Source Heuristics":     "heuristics",
+	"Token Metadata":        "token",
+	"Bytecode Match":        "bytecode",
+	"Method Profile":        "methods",
+	"Honeypot Simulation":   "honeypot",
+	"NFT Metadata":          "nft",
+	"Active Approvals":      "allowances",
+	"Abuse Reports":         "abuse",
+	"Fund Tracing":          "trace",
+	"Smart Wallet":          "wallet",
+	"Token Impersonation":   "impersonation",
+	"Proxy Upgrades":        "upgrades",
+	// Counterparty Reputation runs with --deep
+	"Counterparty Reputation": "counterparties",
+	// MEV Activity runs with --deep
+	"MEV Activity": "mev",
+}
+
+// CheckID returns the short name that selects a check: "age" for Account
+// Age, or the lowercased name with dashes for registered checks
+func CheckID(name string) string {
+	if id, ok := builtinCheckIDs[name]; ok {
+		return id
+	}
+	return strings.ReplaceAll(strings.ToLower(name), " ", "-")
+}
+
+// CheckIDs lists the short names of the built-in and registered checks in
+// report order, including checks that only run with Config.Deep
+func CheckIDs() []string {
+	names := allCheckNames()
+	ids := make([]string, len(names))
+	for i, name := range names {
+		ids[i] = CheckID(name)
+	}
+	return ids
+}
+
+func allCheckNames() []string {
+	var names []string
+	all := &Scanner{cfg: Config{Deep: true, AbuseReportsURL: "all", TraceDepth: 1}}
+	for _, c := range append(all.builtinChecks(), registeredChecks()...) {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// ValidateCheckNames returns an error listing the valid check IDs if any
+// of names selects no check. Names are check IDs or full check names.
+func ValidateCheckNames(names []string) error {
+	all := allCheckNames()
+	for _, name := range names {
+		if !selectsAny([]string{name}, all...) {
+			return fmt.Errorf("unknown check %q (valid: %s)", name, strings.Join(CheckIDs(), ", "))
+		}
+	}
+	return nil
+}
+
+// selects reports whether selector, an ID or a full name in any case,
+// names the check
+func selects(selector, name string) bool {
+	selector = strings.TrimSpace(selector)
+	return strings.EqualFold(selector, CheckID(name)) || strings.EqualFold(selector, name)
+}
+
+// selectsAny reports whether any of selectors names any of the checks
+func selectsAny(selectors []string, names ...string) bool {
+	for _, selector := range selectors {
+		for _, name := range names {
+			if selects(selector, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectChecks keeps the checks named in Config.Checks, if any, minus those
+// in Config.SkipChecks
+func (s *Scanner) selectChecks(checks []Check) []Check {
+	var selected []Check
+	for _, c := range checks {
+		if len(s.cfg.Checks) > 0 && !selectsAny(s.cfg.Checks, c.Name()) || selectsAny(s.cfg.SkipChecks, c.Name()) {
+			continue
+		}
+		selected = append(selected, c)
+	}
+	return selected
+}
+
+// CheckNames lists the checks the scanner runs, in report order
+func (s *Scanner) CheckNames() []string {
+	names := make([]string, len(s.checks))
+	for i, c := range s.checks {
+		names[i] = c.Name()
+	}
+	return names
+}
@@ -0,0 +1,236 @@
+package scanner
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Source path prefixes of vendored libraries. Their code still informs
+// state variables, but heuristic hits inside them are not reported.
+var vendoredSourcePrefixes = []string{"@openzeppelin/", "@uniswap/", "node_modules/", "lib/"}
+
+// solidityFiles splits Etherscan's SourceCode field into files: plain
+// source, a {path: {content}} map, or standard JSON input wrapped in an
+// extra pair of braces
+func solidityFiles(source *sourceCodeResult) map[string]string {
+	code := strings.TrimSpace(source.SourceCode)
+	if strings.HasPrefix(code, "{{") && strings.HasSuffix(code, "}}") {
+		code = code[1 : len(code)-1]
+	}
+	if strings.HasPrefix(code, "{") {
+		var input struct {
+			Sources map[string]sourceFile `json:"sources"`
+		}
+		if json.Unmarshal([]byte(code), &input) == nil && len(input.Sources) > 0 {
+			return fileContents(input.Sources)
+		}
+		var sources map[string]sourceFile
+		if json.Unmarshal([]byte(code), &sources) == nil && len(sources) > 0 {
+			return fileContents(sources)
+		}
+	}
+	name := source.ContractName
+	if name == "" {
+		name = "Contract"
+	}
+	return map[string]string{name + ".sol": source.SourceCode}
+}
+
+// sourceFile is a file entry of multi-file verified source
+type sourceFile struct {
+	Content string `json:"content"`
+}
+
+func fileContents(files map[string]sourceFile) map[string]string {
+	out := make(map[string]string, len(files))
+	for path, f := range files {
+		out[path] = f.Content
+	}
+	return out
+}
+
+func isVendored(path string) bool {
+	for _, prefix := range vendoredSourcePrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// stripSolidity blanks out comments and string literals, keeping line
+// breaks so line numbers still match the original source
+func stripSolidity(src string) string {
+	out := []byte(src)
+	for i := 0; i < len(out); i++ {
+		switch {
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			stop := len(out)
+			if end >= 0 {
+				stop = i + 2 + end + 2
+			}
+			for ; i < stop; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+		case out[i] == '"' || out[i] == '\'':
+			quote := out[i]
+			for i++; i < len(out) && out[i] != quote && out[i] != '\n'; i++ {
+				if out[i] == '\\' && i+1 < len(out) {
+					out[i] = ' '
+					i++
+				}
+				out[i] = ' '
+			}
+		}
+	}
+	return string(out)
+}
+
+// solLine is a line of stripped source with its 1-based line number
+type solLine struct {
+	n    int
+	text string
+}
+
+// solFunction is a function, modifier or constructor body
+type solFunction struct {
+	file      string
+	line      int
+	header    string            // from the keyword to the opening brace
+	params    map[string]string // parameter name -> type
+	body      []solLine
+	vendored  bool
+	modifiers string // header text after the parameter list
+}
+
+// solContracts holds what the heuristics need from a set of source files
+type solContracts struct {
+	functions   []solFunction
+	stateVars   map[string]bool // mutable state variables
+	stateArrays map[string]bool // state variables of array type
+	pushed      map[string]bool // arrays appended to outside vendored code
+}
+
+var (
+	functionStart = regexp.MustCompile(`^(function|constructor|modifier|receive|fallback)\b`)
+	stateVarDecl  = regexp.MustCompile(`^(mapping\s*\(.*\)|[A-Za-z_][\w.]*(?:\s*\[[^\]]*\])*)\s+((?:(?:public|private|internal|constant|immutable|override|transient)\s+)*)([A-Za-z_]\w*)\s*(?:=|;)`)
+	arrayPush     = regexp.MustCompile(`\b([A-Za-z_]\w*)\s*\.\s*push\s*\(`)
+)
+
+// declarationKeywords start contract-level statements that are not state variables
+var declarationKeywords = map[string]bool{
+	"event": true, "error": true, "using": true, "import": true, "pragma": true,
+	"emit": true, "return": true, "struct": true, "enum": true, "type": true,
+}
+
+// parseSolidity finds the functions and state variables of the files by
+// tracking brace depth. It is deliberately shallow: contract members are
+// expected at depth 1, as in any compiling contract.
+func parseSolidity(files map[string]string) solContracts {
+	contracts := solContracts{stateVars: map[string]bool{}, stateArrays: map[string]bool{}, pushed: map[string]bool{}}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		vendored := isVendored(path)
+		depth := 0
+		var current *solFunction
+		var header []string
+		headerLine, functionDepth := 0, 0
+
+		for i, line := range strings.Split(stripSolidity(files[path]), "\n") {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case current == nil && header == nil && depth == 1 && functionStart.MatchString(trimmed):
+				header, headerLine = []string{}, i+1
+			case current == nil && header == nil && depth == 1 && strings.HasSuffix(trimmed, ";"):
+				if m := stateVarDecl.FindStringSubmatch(trimmed); m != nil && !declarationKeywords[strings.Fields(trimmed)[0]] {
+					if strings.Contains(m[2], "constant") || strings.Contains(m[2], "immutable") {
+						break
+					}
+					contracts.stateVars[m[3]] = true
+					if strings.Contains(m[1], "[") {
+						contracts.stateArrays[m[3]] = true
+					}
+				}
+			case current != nil:
+				current.body = append(current.body, solLine{n: i + 1, text: line})
+			}
+
+			if header != nil {
+				if brace := strings.Index(line, "{"); brace >= 0 {
+					header = append(header, line[:brace])
+					text := strings.Join(header, " ")
+					params, modifiers := functionParams(text)
+					current = &solFunction{file: path, line: headerLine, header: text, params: params, modifiers: modifiers, vendored: vendored}
+					current.body = append(current.body, solLine{n: i + 1, text: line[brace+1:]})
+					functionDepth = depth
+					header = nil
+				} else if strings.Contains(line, ";") {
+					// Declaration without a body
+					header = nil
+				} else {
+					header = append(header, line)
+				}
+			}
+			if !vendored {
+				for _, m := range arrayPush.FindAllStringSubmatch(line, -1) {
+					contracts.pushed[m[1]] = true
+				}
+			}
+
+			depth += strings.Count(line, "{") - strings.Count(line, "}")
+			if current != nil && depth <= functionDepth {
+				contracts.functions = append(contracts.functions, *current)
+				current = nil
+			}
+		}
+	}
+	return contracts
+}
+
+// functionParams parses the parameter list of a function header and
+// returns the parameters and the rest of the header (modifiers)
+func functionParams(header string) (map[string]string, string) {
+	params := map[string]string{}
+	open := strings.Index(header, "(")
+	if open < 0 {
+		return params, ""
+	}
+	nesting, end := 0, len(header)
+	for i := open; i < len(header); i++ {
+		if header[i] == '(' {
+			nesting++
+		} else if header[i] == ')' {
+			nesting--
+			if nesting == 0 {
+				end = i
+				break
+			}
+		}
+	}
+	for _, param := range strings.Split(header[open+1:end], ",") {
+		fields := strings.Fields(param)
+		if len(fields) >= 2 {
+			params[fields[len(fields)-1]] = strings.Join(fields[:len(fields)-1], " ")
+		}
+	}
+	if end < len(header) {
+		return params, header[end+1:]
+	}
+	return params, ""
+}
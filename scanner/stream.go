@@ -0,0 +1,95 @@
+package scanner
+
+import "context"
+
+// streamWindow is how many reports ScanStreamOrdered may hold back while an
+// earlier address is still being scanned; it stops reading addresses once
+// this many are waiting
+const streamWindow = 256
+
+// ScanStream scans the addresses received from addresses with the worker
+// pool of ScanStreamFunc and sends each report on the returned channel as
+// soon as it completes, so results come in no particular order. Failed
+// scans are sent too, with Error set.
+//
+// The returned channel is unbuffered: a consumer that falls behind pauses
+// the workers, and with them the reading of addresses. It is closed once
+// addresses is closed and every report was received, or soon after ctx is
+// done; addresses not yet read are then left unscanned and undelivered
+// reports are dropped, so senders should select on ctx.Done() too.
+func (s *Scanner) ScanStream(ctx context.Context, addresses <-chan string, network string) <-chan ReputationReport {
+	return s.scanToChannel(ctx, addresses, network, false)
+}
+
+// ScanStreamOrdered is ScanStream with the reports sent in the order their
+// addresses were received. A slow address holds back the ones after it, at
+// most 256 of them.
+func (s *Scanner) ScanStreamOrdered(ctx context.Context, addresses <-chan string, network string) <-chan ReputationReport {
+	return s.scanToChannel(ctx, addresses, network, true)
+}
+
+func (s *Scanner) scanToChannel(ctx context.Context, addresses <-chan string, network string, ordered bool) <-chan ReputationReport {
+	out := make(chan ReputationReport)
+	jobs := make(chan string)
+	// Free slots in the reorder buffer; only used when ordered
+	window := make(chan struct{}, streamWindow)
+
+	go func() {
+		defer close(jobs)
+		for {
+			var address string
+			select {
+			case <-ctx.Done():
+				return
+			case a, ok := <-addresses:
+				if !ok {
+					return
+				}
+				address = a
+			}
+			if ordered {
+				select {
+				case window <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case jobs <- address:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(out)
+		send := func(report ReputationReport) {
+			select {
+			case out <- report:
+			case <-ctx.Done():
+			}
+		}
+		// Out-of-order reports waiting for their turn, by input position
+		pending := map[int]ReputationReport{}
+		next := 0
+		s.ScanStreamFunc(ctx, jobs, network, func(i int, report ReputationReport, err error) {
+			if !ordered {
+				send(report)
+				return
+			}
+			pending[i] = report
+			for {
+				report, ok := pending[next]
+				if !ok {
+					break
+				}
+				send(report)
+				delete(pending, next)
+				next++
+				<-window
+			}
+		})
+	}()
+	return out
+}
@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+)
+
+func streamAddresses(n int) []string {
+	addresses := make([]string, n)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("0x%040x", i+1)
+	}
+	return addresses
+}
+
+func feed(addresses []string) <-chan string {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, address := range addresses {
+			ch <- address
+		}
+	}()
+	return ch
+}
+
+func TestScanStreamOrdered(t *testing.T) {
+	// More addresses than the reorder window, so it fills and drains
+	addresses := streamAddresses(streamWindow + 50)
+	s := fixtureScanner(t, Config{Concurrency: 8, Checks: []string{"nonce"}}, nil)
+	var got []string
+	for report := range s.ScanStreamOrdered(context.Background(), feed(addresses), "ethereum") {
+		got = append(got, report.Address)
+	}
+	if len(got) != len(addresses) {
+		t.Fatalf("got %d reports, want %d", len(got), len(addresses))
+	}
+	for i := range got {
+		if got[i] != addresses[i] {
+			t.Fatalf("report %d is for %s, want %s", i, got[i], addresses[i])
+		}
+	}
+}
+
+func TestScanStream(t *testing.T) {
+	addresses := streamAddresses(40)
+	s := fixtureScanner(t, Config{Concurrency: 8, Checks: []string{"nonce"}}, nil)
+	var got []string
+	for report := range s.ScanStream(context.Background(), feed(addresses), "ethereum") {
+		got = append(got, report.Address)
+	}
+	sort.Strings(got)
+	if fmt.Sprint(got) != fmt.Sprint(addresses) {
+		t.Errorf("ScanStream() reported %v, want every address once", got)
+	}
+}
+
+func TestScanStreamCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s := fixtureScanner(t, Config{Checks: []string{"nonce"}}, nil)
+	// Addresses is never closed; the stream must still end
+	for range s.ScanStreamOrdered(ctx, make(chan string), "ethereum") {
+	}
+}
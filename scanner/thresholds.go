@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RiskThresholds are the lowest overall scores of the low, medium and high
+// risk levels; anything below High is critical
+type RiskThresholds struct {
+	Low    int `json:"low"`
+	Medium int `json:"medium"`
+	High   int `json:"high"`
+}
+
+// DefaultRiskThresholds: 90-100 low, 70-89 medium, 40-69 high, 0-39 critical
+var DefaultRiskThresholds = RiskThresholds{Low: 90, Medium: 70, High: 40}
+
+// Validate checks that the thresholds are within 0-100 and do not increase
+// from low to high
+func (t RiskThresholds) Validate() error {
+	for _, level := range []struct {
+		name  string
+		value int
+	}{{"low", t.Low}, {"medium", t.Medium}, {"high", t.High}} {
+		if level.value < 0 || level.value > 100 {
+			return fmt.Errorf("%s threshold %d is outside 0-100", level.name, level.value)
+		}
+	}
+	if t.Low < t.Medium || t.Medium < t.High {
+		return fmt.Errorf("thresholds must satisfy low >= medium >= high (got low=%d, medium=%d, high=%d)", t.Low, t.Medium, t.High)
+	}
+	return nil
+}
+
+// String formats the thresholds as accepted by ParseRiskThresholds
+func (t RiskThresholds) String() string {
+	return fmt.Sprintf("low=%d,medium=%d,high=%d", t.Low, t.Medium, t.High)
+}
+
+// level maps an overall score to a risk level
+func (t RiskThresholds) level(score int) string {
+	switch {
+	case score >= t.Low:
+		return "low"
+	case score >= t.Medium:
+		return "medium"
+	case score >= t.High:
+		return "high"
+	default:
+		return "critical"
+	}
+}
+
+// ParseRiskThresholds applies a spec such as "low=85,medium=60,high=35"
+// to base; levels missing from the spec keep their base value. A zero base
+// means DefaultRiskThresholds.
+func ParseRiskThresholds(spec string, base RiskThresholds) (RiskThresholds, error) {
+	levels := map[string]int{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return RiskThresholds{}, fmt.Errorf("invalid threshold %q (want level=score)", part)
+		}
+		score, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return RiskThresholds{}, fmt.Errorf("invalid threshold %q: score must be a number", part)
+		}
+		levels[strings.ToLower(strings.TrimSpace(name))] = score
+	}
+	return base.with(levels)
+}
+
+// with returns t with the given levels replaced, validated
+func (t RiskThresholds) with(levels map[string]int) (RiskThresholds, error) {
+	if t == (RiskThresholds{}) {
+		t = DefaultRiskThresholds
+	}
+	for name, score := range levels {
+		switch name {
+		case "low":
+			t.Low = score
+		case "medium":
+			t.Medium = score
+		case "high":
+			t.High = score
+		default:
+			return RiskThresholds{}, fmt.Errorf("unknown risk level %q in thresholds (use low, medium, high)", name)
+		}
+	}
+	if err := t.Validate(); err != nil {
+		return RiskThresholds{}, err
+	}
+	return t, nil
+}
@@ -0,0 +1,74 @@
+package scanner
+
+import "testing"
+
+func TestParseRiskThresholds(t *testing.T) {
+	custom := RiskThresholds{Low: 95, Medium: 80, High: 50}
+	tests := []struct {
+		spec string
+		base RiskThresholds
+		want RiskThresholds
+	}{
+		{"", RiskThresholds{}, DefaultRiskThresholds},
+		{"low=85,medium=60,high=35", RiskThresholds{}, RiskThresholds{85, 60, 35}},
+		{" High = 20 ", RiskThresholds{}, RiskThresholds{90, 70, 20}},
+		// Levels missing from the spec keep the base value
+		{"medium=60", custom, RiskThresholds{95, 60, 50}},
+		{"low=70,medium=70,high=70", RiskThresholds{}, RiskThresholds{70, 70, 70}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRiskThresholds(tt.spec, tt.base)
+		if err != nil {
+			t.Errorf("ParseRiskThresholds(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseRiskThresholds(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseRiskThresholdsErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"low", `invalid threshold "low" (want level=score)`},
+		{"low=high", `invalid threshold "low=high": score must be a number`},
+		{"severe=10", `unknown risk level "severe" in thresholds (use low, medium, high)`},
+		{"low=101", "low threshold 101 is outside 0-100"},
+		{"high=-1", "high threshold -1 is outside 0-100"},
+		{"medium=95", "thresholds must satisfy low >= medium >= high (got low=90, medium=95, high=40)"},
+	}
+	for _, tt := range tests {
+		_, err := ParseRiskThresholds(tt.spec, RiskThresholds{})
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("ParseRiskThresholds(%q) error = %v, want %s", tt.spec, err, tt.want)
+		}
+	}
+}
+
+func TestRiskThresholdsLevel(t *testing.T) {
+	tests := []struct {
+		score int
+		want  string
+	}{
+		{100, "low"}, {90, "low"}, {89, "medium"}, {70, "medium"},
+		{69, "high"}, {40, "high"}, {39, "critical"}, {0, "critical"},
+	}
+	for _, tt := range tests {
+		if got := DefaultRiskThresholds.level(tt.score); got != tt.want {
+			t.Errorf("level(%d) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+	if got := (RiskThresholds{Low: 50, Medium: 50, High: 50}).level(49); got != "critical" {
+		t.Errorf("level(49) with all thresholds at 50 = %s, want critical", got)
+	}
+}
+
+func TestRiskThresholdsString(t *testing.T) {
+	got, err := ParseRiskThresholds(DefaultRiskThresholds.String(), RiskThresholds{Low: 1})
+	if err != nil || got != DefaultRiskThresholds {
+		t.Errorf("ParseRiskThresholds(%q) = %v, %v, want %v", DefaultRiskThresholds.String(), got, err, DefaultRiskThresholds)
+	}
+}
@@ -0,0 +1,214 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// ERC-20 metadata getters; decimals is shared with the honeypot simulation
+const (
+	sigName        = "name()"
+	sigSymbol      = "symbol()"
+	sigTotalSupply = "totalSupply()"
+)
+
+// Longest name or symbol kept in a report
+const maxTokenStringLen = 64
+
+// TokenInfo is the ERC-20 metadata of a token contract
+type TokenInfo struct {
+	Name        string `json:"name,omitempty"`
+	Symbol      string `json:"symbol"`
+	Decimals    int    `json:"decimals"`
+	TotalSupply string `json:"total_supply"` // in base units, as a decimal string
+}
+
+// String summarizes the metadata, e.g. "USD Coin (USDC), 6 decimals,
+// total supply 1000000"
+func (t TokenInfo) String() string {
+	label := t.Symbol
+	if label == "" {
+		label = "Token without symbol"
+	}
+	if t.Name != "" {
+		label = fmt.Sprintf("%s (%s)", t.Name, t.Symbol)
+	}
+	return fmt.Sprintf("%s, %d decimals, total supply %s", label, t.Decimals, t.FormattedSupply())
+}
+
+// FormattedSupply returns the total supply in whole tokens, e.g. "1000000.5"
+func (t TokenInfo) FormattedSupply() string {
+	supply, ok := new(big.Int).SetString(t.TotalSupply, 10)
+	if !ok {
+		return t.TotalSupply
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.Decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(supply, unit, new(big.Int))
+	if frac.Sign() == 0 {
+		return whole.String()
+	}
+	digits := fmt.Sprintf("%0*s", t.Decimals, frac.String())
+	return whole.String() + "." + strings.TrimRight(digits, "0")
+}
+
+// tokenInfo reads the ERC-20 metadata of address with eth_call. It returns
+// nil without an error for accounts and contracts that do not answer
+// decimals() and totalSupply(), i.e. are not tokens.
+func (s *Scanner) tokenInfo(ctx context.Context, address, network string) (*TokenInfo, error) {
+	key := chainCacheKey(address, network)
+	s.cacheMu.Lock()
+	info, ok := s.tokenCache[key]
+	s.cacheMu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	info, err := s.fetchTokenInfo(ctx, address, network)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMu.Lock()
+	s.tokenCache[key] = info
+	s.cacheMu.Unlock()
+	return info, nil
+}
+
+func (s *Scanner) fetchTokenInfo(ctx context.Context, address, network string) (*TokenInfo, error) {
+	code, err := s.getCode(ctx, address, network)
+	if err != nil || len(code) == 0 {
+		return nil, err
+	}
+
+	// call returns nil for getters that revert or return nothing
+	call := func(sig string) ([]byte, error) {
+		result, err := s.ethCall(ctx, network, address, encodeCall(sig))
+		if isRevert(err) {
+			return nil, nil
+		}
+		return result, err
+	}
+
+	decimals, err := call(sigDecimals)
+	if err != nil {
+		return nil, err
+	}
+	if len(decimals) < 32 {
+		return nil, nil
+	}
+	places := new(big.Int).SetBytes(decimals[:32])
+	if places.Cmp(big.NewInt(255)) > 0 {
+		return nil, nil
+	}
+	supply, err := call(sigTotalSupply)
+	if err != nil {
+		return nil, err
+	}
+	if len(supply) < 32 {
+		return nil, nil
+	}
+
+	info := &TokenInfo{
+		Decimals:    int(places.Int64()),
+		TotalSupply: new(big.Int).SetBytes(supply[:32]).String(),
+	}
+	symbol, err := call(sigSymbol)
+	if err != nil {
+		return nil, err
+	}
+	info.Symbol = decodeTokenString(symbol)
+	name, err := call(sigName)
+	if err != nil {
+		return nil, err
+	}
+	info.Name = decodeTokenString(name)
+	return info, nil
+}
+
+// decodeTokenString decodes a name() or symbol() result, which is a string
+// for most tokens and a bytes32 for some early ones (e.g. MKR)
+func decodeTokenString(result []byte) string {
+	text, err := decodeStringResult(result)
+	if err != nil && len(result) == 32 {
+		text, err = string(bytes.TrimRight(result, "\x00")), nil
+	}
+	if err != nil {
+		return ""
+	}
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return -1
+		}
+		return r
+	}, strings.ToValidUTF8(text, ""))
+	text = strings.TrimSpace(text)
+	if runes := []rune(text); len(runes) > maxTokenStringLen {
+		text = string(runes[:maxTokenStringLen])
+	}
+	return text
+}
+
+// checkTokenMetadata reads a token's name, symbol, decimals and total
+// supply, and flags empty symbols or symbols hiding look-alike characters
+func (s *Scanner) checkTokenMetadata(ctx context.Context, address, network string) (CheckResult, error) {
+	if _, err := s.Network(network); err != nil {
+		return CheckResult{}, err
+	}
+
+	info, err := s.tokenInfo(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Token Metadata",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+	if info == nil {
+		return CheckResult{
+			Name:          "Token Metadata",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not an ERC-20 token (token metadata not applicable)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	// Copies of major tokens are left to the Token Impersonation check
+	var flags []string
+	if info.Symbol == "" {
+		flags = append(flags, "empty symbol")
+	} else if !isPrintableASCII(info.Symbol) {
+		flags = append(flags, fmt.Sprintf("symbol %q has non-ASCII characters, possibly look-alikes", info.Symbol))
+	}
+
+	summary := info.String()
+	if len(flags) > 0 {
+		return CheckResult{
+			Name:       "Token Metadata",
+			Status:     "warning",
+			Score:      30,
+			Details:    "Suspicious token metadata: " + strings.Join(flags, "; ") + " (" + summary + ")",
+			ReasonCode: ReasonSuspiciousTokenMetadata,
+		}, nil
+	}
+	return CheckResult{
+		Name:    "Token Metadata",
+		Status:  "pass",
+		Score:   100,
+		Details: summary,
+	}, nil
+}
+
+func isPrintableASCII(text string) bool {
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
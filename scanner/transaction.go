@@ -0,0 +1,231 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transaction participant roles
+const (
+	RoleFrom    = "from"
+	RoleTo      = "to"
+	RoleCreated = "created" // contract deployed by the transaction
+)
+
+// Transaction statuses
+const (
+	TxSuccess  = "success"
+	TxReverted = "reverted"
+	TxPending  = "pending"
+)
+
+// TxParticipant is an address involved in a transaction and its report
+type TxParticipant struct {
+	Address string           `json:"address"`
+	Roles   []string         `json:"roles"` // RoleFrom, RoleTo and/or RoleCreated
+	Report  ReputationReport `json:"report"`
+}
+
+// TransactionReport assesses the participants of one transaction, with the
+// call's method and value for context. Like a MultiChainReport it is as
+// risky as its riskiest participant.
+type TransactionReport struct {
+	TxHash          string          `json:"tx_hash"`
+	Network         string          `json:"network"`
+	Timestamp       time.Time       `json:"timestamp"`
+	Status          string          `json:"status"`                 // TxSuccess, TxReverted or TxPending
+	BlockNumber     *uint64         `json:"block_number,omitempty"` // nil while pending
+	From            string          `json:"from"`
+	To              string          `json:"to,omitempty"`               // empty for contract creations
+	ContractCreated string          `json:"contract_created,omitempty"` // deployed contract, if any
+	Method          string          `json:"method"`                     // called function, "ETH transfer" or "contract creation"
+	Value           string          `json:"value"`                      // in the native currency, e.g. "1.5 ETH"
+	ValueWei        string          `json:"value_wei"`                  // decimal
+	OverallScore    int             `json:"overall_score"`              // lowest score of the participants
+	RiskLevel       string          `json:"risk_level"`                 // highest risk level of the participants
+	RiskiestAddress string          `json:"riskiest_address"`           // empty if every scan failed
+	Participants    []TxParticipant `json:"participants"`
+	Incomplete      bool            `json:"incomplete,omitempty"`
+}
+
+// rpcTransaction is the part of eth_getTransactionByHash the report uses
+type rpcTransaction struct {
+	BlockNumber *string `json:"blockNumber"`
+	From        string  `json:"from"`
+	To          *string `json:"to"`
+	Value       string  `json:"value"`
+	Input       string  `json:"input"`
+}
+
+// rpcReceipt is the part of eth_getTransactionReceipt the report uses
+type rpcReceipt struct {
+	Status          string  `json:"status"`
+	ContractAddress *string `json:"contractAddress"`
+}
+
+// ScanTransaction fetches a transaction and scans its sender, recipient
+// and any contract it created
+func (s *Scanner) ScanTransaction(hash, network string) (TransactionReport, error) {
+	return s.ScanTransactionContext(context.Background(), hash, network)
+}
+
+// ScanTransactionContext is ScanTransaction with a context. A participant
+// whose scan fails gets a report with Error set and is left out of the
+// aggregate; it returns an error if every scan failed.
+func (s *Scanner) ScanTransactionContext(ctx context.Context, hash, network string) (TransactionReport, error) {
+	if !IsTxHash(hash) {
+		return TransactionReport{}, fmt.Errorf("invalid transaction hash %q (want 0x and 64 hex characters)", hash)
+	}
+	netCfg, err := s.Network(network)
+	if err != nil {
+		return TransactionReport{}, err
+	}
+
+	result, err := s.rpcCall(ctx, network, "eth_getTransactionByHash", []interface{}{hash})
+	if err != nil {
+		return TransactionReport{}, err
+	}
+	var tx *rpcTransaction
+	if err := json.Unmarshal(result, &tx); err != nil {
+		return TransactionReport{}, fmt.Errorf("unexpected transaction result: %w", err)
+	}
+	if tx == nil {
+		return TransactionReport{}, fmt.Errorf("transaction %s on %s %w", hash, network, ErrNotFound)
+	}
+
+	report := TransactionReport{
+		TxHash:       strings.ToLower(hash),
+		Network:      network,
+		Timestamp:    s.cfg.Clock(),
+		Status:       TxPending,
+		From:         ToChecksumAddress(tx.From),
+		Participants: []TxParticipant{},
+	}
+	if tx.To != nil && *tx.To != "" {
+		report.To = ToChecksumAddress(*tx.To)
+	}
+	wei, ok := new(big.Int).SetString(strings.TrimPrefix(tx.Value, "0x"), 16)
+	if !ok {
+		wei = new(big.Int)
+	}
+	report.ValueWei = wei.String()
+	report.Value = strings.TrimSpace(formatUnits(wei, 18) + " " + netCfg.NativeSymbol)
+
+	if tx.BlockNumber != nil {
+		block, err := strconv.ParseUint(strings.TrimPrefix(*tx.BlockNumber, "0x"), 16, 64)
+		if err != nil {
+			return report, fmt.Errorf("invalid block number %q", *tx.BlockNumber)
+		}
+		report.BlockNumber = &block
+		receipt, err := s.getReceipt(ctx, hash, network)
+		if err != nil {
+			return report, err
+		}
+		report.Status = TxSuccess
+		if receipt.Status == "0x0" {
+			report.Status = TxReverted
+		}
+		if receipt.ContractAddress != nil && *receipt.ContractAddress != "" {
+			report.ContractCreated = ToChecksumAddress(*receipt.ContractAddress)
+		}
+	}
+	report.Method = s.txMethod(ctx, report.To, tx.Input, network)
+
+	// The same address can play several roles, e.g. a self-transfer
+	var addresses []string
+	roles := map[string][]string{}
+	for _, p := range []struct{ address, role string }{
+		{report.From, RoleFrom}, {report.To, RoleTo}, {report.ContractCreated, RoleCreated},
+	} {
+		if p.address == "" {
+			continue
+		}
+		if _, ok := roles[p.address]; !ok {
+			addresses = append(addresses, p.address)
+		}
+		roles[p.address] = append(roles[p.address], p.role)
+	}
+
+	reports, _ := s.ScanBatchContext(ctx, addresses, network, nil)
+	var riskiest *ReputationReport
+	for i, address := range addresses {
+		r := &reports[i]
+		report.Participants = append(report.Participants, TxParticipant{Address: address, Roles: roles[address], Report: *r})
+		if r.Error != "" {
+			continue
+		}
+		report.Incomplete = report.Incomplete || r.Incomplete
+		if riskiest == nil || RiskRank(r.RiskLevel) > RiskRank(riskiest.RiskLevel) ||
+			r.RiskLevel == riskiest.RiskLevel && r.OverallScore < riskiest.OverallScore {
+			riskiest = r
+		}
+	}
+	if riskiest == nil {
+		return report, fmt.Errorf("scan of every participant of %s failed", hash)
+	}
+
+	report.OverallScore = riskiest.OverallScore
+	for _, r := range reports {
+		if r.Error == "" && r.OverallScore < report.OverallScore {
+			report.OverallScore = r.OverallScore
+		}
+	}
+	report.RiskLevel = riskiest.RiskLevel
+	report.RiskiestAddress = riskiest.Address
+	return report, nil
+}
+
+// getReceipt returns the receipt of a mined transaction
+func (s *Scanner) getReceipt(ctx context.Context, hash, network string) (*rpcReceipt, error) {
+	result, err := s.rpcCall(ctx, network, "eth_getTransactionReceipt", []interface{}{hash})
+	if err != nil {
+		return nil, err
+	}
+	var receipt *rpcReceipt
+	if err := json.Unmarshal(result, &receipt); err != nil {
+		return nil, fmt.Errorf("unexpected receipt result: %w", err)
+	}
+	if receipt == nil {
+		return nil, fmt.Errorf("receipt of %s %w", hash, ErrNotFound)
+	}
+	return receipt, nil
+}
+
+// txMethod names the function a transaction called, from the verified ABI
+// of the called contract when the explorer has it
+func (s *Scanner) txMethod(ctx context.Context, to, input, network string) string {
+	if to == "" {
+		return "contract creation"
+	}
+	var abi *contractABI
+	if len(input) >= 10 && s.hasExplorer(network) {
+		var err error
+		if abi, err = s.getABI(ctx, to, network); err != nil && !errors.Is(err, ErrNoAPIKey) {
+			s.logger.Debug("ABI lookup failed", "address", to, "err", err)
+		}
+	}
+	return s.methodName(abi, input)
+}
+
+// formatUnits renders an integer amount with decimals as a decimal number
+// without trailing zeros, e.g. 1500000000000000000 with 18 as "1.5"
+func formatUnits(amount *big.Int, decimals int) string {
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+	if amount.Sign() < 0 {
+		whole = "-" + whole
+	}
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
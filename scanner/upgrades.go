@@ -0,0 +1,157 @@
+package scanner
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultUpgradeWindow is how recent a proxy upgrade has to be for the
+// Proxy Upgrades check to flag it
+const DefaultUpgradeWindow = 7 * 24 * time.Hour
+
+// upgradeLogSample is how many Upgraded events of a proxy are read; the
+// explorer returns the oldest first
+const upgradeLogSample = 1000
+
+// upgradedTopic is the EIP-1967 event a proxy emits when its
+// implementation changes, at deployment too
+var upgradedTopic = "0x" + hex.EncodeToString(keccak256([]byte("Upgraded(address)")))
+
+// proxyUpgrade is one implementation change read from an Upgraded event
+type proxyUpgrade struct {
+	implementation string
+	block          uint64
+	blockHex       string
+	timestamp      string // hex, empty when the explorer omits it
+}
+
+// proxyUpgrades returns the implementation changes of a proxy, oldest first
+func (s *Scanner) proxyUpgrades(ctx context.Context, address, network string) ([]proxyUpgrade, error) {
+	logs, err := s.getContractLogs(ctx, network, address, upgradedTopic, upgradeLogSample)
+	if err != nil {
+		return nil, err
+	}
+	var upgrades []proxyUpgrade
+	for _, log := range logs {
+		if len(log.Topics) != 2 {
+			continue
+		}
+		implementation, ok := decodeAddressWord(topicWord(log.Topics[1]))
+		if !ok {
+			continue
+		}
+		block, err := strconv.ParseUint(strings.TrimPrefix(log.BlockNumber, "0x"), 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log block number %q", log.BlockNumber)
+		}
+		upgrades = append(upgrades, proxyUpgrade{implementation, block, log.BlockNumber, log.TimeStamp})
+	}
+	return upgrades, nil
+}
+
+// upgradeTime returns when an upgrade happened, from the log or else the
+// block header
+func (s *Scanner) upgradeTime(ctx context.Context, upgrade proxyUpgrade, network string) (time.Time, error) {
+	if upgrade.timestamp == "" {
+		return s.blockTime(ctx, network, upgrade.blockHex)
+	}
+	secs, err := strconv.ParseInt(strings.TrimPrefix(upgrade.timestamp, "0x"), 16, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid log timestamp %q", upgrade.timestamp)
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// checkProxyUpgrades flags proxies whose implementation was swapped within
+// Config.UpgradeWindow, the "was safe, just got maliciously upgraded" case
+// a scan of the current code does not catch. Upgradeability itself is left
+// to Proxy Check.
+func (s *Scanner) checkProxyUpgrades(ctx context.Context, address, network string) (CheckResult, error) {
+	if code, err := s.getCode(ctx, address, network); err == nil && len(code) == 0 {
+		return CheckResult{
+			Name:          "Proxy Upgrades",
+			Status:        "pass",
+			Score:         100,
+			Details:       "Not a contract (upgrade check skipped)",
+			NotApplicable: true,
+		}, nil
+	}
+
+	if !s.hasExplorer(network) {
+		return CheckResult{
+			Name:    "Proxy Upgrades",
+			Status:  "warning",
+			Score:   50,
+			Details: s.noExplorerDetails(),
+		}, ErrNoAPIKey
+	}
+
+	upgrades, err := s.proxyUpgrades(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Proxy Upgrades",
+			Status:  "warning",
+			Score:   50,
+			Details: "Explorer query failed: " + err.Error(),
+		}, err
+	}
+	switch len(upgrades) {
+	case 0:
+		return CheckResult{
+			Name:    "Proxy Upgrades",
+			Status:  "pass",
+			Score:   100,
+			Details: "No Upgraded events",
+		}, nil
+	case 1:
+		// The first event sets the implementation at deployment
+		return CheckResult{
+			Name:    "Proxy Upgrades",
+			Status:  "pass",
+			Score:   100,
+			Details: fmt.Sprintf("Never upgraded: implementation %s since block %d", upgrades[0].implementation, upgrades[0].block),
+		}, nil
+	}
+
+	latest, previous := upgrades[len(upgrades)-1], upgrades[len(upgrades)-2]
+	upgraded, err := s.upgradeTime(ctx, latest, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Proxy Upgrades",
+			Status:  "warning",
+			Score:   50,
+			Details: fmt.Sprintf("Upgraded in block %d from %s to %s; upgrade time unknown: %v", latest.block, previous.implementation, latest.implementation, err),
+		}, err
+	}
+
+	window := s.cfg.UpgradeWindow
+	if window == 0 {
+		window = DefaultUpgradeWindow
+	}
+	age := s.cfg.Clock().Sub(upgraded)
+	change := fmt.Sprintf("from %s to %s in block %d (%s)", previous.implementation, latest.implementation, latest.block, upgraded.UTC().Format("2006-01-02 15:04 UTC"))
+	if age < window {
+		ago := fmt.Sprintf("%d days", int(age.Hours()/24))
+		if age < 48*time.Hour {
+			ago = fmt.Sprintf("%d hours", int(age.Hours()))
+		}
+		return CheckResult{
+			Name:       "Proxy Upgrades",
+			Status:     "warning",
+			Score:      20,
+			Severity:   "high",
+			Details:    fmt.Sprintf("Implementation changed %s ago, %s; review the new implementation", ago, change),
+			ReasonCode: ReasonRecentUpgrade,
+		}, nil
+	}
+	return CheckResult{
+		Name:    "Proxy Upgrades",
+		Status:  "pass",
+		Score:   100,
+		Details: fmt.Sprintf("Last upgraded %d days ago, %s", int(age.Hours()/24), change),
+	}, nil
+}
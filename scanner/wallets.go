@@ -0,0 +1,239 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// smartWallet is a known smart contract wallet implementation
+type smartWallet struct {
+	Kind string // e.g. "Safe v1.3.0"
+	// EIP1271 is set for wallets validating signatures with
+	// isValidSignature, possibly through a fallback handler
+	EIP1271 bool
+}
+
+// knownWalletImplementations are the singletons and implementations that
+// wallet proxies delegate to. Factories deploy them at the same address on
+// every chain.
+var knownWalletImplementations = map[string]smartWallet{
+	"0x34CfAC646f301356fAa8B21e94227e3583Fe3F5F": {"Safe v1.1.1", true},
+	"0x6851D6fDFAfD08c0295C392436245E5bc78B0185": {"Safe v1.2.0", true},
+	"0xd9Db270c1B5E3Bd161E8c8503c55cEABeE709552": {"Safe v1.3.0", true},
+	"0x3E5c63644E683549055b9Be8653de26E0B4Cd36E": {"Safe v1.3.0 (L2)", true},
+	"0x41675C099F32341bf84BFc5382aF534df5C7461a": {"Safe v1.4.1", true},
+	"0x29fcB43b46531BcA003ddC8FCB67FFE91900C762": {"Safe v1.4.1 (L2)", true},
+	"0x000100abaad02f1cfC8Bbe32bD5a564817339E72": {"Coinbase Smart Wallet", true},
+}
+
+// knownWalletFactories are the factories that deploy wallet proxies, for
+// wallets whose implementation is not in knownWalletImplementations
+var knownWalletFactories = map[string]smartWallet{
+	"0x76E2cFc1F5Fa8F6a5b3fC4c8F4788F0116861F9B": {"Safe v1.1.1", true},
+	"0xa6B71E26C5e0845f74c812102Ca7114b6a896AB2": {"Safe v1.3.0", true},
+	"0x4e1DCf7AD4e460CfD30791CCC4F9c8a4f820ec67": {"Safe v1.4.1", true},
+	"0x40C84310Ef15B0c0E5c69d25138e0E16e8000fE9": {"Argent", true},
+	"0x0BA5ED0c6AA8c49038F819E587E2633c4A9F428a": {"Coinbase Smart Wallet", true},
+	"0x9406Cc6185a346906296840746125a0E44976454": {"ERC-4337 SimpleAccount", false},
+}
+
+// Runtime code prefixes of wallet proxies: EIP-1167 minimal proxies embed
+// the implementation after the prefix, Safe proxies keep their singleton
+// in storage slot 0 and answer masterCopy() themselves
+var (
+	minimalProxyPrefix = mustDecodeHex("363d3d373d3d3d363d73")
+	safeProxyPrefix    = mustDecodeHex("608060405273ffffffffffffffffffffffffffffffffffffffff600054167fa619486e")
+)
+
+// EIP-1271 isValidSignature(bytes32,bytes) and the values a wallet returns
+// for valid and invalid signatures
+var (
+	eip1271Selector = selector("isValidSignature(bytes32,bytes)")
+	eip1271Invalid  = []byte{0xff, 0xff, 0xff, 0xff}
+)
+
+// walletAdjustedScore is the score of a code check that flagged only what
+// a recognized wallet is expected to have
+const walletAdjustedScore = 90
+
+// walletExpectedChecks flag what every proxy wallet has: delegated,
+// upgradeable code with owner management functions. Recognized wallets
+// pass them.
+var walletExpectedChecks = map[string]bool{
+	"Contract Verification": true,
+	"Proxy Check":           true,
+	"Approval Risk":         true,
+	"Dangerous Opcodes":     true,
+	"Source Heuristics":     true,
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// walletInfo is what detectWallet found about an address
+type walletInfo struct {
+	contract bool
+	wallet   *smartWallet // nil unless recognized
+	via      string       // how it was recognized, e.g. "factory 0x..."
+	eip1271  bool
+}
+
+// lookupWallet finds address in a registry keyed by checksummed address
+func lookupWallet(registry map[string]smartWallet, address string) (smartWallet, bool) {
+	for key, wallet := range registry {
+		if strings.EqualFold(key, address) {
+			return wallet, true
+		}
+	}
+	return smartWallet{}, false
+}
+
+// detectWallet recognizes smart contract wallets by the implementation
+// their proxy delegates to, else by the factory that deployed them, and
+// tells whether the contract validates EIP-1271 signatures
+func (s *Scanner) detectWallet(ctx context.Context, address, network string) (walletInfo, error) {
+	key := chainCacheKey(address, network)
+	s.cacheMu.Lock()
+	info, ok := s.walletCache[key]
+	s.cacheMu.Unlock()
+	if ok {
+		return info, nil
+	}
+
+	code, err := s.getCode(ctx, address, network)
+	if err != nil {
+		return walletInfo{}, err
+	}
+	if len(code) > 0 {
+		info.contract = true
+		implementation, err := s.walletImplementation(ctx, address, network, code)
+		if err != nil {
+			return walletInfo{}, err
+		}
+		if wallet, ok := lookupWallet(knownWalletImplementations, implementation); ok {
+			info.wallet, info.via = &wallet, "implementation "+implementation
+		} else if s.hasExplorer(network) {
+			// Without the creation a wallet can still be told by EIP-1271
+			creation, err := s.getContractCreation(ctx, address, network)
+			if err != nil {
+				s.logger.Debug("contract creation lookup failed", "address", address, "err", err)
+			} else if creation != nil {
+				factory := creation.ContractFactory
+				if factory == "" {
+					factory = creation.ContractCreator
+				}
+				if wallet, ok := lookupWallet(knownWalletFactories, factory); ok {
+					info.wallet, info.via = &wallet, "factory "+ToChecksumAddress(factory)
+				}
+			}
+		}
+		info.eip1271 = info.wallet != nil && info.wallet.EIP1271 ||
+			hasSelector(code, eip1271Selector) || s.probeEIP1271(ctx, address, network)
+	}
+
+	s.cacheMu.Lock()
+	s.walletCache[key] = info
+	s.cacheMu.Unlock()
+	return info, nil
+}
+
+// walletImplementation returns the address a proxy delegates to, or ""
+func (s *Scanner) walletImplementation(ctx context.Context, address, network string, code []byte) (string, error) {
+	if bytes.HasPrefix(code, minimalProxyPrefix) && len(code) >= len(minimalProxyPrefix)+20 {
+		return ToChecksumAddress(hex.EncodeToString(code[len(minimalProxyPrefix) : len(minimalProxyPrefix)+20])), nil
+	}
+	slot := eip1967ImplementationSlot
+	if bytes.HasPrefix(code, safeProxyPrefix) {
+		slot = "0x0"
+	}
+	value, err := s.getStorageAt(ctx, address, slot, network)
+	if err != nil {
+		return "", err
+	}
+	implementation, _ := slotAddress(value)
+	return implementation, nil
+}
+
+// probeEIP1271 asks the contract to validate an empty signature of the
+// zero hash. Wallets answer with a magic value, valid or not; many revert
+// instead, which proves nothing.
+func (s *Scanner) probeEIP1271(ctx context.Context, address, network string) bool {
+	data := "0x" + hex.EncodeToString(eip1271Selector) + strings.Repeat("0", 64) +
+		fmt.Sprintf("%064x", 64) + strings.Repeat("0", 64)
+	result, err := s.ethCall(ctx, network, address, data)
+	if err != nil || len(result) < 32 {
+		return false
+	}
+	return bytes.Equal(result[:4], eip1271Selector) || bytes.Equal(result[:4], eip1271Invalid)
+}
+
+// checkSmartWallet identifies smart contract wallets such as Safe, which
+// other code checks would take for risky proxies. Recognized wallets pass
+// the checks in walletExpectedChecks; see adjustForWallet.
+func (s *Scanner) checkSmartWallet(ctx context.Context, address, network string) (CheckResult, error) {
+	info, err := s.detectWallet(ctx, address, network)
+	if err != nil {
+		return CheckResult{
+			Name:    "Smart Wallet",
+			Status:  "warning",
+			Score:   50,
+			Details: "RPC query failed: " + err.Error(),
+		}, err
+	}
+
+	details := "Not a recognized smart wallet"
+	switch {
+	case !info.contract:
+		details = "Not a contract (smart wallet check not applicable)"
+	case info.wallet != nil:
+		details = fmt.Sprintf("%s smart wallet (%s)", info.wallet.Kind, info.via)
+		if info.eip1271 {
+			details += ", validates EIP-1271 signatures"
+		}
+	case info.eip1271:
+		details = "Validates EIP-1271 signatures, but the wallet type is not recognized"
+	}
+	return CheckResult{
+		Name:          "Smart Wallet",
+		Status:        "pass",
+		Score:         100,
+		Details:       details,
+		NotApplicable: !info.contract,
+	}, nil
+}
+
+// adjustForWallet passes the walletExpectedChecks of a recognized smart
+// wallet and returns its kind. Only scans running the Smart Wallet check
+// are adjusted; fallback results and results with a severity are kept.
+func (s *Scanner) adjustForWallet(checks []CheckResult, address, network string) (string, []CheckResult) {
+	ran := false
+	for _, check := range checks {
+		ran = ran || check.Name == "Smart Wallet"
+	}
+	s.cacheMu.Lock()
+	info := s.walletCache[chainCacheKey(address, network)]
+	s.cacheMu.Unlock()
+	if !ran || info.wallet == nil {
+		return "", checks
+	}
+
+	adjusted := make([]CheckResult, len(checks))
+	for i, check := range checks {
+		if walletExpectedChecks[check.Name] && check.Status != "pass" && check.Severity == "" && check.DataSource != DataFallback {
+			check.Status = "pass"
+			check.Score = walletAdjustedScore
+			check.ReasonCode = ""
+			check.Details += fmt.Sprintf(" (expected for %s smart wallets)", info.wallet.Kind)
+		}
+		adjusted[i] = check
+	}
+	return info.wallet.Kind, adjusted
+}
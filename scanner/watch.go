@@ -0,0 +1,398 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strings"
+	"time"
+
+	"agent-reputation-scanner/internal/websocket"
+)
+
+// Watch defaults: the quiet period after activity before a rescan, and
+// the limits of reconnection backoff
+const (
+	DefaultWatchDebounce = 30 * time.Second
+	watchMaxDelay        = 5 // debounce periods a rescan may be put off for
+	watchMinBackoff      = time.Second
+	watchMaxBackoff      = time.Minute
+	// watchIdleTimeout drops a socket that delivered nothing, not even a
+	// new block header, for this long
+	watchIdleTimeout = 2 * time.Minute
+)
+
+// Kinds of WatchEvent
+const (
+	WatchConnected    = "connected"    // subscribed; Reconnected after a drop
+	WatchDisconnected = "disconnected" // the socket dropped; Err says why, Retry when
+	WatchActivity     = "activity"     // a transaction involving the address
+	WatchScanned      = "scanned"      // a rescan that did not cross the threshold
+	WatchAlert        = "alert"        // risk rose to the threshold or above
+	WatchRecovered    = "recovered"    // risk fell below the threshold again
+	WatchScanFailed   = "scan_failed"  // a rescan returned Err
+)
+
+// WatchOptions tunes Watch
+type WatchOptions struct {
+	// Threshold is the lowest risk level that alerts; defaults to the
+	// webhook threshold
+	Threshold string
+	// Debounce is how long activity must pause before a rescan; busy
+	// addresses are rescanned at least every five periods. Defaults to
+	// DefaultWatchDebounce.
+	Debounce time.Duration
+	// URL overrides the endpoint from WSURL
+	URL string
+}
+
+// WatchEvent is something Watch saw or did
+type WatchEvent struct {
+	Kind   string
+	TxHash string            // WatchActivity
+	Report *ReputationReport // WatchScanned, WatchAlert, WatchRecovered
+	// Previous is the risk level of the scan before, empty for the first
+	Previous string
+	Err      error
+	Retry    time.Duration // WatchDisconnected
+	// Reconnected marks the WatchConnected event after a dropped socket,
+	// when activity may have been missed
+	Reconnected bool
+}
+
+// WSURL returns the WebSocket endpoint Watch subscribes to on network:
+// Config.WSURLs, else <NETWORK>_WS_URL, else the RPC endpoint with its
+// scheme changed to ws or wss
+func (s *Scanner) WSURL(network string) string {
+	network = strings.ToLower(network)
+	if url := s.cfg.WSURLs[network]; url != "" {
+		return url
+	}
+	if url := os.Getenv(strings.ToUpper(network) + "_WS_URL"); url != "" {
+		return url
+	}
+	rpc := s.getRPCURL(network)
+	switch {
+	case strings.HasPrefix(rpc, "https://"):
+		return "wss://" + strings.TrimPrefix(rpc, "https://")
+	case strings.HasPrefix(rpc, "http://"):
+		return "ws://" + strings.TrimPrefix(rpc, "http://")
+	}
+	return rpc
+}
+
+// Watch scans address on network, then subscribes over the network's
+// WebSocket endpoint to logs naming the address and to new blocks, and
+// rescans after activity settles for WatchOptions.Debounce. Dropped
+// sockets are reconnected with backoff, followed by a rescan for activity
+// missed meanwhile. Reports crossing the threshold are posted to
+// Config.Webhook. Watch emits events until ctx is done.
+func (s *Scanner) Watch(ctx context.Context, address, network string, opts WatchOptions, emit func(WatchEvent)) error {
+	network = strings.ToLower(network)
+	if _, err := s.Network(network); err != nil {
+		return err
+	}
+	if IsTruncatedAddress(address) {
+		return truncatedAddressError(address)
+	}
+	if IsENSName(address) {
+		resolved, err := s.resolveENS(ctx, address)
+		if err != nil {
+			return fmt.Errorf("cannot resolve ENS name %s: %w", address, err)
+		}
+		address = resolved
+	}
+	if !IsHexAddress(address) {
+		return fmt.Errorf("invalid address %q", address)
+	}
+	url := opts.URL
+	if url == "" {
+		url = s.WSURL(network)
+	}
+	if !strings.HasPrefix(url, "ws://") && !strings.HasPrefix(url, "wss://") {
+		return fmt.Errorf("no WebSocket endpoint for %s (set %s_WS_URL)", network, strings.ToUpper(network))
+	}
+	if opts.Threshold == "" {
+		opts.Threshold = s.cfg.Webhook.Threshold
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultWatchDebounce
+	}
+
+	level := ""
+	rescan := func() {
+		s.Forget(address, network)
+		report, err := s.scan(ctx, address, network)
+		s.metrics.recordScan(report, err)
+		if err != nil {
+			if ctx.Err() == nil {
+				emit(WatchEvent{Kind: WatchScanFailed, Err: err})
+			}
+			return
+		}
+		previous := level
+		level = report.RiskLevel
+		above := RiskRank(level) >= RiskRank(opts.Threshold)
+		wasAbove := previous != "" && RiskRank(previous) >= RiskRank(opts.Threshold)
+		event := WatchEvent{Kind: WatchScanned, Report: &report, Previous: previous}
+		switch {
+		case above && !wasAbove:
+			event.Kind = WatchAlert
+			s.notify(ctx, report)
+		case !above && wasAbove:
+			event.Kind = WatchRecovered
+		}
+		emit(event)
+	}
+	rescan()
+
+	events := make(chan WatchEvent, 64)
+	go s.subscribe(ctx, url, address, network, events)
+
+	var due <-chan time.Time
+	var pendingSince time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-events:
+			emit(event)
+			if event.Kind != WatchActivity && !event.Reconnected {
+				continue
+			}
+			// Debounce: wait for a quiet period, but not forever
+			now := time.Now()
+			if pendingSince.IsZero() {
+				pendingSince = now
+			}
+			delay := opts.Debounce
+			if latest := pendingSince.Add(watchMaxDelay * opts.Debounce); now.Add(delay).After(latest) {
+				delay = latest.Sub(now)
+			}
+			due = time.After(delay)
+		case <-due:
+			due, pendingSince = nil, time.Time{}
+			rescan()
+		}
+	}
+}
+
+// subscribe keeps a subscription open until ctx is done, reconnecting with
+// exponential backoff
+func (s *Scanner) subscribe(ctx context.Context, url, address, network string, events chan<- WatchEvent) {
+	backoff := watchMinBackoff
+	reconnect := false
+	for ctx.Err() == nil {
+		connected, err := s.watchConnection(ctx, url, address, network, reconnect, events)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff, reconnect = watchMinBackoff, true
+		}
+		sendWatchEvent(ctx, events, WatchEvent{Kind: WatchDisconnected, Err: err, Retry: backoff})
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > watchMaxBackoff {
+			backoff = watchMaxBackoff
+		}
+	}
+}
+
+// sendWatchEvent delivers event unless the watch has ended
+func sendWatchEvent(ctx context.Context, events chan<- WatchEvent, event WatchEvent) {
+	select {
+	case events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// watchNotification is an eth_subscription message, or the reply to a
+// request when ID is set
+type watchNotification struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+	Params struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+// websocketOptions dials watch sockets through the proxy of the HTTP
+// client with its root certificates, logging the proxy at debug as
+// logProxy does for HTTP
+func (s *Scanner) websocketOptions(rawURL string) *websocket.Options {
+	transport, ok := s.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil
+	}
+	opts := &websocket.Options{Proxy: transport.Proxy, TLSConfig: transport.TLSClientConfig}
+	if opts.Proxy == nil {
+		return opts
+	}
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return opts
+	}
+	u.Scheme = strings.Replace(u.Scheme, "ws", "http", 1)
+	proxy, err := opts.Proxy(&http.Request{Method: http.MethodGet, URL: u, Host: u.Host})
+	if err == nil && proxy != nil {
+		s.logger.Debug("websocket proxy", "proxy", proxy.Redacted(), "url", redactURL(rawURL))
+	} else if err == nil {
+		s.logger.Debug("no websocket proxy", "url", redactURL(rawURL))
+	}
+	return opts
+}
+
+// watchConnection subscribes once and forwards activity until the socket
+// fails. connected reports whether the subscriptions were set up.
+func (s *Scanner) watchConnection(ctx context.Context, url, address, network string, reconnect bool, events chan<- WatchEvent) (connected bool, err error) {
+	dialCtx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	conn, err := websocket.Dial(dialCtx, url, nil, s.websocketOptions(url))
+	cancel()
+	if err != nil {
+		return false, redactError(err)
+	}
+	defer conn.Close()
+	// Unblock the read below when the watch ends
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	topic := "0x" + strings.Repeat("0", 24) + strings.ToLower(strings.TrimPrefix(address, "0x"))
+	subscriptions := [][]interface{}{
+		{"newHeads"},
+		{"logs", map[string]interface{}{"address": address}},
+		{"logs", map[string]interface{}{"topics": []interface{}{nil, topic}}},
+		{"logs", map[string]interface{}{"topics": []interface{}{nil, nil, topic}}},
+	}
+	for i, params := range subscriptions {
+		body, _ := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: i + 1, Method: "eth_subscribe", Params: params})
+		if err := conn.WriteMessage(body); err != nil {
+			return false, err
+		}
+	}
+
+	heads, state := "", ""
+	pending := len(subscriptions)
+	seen := map[string]bool{}
+	for {
+		conn.SetReadDeadline(time.Now().Add(watchIdleTimeout))
+		data, err := conn.ReadMessage()
+		if err != nil {
+			return connected, err
+		}
+		var msg watchNotification
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+		if msg.ID > 0 {
+			if msg.Error != nil {
+				return connected, fmt.Errorf("eth_subscribe: %w", msg.Error)
+			}
+			if msg.ID == 1 {
+				json.Unmarshal(msg.Result, &heads)
+			}
+			if pending--; pending == 0 {
+				connected = true
+				sendWatchEvent(ctx, events, WatchEvent{Kind: WatchConnected, Reconnected: reconnect})
+			}
+			continue
+		}
+
+		var txs []string
+		if msg.Params.Subscription == heads {
+			txs = s.blockActivity(ctx, msg.Params.Result, address, network, &state)
+		} else {
+			var log struct {
+				TxHash  string `json:"transactionHash"`
+				Removed bool   `json:"removed"`
+			}
+			if json.Unmarshal(msg.Params.Result, &log) == nil && !log.Removed {
+				txs = []string{log.TxHash}
+			}
+		}
+		for _, tx := range txs {
+			if seen[tx] {
+				continue
+			}
+			if len(seen) > 1000 {
+				seen = map[string]bool{}
+			}
+			seen[tx] = true
+			sendWatchEvent(ctx, events, WatchEvent{Kind: WatchActivity, TxHash: tx})
+		}
+	}
+}
+
+// blockActivity lists the transactions of a new block sent from or to
+// address. Native transfers emit no logs, so they are only seen here. The
+// full block is only fetched when the nonce or balance of address moved
+// since the last block; state carries them from one call to the next.
+// A zero-value call that leaves both alone and logs nothing is missed.
+func (s *Scanner) blockActivity(ctx context.Context, header json.RawMessage, address, network string, state *string) []string {
+	var head struct {
+		Hash   string `json:"hash"`
+		Number string `json:"number"`
+	}
+	if json.Unmarshal(header, &head) != nil || head.Hash == "" {
+		return nil
+	}
+	if head.Number != "" {
+		current, err := s.accountState(ctx, network, address, head.Number)
+		if err != nil {
+			// Fetch the block instead, and compare afresh next time
+			s.logger.Debug("account state lookup failed", "block", head.Hash, "err", err)
+			*state = ""
+		} else {
+			previous := *state
+			*state = current
+			if previous == current {
+				return nil
+			}
+		}
+	}
+
+	result, err := s.rpcCall(ctx, network, "eth_getBlockByHash", []interface{}{head.Hash, true})
+	if err != nil {
+		s.logger.Debug("block lookup failed", "block", head.Hash, "err", err)
+		return nil
+	}
+	var block struct {
+		Transactions []struct {
+			Hash string `json:"hash"`
+			From string `json:"from"`
+			To   string `json:"to"`
+		} `json:"transactions"`
+	}
+	if json.Unmarshal(result, &block) != nil {
+		return nil
+	}
+	var txs []string
+	for _, tx := range block.Transactions {
+		if strings.EqualFold(tx.From, address) || strings.EqualFold(tx.To, address) {
+			txs = append(txs, tx.Hash)
+		}
+	}
+	return txs
+}
+
+// accountState fingerprints address at block by its nonce and balance,
+// which every transaction it sends or value it receives changes
+func (s *Scanner) accountState(ctx context.Context, network, address, block string) (string, error) {
+	nonce, err := s.rpcCall(ctx, network, "eth_getTransactionCount", []interface{}{address, block})
+	if err != nil {
+		return "", err
+	}
+	balance, err := s.rpcCall(ctx, network, "eth_getBalance", []interface{}{address, block})
+	if err != nil {
+		return "", err
+	}
+	return string(nonce) + "/" + string(balance), nil
+}
@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBlockActivityFetchesOnlyChangedBlocks(t *testing.T) {
+	const address = "0x4444444444444444444444444444444444444444"
+	// Balance of address per block; block 0x4 fails the state lookup
+	balances := map[string]string{"0x1": "0x10", "0x2": "0x10", "0x3": "0x20", "0x5": "0x20"}
+	var fetched []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params []interface{}   `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		reply := func(result string) {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.ID, result)
+		}
+		switch req.Method {
+		case "eth_getTransactionCount":
+			reply(`"0x7"`)
+		case "eth_getBalance":
+			balance, ok := balances[req.Params[1].(string)]
+			if !ok {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32000,"message":"missing trie node"}}`, req.ID)
+				return
+			}
+			reply(`"` + balance + `"`)
+		case "eth_getBlockByHash":
+			hash := req.Params[0].(string)
+			fetched = append(fetched, hash)
+			reply(`{"transactions":[{"hash":"0xaa` + hash + `","from":"0x5555555555555555555555555555555555555555","to":"` + address + `"}]}`)
+		}
+	}))
+	defer server.Close()
+	s := NewScanner(Config{RPCURLs: map[string]string{"ethereum": server.URL}, MaxRetries: -1, RetryDelay: -1})
+
+	state := ""
+	var activity []string
+	for _, number := range []string{"0x1", "0x2", "0x3", "0x4", "0x5"} {
+		header := json.RawMessage(`{"hash":"` + number + `","number":"` + number + `"}`)
+		activity = append(activity, s.blockActivity(context.Background(), header, address, "ethereum", &state)...)
+	}
+	// 0x1 has no earlier state to compare with, 0x2 changed nothing, 0x3
+	// moved the balance, 0x4 could not be checked and 0x5 follows it
+	if want := []string{"0x1", "0x3", "0x4", "0x5"}; !reflect.DeepEqual(fetched, want) {
+		t.Errorf("fetched blocks %v, want %v", fetched, want)
+	}
+	if want := []string{"0xaa0x1", "0xaa0x3", "0xaa0x4", "0xaa0x5"}; !reflect.DeepEqual(activity, want) {
+		t.Errorf("activity %v, want %v", activity, want)
+	}
+}
@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DefaultWebhookThreshold is the lowest risk level posted to a webhook
+const DefaultWebhookThreshold = "high"
+
+// WebhookSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+// request body, keyed with WebhookConfig.Secret
+const WebhookSignatureHeader = "X-Scanner-Signature"
+
+// WebhookConfig posts reports at or above a risk level to a URL
+type WebhookConfig struct {
+	URL       string
+	Secret    string // signs the body; no signature header when empty
+	Threshold string // defaults to DefaultWebhookThreshold
+}
+
+// SignWebhook returns the WebhookSignatureHeader value for body, for
+// receivers verifying deliveries
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// notify posts report to the configured webhook if its risk is at or above
+// the threshold. Delivery failures are logged, never returned.
+func (s *Scanner) notify(ctx context.Context, report ReputationReport) {
+	hook := s.cfg.Webhook
+	if hook.URL == "" || RiskRank(report.RiskLevel) < RiskRank(hook.Threshold) {
+		return
+	}
+	// Deliver even when the scan itself ran out of time
+	ctx = context.WithoutCancel(ctx)
+	if err := s.postWebhook(ctx, report); err != nil {
+		s.logger.Warn("webhook delivery failed", "address", report.Address, "url", redactURL(hook.URL), "err", err)
+		return
+	}
+	s.logger.Debug("webhook delivered", "address", report.Address, "risk", report.RiskLevel)
+}
+
+func (s *Scanner) postWebhook(ctx context.Context, report ReputationReport) (err error) {
+	began := time.Now()
+	defer func() { s.metrics.recordRequest("webhook", time.Since(began), err) }()
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.RequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return redactError(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "agent-reputation-scanner/"+Version)
+	if s.cfg.Webhook.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(s.cfg.Webhook.Secret, body))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return redactError(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+
+	"agent-reputation-scanner/scanner"
+)
+
+// formatSQLite appends batch results to a SQLite database instead of
+// rendering them; it is picked by --output results.db too
+const formatSQLite = "sqlite"
+
+// sqliteTimeFormat is SQLite's own date and time text format, in UTC, so
+// columns compare with datetime('now', '-7 days') and the like
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+// sqliteBusyTimeout is how long a batch waits for another writer, such as
+// a concurrent batch or an open sqlite3 shell, to release the database
+const sqliteBusyTimeout = 5 * time.Second
+
+// sqliteTable is a table of --format sqlite: the statement creating it
+// and the columns the scanner inserts into
+type sqliteTable struct {
+	name    string
+	create  string
+	columns []string
+}
+
+// The tables of --format sqlite; run_at is the start of the batch that
+// wrote a report, scanned_at the report's own time and report_hash its
+// content hash. id columns are assigned by SQLite.
+var (
+	sqliteReports = sqliteTable{"reports", `CREATE TABLE IF NOT EXISTS reports (
+  id INTEGER PRIMARY KEY,
+  run_at TEXT NOT NULL,
+  scanned_at TEXT NOT NULL,
+  address TEXT NOT NULL,
+  network TEXT NOT NULL,
+  ens_name TEXT,
+  label TEXT,
+  overall_score INTEGER NOT NULL,
+  risk_level TEXT,
+  confidence INTEGER NOT NULL,
+  report_hash TEXT,
+  incomplete INTEGER NOT NULL,
+  error TEXT
+)`, []string{"run_at", "scanned_at", "address", "network", "ens_name", "label", "overall_score", "risk_level", "confidence", "report_hash", "incomplete", "error"}}
+	sqliteChecks = sqliteTable{"checks", `CREATE TABLE IF NOT EXISTS checks (
+  id INTEGER PRIMARY KEY,
+  report_id INTEGER NOT NULL REFERENCES reports(id),
+  name TEXT NOT NULL,
+  status TEXT NOT NULL,
+  score INTEGER NOT NULL,
+  severity TEXT,
+  reason_code TEXT,
+  details TEXT,
+  confidence INTEGER NOT NULL,
+  data_source TEXT
+)`, []string{"report_id", "name", "status", "score", "severity", "reason_code", "details", "confidence", "data_source"}}
+)
+
+// insert is the parameterized INSERT of a row into t
+func (t sqliteTable) insert() string {
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (?%s)", t.name, strings.Join(t.columns, ", "), strings.Repeat(", ?", len(t.columns)-1))
+}
+
+// isSQLitePath reports whether an --output file name asks for a database
+func isSQLitePath(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".sqlite", ".sqlite3":
+		return true
+	}
+	return false
+}
+
+// writeSQLite appends reports and their checks to the database at path,
+// creating it and its tables if missing. All rows go in one transaction,
+// so a failed write adds none of them.
+func writeSQLite(path string, reports []scanner.ReputationReport, runAt time.Time) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA busy_timeout = %d", sqliteBusyTimeout.Milliseconds())); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, table := range []sqliteTable{sqliteReports, sqliteChecks} {
+		if _, err := db.Exec(table.create); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err := table.checkColumns(db); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	defer tx.Rollback()
+	insertReport, err := tx.Prepare(sqliteReports.insert())
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	insertCheck, err := tx.Prepare(sqliteChecks.insert())
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, report := range reports {
+		result, err := insertReport.Exec(
+			runAt.UTC().Format(sqliteTimeFormat),
+			report.Timestamp.UTC().Format(sqliteTimeFormat),
+			report.Address,
+			report.Network,
+			nullable(report.ENSName),
+			nullable(report.Label),
+			report.OverallScore,
+			nullable(report.RiskLevel),
+			report.Confidence,
+			nullable(report.ContentHash),
+			report.Incomplete,
+			nullable(report.Error),
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		reportID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, check := range report.Checks {
+			_, err := insertCheck.Exec(
+				reportID,
+				check.Name,
+				check.Status,
+				check.Score,
+				nullable(check.Severity),
+				nullable(check.ReasonCode),
+				nullable(check.Details),
+				check.Confidence,
+				nullable(check.DataSource),
+			)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// checkColumns reports an existing table that lacks columns the scanner
+// writes, e.g. one made by another tool. Extra columns, indexes, views and
+// triggers added by analysts are fine.
+func (t sqliteTable) checkColumns(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", t.name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	have := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		have[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	var missing []string
+	for _, column := range t.columns {
+		if !have[column] {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("table %s does not have the columns the scanner writes (missing %s)", t.name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// nullable stores empty strings as NULL
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
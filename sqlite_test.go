@@ -0,0 +1,112 @@
+package main
+
+import (
+	"database/sql"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-reputation-scanner/scanner"
+)
+
+func TestWriteSQLiteAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.db")
+	runAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	report := scanner.ReputationReport{
+		Address:   "0x1111111111111111111111111111111111111111",
+		Network:   "ethereum",
+		Timestamp: runAt,
+		RiskLevel: "critical",
+		Checks: []scanner.CheckResult{
+			{Name: "Sanctions", Status: "fail", Severity: "critical", ReasonCode: scanner.ReasonSanctioned, Details: "listed"},
+			{Name: "Account Age", Status: "pass", Score: 100},
+		},
+	}
+	report.Seal()
+	errored := scanner.ReputationReport{Address: "0x2222222222222222222222222222222222222222", Network: "ethereum", Error: "timed out", Incomplete: true}
+
+	if err := writeSQLite(path, []scanner.ReputationReport{report}, runAt); err != nil {
+		t.Fatalf("first writeSQLite() error = %v", err)
+	}
+	// Indexes and tables analysts add do not get in the way of appends
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		"CREATE INDEX ix ON reports(address)",
+		"CREATE TABLE notes (address TEXT, note TEXT)",
+		"CREATE VIEW critical AS SELECT address FROM reports WHERE risk_level = 'critical'",
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if err := writeSQLite(path, []scanner.ReputationReport{report, errored}, runAt.Add(time.Hour)); err != nil {
+		t.Fatalf("second writeSQLite() error = %v", err)
+	}
+
+	var reports, checks, critical int
+	if err := db.QueryRow("SELECT (SELECT count(*) FROM reports), (SELECT count(*) FROM checks), (SELECT count(*) FROM critical)").Scan(&reports, &checks, &critical); err != nil {
+		t.Fatal(err)
+	}
+	if reports != 3 || checks != 4 || critical != 2 {
+		t.Fatalf("got %d reports, %d checks and %d critical, want 3, 4 and 2", reports, checks, critical)
+	}
+
+	var hash, reason string
+	if err := db.QueryRow("SELECT r.report_hash, c.reason_code FROM reports r JOIN checks c ON c.report_id = r.id WHERE r.id = 1 AND c.severity = 'critical'").Scan(&hash, &reason); err != nil {
+		t.Fatal(err)
+	}
+	if hash != report.ContentHash || reason != scanner.ReasonSanctioned {
+		t.Errorf("report_hash, reason_code = %s, %s, want %s, %s", hash, reason, report.ContentHash, scanner.ReasonSanctioned)
+	}
+	var runAtText, failure string
+	var label sql.NullString
+	var incomplete int
+	if err := db.QueryRow("SELECT run_at, label, incomplete, error FROM reports WHERE id = 3").Scan(&runAtText, &label, &incomplete, &failure); err != nil {
+		t.Fatal(err)
+	}
+	if runAtText != "2026-01-02 04:04:05" || label.Valid || incomplete != 1 || failure != "timed out" {
+		t.Errorf("errored report = %s, %v, %d, %q, want 2026-01-02 04:04:05, NULL, 1, timed out", runAtText, label, incomplete, failure)
+	}
+	var reportID int64
+	if err := db.QueryRow("SELECT report_id FROM checks WHERE id = 3").Scan(&reportID); err != nil || reportID != 2 {
+		t.Errorf("check 3 report_id = %d, %v, want 2", reportID, err)
+	}
+
+	sqlite3, err := exec.LookPath("sqlite3")
+	if err != nil {
+		return
+	}
+	out, err := exec.Command(sqlite3, path, "PRAGMA integrity_check; SELECT count(*) FROM reports WHERE address = '"+report.Address+"'").CombinedOutput()
+	if err != nil {
+		t.Fatalf("sqlite3: %v: %s", err, out)
+	}
+	if got := strings.Fields(string(out)); len(got) != 2 || got[0] != "ok" || got[1] != "2" {
+		t.Errorf("sqlite3 = %q, want ok and 2 reports", out)
+	}
+}
+
+func TestWriteSQLiteRejectsOtherSchemas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE reports (id INTEGER PRIMARY KEY, address TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	err = writeSQLite(path, []scanner.ReputationReport{{Address: "0x1111111111111111111111111111111111111111"}}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "table reports does not have the columns the scanner writes (missing run_at,") {
+		t.Errorf("writeSQLite() error = %v, want a schema mismatch", err)
+	}
+	var tables int
+	if err := db.QueryRow("SELECT count(*) FROM sqlite_master WHERE name = 'checks'").Scan(&tables); err != nil || tables != 0 {
+		t.Errorf("checks table created alongside a foreign reports table (%d, %v)", tables, err)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// streamBatch scans addresses while they are read and writes each report
+// as a JSON line: as soon as it completes, or in input order with
+// opts.ordered. Beyond the set of seen addresses nothing is kept per
+// address, so memory stays flat; in exchange there is no checkpoint. It
+// returns the most severe risk level found and the number of failed scans.
+func streamBatch(s *scanner.Scanner, filename string, opts batchOptions) (worst string, failed int) {
+	input := os.Stdin
+	if filename != stdinInput {
+		f, err := os.Open(filename)
+		if err != nil {
+			fatalf("Cannot read file: %v", err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	var w io.Writer = os.Stdout
+	if opts.output != "" {
+		if dir := filepath.Dir(opts.output); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				fatalf("Cannot write results: %v", err)
+			}
+		}
+		f, err := os.Create(opts.output)
+		if err != nil {
+			fatalf("Cannot write results: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+	}
+
+	infof("🔍 Streaming batch scan on %s...", opts.network)
+
+	parser := newBatchParser()
+	jobs := make(chan string)
+	parsed := make(chan struct{})
+	go func() {
+		// parsed is closed first, so it is once the reports run out
+		defer close(jobs)
+		defer close(parsed)
+		err := parser.read(input, opts.input, func(address string) {
+			select {
+			case jobs <- address:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil {
+			errorf("Cannot read input: %v", err)
+		}
+	}()
+
+	summary := newSummaryBuilder()
+	write := func(report scanner.ReputationReport) {
+		data, err := json.Marshal(opts.redactReport(report))
+		if err != nil {
+			fatalf("Cannot render results: %v", err)
+		}
+		if opts.validate {
+			if err := scanner.ValidateReportJSON(data); err != nil {
+				fatalf("Results do not match schema: %v", err)
+			}
+		}
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			fatalf("Cannot write results: %v", err)
+		}
+	}
+
+	var reports <-chan scanner.ReputationReport
+	if opts.ordered {
+		reports = s.ScanStreamOrdered(ctx, jobs, opts.network)
+	} else {
+		reports = s.ScanStream(ctx, jobs, opts.network)
+	}
+	for report := range reports {
+		parser.annotate(&report)
+		printBatchLine(report)
+		summary.add(report)
+		if report.Error != "" {
+			failed++
+		}
+		// With --baseline, addresses without new findings do not count
+		accepted := report.Baseline != nil && report.Baseline.New == 0
+		if !accepted && scanner.RiskRank(report.RiskLevel) > scanner.RiskRank(worst) {
+			worst = report.RiskLevel
+		}
+		write(report)
+	}
+	// After a timeout the input may be a pipe that is still open, so the
+	// reader is not waited for and its skipped lines are not reported
+	var in batchInput
+	select {
+	case <-parsed:
+		in = batchInput{invalid: parser.invalid, duplicates: parser.duplicates}
+	default:
+	}
+	if ctx.Err() != nil {
+		warnf("Batch timed out after %s; the remaining addresses were not scanned", opts.timeout)
+	}
+	in.logSkipped()
+	if failed > 0 {
+		warnf("%d addresses could not be scanned", failed)
+	}
+	infof("📊 %s", summary.finish(in.duplicates, len(in.invalid)))
+	if opts.output != "" {
+		infof("✅ Results saved to %s", opts.output)
+	}
+	return worst, failed
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// batchSummary aggregates the results of a batch scan
+type batchSummary struct {
+	Total             int            `json:"total"`  // unique addresses
+	Failed            int            `json:"failed"` // scans that returned an error
+	Duplicates        int            `json:"duplicates"`
+	InvalidLines      int            `json:"invalid_lines"`
+	RiskLevels        map[string]int `json:"risk_levels"`
+	MeanScore         float64        `json:"mean_score"`
+	MedianScore       float64        `json:"median_score"`
+	CriticalAddresses []string       `json:"critical_addresses"`
+	// RiskMismatches are addresses whose risk level differs from the
+	// expected_risk of a CSV batch file
+	RiskMismatches []string `json:"risk_mismatches,omitempty"`
+	// TimedOut are addresses whose scan ran out of time and whose report
+	// is partial
+	TimedOut []string `json:"timed_out,omitempty"`
+}
+
+// summarize computes aggregate statistics over reports. Failed scans are
+// counted but excluded from the score statistics.
+func summarize(reports []scanner.ReputationReport, input batchInput) batchSummary {
+	b := newSummaryBuilder()
+	for _, report := range reports {
+		b.add(report)
+	}
+	return b.finish(input.duplicates, len(input.invalid))
+}
+
+// summaryBuilder accumulates a batchSummary as reports arrive. Of each
+// report only the score is kept, for the median.
+type summaryBuilder struct {
+	summary batchSummary
+	scores  []int
+}
+
+func newSummaryBuilder() *summaryBuilder {
+	b := &summaryBuilder{summary: batchSummary{
+		RiskLevels:        map[string]int{},
+		CriticalAddresses: []string{},
+	}}
+	for _, level := range scanner.RiskLevels {
+		b.summary.RiskLevels[level] = 0
+	}
+	return b
+}
+
+func (b *summaryBuilder) add(report scanner.ReputationReport) {
+	b.summary.Total++
+	if report.Incomplete {
+		b.summary.TimedOut = append(b.summary.TimedOut, report.Address)
+	}
+	if report.Error != "" {
+		b.summary.Failed++
+		return
+	}
+	b.summary.RiskLevels[report.RiskLevel]++
+	if report.RiskMismatch {
+		b.summary.RiskMismatches = append(b.summary.RiskMismatches, report.Address)
+	}
+	b.scores = append(b.scores, report.OverallScore)
+	if report.RiskLevel == "critical" {
+		b.summary.CriticalAddresses = append(b.summary.CriticalAddresses, report.Address)
+	}
+}
+
+// finish computes the score statistics and adds the input line counts
+func (b *summaryBuilder) finish(duplicates, invalidLines int) batchSummary {
+	summary := b.summary
+	summary.Duplicates = duplicates
+	summary.InvalidLines = invalidLines
+
+	scores := b.scores
+	if len(scores) > 0 {
+		sort.Ints(scores)
+		total := 0
+		for _, score := range scores {
+			total += score
+		}
+		summary.MeanScore = roundTenth(float64(total) / float64(len(scores)))
+		mid := len(scores) / 2
+		if len(scores)%2 == 0 {
+			summary.MedianScore = float64(scores[mid-1]+scores[mid]) / 2
+		} else {
+			summary.MedianScore = float64(scores[mid])
+		}
+	}
+	return summary
+}
+
+func roundTenth(f float64) float64 {
+	return float64(int(f*10+0.5)) / 10
+}
+
+// String is the one-line summary printed at the end of a batch, e.g.
+// "Scanned 1000: 12 critical, 34 high, 100 medium, 854 low"
+func (s batchSummary) String() string {
+	parts := []string{}
+	for i := len(scanner.RiskLevels) - 1; i >= 0; i-- {
+		level := scanner.RiskLevels[i]
+		parts = append(parts, fmt.Sprintf("%d %s", s.RiskLevels[level], level))
+	}
+	if s.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", s.Failed))
+	}
+	line := fmt.Sprintf("Scanned %d: %s (mean score %.1f, median %.1f)",
+		s.Total, strings.Join(parts, ", "), s.MeanScore, s.MedianScore)
+	if len(s.RiskMismatches) > 0 {
+		line += fmt.Sprintf("; %d not at their expected risk level", len(s.RiskMismatches))
+	}
+	if len(s.TimedOut) > 0 {
+		line += fmt.Sprintf("; %d timed out", len(s.TimedOut))
+	}
+	if s.Duplicates > 0 || s.InvalidLines > 0 {
+		line += fmt.Sprintf("; skipped %d duplicates, %d invalid lines", s.Duplicates, s.InvalidLines)
+	}
+	return line
+}
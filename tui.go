@@ -0,0 +1,491 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"agent-reputation-scanner/internal/term"
+	"agent-reputation-scanner/scanner"
+)
+
+// ANSI escape sequences used by the TUI
+const (
+	ansiAltScreen  = "\x1b[?1049h"
+	ansiMainScreen = "\x1b[?1049l"
+	ansiHideCursor = "\x1b[?25l"
+	ansiShowCursor = "\x1b[?25h"
+	ansiHome       = "\x1b[H"
+	ansiClearLine  = "\x1b[K"
+	ansiReverse    = "\x1b[7m"
+	ansiBold       = "\x1b[1m"
+	ansiReset      = "\x1b[0m"
+)
+
+// Keys decoded from terminal input
+const (
+	keyUp = iota + 256
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+	keyCtrlC = 3
+)
+
+// tuiSort is a table column the TUI can sort by
+type tuiSort int
+
+const (
+	sortInput tuiSort = iota
+	sortScore
+	sortRisk
+	sortAddress
+)
+
+var tuiSortNames = map[tuiSort]string{
+	sortInput:   "input order",
+	sortScore:   "score",
+	sortRisk:    "risk",
+	sortAddress: "address",
+}
+
+// tuiRow is one address in the results table
+type tuiRow struct {
+	index    int // position in the input file
+	address  string
+	report   scanner.ReputationReport
+	scanning bool
+}
+
+// tuiModel holds the TUI state; it is only touched by the event loop
+type tuiModel struct {
+	rows     []*tuiRow
+	byAddr   map[string]*tuiRow
+	view     []*tuiRow // rows in display order
+	sortBy   tuiSort
+	desc     bool
+	selected int
+	offset   int // first visible table row
+	width    int
+	height   int
+}
+
+// runTUI scans the addresses in filename and shows the results in an
+// interactive table until the user quits
+func runTUI(s *scanner.Scanner, filename, network, format string) {
+	if filename == stdinInput {
+		fatalf("The TUI reads keys from stdin; pass an addresses file instead of -")
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		fatalf("The TUI requires an interactive terminal")
+	}
+	data, err := readBatchInput(filename)
+	if err != nil {
+		fatalf("Cannot read file: %v", err)
+	}
+	input := parseBatchInput(string(data), format)
+	input.logSkipped()
+	if len(input.addresses) == 0 {
+		fatalf("No addresses to scan in %s", filename)
+	}
+
+	restore, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		fatalf("Cannot start the TUI: %v", err)
+	}
+	fmt.Print(ansiAltScreen + ansiHideCursor)
+	defer func() {
+		fmt.Print(ansiShowCursor + ansiMainScreen)
+		restore()
+	}()
+
+	m := newTUIModel(input.addresses)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Scans run in the background and report back to the event loop
+	results := make(chan scanner.ReputationReport, len(input.addresses))
+	go s.ScanBatchContext(ctx, input.addresses, network, func(report scanner.ReputationReport) {
+		results <- report
+	})
+	keys := make(chan int)
+	go readKeys(keys)
+
+	for {
+		m.resize()
+		os.Stdout.WriteString(m.render())
+
+		select {
+		case report := <-results:
+			m.update(report)
+		case key, ok := <-keys:
+			if !ok || key == 'q' || key == keyCtrlC {
+				return
+			}
+			if key == 'r' {
+				if row := m.current(); row != nil && !row.scanning {
+					row.scanning = true
+					go func(address string) {
+						s.Forget(address, network)
+						report, err := s.ScanContext(ctx, address, network)
+						if err != nil && report.Address == "" {
+							report = scanner.ReputationReport{Address: address, Error: err.Error()}
+						}
+						results <- report
+					}(row.address)
+				}
+				continue
+			}
+			m.handleKey(key)
+		}
+	}
+}
+
+func newTUIModel(addresses []string) *tuiModel {
+	m := &tuiModel{byAddr: map[string]*tuiRow{}}
+	for i, address := range addresses {
+		row := &tuiRow{index: i, address: address, scanning: true}
+		m.rows = append(m.rows, row)
+		m.byAddr[strings.ToLower(address)] = row
+	}
+	m.sort()
+	return m
+}
+
+// update stores a finished scan and keeps the selection on the same row
+func (m *tuiModel) update(report scanner.ReputationReport) {
+	row, ok := m.byAddr[strings.ToLower(report.Address)]
+	if !ok {
+		return
+	}
+	row.report = report
+	row.scanning = false
+	m.sort()
+}
+
+func (m *tuiModel) current() *tuiRow {
+	if m.selected < 0 || m.selected >= len(m.view) {
+		return nil
+	}
+	return m.view[m.selected]
+}
+
+func (m *tuiModel) handleKey(key int) {
+	page := m.tableHeight()
+	switch key {
+	case keyUp, 'k':
+		m.selected--
+	case keyDown, 'j':
+		m.selected++
+	case keyPageUp:
+		m.selected -= page
+	case keyPageDown, ' ':
+		m.selected += page
+	case keyHome, 'g':
+		m.selected = 0
+	case keyEnd, 'G':
+		m.selected = len(m.view) - 1
+	case 's':
+		m.setSort(sortScore)
+	case 'l':
+		m.setSort(sortRisk)
+	case 'a':
+		m.setSort(sortAddress)
+	case 'o':
+		m.setSort(sortInput)
+	}
+	if m.selected >= len(m.view) {
+		m.selected = len(m.view) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+}
+
+// setSort sorts by column, toggling the direction when it is already active
+func (m *tuiModel) setSort(column tuiSort) {
+	if m.sortBy == column {
+		m.desc = !m.desc
+	} else {
+		m.sortBy, m.desc = column, false
+	}
+	m.sort()
+}
+
+// sort rebuilds the display order; rows still scanning sort last
+func (m *tuiModel) sort() {
+	selected := m.current()
+	m.view = append(m.view[:0], m.rows...)
+	sort.SliceStable(m.view, func(i, j int) bool {
+		a, b := m.view[i], m.view[j]
+		if m.sortBy != sortInput && m.sortBy != sortAddress && a.scanning != b.scanning {
+			return b.scanning
+		}
+		var less, greater bool
+		switch m.sortBy {
+		case sortScore:
+			less, greater = a.report.OverallScore < b.report.OverallScore, a.report.OverallScore > b.report.OverallScore
+		case sortRisk:
+			ra, rb := scanner.RiskRank(a.report.RiskLevel), scanner.RiskRank(b.report.RiskLevel)
+			less, greater = ra < rb, ra > rb
+		case sortAddress:
+			aa, ab := strings.ToLower(a.address), strings.ToLower(b.address)
+			less, greater = aa < ab, aa > ab
+		default:
+			less, greater = a.index < b.index, a.index > b.index
+		}
+		if m.desc {
+			return greater
+		}
+		return less
+	})
+	for i, row := range m.view {
+		if row == selected {
+			m.selected = i
+		}
+	}
+}
+
+func (m *tuiModel) resize() {
+	m.width, m.height = 80, 24
+	if w, h, err := term.Size(int(os.Stdout.Fd())); err == nil && w > 0 && h > 0 {
+		m.width, m.height = w, h
+	}
+}
+
+// tableHeight is the number of result rows shown; the table gets the top
+// half of the screen below the title and column header, leaving small
+// screens room for the separator and key help
+func (m *tuiModel) tableHeight() int {
+	h := m.height/2 - 2
+	if h < 3 {
+		h = 3
+	}
+	if limit := m.height - 4; h > limit {
+		h = limit
+	}
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// render draws the whole screen
+func (m *tuiModel) render() string {
+	var b strings.Builder
+	b.WriteString(ansiHome)
+	lines := 0
+	line := func(style, text string) {
+		if lines >= m.height {
+			return
+		}
+		if lines > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(style + truncate(text, m.width) + ansiReset + ansiClearLine)
+		lines++
+	}
+
+	scanned := 0
+	for _, row := range m.rows {
+		if !row.scanning {
+			scanned++
+		}
+	}
+	order := "ascending"
+	if m.desc {
+		order = "descending"
+	}
+	line(ansiBold, fmt.Sprintf("🔍 Agent Reputation Scanner — %d/%d scanned — sorted by %s (%s)", scanned, len(m.rows), tuiSortNames[m.sortBy], order))
+	line(ansiBold, fmt.Sprintf("  %-42s  %-8s  %5s  %s", "ADDRESS", "RISK", "SCORE", "STATUS"))
+
+	// Keep the selection visible
+	height := m.tableHeight()
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+	if m.selected >= m.offset+height {
+		m.offset = m.selected - height + 1
+	}
+	for i := m.offset; i < m.offset+height; i++ {
+		if i >= len(m.view) {
+			line("", "")
+			continue
+		}
+		style := ""
+		if i == m.selected {
+			style = ansiReverse
+		}
+		line(style, "  "+formatTUIRow(m.view[i]))
+	}
+
+	line("", strings.Repeat("─", m.width))
+	for _, text := range m.details(m.current()) {
+		if lines >= m.height-1 {
+			break
+		}
+		line("", text)
+	}
+	for lines < m.height-1 {
+		line("", "")
+	}
+	line(ansiReverse, " ↑/↓ move  s score  l risk  a address  o input order  r rescan  q quit")
+	return b.String()
+}
+
+func formatTUIRow(row *tuiRow) string {
+	switch {
+	case row.scanning && row.report.Timestamp.IsZero():
+		return fmt.Sprintf("%-42s  %-8s  %5s  %s", row.address, "", "", "scanning…")
+	case row.report.Error != "":
+		return fmt.Sprintf("%-42s  %-8s  %5s  %s", row.address, "", "", "error: "+row.report.Error)
+	}
+	status := ""
+	if row.scanning {
+		status = "rescanning…"
+	} else if row.report.Incomplete {
+		status = "incomplete"
+	}
+	return fmt.Sprintf("%-42s  %-8s  %5d  %s", row.address, strings.ToUpper(row.report.RiskLevel), row.report.OverallScore, status)
+}
+
+// details returns the detail pane lines for the selected row
+func (m *tuiModel) details(row *tuiRow) []string {
+	if row == nil {
+		return nil
+	}
+	report := row.report
+	lines := []string{row.address}
+	if report.Timestamp.IsZero() {
+		return append(lines, "  Scanning…")
+	}
+	if report.Error != "" {
+		return append(lines, "  Error: "+report.Error)
+	}
+	header := fmt.Sprintf("  %s %s — score %d/100 — confidence %d%%", getRiskEmoji(report.RiskLevel), strings.ToUpper(report.RiskLevel), report.OverallScore, report.Confidence)
+	if report.ENSName != "" {
+		header += " — " + report.ENSName
+	}
+	if report.AllowlistLabel != "" {
+		header += " — " + report.AllowlistLabel
+	}
+	lines = append(lines, header)
+	for _, check := range report.Checks {
+		statusIcon := "✓"
+		if check.Status == "warning" {
+			statusIcon = "!"
+		} else if check.Status == "fail" {
+			statusIcon = "✗"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %-25s %3d%%  %s", statusIcon, check.Name, check.Score, check.Details))
+	}
+	return lines
+}
+
+// truncate cuts s to width terminal columns, ending it with … when cut
+func truncate(s string, width int) string {
+	if width <= 0 || stringWidth(s) <= width {
+		return s
+	}
+	used := 0
+	for i, r := range s {
+		w := runeWidth(r)
+		if used+w > width-1 {
+			return s[:i] + "…"
+		}
+		used += w
+	}
+	return s
+}
+
+// stringWidth is the number of terminal columns s takes
+func stringWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// wideRunes are the ranges of East Asian wide and fullwidth characters
+// and of emoji shown two columns wide, in ascending order
+var wideRunes = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe30, 0xfe4f}, {0xff00, 0xff60},
+	{0xffe0, 0xffe6}, {0x1f300, 0x1f64f}, {0x1f680, 0x1f6ff}, {0x1f7e0, 0x1f7eb},
+	{0x1f900, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x3fffd},
+}
+
+// runeWidth is the number of terminal columns r takes: none for combining
+// marks and format characters such as the emoji variation selector, two
+// for wide characters and one otherwise
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	i := sort.Search(len(wideRunes), func(i int) bool { return wideRunes[i][1] >= r })
+	if i < len(wideRunes) && wideRunes[i][0] <= r {
+		return 2
+	}
+	return 1
+}
+
+// readKeys decodes raw terminal input into keys until stdin closes
+func readKeys(keys chan<- int) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, key := range decodeKeys(buf[:n]) {
+			keys <- key
+		}
+	}
+}
+
+// decodeKeys maps bytes, and the escape sequences of arrow and paging
+// keys, to keys
+func decodeKeys(data []byte) []int {
+	sequences := map[string]int{
+		"\x1b[A": keyUp, "\x1bOA": keyUp,
+		"\x1b[B": keyDown, "\x1bOB": keyDown,
+		"\x1b[5~": keyPageUp,
+		"\x1b[6~": keyPageDown,
+		"\x1b[H":  keyHome, "\x1b[1~": keyHome, "\x1bOH": keyHome,
+		"\x1b[F": keyEnd, "\x1b[4~": keyEnd, "\x1bOF": keyEnd,
+	}
+	var keys []int
+	for len(data) > 0 {
+		matched := false
+		if data[0] == 0x1b {
+			for seq, key := range sequences {
+				if strings.HasPrefix(string(data), seq) {
+					keys = append(keys, key)
+					data = data[len(seq):]
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			keys = append(keys, int(data[0]))
+			data = data[1:]
+		}
+	}
+	return keys
+}
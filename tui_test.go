@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"agent-reputation-scanner/scanner"
+)
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"—…✓✗─", 5},
+		{"🔍", 2},
+		{"🟢🟡🟠🔴⚪", 10},
+		{"⚠️", 1}, // text-default symbol with a variation selector
+		{"é", 1},  // e + combining acute accent
+		{"日本", 4},
+		{"ｆｕｌｌ", 8},
+	}
+	for _, tt := range tests {
+		if got := stringWidth(tt.s); got != tt.want {
+			t.Errorf("stringWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 5, "hello"},
+		{"hello", 4, "hel…"},
+		{"hello", 1, "…"},
+		{"hello", 0, "hello"},
+		{"🔍 scan", 7, "🔍 scan"},
+		{"🔍 scan", 6, "🔍 sc…"},
+		// A wide rune that would straddle the last column is left out
+		{"ab🔍cd", 4, "ab…"},
+		{"日本語", 5, "日本…"},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if tt.width > 0 && stringWidth(got) > tt.width {
+			t.Errorf("truncate(%q, %d) is %d columns wide", tt.s, tt.width, stringWidth(got))
+		}
+	}
+}
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// screenLines splits a rendered screen into its lines without escapes
+func screenLines(screen string) []string {
+	return strings.Split(ansiPattern.ReplaceAllString(screen, ""), "\r\n")
+}
+
+func testModel(n int) *tuiModel {
+	addresses := make([]string, n)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("0x%040x", i+1)
+	}
+	m := newTUIModel(addresses)
+	for i, address := range addresses {
+		m.update(scanner.ReputationReport{
+			Address:      address,
+			Timestamp:    time.Unix(1, 0),
+			OverallScore: (i * 37) % 100,
+			RiskLevel:    scanner.RiskLevels[i%len(scanner.RiskLevels)],
+			Checks: []scanner.CheckResult{
+				{Name: "Sanctions", Status: "pass", Score: 100, Details: strings.Repeat("very long details ", 20)},
+				{Name: "Account Age", Status: "warning", Score: 50, Details: "日本語の説明"},
+			},
+		})
+	}
+	return m
+}
+
+func TestRenderFitsScreen(t *testing.T) {
+	for _, size := range [][2]int{{80, 24}, {40, 10}, {120, 50}, {20, 6}} {
+		m := testModel(30)
+		m.width, m.height = size[0], size[1]
+		lines := screenLines(m.render())
+		if len(lines) != m.height {
+			t.Errorf("%dx%d: rendered %d lines", m.width, m.height, len(lines))
+		}
+		for i, line := range lines {
+			if w := stringWidth(line); w > m.width {
+				t.Errorf("%dx%d: line %d is %d columns wide: %q", m.width, m.height, i+1, w, line)
+			}
+		}
+		if last := lines[len(lines)-1]; !strings.Contains(last, "move") {
+			t.Errorf("%dx%d: last line %q is not the key help", m.width, m.height, last)
+		}
+	}
+}
+
+func TestRenderKeepsSelectionVisible(t *testing.T) {
+	m := testModel(30)
+	m.width, m.height = 100, 20
+	for i := 0; i < 25; i++ {
+		m.handleKey(keyDown)
+	}
+	screen := strings.Join(screenLines(m.render()), "\n")
+	if selected := m.current().address; !strings.Contains(screen, "  "+selected) {
+		t.Errorf("selected row %s is off screen:\n%s", selected, screen)
+	}
+	if m.offset == 0 {
+		t.Error("table did not scroll")
+	}
+
+	m.handleKey(keyEnd)
+	if m.selected != 29 {
+		t.Errorf("End selected row %d, want 29", m.selected)
+	}
+	m.handleKey(keyPageDown)
+	if m.selected != 29 {
+		t.Errorf("Page Down past the end selected row %d, want 29", m.selected)
+	}
+	m.handleKey(keyHome)
+	m.handleKey(keyUp)
+	if m.selected != 0 {
+		t.Errorf("Up at the top selected row %d, want 0", m.selected)
+	}
+}
+
+func TestSortKeepsSelection(t *testing.T) {
+	m := testModel(10)
+	m.handleKey(keyDown)
+	m.handleKey(keyDown)
+	want := m.current()
+
+	m.handleKey('s')
+	if m.current() != want {
+		t.Errorf("sorting by score moved the selection to %s, want %s", m.current().address, want.address)
+	}
+	for i := 1; i < len(m.view); i++ {
+		if m.view[i-1].report.OverallScore > m.view[i].report.OverallScore {
+			t.Fatalf("rows not sorted by ascending score at %d", i)
+		}
+	}
+	m.handleKey('s')
+	if !m.desc || m.view[0].report.OverallScore < m.view[len(m.view)-1].report.OverallScore {
+		t.Error("pressing s again did not sort by descending score")
+	}
+
+	// Rows still scanning sort last by score
+	m.rows[0].scanning = true
+	m.sort()
+	if m.view[len(m.view)-1] != m.rows[0] {
+		t.Error("a row being scanned does not sort last")
+	}
+}
+
+func TestDecodeKeys(t *testing.T) {
+	got := decodeKeys([]byte("j\x1b[A\x1bOB\x1b[5~\x1b[6~\x1b[H\x1b[4~q\x1b\x03"))
+	want := []int{'j', keyUp, keyDown, keyPageUp, keyPageDown, keyHome, keyEnd, 'q', 0x1b, keyCtrlC}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decodeKeys() = %v, want %v", got, want)
+	}
+}
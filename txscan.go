@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// scanTransaction scans the participants of a transaction and writes the
+// combined report: a TransactionReport in JSON, the per-check rows of
+// every participant in CSV, or the transaction context with a section per
+// participant in text
+func scanTransaction(ctx context.Context, s *scanner.Scanner, hash, network string, out outputOptions) scanner.TransactionReport {
+	if out.format != formatText && out.format != formatJSON && out.format != formatCSV && out.format != formatVerdict {
+		fatalf("scan-tx supports --format text, json or csv")
+	}
+	infof("🔍 Scanning the participants of %s on %s...", hash, network)
+
+	report, err := s.ScanTransactionContext(ctx, hash, network)
+	for _, p := range report.Participants {
+		if p.Report.Error != "" {
+			warnf("%s: %s", p.Address, p.Report.Error)
+		}
+	}
+	if err != nil {
+		fatalf("%v", err)
+	}
+	if report.Incomplete {
+		warnf("Scan deadline exceeded; some checks timed out (raise --timeout)")
+	}
+	participants := make([]scanner.ReputationReport, len(report.Participants))
+	for i, p := range report.Participants {
+		participants[i] = p.Report
+	}
+	reportSetupErrors(participants...)
+
+	rendered := out.redactTransaction(report)
+	var buf bytes.Buffer
+	switch out.format {
+	case formatJSON:
+		err = writeJSON(&buf, rendered)
+	case formatCSV:
+		cw := csv.NewWriter(&buf)
+		cw.Write(csvHeader)
+		for _, p := range rendered.Participants {
+			writeCSVRows(cw, p.Report)
+		}
+		cw.Flush()
+		err = cw.Error()
+	case formatVerdict:
+		for _, p := range rendered.Participants {
+			writeVerdict(&buf, p.Report)
+		}
+	default:
+		writeTransactionReport(&buf, rendered)
+	}
+	if err != nil {
+		fatalf("Cannot render report: %v", err)
+	}
+	if out.validate {
+		if err := scanner.ValidateTransactionJSON(buf.Bytes()); err != nil {
+			fatalf("Report does not match schema: %v", err)
+		}
+	}
+	out.explainReports(&buf, s, participants...)
+
+	if out.output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return report
+	}
+	if err := writeOutput(out.output, buf.Bytes()); err != nil {
+		fatalf("Cannot write report: %v", err)
+	}
+	infof("✅ Report saved to %s", out.output)
+	return report
+}
+
+func writeTransactionReport(w io.Writer, report scanner.TransactionReport) {
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "  TRANSACTION REPUTATION REPORT\n")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintf(w, "Tx:       %s\n", report.TxHash)
+	fmt.Fprintf(w, "Network:  %s\n", report.Network)
+	status := report.Status
+	if report.BlockNumber != nil {
+		status += fmt.Sprintf(" (block %d)", *report.BlockNumber)
+	}
+	fmt.Fprintf(w, "Status:   %s\n", status)
+	fmt.Fprintf(w, "From:     %s\n", report.From)
+	if report.To != "" {
+		fmt.Fprintf(w, "To:       %s\n", report.To)
+	}
+	if report.ContractCreated != "" {
+		fmt.Fprintf(w, "Created:  %s\n", report.ContractCreated)
+	}
+	fmt.Fprintf(w, "Method:   %s\n", report.Method)
+	fmt.Fprintf(w, "Value:    %s\n", report.Value)
+	fmt.Fprintf(w, "Time:     %s\n", report.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "Overall Score: %d/100 (worst participant)\n", report.OverallScore)
+	fmt.Fprintf(w, "Risk Level:    %s %s (%s)\n", getRiskEmoji(report.RiskLevel), strings.ToUpper(report.RiskLevel), report.RiskiestAddress)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "PARTICIPANTS:")
+	fmt.Fprintln(w, strings.Repeat("─", 60))
+	for _, p := range report.Participants {
+		roles := strings.Join(p.Roles, ", ")
+		if p.Report.Error != "" {
+			fmt.Fprintf(w, "  %s %-9s %s error: %s\n", getRiskEmoji(""), roles, p.Address, p.Report.Error)
+			continue
+		}
+		fmt.Fprintf(w, "  %s %-9s %s %3d/100 %s\n", getRiskEmoji(p.Report.RiskLevel), roles, p.Address, p.Report.OverallScore, p.Report.RiskLevel)
+		for _, check := range p.Report.Checks {
+			// Same rule as the multi-chain findings: skipped checks say nothing
+			if check.Status == "fail" || check.Status == "warning" && check.DataSource != scanner.DataFallback {
+				statusIcon := "⚠️"
+				if check.Status == "fail" {
+					statusIcon = "✗"
+				}
+				fmt.Fprintf(w, "     %s %-25s [%d%%] %s\n", statusIcon, check.Name, check.Score, check.Details)
+			}
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+	fmt.Fprintln(w, "⚠️  This is an automated assessment. Always conduct")
+	fmt.Fprintln(w, "   additional due diligence for high-value transactions.")
+	fmt.Fprintln(w, strings.Repeat("═", 60))
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// runVerify checks the content hash of every report in the files: a JSON
+// report, an array of reports as written by a multi-address scan, a batch
+// JSON document or ndjson. It returns exitError if any report was altered
+// or has no hash.
+func runVerify(paths []string) int {
+	if len(paths) == 0 {
+		fatalf("Usage: scanner verify report.json [...]")
+	}
+	failed := false
+	for _, path := range paths {
+		reports, err := readReports(path)
+		if err != nil {
+			fatalf("%v", err)
+		}
+		for i, data := range reports {
+			var id struct {
+				Address  string `json:"address"`
+				ReportID string `json:"report_id"`
+			}
+			json.Unmarshal(data, &id)
+			name := fmt.Sprintf("%s: %s", path, id.Address)
+			if len(reports) > 1 && id.Address == "" {
+				name = fmt.Sprintf("%s: report %d", path, i+1)
+			}
+			if err := scanner.VerifyContentHash(data); err != nil {
+				errorf("%s: %v", name, err)
+				failed = true
+				continue
+			}
+			infof("✅ %s (%s) unchanged", name, id.ReportID)
+		}
+	}
+	if failed {
+		return exitError
+	}
+	return 0
+}
+
+// readReports splits a report file into the JSON of each report
+func readReports(path string) ([]json.RawMessage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		var reports []json.RawMessage
+		if err := json.Unmarshal(data, &reports); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return reports, nil
+	}
+	if json.Valid(data) {
+		var batch struct {
+			Results []json.RawMessage `json:"results"`
+		}
+		if json.Unmarshal(data, &batch) == nil && batch.Results != nil {
+			return batch.Results, nil
+		}
+		return []json.RawMessage{data}, nil
+	}
+
+	// ndjson: one report per line
+	var reports []json.RawMessage
+	lines := bufio.NewScanner(bytes.NewReader(data))
+	lines.Buffer(nil, 16<<20)
+	for lines.Scan() {
+		if line := bytes.TrimSpace(lines.Bytes()); len(line) > 0 {
+			reports = append(reports, json.RawMessage(append([]byte(nil), line...)))
+		}
+	}
+	return reports, lines.Err()
+}
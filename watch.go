@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"agent-reputation-scanner/scanner"
+)
+
+// runWatch rescans address whenever it is active on network until
+// interrupted, alerting when its risk reaches the threshold. Text output
+// logs each event and prints the full report of alerts; ndjson writes the
+// report of every scan.
+func runWatch(s *scanner.Scanner, address, network string, opts scanner.WatchOptions, out outputOptions) int {
+	format := out.format
+	if format != formatText && format != formatNDJSON {
+		fatalf("watch supports --format text or ndjson")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	infof("👀 Watching %s on %s (alerts at %s risk and above)", address, network, opts.Threshold)
+	err := s.Watch(ctx, address, network, opts, func(event scanner.WatchEvent) {
+		switch event.Kind {
+		case scanner.WatchConnected:
+			if event.Reconnected {
+				infof("🔌 Reconnected; rescanning for missed activity")
+			} else {
+				infof("🔌 Subscribed to new blocks and logs")
+			}
+		case scanner.WatchDisconnected:
+			warnf("Connection lost (%v); reconnecting in %s", event.Err, event.Retry)
+		case scanner.WatchActivity:
+			infof("⚡ Activity in %s", event.TxHash)
+		case scanner.WatchScanFailed:
+			errorf("Rescan failed: %v", event.Err)
+		case scanner.WatchScanned, scanner.WatchAlert, scanner.WatchRecovered:
+			report := out.redactReport(*event.Report)
+			if format == formatNDJSON {
+				if err := renderReport(os.Stdout, report, formatNDJSON); err != nil {
+					fatalf("Cannot render report: %v", err)
+				}
+			}
+			switch event.Kind {
+			case scanner.WatchAlert:
+				errorf("ALERT: %s risk is %s (score %d/100)", shortAddress(report.Address), report.RiskLevel, report.OverallScore)
+				if format == formatText {
+					writeTextReport(os.Stdout, report)
+				}
+			case scanner.WatchRecovered:
+				infof("✅ %s risk back to %s (score %d/100)", shortAddress(report.Address), report.RiskLevel, report.OverallScore)
+			default:
+				printBatchLine(report)
+			}
+		}
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fatalf("%v", err)
+	}
+	infof("⏹  Stopped watching")
+	return exitOK
+}